@@ -20,7 +20,10 @@ func newBreakingCmd() *cobra.Command {
 	}
 	cmd.Flags().String("against", "", "git reference or path to compare against (required)")
 	cmd.Flags().StringP("format", "f", "", "Schema format (proto, openapi, avro, jsonschema, parquet, crd)")
+	cmd.Flags().String("api-id", "", "API ID whose release tags are checked by transitive compatibility modes (default: the path argument when it is an API ID)")
 	cmd.Flags().Bool("advisory", false, "Report breaking changes without failing (exit 0). Lets CI gate blocking vs advisory declaratively instead of shell '|| true'.")
+	addEngineFlag(cmd)
+	addOutputFlag(cmd)
 	return cmd
 }
 
@@ -30,6 +33,12 @@ func breakingAction(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	defer quietForReport(output)()
+
 	against, _ := cmd.Flags().GetString("against")
 	if against == "" {
 		return fmt.Errorf("--against is required\n\nUsage: apx breaking [path] --against <git-ref>\n\nExamples:\n  apx breaking --against HEAD^\n  apx breaking --against origin/main")
@@ -38,10 +47,17 @@ func breakingAction(cmd *cobra.Command, args []string) error {
 	// Try to resolve API ID (e.g. proto/payments/ledger/v1) to a path.
 	// Falls back to treating the argument as a filesystem path.
 	var apiFormat string
+	apiID, _ := cmd.Flags().GetString("api-id")
 	cfg, _ := config.Load("")
+	if apiID != "" && len(args) == 0 {
+		path = apiID
+	}
 	resolved, resolveErr := config.ResolveAPIPath(path, cfg)
 	if resolveErr == nil {
 		apiFormat = config.ResolveAPIFormat(path)
+		if apiID == "" {
+			apiID = path
+		}
 		path = resolved
 	}
 
@@ -54,8 +70,18 @@ func breakingAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path does not exist: %s", absPath)
 	}
 
-	resolver := validator.NewToolchainResolver()
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return err
+	}
 	v := validator.NewValidator(resolver)
+	configureBreaking(v, cfg, apiID, absPath)
+	if err := configureWaivers(v, apiID); err != nil {
+		return err
+	}
+	if err := applyEngine(cmd, v); err != nil {
+		return err
+	}
 
 	var format validator.SchemaFormat
 	if formatStr, _ := cmd.Flags().GetString("format"); formatStr != "" {
@@ -77,6 +103,9 @@ func breakingAction(cmd *cobra.Command, args []string) error {
 		if info, statErr := os.Stat(absPath); statErr == nil && info.IsDir() {
 			ui.Info("No schema files found in: %s", absPath)
 			ui.Info("Nothing to check. Add schema files or specify --format.")
+			if output != "" {
+				return writeReport(cmd, output, "apx breaking", "", nil)
+			}
 			return nil
 		}
 		return fmt.Errorf("could not detect schema format for: %s\nPlease specify format with --format flag", absPath)
@@ -85,13 +114,29 @@ func breakingAction(cmd *cobra.Command, args []string) error {
 	ui.Info("Checking %s for breaking changes against: %s", format, against)
 
 	advisory, _ := cmd.Flags().GetBool("advisory")
-	if err := v.Breaking(absPath, against, format); err != nil {
+	findings, err := v.BreakingFindings(absPath, against, format)
+	if err != nil {
+		ui.Error("Breaking-change check failed: %v", err)
+		return err
+	}
+	if output != "" {
+		reported := findings
 		if advisory {
-			ui.Warning("Breaking changes detected (advisory \u2014 not failing): %v", err)
+			reported = downgradeErrors(findings)
+		}
+		if err := writeReport(cmd, output, "apx breaking", absPath, reported); err != nil {
+			return err
+		}
+	} else {
+		printFindings(findings, advisory)
+	}
+	if n := countSeverity(findings, validator.SeverityError); n > 0 {
+		if advisory {
+			ui.Warning("Breaking changes detected (advisory \u2014 not failing): %d breaking change(s)", n)
 			return nil
 		}
-		ui.Error("Breaking changes detected: %v", err)
-		return err
+		ui.Error("Breaking changes detected: %d breaking change(s)", n)
+		return fmt.Errorf("breaking changes detected: %d breaking change(s) against %s", n, against)
 	}
 
 	ui.Success("\u2713 No breaking changes detected")
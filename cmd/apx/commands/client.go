@@ -11,6 +11,7 @@ import (
 	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/publisher"
 	"github.com/infobloxopen/apx/internal/ui"
+	"github.com/infobloxopen/apx/internal/validator"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -149,12 +150,18 @@ func clientVerifyAction(cmd *cobra.Command, args []string) error {
 		warnOnly = cfg.Release.VerifyClients.WarnOnly
 	}
 
+	tools, err := newClientToolResolver()
+	if err != nil {
+		return err
+	}
+
 	ui.Info("Verifying client build for %s ...", specPath)
 	report, err := client.Verify(cmd.Context(), client.VerifyOptions{
 		SpecPath:    specPath,
 		Generators:  generators,
 		PackageName: pkg,
 		Scope:       scope,
+		Tools:       tools,
 	})
 	if err != nil {
 		return fmt.Errorf("verifying client: %w", err)
@@ -257,15 +264,36 @@ func resolveClientContext(cmd *cobra.Command, args []string) (client.Generator,
 			generatorName, strings.Join(client.Names(), ", "))
 	}
 
+	tools, err := newClientToolResolver()
+	if err != nil {
+		return nil, client.GenerateContext{}, err
+	}
+
 	return gen, client.GenerateContext{
 		SpecPath:       specPath,
 		OutputDir:      output,
 		PackageName:    pkg,
 		Scope:          scope,
 		PackageVersion: version,
+		Tools:          tools,
 	}, nil
 }
 
+// newClientToolResolver returns the resolver client generators find their
+// pinned tools with: the offline bundle or the local tool cache, verified
+// against apx.lock. It never downloads; a generator fetches a tool missing
+// there with go run or npx.
+func newClientToolResolver() (*validator.ToolchainResolver, error) {
+	lock, err := loadLockFile("apx.lock")
+	if err != nil {
+		return nil, err
+	}
+	return validator.NewToolchainResolver(
+		validator.WithLock(lock.Toolchains),
+		validator.WithOfflineMode(true),
+	), nil
+}
+
 func clientGenerateAction(cmd *cobra.Command, args []string) error {
 	doBuild, _ := cmd.Flags().GetBool("build")
 	doClean, _ := cmd.Flags().GetBool("clean")
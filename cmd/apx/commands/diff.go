@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/publisher"
+	"github.com/infobloxopen/apx/internal/ui"
+	"github.com/infobloxopen/apx/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <api-id|path> <from> <to>",
+		Short: "Show a semantic changelog between two versions of an API",
+		Long: `Diff lists what changed between two versions of an API: the messages,
+fields, RPCs, operations, columns and CRD properties that were added,
+removed or changed, each marked breaking or non-breaking.
+
+<from> and <to> are released versions of the API (resolved to their
+release tags, as listed by "apx release history"), git refs, or paths.
+Breaking changes are judged by the same checks as "apx breaking".
+
+Examples:
+  apx diff proto/payments/ledger/v1 v1.2.0 v1.3.0
+  apx diff proto/payments/ledger/v1 v1.3.0 HEAD --format markdown
+  apx diff avro/events/orders/v1 main feature/new-fields --format json
+  apx diff schemas/user.avsc old/user.avsc schemas/user.avsc`,
+		Args: cobra.ExactArgs(3),
+		RunE: diffAction,
+	}
+	cmd.Flags().String("format", "text", "Output format: text, json, markdown")
+	cmd.Flags().String("schema-format", "", "Schema format (proto, openapi, avro, jsonschema, parquet, crd); default: from the API ID or detected")
+	addEngineFlag(cmd)
+	return cmd
+}
+
+// diffReport is the JSON form of apx diff.
+type diffReport struct {
+	APIID   string `json:"api_id,omitempty"`
+	Path    string `json:"path"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	FromRef string `json:"from_ref"`
+	ToRef   string `json:"to_ref"`
+	*validator.Changelog
+	BreakingCount    int `json:"breaking"`
+	NonBreakingCount int `json:"non_breaking"`
+}
+
+func diffAction(cmd *cobra.Command, args []string) error {
+	target, from, to := args[0], args[1], args[2]
+	outFormat, _ := cmd.Flags().GetString("format")
+	if jsonOut, _ := cmd.Root().PersistentFlags().GetBool("json"); jsonOut && !cmd.Flags().Changed("format") {
+		outFormat = "json"
+	}
+	switch outFormat {
+	case "text", "json", "markdown":
+	default:
+		return fmt.Errorf("invalid --format %q: expected text, json, or markdown", outFormat)
+	}
+
+	cfg, _ := loadConfig(cmd)
+
+	// An API ID resolves to its source directory; the directory need not
+	// exist in the working tree, since both sides may be read from refs.
+	apiID := ""
+	path := target
+	if _, err := config.ParseAPIID(target); err == nil {
+		apiID = target
+		if resolved, err := config.ResolveAPIPath(target, cfg); err == nil {
+			path = resolved
+		} else if cfg != nil && len(cfg.ModuleRoots) > 0 {
+			path = filepath.Join(cfg.ModuleRoots[0], config.DeriveSourcePath(apiID))
+		}
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	fromRef, err := diffRevision(apiID, from)
+	if err != nil {
+		return err
+	}
+	toRef, err := diffRevision(apiID, to)
+	if err != nil {
+		return err
+	}
+
+	schemaFormat, _ := cmd.Flags().GetString("schema-format")
+	format := validator.SchemaFormat(schemaFormat)
+	if format == "" && apiID != "" {
+		format = validator.SchemaFormat(config.ResolveAPIFormat(apiID))
+	}
+	if format == "" {
+		format = validator.DetectFormat(absPath)
+	}
+	if format == validator.FormatUnknown && cfg != nil {
+		format = validator.DetectFormatFromModuleRoots(cfg.ModuleRoots)
+	}
+	if format == "" || format == validator.FormatUnknown {
+		return fmt.Errorf("could not detect schema format for: %s\nPlease specify format with --schema-format", absPath)
+	}
+
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return err
+	}
+	v := validator.NewValidator(resolver)
+	configureBreaking(v, cfg, apiID, absPath)
+	if err := applyEngine(cmd, v); err != nil {
+		return err
+	}
+	log, err := v.Changelog(absPath, fromRef, toRef, format)
+	if err != nil {
+		return fmt.Errorf("comparing %s with %s: %w", from, to, err)
+	}
+
+	report := diffReport{
+		APIID: apiID, Path: path, From: from, To: to, FromRef: fromRef, ToRef: toRef,
+		Changelog:     log,
+		BreakingCount: log.Breaking(),
+	}
+	report.NonBreakingCount = len(log.Entries) + len(log.Unmatched) - report.BreakingCount
+	if report.Entries == nil {
+		report.Entries = []validator.ChangelogEntry{}
+	}
+
+	switch outFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	case "markdown":
+		writeDiffMarkdown(cmd.OutOrStdout(), report)
+	default:
+		printDiffText(report)
+	}
+	return nil
+}
+
+// diffRevision resolves one side of apx diff. For an API ID, a version
+// (v1.2.0 or 1.2.0) is one of its releases and resolves to the release tag;
+// anything else is passed through as a git ref or path.
+func diffRevision(apiID, rev string) (string, error) {
+	if apiID == "" {
+		return rev, nil
+	}
+	if _, err := config.ParseSemVer(rev); err != nil {
+		return rev, nil
+	}
+	if _, err := os.Stat(rev); err == nil {
+		return rev, nil
+	}
+	repoPath, _ := os.Getwd()
+	versions, err := publisher.NewTagManager(repoPath, "").ListVersionsForAPI(apiID)
+	if err != nil {
+		return "", fmt.Errorf("listing versions: %w", err)
+	}
+	want := strings.TrimPrefix(rev, "v")
+	for _, v := range versions {
+		if strings.TrimPrefix(v, "v") == want {
+			return config.DeriveTag(apiID, v), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no release %s (see apx release history %s)", apiID, rev, apiID)
+}
+
+// diffTitle names the compared API and versions.
+func diffTitle(r diffReport) string {
+	name := r.APIID
+	if name == "" {
+		name = r.Path
+	}
+	return fmt.Sprintf("%s %s → %s", name, r.From, r.To)
+}
+
+// diffCategoryTitle returns the heading for a changelog category.
+func diffCategoryTitle(category string) string {
+	switch category {
+	case "rpc":
+		return "RPCs"
+	case "property":
+		return "Properties"
+	case "request body":
+		return "Request bodies"
+	case "":
+		return "Other"
+	}
+	return strings.ToUpper(category[:1]) + category[1:] + "s"
+}
+
+// diffGroups splits entries, already ordered by category, into runs of one
+// category.
+func diffGroups(entries []validator.ChangelogEntry) [][]validator.ChangelogEntry {
+	var groups [][]validator.ChangelogEntry
+	for i, e := range entries {
+		if i == 0 || e.Category != entries[i-1].Category {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], e)
+	}
+	return groups
+}
+
+func printDiffText(r diffReport) {
+	ui.Info("%s: %s (%d breaking, %d non-breaking)", diffTitle(r), r.Level, r.BreakingCount, r.NonBreakingCount)
+	if len(r.Entries) == 0 && len(r.Unmatched) == 0 {
+		ui.Info("No schema changes")
+		return
+	}
+	multiFile := diffMultiFile(r.Entries)
+	for _, group := range diffGroups(r.Entries) {
+		ui.Info("")
+		ui.Info("%s:", diffCategoryTitle(group[0].Category))
+		for _, e := range group {
+			if multiFile && e.Category != "file" {
+				e.Element = e.File + ": " + e.Element
+			}
+			if e.Breaking {
+				ui.Warning("  BREAKING %s [%s]", e, strings.Join(e.Rules, ", "))
+			} else {
+				ui.Info("  %s", e)
+			}
+		}
+	}
+	if len(r.Unmatched) > 0 {
+		ui.Info("")
+		ui.Info("Other breaking changes:")
+		for _, f := range r.Unmatched {
+			ui.Warning("  BREAKING %s [%s]", f.Message, f.RuleID)
+		}
+	}
+}
+
+// diffMultiFile reports whether entries span more than one file, in which
+// case elements are qualified by their file.
+func diffMultiFile(entries []validator.ChangelogEntry) bool {
+	for _, e := range entries {
+		if e.File != entries[0].File {
+			return true
+		}
+	}
+	return false
+}
+
+func writeDiffMarkdown(w io.Writer, r diffReport) {
+	fmt.Fprintf(w, "## %s\n\n", diffTitle(r))
+	fmt.Fprintf(w, "**Change level:** %s (%d breaking, %d non-breaking)\n", r.Level, r.BreakingCount, r.NonBreakingCount)
+	if len(r.Entries) == 0 && len(r.Unmatched) == 0 {
+		fmt.Fprintf(w, "\nNo schema changes.\n")
+		return
+	}
+	cell := func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+	multiFile := diffMultiFile(r.Entries)
+	for _, group := range diffGroups(r.Entries) {
+		fmt.Fprintf(w, "\n### %s\n\n", diffCategoryTitle(group[0].Category))
+		fmt.Fprintf(w, "| Change | Element | Details | Breaking |\n")
+		fmt.Fprintf(w, "|--------|---------|---------|----------|\n")
+		for _, e := range group {
+			element := e.Element
+			if element == "" {
+				element = "(root)"
+			}
+			if multiFile && e.Category != "file" {
+				element = e.File + ": " + element
+			}
+			details := e.Attribute
+			values := ""
+			switch {
+			case e.Old != "" && e.New != "":
+				values = fmt.Sprintf("`%s` → `%s`", e.Old, e.New)
+			case e.Old != "":
+				values = fmt.Sprintf("`%s`", e.Old)
+			case e.New != "":
+				values = fmt.Sprintf("`%s`", e.New)
+			}
+			if details != "" && values != "" {
+				details += ": "
+			}
+			details += values
+			breaking := "no"
+			if e.Breaking {
+				breaking = "**yes**"
+				if len(e.Rules) > 0 {
+					breaking += " (" + strings.Join(e.Rules, ", ") + ")"
+				}
+			}
+			fmt.Fprintf(w, "| %s | `%s` | %s | %s |\n", e.Kind, cell(element), cell(details), breaking)
+		}
+	}
+	if len(r.Unmatched) > 0 {
+		fmt.Fprintf(w, "\n### Other breaking changes\n\n")
+		for _, f := range r.Unmatched {
+			fmt.Fprintf(w, "- %s (%s)\n", f.Message, f.RuleID)
+		}
+	}
+}
@@ -1,75 +1,161 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
+	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/validator"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func newFetchCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:    "fetch",
-		Short:  "Download and cache toolchain dependencies for offline use",
-		Hidden: true,
-		RunE:   fetchAction,
+		Use:   "fetch",
+		Short: "Download and cache toolchain dependencies for offline use",
+		Long: `Download the external tools APX runs (buf, oasdiff, ...) into the local
+tool cache. Versions come from the tools section of apx.yaml, else from the
+toolchains section of apx.lock, else from APX's defaults. A tool pinned in
+apx.lock must match its pinned checksum.
+
+--update-lock pins the fetched versions and their sha256 checksums in the
+toolchains section of apx.lock, refreshing entries that apx.yaml has moved
+to another version.
+
+--bundle writes the tools, for each --platform, to a self-describing .tar.gz
+for air-gapped machines. Its manifest lists each tool's name, version,
+platform and sha256. Point APX_TOOLS_BUNDLE at the bundle there, and apx
+--offline extracts and verifies the tools it needs from it.`,
+		RunE: fetchAction,
 	}
 	cmd.Flags().StringP("config", "c", "apx.yaml", "Path to configuration file")
 	cmd.Flags().String("output", ".apx-tools", "Output directory for toolchain bundles")
+	_ = cmd.Flags().MarkDeprecated("output", "tools are cached in ~/.apx/tools; use --bundle for a portable copy")
 	cmd.Flags().Bool("verify", true, "Verify checksums of downloaded tools")
+	cmd.Flags().Bool("update-lock", false, "Pin the fetched tool versions and checksums in apx.lock")
+	cmd.Flags().String("bundle", "", "Write an offline toolchain bundle (.tar.gz) to this path")
+	cmd.Flags().StringSlice("platform", []string{validator.HostPlatform()}, "Platforms (os/arch) to bundle tools for")
 	return cmd
 }
 
 func fetchAction(cmd *cobra.Command, args []string) error {
 	configPath, _ := cmd.Flags().GetString("config")
-	outputDir, _ := cmd.Flags().GetString("output")
 	verify, _ := cmd.Flags().GetBool("verify")
+	updateLock, _ := cmd.Flags().GetBool("update-lock")
+	bundleOut, _ := cmd.Flags().GetString("bundle")
+	platforms, _ := cmd.Flags().GetStringSlice("platform")
 
 	fmt.Printf("Fetching toolchain dependencies...\n")
 	fmt.Printf("Config: %s\n", configPath)
-	fmt.Printf("Output: %s\n", outputDir)
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	const lockPath = "apx.lock"
+	lock, err := loadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	if lock.Toolchains == nil {
+		lock.Toolchains = map[string]config.ToolchainLock{}
 	}
 
-	resolver := validator.NewToolchainResolver()
+	// Refreshing the lock replaces its checksums, so the old ones are not
+	// checked.
+	opts := []validator.ToolchainResolverOption{
+		validator.WithLock(lock.Toolchains),
+		validator.WithChecksumValidation(verify && !updateLock),
+		validator.WithOfflineMode(offlineMode),
+	}
+	if cfg, err := config.Load(configPath); err == nil {
+		opts = append(opts, validator.WithToolVersions(toolVersions(cfg)))
+	}
+	resolver := validator.NewToolchainResolver(opts...)
 
-	lockPath := "apx.lock"
-	if _, err := os.Stat(lockPath); err == nil {
-		fmt.Printf("Loading toolchain profile from %s...\n", lockPath)
+	host := validator.HostPlatform()
+	pinned := map[string]config.ToolchainLock{}
+	verified := 0
+	for _, tool := range resolver.Tools() {
+		version := resolver.ToolVersion(tool)
+		fmt.Printf("Fetching %s@%s...\n", tool, version)
 
-		profile, err := validator.LoadToolchainProfile(lockPath)
+		path, err := resolver.FetchTool(tool, version)
+		if errors.Is(err, validator.ErrToolNotFound) {
+			fmt.Printf("Warning: failed to resolve %s: %v\n", tool, err)
+			pinned[tool] = config.ToolchainLock{Version: version}
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("failed to load toolchain profile: %w", err)
+			return err
 		}
 
-		for tool, toolRef := range profile.Tools {
-			fmt.Printf("Fetching %s@%s...\n", tool, toolRef.Version)
+		checksum, err := validator.ComputeChecksum(path)
+		if err != nil {
+			return err
+		}
+		pinned[tool] = config.ToolchainLock{Version: version, Checksums: map[string]string{host: "sha256:" + checksum}}
+		if verify && !updateLock && lock.Toolchains[tool].Checksums[host] != "" {
+			verified++
+		}
+		fmt.Printf("✓ Cached %s at %s\n", tool, path)
+	}
 
-			_, err := resolver.ResolveTool(tool, toolRef.Version)
-			if err != nil {
-				fmt.Printf("Warning: failed to resolve %s: %v\n", tool, err)
-				continue
-			}
+	if verified > 0 {
+		fmt.Printf("✓ Verified %d tool(s) against %s\n", verified, lockPath)
+	}
 
-			bundlePath := filepath.Join(outputDir, tool, toolRef.Version)
-			if err := os.MkdirAll(bundlePath, 0755); err != nil {
-				return fmt.Errorf("failed to create bundle directory: %w", err)
+	if updateLock {
+		for tool, pin := range pinned {
+			// Checksums of the same version for other platforms are kept,
+			// and so is this platform's when the tool could not be fetched.
+			if old, ok := lock.Toolchains[tool]; ok && old.Version == pin.Version {
+				for platform, sum := range old.Checksums {
+					if _, ok := pin.Checksums[platform]; !ok {
+						if pin.Checksums == nil {
+							pin.Checksums = map[string]string{}
+						}
+						pin.Checksums[platform] = sum
+					}
+				}
 			}
-
-			fmt.Printf("\u2713 Cached %s at %s\n", tool, bundlePath)
+			lock.Toolchains[tool] = pin
+		}
+		if err := writeLockFile(lockPath, lock); err != nil {
+			return err
 		}
-	} else {
-		fmt.Printf("No apx.lock found, skipping toolchain fetch\n")
+		fmt.Printf("✓ Pinned %d tool(s) in %s\n", len(pinned), lockPath)
 	}
 
-	if verify {
-		fmt.Printf("Verifying checksums...\n")
+	if bundleOut != "" {
+		fmt.Printf("Bundling toolchain for %s...\n", strings.Join(platforms, ", "))
+		manifest, skipped, err := resolver.BuildBundle(bundleOut, platforms)
+		if err != nil {
+			return err
+		}
+		for _, err := range skipped {
+			fmt.Printf("Warning: not bundled: %v\n", err)
+		}
+		for _, t := range manifest.Tools {
+			fmt.Printf("  %-16s %-10s %-14s sha256:%s\n", t.Name, t.Version, t.Platform, t.SHA256)
+		}
+		fmt.Printf("✓ Bundled %d tool build(s) into %s\n", len(manifest.Tools), bundleOut)
 	}
 
-	fmt.Printf("\u2713 Toolchain fetch complete\n")
+	fmt.Printf("✓ Toolchain fetch complete\n")
+	return nil
+}
+
+// writeLockFile writes an apx.lock, keeping its dependencies.
+func writeLockFile(path string, lock *config.LockFile) error {
+	if lock.Version == 0 {
+		lock.Version = 1
+	}
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
 	return nil
 }
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/codegen"
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/publisher"
+	"github.com/infobloxopen/apx/internal/report"
+	"github.com/infobloxopen/apx/internal/ui"
+	"github.com/infobloxopen/apx/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+// printFindings reports validator findings through the ui package: errors on
+// stderr, warnings and informational findings on stdout. When advisory is
+// set, error findings are downgraded to warnings (apx breaking --advisory).
+func printFindings(findings []validator.Finding, advisory bool) {
+	for _, f := range findings {
+		line := f.String()
+		if f.RuleID != "" {
+			line += " [" + f.RuleID + "]"
+		}
+		switch {
+		case f.Severity == validator.SeverityError && !advisory:
+			ui.Error("%s", line)
+		case f.Severity == validator.SeverityError, f.Severity == validator.SeverityWarning:
+			ui.Warning("%s", line)
+		default:
+			ui.Info("%s", line)
+		}
+	}
+}
+
+// countSeverity returns the number of findings with the given severity.
+func countSeverity(findings []validator.Finding, sev validator.Severity) int {
+	return len(validator.FilterSeverity(findings, sev))
+}
+
+// downgradeErrors returns a copy of findings with error severity lowered to
+// warning, for reports produced in advisory mode.
+func downgradeErrors(findings []validator.Finding) []validator.Finding {
+	out := make([]validator.Finding, len(findings))
+	for i, f := range findings {
+		if f.Severity == validator.SeverityError {
+			f.Severity = validator.SeverityWarning
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// addOutputFlag registers --output on a command that produces findings.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "", "Machine-readable report on stdout: sarif, junit, or json (default: human-readable)")
+}
+
+// addEngineFlag registers --engine on a command that lints or compares
+// OpenAPI specs.
+func addEngineFlag(cmd *cobra.Command) {
+	cmd.Flags().String("engine", validator.OpenAPIEngineAuto, "OpenAPI engine: auto (spectral/oasdiff when installed, else built-in), native, or external")
+}
+
+// applyEngine validates --engine and configures v with it.
+func applyEngine(cmd *cobra.Command, v *validator.Validator) error {
+	engine, _ := cmd.Flags().GetString("engine")
+	switch engine {
+	case validator.OpenAPIEngineAuto, validator.OpenAPIEngineNative, validator.OpenAPIEngineExternal:
+	default:
+		return fmt.Errorf("invalid --engine %q: expected auto, native, or external", engine)
+	}
+	v.SetOpenAPIEngine(engine)
+	return nil
+}
+
+// outputFormat returns the --output format of cmd. The global --json flag
+// is honored as --output json.
+func outputFormat(cmd *cobra.Command) (report.Format, error) {
+	out, _ := cmd.Flags().GetString("output")
+	format, err := report.ParseFormat(out)
+	if err != nil {
+		return "", err
+	}
+	if format == "" {
+		if jsonOut, _ := cmd.Root().PersistentFlags().GetBool("json"); jsonOut {
+			format = report.FormatJSON
+		}
+	}
+	return format, nil
+}
+
+// quietForReport silences informational ui output while a structured report
+// is being produced, so stdout carries only the report. Errors still reach
+// stderr. The returned func restores the previous setting.
+func quietForReport(format report.Format) func() {
+	if format == "" {
+		return func() {}
+	}
+	prev := ui.IsQuiet()
+	ui.SetQuiet(true)
+	return func() { ui.SetQuiet(prev) }
+}
+
+// writeReport renders findings to cmd's stdout in the given format. File
+// locations are made relative to the working directory so SARIF URIs match
+// the repository checkout; defaultFile locates findings without a file.
+func writeReport(cmd *cobra.Command, format report.Format, tool, defaultFile string, findings []validator.Finding) error {
+	wd, _ := os.Getwd()
+	return report.Write(cmd.OutOrStdout(), format, report.Report{
+		Tool:        tool,
+		BaseDir:     wd,
+		DefaultFile: defaultFile,
+		Findings:    findings,
+	})
+}
+
+// configureBreaking applies the apx.yaml policy settings that change how
+// breaking changes are judged: the Avro compatibility mode, the JSON Schema
+// strict/lenient mode, whether Parquet columns may only be added as nullable
+// and, when the API ID is known, the release history the Avro *_TRANSITIVE
+// modes check against.
+func configureBreaking(v *validator.Validator, cfg *config.Config, apiID, absPath string) {
+	if cfg == nil {
+		return
+	}
+	if mode := cfg.Policy.Avro.Compatibility; mode != "" {
+		v.SetAvroCompatibilityMode(mode)
+	}
+	if mode := cfg.Policy.JSONSchema.BreakingMode; mode != "" {
+		v.SetJSONSchemaBreakingMode(mode)
+	}
+	if cfg.Policy.Parquet.AllowAdditiveNullableOnly != nil {
+		v.SetParquetAdditiveNullableOnly(*cfg.Policy.Parquet.AllowAdditiveNullableOnly)
+	}
+	if apiID == "" {
+		return
+	}
+	dir := absPath
+	if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+		dir = filepath.Dir(absPath)
+	}
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return
+	}
+	root := strings.TrimSpace(string(out))
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return
+	}
+	v.SetAvroHistory(publisher.NewReleaseHistory(root, apiID, filepath.ToSlash(rel)))
+}
+
+// newExecutor returns the executor for the execution settings of apx.yaml:
+// the host, or a container image.
+func newExecutor(cfg *config.Config) (validator.Executor, error) {
+	var execution config.Execution
+	if cfg != nil {
+		execution = cfg.Execution
+	}
+	return validator.NewExecutor(execution)
+}
+
+// newToolchainResolver returns a resolver that runs the tool versions of
+// apx.yaml, verifies them against the checksums pinned in apx.lock, and runs
+// them where the execution settings of apx.yaml say. With --offline it never
+// downloads tools.
+func newToolchainResolver(cfg *config.Config) (*validator.ToolchainResolver, error) {
+	executor, err := newExecutor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := loadLockFile("apx.lock")
+	if err != nil {
+		return nil, err
+	}
+	opts := []validator.ToolchainResolverOption{
+		validator.WithExecutor(executor),
+		validator.WithLock(lock.Toolchains),
+		validator.WithOfflineMode(offlineMode),
+	}
+	if cfg != nil {
+		opts = append(opts, validator.WithToolVersions(toolVersions(cfg)))
+	}
+	return validator.NewToolchainResolver(opts...), nil
+}
+
+// toolVersions returns the tool versions apx.yaml sets, together with the
+// protoc plugins of its enabled language_targets, which are fetched and
+// pinned in apx.lock like any other tool.
+func toolVersions(cfg *config.Config) map[string]string {
+	versions := cfg.Tools.Versions()
+	langs := make([]string, 0, len(cfg.LanguageTargets))
+	for lang := range cfg.LanguageTargets {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		target := cfg.LanguageTargets[lang]
+		if !target.Enabled {
+			continue
+		}
+		// Malformed plugin entries are reported by apx gen.
+		plugins, _ := codegen.ParsePlugins(lang, target)
+		for _, p := range plugins {
+			if _, ok := versions[p.Name]; !ok {
+				versions[p.Name] = p.Version
+			}
+		}
+	}
+	return versions
+}
+
+// configureLint applies the lint rule settings of apx.yaml to v.
+func configureLint(v *validator.Validator, cfg *config.Config) error {
+	if cfg == nil {
+		return nil
+	}
+	for format, rules := range map[validator.SchemaFormat]config.LintFormat{
+		validator.FormatAvro:       cfg.Lint.Avro,
+		validator.FormatParquet:    cfg.Lint.Parquet,
+		validator.FormatJSONSchema: cfg.Lint.JSONSchema,
+		validator.FormatCRD:        cfg.Lint.CRD,
+	} {
+		if err := v.SetLintRules(format, rules.Rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureWaivers makes v honor the breaking-change waivers of apiID in
+// apx-waivers.yaml, together with any already recorded for the release
+// (extra). Without an API ID no waiver applies.
+func configureWaivers(v *validator.Validator, apiID string, extra ...config.Waiver) error {
+	if apiID == "" {
+		return nil
+	}
+	waivers, err := config.LoadWaivers(config.WaiversFile)
+	if err != nil {
+		return err
+	}
+	for _, w := range extra {
+		if !containsWaiver(waivers, w) {
+			waivers = append(waivers, w)
+		}
+	}
+	v.SetWaivers(waivers, config.WaiversFile, apiID)
+	return nil
+}
+
+func containsWaiver(waivers []config.Waiver, w config.Waiver) bool {
+	for _, x := range waivers {
+		if x == w {
+			return true
+		}
+	}
+	return false
+}
+
+// printWaived lists the waivers that allowed a breaking change.
+func printWaived(waivers []config.Waiver) {
+	for _, w := range waivers {
+		ui.Warning("Breaking change %s at %s waived until %s by %s: %s", w.Rule, w.Path, w.Expires, w.Owner, w.Reason)
+	}
+}
+
+// expiredWaivers returns the findings that report an expired waiver.
+func expiredWaivers(findings []validator.Finding) []validator.Finding {
+	var expired []validator.Finding
+	for _, f := range findings {
+		if f.RuleID == validator.RuleWaiverExpired {
+			expired = append(expired, f)
+		}
+	}
+	return expired
+}
@@ -1,23 +1,36 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/infobloxopen/apx/internal/codegen"
 	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/language"
 	"github.com/infobloxopen/apx/internal/overlay"
 	"github.com/infobloxopen/apx/internal/ui"
+	"github.com/infobloxopen/apx/internal/validator"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // GenerateOptions holds options for code generation
 type GenerateOptions struct {
-	Language  string
-	Path      string
-	OutputDir string
-	Clean     bool
-	Manifest  bool
+	Language   string
+	Path       string
+	OutputDir  string
+	Clean      bool
+	Manifest   bool
+	Check      bool
+	Prune      bool
+	Jobs       int    // dependencies generated concurrently
+	APXVersion string // recorded in generation manifests
 }
 
 func newGenCmd() *cobra.Command {
@@ -29,9 +42,12 @@ func newGenCmd() *cobra.Command {
 		Args: cobra.RangeArgs(1, 2),
 		RunE: genAction,
 	}
-	cmd.Flags().String("out", "", "output directory")
-	cmd.Flags().Bool("clean", false, "clean output directory before generation")
-	cmd.Flags().Bool("manifest", false, "emit generation manifest")
+	cmd.Flags().String("out", "", "overlay root directory (default internal/gen)")
+	cmd.Flags().Bool("clean", false, "remove each overlay before regenerating it")
+	cmd.Flags().Bool("manifest", false, "print the generation manifest of each overlay")
+	cmd.Flags().Bool("check", false, "exit non-zero if any overlay is stale, without generating")
+	cmd.Flags().Bool("prune", false, "remove overlays whose module or version is not locked in apx.lock")
+	cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of dependencies to generate concurrently")
 	return cmd
 }
 
@@ -45,82 +61,539 @@ func genAction(cmd *cobra.Command, args []string) error {
 	outDir, _ := cmd.Flags().GetString("out")
 	clean, _ := cmd.Flags().GetBool("clean")
 	manifest, _ := cmd.Flags().GetBool("manifest")
+	check, _ := cmd.Flags().GetBool("check")
+	prune, _ := cmd.Flags().GetBool("prune")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	if check && (clean || prune) {
+		return fmt.Errorf("--check cannot be combined with --clean or --prune")
+	}
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", jobs)
+	}
 
 	opts := GenerateOptions{
-		Language:  lang,
-		Path:      path,
-		OutputDir: outDir,
-		Clean:     clean,
-		Manifest:  manifest,
+		Language:   lang,
+		Path:       path,
+		OutputDir:  outDir,
+		Clean:      clean,
+		Manifest:   manifest,
+		Check:      check,
+		Prune:      prune,
+		Jobs:       jobs,
+		APXVersion: cmd.Root().Version,
 	}
 
 	return generateCode(opts)
 }
 
 func generateCode(opts GenerateOptions) error {
-	ui.Info("Generating %s code from dependencies...", opts.Language)
+	if opts.Check {
+		ui.Info("Checking %s overlays against their generation manifests...", opts.Language)
+	} else {
+		ui.Info("Generating %s code from dependencies...", opts.Language)
+	}
 
 	// Look up the plugin for scaffolding / post-gen hooks.
 	plugin := language.Get(opts.Language)
 
-	dm := config.NewDependencyManager("apx.yaml", "apx.lock", "")
-	deps, err := dm.List()
+	lock, err := loadLockFile("apx.lock")
 	if err != nil {
 		return fmt.Errorf("failed to list dependencies: %w", err)
 	}
 
-	if len(deps) == 0 {
+	if len(lock.Dependencies) == 0 && !opts.Prune {
 		ui.Info("No dependencies found in apx.lock")
 		return nil
 	}
 
 	cfg, _ := config.LoadRaw("")
 
+	var protoPlugins []codegen.Plugin
+	if cfg != nil {
+		if target, ok := cfg.LanguageTargets[opts.Language]; ok && target.Enabled {
+			if protoPlugins, err = codegen.ParsePlugins(opts.Language, target); err != nil {
+				return err
+			}
+		}
+	}
+	var tools *validator.ToolchainResolver
+	if len(protoPlugins) > 0 && !opts.Check {
+		if tools, err = newToolchainResolver(cfg); err != nil {
+			return err
+		}
+	}
+
 	mgr := overlay.NewManager(".")
-	for _, dep := range deps {
-		ui.Info("Creating overlay for %s...", dep.ModulePath)
-		ov, err := mgr.Create(dep.ModulePath, opts.Language)
+	if opts.OutputDir != "" {
+		root, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to create overlay: %w", err)
+			return err
 		}
+		out, err := filepath.Abs(opts.OutputDir)
+		if err != nil {
+			return fmt.Errorf("resolving --out %q: %w", opts.OutputDir, err)
+		}
+		mgr = overlay.NewManagerAt(root, out)
+	}
 
-		// Run Scaffolder if the plugin implements it.
-		if scaffolder, ok := plugin.(language.Scaffolder); ok {
-			api, parseErr := config.ParseAPIID(dep.ModulePath)
-			if parseErr != nil {
-				return fmt.Errorf("parsing API ID %s: %w", dep.ModulePath, parseErr)
-			}
-			org := ""
-			importRoot := ""
-			if cfg != nil {
-				org = cfg.Org
-				importRoot = cfg.ImportRoot
-			}
-			ctx := language.DerivationContext{
-				SourceRepo: resolveSourceRepoFromConfig(cfg),
-				ImportRoot: importRoot,
-				Org:        org,
-				API:        api,
-			}
-			if ctx.Org != "" {
-				if err := scaffolder.Scaffold(ov.Path, ctx); err != nil {
-					return fmt.Errorf("scaffolding %s for %s: %w", opts.Language, dep.ModulePath, err)
+	if opts.Prune {
+		if err := pruneOverlays(mgr, cfg, opts.Language, false); err != nil {
+			return err
+		}
+	}
+
+	apiIDs := make([]string, 0, len(lock.Dependencies))
+	for apiID := range lock.Dependencies {
+		apiIDs = append(apiIDs, apiID)
+	}
+	sort.Strings(apiIDs)
+
+	org := ""
+	importRoot := ""
+	if cfg != nil {
+		org = cfg.Org
+		importRoot = cfg.ImportRoot
+	}
+
+	depContext := func(apiID string) (language.DerivationContext, error) {
+		api, err := config.ParseAPIID(apiID)
+		if err != nil {
+			return language.DerivationContext{}, fmt.Errorf("parsing API ID: %w", err)
+		}
+		return language.DerivationContext{
+			SourceRepo: dependencySourceRepo(lock.Dependencies[apiID], cfg),
+			ImportRoot: importRoot,
+			Org:        org,
+			API:        api,
+		}, nil
+	}
+	resolveImports := func(apiID, schemaDir string) ([]codegen.ProtoImport, func() error, error) {
+		return lockedImports(apiID, schemaDir, lock.Dependencies, depContext, plugin)
+	}
+
+	// Dependencies are generated by a bounded pool of workers. Each writes
+	// only its own overlay; go.work and the post-generation hooks, which
+	// span overlays, run once all workers are done.
+	results := make([]genResult, len(apiIDs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(min(opts.Jobs, len(apiIDs)), 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				apiID := apiIDs[i]
+				ctx, err := depContext(apiID)
+				if err != nil {
+					results[i] = genResult{apiID: apiID, status: genFailed, err: err}
+					continue
 				}
+				results[i] = generateOverlay(opts, mgr, plugin, ctx, lock.Dependencies[apiID], protoPlugins, tools, resolveImports)
 			}
+		}()
+	}
+	for i := range apiIDs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	printGenSummary(results)
+
+	var stale, failed []string
+	for _, r := range results {
+		switch r.status {
+		case genStale:
+			stale = append(stale, r.apiID)
+		case genFailed:
+			failed = append(failed, fmt.Sprintf("  %s: %v", r.apiID, r.err))
 		}
 	}
 
-	// Run PostGenHook if the plugin implements it.
-	if hook, ok := plugin.(language.PostGenHook); ok {
+	if opts.Check {
+		if len(failed) > 0 {
+			return fmt.Errorf("checking %d of %d dependencies failed:\n%s", len(failed), len(results), strings.Join(failed, "\n"))
+		}
+		if len(stale) > 0 {
+			return fmt.Errorf("%d %s overlay(s) are stale: %s; run 'apx gen %s' to regenerate",
+				len(stale), opts.Language, strings.Join(stale, ", "), opts.Language)
+		}
+		ui.Success("All %s overlays are up to date", opts.Language)
+		return nil
+	}
+
+	// Run PostGenHook if the plugin implements it. With --out, go.work is
+	// synced against the custom overlay root.
+	if opts.OutputDir != "" {
+		if opts.Language == "go" {
+			if err := mgr.Sync(); err != nil {
+				return fmt.Errorf("post-generation hook for %s: %w", opts.Language, err)
+			}
+		}
+	} else if hook, ok := plugin.(language.PostGenHook); ok {
 		if err := hook.PostGen("."); err != nil {
 			return fmt.Errorf("post-generation hook for %s: %w", opts.Language, err)
 		}
 	}
+	if opts.Language == "go" {
+		if err := warnWorkConflicts(mgr); err != nil {
+			return err
+		}
+	}
 
+	if len(failed) > 0 {
+		return fmt.Errorf("generation failed for %d of %d dependencies:\n%s", len(failed), len(results), strings.Join(failed, "\n"))
+	}
 	ui.Success("Code generation completed successfully")
 	return nil
 }
 
+// genStatus is the outcome of bringing one dependency's overlay up to date.
+type genStatus string
+
+const (
+	genGenerated  genStatus = "generated"
+	genUpToDate   genStatus = "up to date" // the generation manifest matched
+	genScaffolded genStatus = "scaffolded" // nothing to generate for the language
+	genSkipped    genStatus = "skipped"    // coordinates or source unavailable
+	genStale      genStatus = "stale"      // --check only
+	genFailed     genStatus = "failed"
+)
+
+// genResult is the outcome for one dependency.
+type genResult struct {
+	apiID    string
+	status   genStatus
+	detail   string
+	err      error  // set when status is genFailed
+	manifest []byte // the manifest printed for --manifest
+}
+
+// printGenSummary prints a table of the outcome for each dependency, then
+// the manifests --manifest asked for, in dependency order.
+func printGenSummary(results []genResult) {
+	counts := map[genStatus]int{}
+	table := ui.NewTable("API", "STATUS", "DETAIL")
+	for _, r := range results {
+		counts[r.status]++
+		detail := r.detail
+		if r.err != nil {
+			detail, _, _ = strings.Cut(r.err.Error(), "\n")
+		}
+		table.AddRow(r.apiID, string(r.status), detail)
+	}
+	if !ui.IsQuiet() {
+		fmt.Println()
+		table.Print()
+	}
+
+	var parts []string
+	for _, status := range []genStatus{genGenerated, genUpToDate, genScaffolded, genSkipped, genStale, genFailed} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	ui.Info("%d dependencies: %s", len(results), strings.Join(parts, ", "))
+
+	for _, r := range results {
+		if r.manifest != nil {
+			fmt.Print(string(r.manifest))
+		}
+	}
+}
+
+// generateOverlay brings the overlay of one dependency up to date. An
+// overlay whose generation manifest matches its current inputs, and whose
+// outputs are untouched, is left alone; otherwise the outputs it recorded
+// are removed and the overlay is scaffolded and generated afresh. With
+// opts.Check nothing is written. It only touches the dependency's own
+// overlay, so dependencies can be generated concurrently.
+func generateOverlay(opts GenerateOptions, mgr *overlay.Manager, plugin language.LanguagePlugin, ctx language.DerivationContext, dep config.DependencyLock, plugins []codegen.Plugin, tools *validator.ToolchainResolver, resolveImports importResolver) genResult {
+	apiID := ctx.API.ID
+	fail := func(err error) genResult {
+		return genResult{apiID: apiID, status: genFailed, err: err}
+	}
+
+	overlayPath := mgr.Path(apiID, opts.Language)
+	if opts.Clean {
+		if err := os.RemoveAll(overlayPath); err != nil {
+			return fail(fmt.Errorf("cleaning overlay: %w", err))
+		}
+	}
+
+	target, skip, cleanup, err := prepareGeneration(opts, plugin, ctx, dep, plugins)
+	if err != nil {
+		return fail(err)
+	}
+	defer cleanup()
+
+	if target == nil {
+		// Nothing to generate: the overlay only needs to exist.
+		if opts.Check {
+			if _, err := os.Stat(overlayPath); err != nil {
+				ui.Warning("%s is stale: overlay is missing", apiID)
+				return genResult{apiID: apiID, status: genStale, detail: "overlay is missing"}
+			}
+			// Generated code that can no longer be checked against its
+			// inputs is not up to date.
+			if skip != "" {
+				prev, err := codegen.ReadManifest(overlayPath)
+				if err != nil {
+					return fail(err)
+				}
+				if prev != nil {
+					ui.Warning("%s is stale: %s", apiID, skip)
+					return genResult{apiID: apiID, status: genStale, detail: skip}
+				}
+			}
+			return genResult{apiID: apiID, status: genUpToDate}
+		}
+	} else {
+		prev, err := codegen.ReadManifest(overlayPath)
+		if err != nil {
+			return fail(err)
+		}
+		reason := prev.Stale(target.manifest, overlayPath)
+		if reason == "" {
+			ui.Info("%s is up to date", apiID)
+			return genResult{apiID: apiID, status: genUpToDate, manifest: renderManifest(opts, overlayPath, prev)}
+		}
+		if opts.Check {
+			ui.Warning("%s is stale: %s", apiID, reason)
+			return genResult{apiID: apiID, status: genStale, detail: reason}
+		}
+		if prev != nil {
+			ui.Info("Regenerating %s: %s", apiID, reason)
+			if err := prev.RemoveOutputs(overlayPath); err != nil {
+				return fail(fmt.Errorf("removing stale outputs: %w", err))
+			}
+		}
+	}
+
+	before, err := codegen.TakeSnapshot(overlayPath)
+	if err != nil {
+		return fail(err)
+	}
+
+	ui.Info("Creating overlay for %s...", apiID)
+	ov, err := mgr.Create(apiID, opts.Language)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create overlay: %w", err))
+	}
+
+	// Run Scaffolder if the plugin implements it.
+	if scaffolder, ok := plugin.(language.Scaffolder); ok && ctx.Org != "" {
+		if err := scaffolder.Scaffold(ov.Path, ctx); err != nil {
+			return fail(fmt.Errorf("scaffolding %s: %w", opts.Language, err))
+		}
+	}
+	if target == nil {
+		if skip != "" {
+			return genResult{apiID: apiID, status: genSkipped, detail: skip}
+		}
+		return genResult{apiID: apiID, status: genScaffolded}
+	}
+
+	if target.format == validator.FormatProto {
+		imports, cleanupImports, err := resolveImports(apiID, target.schemaDir)
+		defer cleanupImports()
+		if err != nil {
+			return fail(err)
+		}
+		target.imports = imports
+	}
+	detail, err := generateDependency(opts.Language, apiID, target, ov.Path, plugins, tools)
+	if err != nil {
+		return fail(err)
+	}
+	if err := codegen.WriteManifest(ov.Path, target.manifest, before); err != nil {
+		return fail(fmt.Errorf("writing generation manifest: %w", err))
+	}
+	return genResult{apiID: apiID, status: genGenerated, detail: detail, manifest: renderManifest(opts, ov.Path, target.manifest)}
+}
+
+// genTarget is a dependency ready for generation.
+type genTarget struct {
+	format    validator.SchemaFormat
+	coords    config.LanguageCoords
+	schemaDir string
+	manifest  *codegen.Manifest     // what a generation records, without outputs
+	imports   []codegen.ProtoImport // locked APIs a proto schema imports
+}
+
+// importResolver materializes the locked APIs that the proto schema of an
+// API imports.
+type importResolver func(apiID, schemaDir string) ([]codegen.ProtoImport, func() error, error)
+
+// lockedImports materializes the locked APIs that the .proto files of
+// schemaDir import, and the ones those import in turn, with their
+// coordinates in the plugin's language. Imports of unlocked files, such as
+// googleapis, are left to the deps of the schema's buf.yaml.
+func lockedImports(apiID, schemaDir string, deps map[string]config.DependencyLock, depContext func(string) (language.DerivationContext, error), plugin language.LanguagePlugin) ([]codegen.ProtoImport, func() error, error) {
+	var cleanups []func() error
+	cleanup := func() error {
+		var errs []error
+		for _, c := range cleanups {
+			errs = append(errs, c())
+		}
+		return errors.Join(errs...)
+	}
+
+	var candidates []string
+	for id := range deps {
+		if id != apiID {
+			candidates = append(candidates, id)
+		}
+	}
+	var imports []codegen.ProtoImport
+	seen := map[string]bool{apiID: true}
+	queue := []string{schemaDir}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		ids, err := codegen.ImportedAPIs(dir, candidates)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ctx, err := depContext(id)
+			if err != nil {
+				return nil, cleanup, fmt.Errorf("imported API %s: %w", id, err)
+			}
+			var coords config.LanguageCoords
+			if plugin != nil && plugin.Available(ctx) {
+				if coords, err = plugin.DeriveCoords(ctx); err != nil {
+					return nil, cleanup, fmt.Errorf("deriving coordinates of imported API %s: %w", id, err)
+				}
+			}
+			importDir, c, err := config.MaterializeSchema(deps[id], id)
+			if err != nil {
+				return nil, cleanup, fmt.Errorf("imported API %s: %w", id, err)
+			}
+			cleanups = append(cleanups, c)
+			imports = append(imports, codegen.ProtoImport{APIID: id, SchemaDir: importDir, Coords: coords})
+			queue = append(queue, importDir)
+		}
+	}
+	return imports, cleanup, nil
+}
+
+// prepareGeneration materializes a dependency at its locked ref and hashes
+// its schema. It returns a nil target when there is nothing to generate for
+// the language: protobuf without configured plugins, CRDs and languages
+// without native models. A dependency whose coordinates are unavailable is
+// reported as a warning, and skip says why; one that cannot be
+// materialized at its locked ref is an error.
+func prepareGeneration(opts GenerateOptions, plugin language.LanguagePlugin, ctx language.DerivationContext, dep config.DependencyLock, plugins []codegen.Plugin) (target *genTarget, skip string, cleanup func() error, err error) {
+	noop := func() error { return nil }
+	apiID := ctx.API.ID
+	lang := opts.Language
+	format := validator.SchemaFormat(ctx.API.Format)
+	switch {
+	case format == validator.FormatProto:
+		if len(plugins) == 0 {
+			return nil, "", noop, nil
+		}
+	case format == validator.FormatCRD || !codegen.SupportsModels(lang):
+		return nil, "", noop, nil
+	default:
+		plugins = nil
+	}
+
+	var coords config.LanguageCoords
+	if plugin != nil && plugin.Available(ctx) {
+		if coords, err = plugin.DeriveCoords(ctx); err != nil {
+			return nil, "", noop, fmt.Errorf("deriving %s coordinates: %w", lang, err)
+		}
+	} else if format != validator.FormatProto {
+		skip = fmt.Sprintf("%s coordinates require org in apx.yaml", lang)
+		ui.Warning("Skipping generation for %s: %s", apiID, skip)
+		return nil, skip, noop, nil
+	}
+
+	schemaDir, cleanup, err := config.MaterializeSchema(dep, apiID)
+	if err != nil {
+		return nil, "", noop, err
+	}
+	inputs, err := codegen.HashInputs(schemaDir)
+	if err != nil {
+		_ = cleanup()
+		return nil, "", noop, err
+	}
+	return &genTarget{
+		format:    format,
+		coords:    coords,
+		schemaDir: schemaDir,
+		manifest:  codegen.NewManifest(apiID, lang, dep.Ref, inputs, opts.APXVersion, coords, plugins),
+	}, "", cleanup, nil
+}
+
+// generateDependency generates code for a prepared dependency into the
+// overlay: protobuf schemas through the language's protoc plugins, and
+// Avro, JSON Schema, Parquet and OpenAPI schemas as native types. It
+// returns a short description of what was generated.
+func generateDependency(lang, apiID string, target *genTarget, overlayPath string, plugins []codegen.Plugin, tools *validator.ToolchainResolver) (string, error) {
+	if target.format == validator.FormatProto {
+		ui.Info("Running %d %s plugin(s) for %s...", len(plugins), lang, apiID)
+		err := codegen.GenerateProto(codegen.ProtoRequest{
+			Language:  lang,
+			APIID:     apiID,
+			SchemaDir: target.schemaDir,
+			Coords:    target.coords,
+			OutDir:    overlayPath,
+			Plugins:   plugins,
+			Tools:     tools,
+			Imports:   target.imports,
+		})
+		return fmt.Sprintf("%d plugin(s)", len(plugins)), err
+	}
+
+	files, err := codegen.GenerateModels(codegen.ModelRequest{
+		Language:  lang,
+		Format:    target.format,
+		APIID:     apiID,
+		SchemaDir: target.schemaDir,
+		Coords:    target.coords,
+		OutDir:    overlayPath,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		ui.Info("No types to generate for %s", apiID)
+		return "no types", nil
+	}
+	ui.Info("Generated %s types for %s: %s", lang, apiID, strings.Join(files, ", "))
+	return fmt.Sprintf("%d file(s)", len(files)), nil
+}
+
+// renderManifest renders the generation manifest of an overlay for
+// --manifest, or returns nil without it.
+func renderManifest(opts GenerateOptions, overlayPath string, m *codegen.Manifest) []byte {
+	if !opts.Manifest {
+		return nil
+	}
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return append([]byte("# "+filepath.Join(overlayPath, codegen.ManifestFile)+"\n"), data...)
+}
+
+// dependencySourceRepo returns the repository a dependency is published from,
+// which its canonical coordinates derive from. Without one in apx.lock the
+// app's own repository is assumed.
+func dependencySourceRepo(dep config.DependencyLock, cfg *config.Config) string {
+	if dep.Repo != "" && !strings.Contains(dep.Repo, "<") {
+		return dep.Repo
+	}
+	return resolveSourceRepoFromConfig(cfg)
+}
+
 // resolveSourceRepoFromConfig builds the source repo string from a loaded config.
 func resolveSourceRepoFromConfig(cfg *config.Config) string {
 	if cfg != nil && cfg.Org != "" && cfg.Repo != "" {
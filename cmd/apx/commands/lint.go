@@ -19,6 +19,9 @@ func newLintCmd() *cobra.Command {
 		RunE:  lintAction,
 	}
 	cmd.Flags().StringP("format", "f", "", "Schema format (proto, openapi, avro, jsonschema, parquet, crd)")
+	cmd.Flags().Bool("list-rules", false, "List the native lint rules and their settings, then exit")
+	addEngineFlag(cmd)
+	addOutputFlag(cmd)
 	return cmd
 }
 
@@ -28,6 +31,18 @@ func lintAction(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	if list, _ := cmd.Flags().GetBool("list-rules"); list {
+		cfg, _ := config.Load("")
+		formatStr, _ := cmd.Flags().GetString("format")
+		return listLintRules(cmd, cfg, validator.SchemaFormat(formatStr))
+	}
+
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	defer quietForReport(output)()
+
 	// Try to resolve API ID (e.g. proto/payments/ledger/v1) to a path.
 	// Falls back to treating the argument as a filesystem path.
 	var apiFormat string
@@ -47,8 +62,17 @@ func lintAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path does not exist: %s", absPath)
 	}
 
-	resolver := validator.NewToolchainResolver()
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return err
+	}
 	v := validator.NewValidator(resolver)
+	if err := applyEngine(cmd, v); err != nil {
+		return err
+	}
+	if err := configureLint(v, cfg); err != nil {
+		return err
+	}
 
 	var format validator.SchemaFormat
 	if formatStr, _ := cmd.Flags().GetString("format"); formatStr != "" {
@@ -71,6 +95,9 @@ func lintAction(cmd *cobra.Command, args []string) error {
 		if info, statErr := os.Stat(absPath); statErr == nil && info.IsDir() {
 			ui.Info("No schema files found in: %s", absPath)
 			ui.Info("Nothing to lint. Add schema files or specify --format.")
+			if output != "" {
+				return writeReport(cmd, output, "apx lint", "", nil)
+			}
 			return nil
 		}
 		return fmt.Errorf("could not detect schema format for: %s\nPlease specify format with --format flag", absPath)
@@ -78,20 +105,67 @@ func lintAction(cmd *cobra.Command, args []string) error {
 
 	ui.Info("Linting %s files in: %s", format, absPath)
 
-	if err := v.Lint(absPath, format); err != nil {
+	findings, err := v.LintFindings(absPath, format)
+	if err != nil {
 		ui.Error("Lint failed: %v", err)
 		return err
 	}
 
 	// For proto files, check go_package for deprecated apis-go import root.
 	if format == validator.FormatProto {
-		if warnings := validator.CheckGoPackageCanonical(absPath); len(warnings) > 0 {
-			for _, w := range warnings {
-				ui.Warning("%s", w)
-			}
+		for _, w := range validator.CheckGoPackageCanonical(absPath) {
+			findings = append(findings, validator.Finding{
+				File:     absPath,
+				RuleID:   "go-package-canonical",
+				Severity: validator.SeverityWarning,
+				Format:   format,
+				Message:  w,
+			})
+		}
+	}
+
+	if output != "" {
+		if err := writeReport(cmd, output, "apx lint", absPath, findings); err != nil {
+			return err
 		}
+	} else {
+		printFindings(findings, false)
+	}
+	if n := countSeverity(findings, validator.SeverityError); n > 0 {
+		return fmt.Errorf("lint failed: %d error(s) found", n)
 	}
 
 	ui.Success("\u2713 All files passed lint checks")
 	return nil
 }
+
+// listLintRules prints the native lint rules of format (every format when
+// empty) with the severity apx.yaml gives them.
+func listLintRules(cmd *cobra.Command, cfg *config.Config, format validator.SchemaFormat) error {
+	if format != "" && len(validator.LintRules(format)) == 0 {
+		return fmt.Errorf("no native lint rules for format %q; the native rules cover avro, parquet, jsonschema and crd", format)
+	}
+	configured := map[validator.SchemaFormat]map[string]config.LintRule{}
+	if cfg != nil {
+		configured[validator.FormatAvro] = cfg.Lint.Avro.Rules
+		configured[validator.FormatParquet] = cfg.Lint.Parquet.Rules
+		configured[validator.FormatJSONSchema] = cfg.Lint.JSONSchema.Rules
+		configured[validator.FormatCRD] = cfg.Lint.CRD.Rules
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%-28s %-11s %-8s %s\n", "RULE", "FORMAT", "SEVERITY", "DESCRIPTION")
+	for _, r := range validator.LintRules(format) {
+		sev := string(r.Severity)
+		desc := r.Summary
+		if c, ok := configured[r.Format][r.ID]; ok {
+			if c.Severity != "" {
+				sev = c.Severity
+			}
+			if c.Pattern != "" {
+				desc += fmt.Sprintf(" (pattern %s)", c.Pattern)
+			}
+		}
+		fmt.Fprintf(out, "%-28s %-11s %-8s %s\n", r.ID, r.Format, sev, desc)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/pathlint"
 	"github.com/spf13/cobra"
 )
@@ -26,7 +27,9 @@ always exit 0 (report unchanged) and surface drift as a warning. Without
 --warn-only the command exits non-zero when bucket [1] or [2] is non-empty.
 
 An --ingress argument may be a chart directory (rendered via 'helm template')
-or an already-rendered manifest YAML file (no helm binary required).
+or an already-rendered manifest YAML file (no helm binary required). With
+execution.mode: container in apx.yaml, helm runs in the configured image;
+otherwise helm comes from the offline bundle, the tool cache or PATH.
 
 Examples:
   apx pathlint --ingress rendered.yaml --spec identity.swagger.json
@@ -53,7 +56,12 @@ func pathlintAction(cmd *cobra.Command, _ []string) error {
 	warnOnly, _ := cmd.Flags().GetBool("warn-only")
 	outPath, _ := cmd.Flags().GetString("out")
 
-	report, err := pathlint.Analyze(ingressInputs, specInputs, helmSets, release)
+	cfg, _ := config.Load("")
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return err
+	}
+	report, err := pathlint.Analyze(ingressInputs, specInputs, helmSets, release, resolver)
 	if err != nil {
 		return err
 	}
@@ -2,10 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/policy"
 	"github.com/infobloxopen/apx/internal/ui"
+	"github.com/infobloxopen/apx/internal/validator"
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +21,20 @@ func newPolicyCmd() *cobra.Command {
 }
 
 func newPolicyCheckCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "check [path]",
 		Short: "Check policy compliance",
-		Args:  cobra.MaximumNArgs(1),
-		RunE:  policyCheckAction,
+		Long: `Check policy compliance of the schemas at path (default: the current directory).
+
+With --against, the rules that judge schema evolution also compare the
+schemas with that baseline: policy.parquet.allow_additive_nullable_only
+reports every Parquet change other than adding a nullable column.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: policyCheckAction,
 	}
+	cmd.Flags().String("against", "", "git reference or path of the baseline for schema evolution rules")
+	addOutputFlag(cmd)
+	return cmd
 }
 
 func policyCheckAction(cmd *cobra.Command, args []string) error {
@@ -33,39 +43,70 @@ func policyCheckAction(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	defer quietForReport(output)()
+
 	cfg, err := loadConfig(cmd)
 	if err != nil {
 		ui.Error("Failed to load config: %v", err)
 		return err
 	}
 
-	return checkPolicy(cfg, path)
+	against, _ := cmd.Flags().GetString("against")
+	result, err := checkPolicy(cfg, path, against)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		abs, _ := filepath.Abs(path)
+		if err := writeReport(cmd, output, "apx policy check", "", result.Findings(abs)); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range result.Violations {
+			if v.Severity == validator.SeverityWarning {
+				ui.Warning("[%s] %s", v.Rule, v.Message)
+				continue
+			}
+			ui.Error("[%s] %s", v.Rule, v.Message)
+		}
+	}
+	if !result.Passed() {
+		return fmt.Errorf("policy check failed: %d violation(s) found", len(result.Errors()))
+	}
+	return nil
 }
 
-func checkPolicy(cfg *config.Config, path string) error {
+// checkPolicy evaluates the configured policy against path and prints a
+// summary. against is the baseline for schema evolution rules, or empty.
+// Violations are left to the caller, which reports them either as text or
+// as a structured report.
+func checkPolicy(cfg *config.Config, path, against string) (*policy.Result, error) {
 	ui.Info("Checking policy compliance in %s...", path)
 
-	result, err := policy.Check(cfg.Policy, path)
+	resolver, err := newToolchainResolver(cfg)
 	if err != nil {
 		ui.Error("Policy check failed: %v", err)
-		return err
+		return nil, err
+	}
+	result, err := policy.CheckAgainst(cfg.Policy, path, against, resolver)
+	if err != nil {
+		ui.Error("Policy check failed: %v", err)
+		return nil, err
 	}
 
 	if result.Checked == 0 {
 		ui.Info("No policy rules configured")
-		return nil
+		return result, nil
 	}
 
 	ui.Info("Evaluated %d policy rule(s)", result.Checked)
 
 	if result.Passed() {
 		ui.Success("All policies are compliant")
-		return nil
-	}
-
-	for _, v := range result.Violations {
-		ui.Error("[%s] %s", v.Rule, v.Message)
 	}
-
-	return fmt.Errorf("policy check failed: %d violation(s) found", len(result.Violations))
+	return result, nil
 }
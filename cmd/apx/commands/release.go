@@ -354,7 +354,7 @@ Examples:
 	cmd.Flags().String("canonical-repo", "", "Canonical repository URL")
 	cmd.Flags().Bool("strict", false, "Make go_package mismatches an error")
 	cmd.Flags().Bool("skip-gomod", false, "Skip go.mod generation and validation")
-	cmd.Flags().Bool("force", false, "Override sunset block")
+	cmd.Flags().Bool("force", false, "Override sunset block, lifecycle and version-bump checks")
 	cmd.Flags().Bool("dry-run", false, "Show what would be prepared without writing the manifest")
 	cmd.Flags().StringSlice("tag", nil, "Catalog tag to record on the module (repeatable; e.g. --tag audience:internal --tag product:ddi)")
 	// ARCH-271 branch routing + pre-release mechanics.
@@ -686,21 +686,30 @@ func releasePrepareAction(cmd *cobra.Command, args []string) error {
 		repoPath, _ := os.Getwd()
 		schemaDir := filepath.Join(repoPath, source.Path)
 		if _, statErr := os.Stat(schemaDir); statErr == nil {
-			polResult, polErr := policy.Check(cfg.Policy, schemaDir)
+			// Schema evolution rules compare with the line's latest release.
+			against := ""
+			if previous := previousRelease(repoPath, manifest); previous != "" {
+				against = config.DeriveTag(manifest.APIID, previous)
+			}
+			resolver, polErr := newToolchainResolver(cfg)
+			var polResult *policy.Result
+			if polErr == nil {
+				polResult, polErr = policy.CheckAgainst(cfg.Policy, schemaDir, against, resolver)
+			}
 			if polErr != nil {
 				ui.Warning("Policy check error: %v", polErr)
 				manifest.Validation.Policy = publisher.ValidationSkipped
 			} else if !polResult.Passed() {
 				manifest.Validation.Policy = publisher.ValidationFailed
-				for _, v := range polResult.Violations {
+				for _, v := range polResult.Errors() {
 					ui.Error("[%s] %s", v.Rule, v.Message)
 				}
 				manifest.Fail(string(publisher.ErrCodePolicyFailed),
-					fmt.Sprintf("%d policy violation(s)", len(polResult.Violations)), "prepare")
+					fmt.Sprintf("%d policy violation(s)", len(polResult.Errors())), "prepare")
 				_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
 				return &publisher.ReleaseError{
 					Code:    publisher.ErrCodePolicyFailed,
-					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Violations)),
+					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Errors())),
 				}
 			} else {
 				manifest.Validation.Policy = publisher.ValidationPassed
@@ -711,6 +720,12 @@ func releasePrepareAction(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Version bump validation: classify the schema changes since the line's
+	// latest release and require the bump they call for.
+	if err := checkReleaseBump(cfg, manifest, api.Format, source.Path, force); err != nil {
+		return err
+	}
+
 	if err := manifest.SetState(publisher.StateValidated); err != nil {
 		return err
 	}
@@ -796,6 +811,157 @@ func releasePrepareAction(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// previousRelease returns the latest version released on the manifest's
+// line before the requested one, or "" for a first release or a line whose
+// tags are not in the repository at repoPath.
+func previousRelease(repoPath string, manifest *publisher.ReleaseManifest) string {
+	versions, err := publisher.NewTagManager(repoPath, "").ListVersionsForAPI(manifest.APIID)
+	if err != nil || len(versions) == 0 {
+		return ""
+	}
+	major, err := config.LineMajor(manifest.Line)
+	if err != nil {
+		return ""
+	}
+	previous, err := config.LatestVersion(versions, major)
+	if err != nil || previous == manifest.RequestedVersion {
+		return ""
+	}
+	return previous
+}
+
+// checkReleaseBump classifies the schema changes since the latest release on
+// the manifest's line and rejects a requested version that bumps by more or
+// less than they call for. A first release, or a line whose tags are not in
+// this repository, has nothing to compare against. A comparison that cannot
+// run (for example a missing buf) fails the release; --force skips it, and
+// records a version mismatch without failing, with a warning.
+func checkReleaseBump(cfg *config.Config, manifest *publisher.ReleaseManifest, format, sourcePath string, force bool) error {
+	repoPath, _ := os.Getwd()
+	schemaDir := filepath.Join(repoPath, sourcePath)
+	if _, err := os.Stat(schemaDir); err != nil {
+		return nil
+	}
+	previous := previousRelease(repoPath, manifest)
+	if previous == "" {
+		return nil
+	}
+	major, _ := config.LineMajor(manifest.Line)
+
+	unclassified := func(err error) error {
+		msg := fmt.Sprintf("could not classify changes since %s: %v", previous, err)
+		if force {
+			ui.Warning("%s (continuing: --force)", msg)
+			return nil
+		}
+		manifest.Fail(string(publisher.ErrCodeValidationFailed), msg, "prepare")
+		_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+		return &publisher.ReleaseError{
+			Code:    publisher.ErrCodeValidationFailed,
+			Message: msg,
+			Hint:    "Install the missing tools (apx fetch) or use --force to skip the version-bump check",
+		}
+	}
+
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return unclassified(err)
+	}
+	v := validator.NewValidator(resolver)
+	configureBreaking(v, cfg, manifest.APIID, schemaDir)
+	if err := configureWaivers(v, manifest.APIID); err != nil {
+		return err
+	}
+	log, err := v.Changelog(schemaDir, config.DeriveTag(manifest.APIID, previous), schemaDir, validator.SchemaFormat(format))
+	if err != nil {
+		return unclassified(err)
+	}
+	report := log.Report
+	manifest.PreviousVersion = previous
+	manifest.ChangeLevel = string(report.Level)
+	manifest.Changes = releaseChanges(log)
+	ui.Info("Changes since %s: %s", previous, report.Level)
+	for _, c := range manifest.Changes {
+		if c.Breaking {
+			ui.Warning("  BREAKING %s", c.Summary)
+		} else {
+			ui.Info("  %s", c.Summary)
+		}
+	}
+
+	// An expired waiver fails the release even with --force: the waiver
+	// file is where the decision to ship a breaking change is recorded.
+	if expired := expiredWaivers(report.Findings); len(expired) > 0 {
+		return failExpiredWaivers(manifest, expired, "prepare")
+	}
+	manifest.Validation.Waivers = v.AppliedWaivers()
+	printWaived(manifest.Validation.Waivers)
+
+	hasBreaking := report.Level == validator.ChangeBreaking
+	if hasBreaking {
+		printFindings(report.Findings, force)
+		manifest.Validation.Breaking = publisher.ValidationFailed
+	} else {
+		manifest.Validation.Breaking = publisher.ValidationPassed
+	}
+
+	bumpErr := config.ValidateVersionBump(previous, manifest.RequestedVersion, hasBreaking, report.Level != validator.ChangeNone)
+	if bumpErr == nil {
+		return nil
+	}
+	if force {
+		ui.Warning("%v (continuing: --force)", bumpErr)
+		return nil
+	}
+	code := publisher.ErrCodeVersionBumpMismatch
+	hint := fmt.Sprintf("Run 'apx semver --api-id %s --against %s' for the suggested version", manifest.APIID, config.DeriveTag(manifest.APIID, previous))
+	if hasBreaking && major > 0 {
+		code = publisher.ErrCodeBreakingChange
+		hint = fmt.Sprintf("Create a new API line (e.g. apx init --line v%d)", major+1)
+	}
+	manifest.Fail(string(code), bumpErr.Error(), "prepare")
+	_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+	return &publisher.ReleaseError{Code: code, Message: bumpErr.Error(), Hint: hint}
+}
+
+// failExpiredWaivers fails the release in the given phase because
+// breaking-change waivers of the API have expired.
+func failExpiredWaivers(manifest *publisher.ReleaseManifest, expired []validator.Finding, phase string) error {
+	printFindings(expired, false)
+	msg := fmt.Sprintf("%d breaking-change waiver(s) expired", len(expired))
+	manifest.Validation.Breaking = publisher.ValidationFailed
+	manifest.Fail(string(publisher.ErrCodeValidationFailed), msg, phase)
+	_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+	return &publisher.ReleaseError{
+		Code:    publisher.ErrCodeValidationFailed,
+		Message: msg,
+		Hint:    fmt.Sprintf("Fix the breaking change, or renew or remove the waiver in %s", config.WaiversFile),
+	}
+}
+
+// releaseChanges turns a changelog into the release notes recorded in the
+// manifest. Breaking findings that are not tied to an element are kept as
+// breaking changes of their own, so the notes never understate a release.
+func releaseChanges(log *validator.Changelog) []publisher.ReleaseChange {
+	var changes []publisher.ReleaseChange
+	multiFile := diffMultiFile(log.Entries)
+	for _, e := range log.Entries {
+		if multiFile && e.Category != "file" {
+			e.Element = e.File + ": " + e.Element
+		}
+		changes = append(changes, publisher.ReleaseChange{
+			Category: e.Category,
+			Kind:     e.Kind,
+			Summary:  e.String(),
+			Breaking: e.Breaking,
+		})
+	}
+	for _, f := range log.Unmatched {
+		changes = append(changes, publisher.ReleaseChange{Summary: f.Message, Breaking: true})
+	}
+	return changes
+}
+
 // ---------------------------------------------------------------------------
 // apx release submit
 // ---------------------------------------------------------------------------
@@ -1398,14 +1564,39 @@ func releaseFinalizeAction(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Create a validator instance for re-validation
-	resolver := validator.NewToolchainResolver()
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		manifest.Fail(string(publisher.ErrCodeValidationFailed), err.Error(), "finalize")
+		_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+		return err
+	}
 	v := validator.NewValidator(resolver)
 	schemaFormat := validator.SchemaFormat(manifest.Format)
+	// Waivers recorded at prepare travel with the manifest, so they apply
+	// even where the waivers file is not checked in.
+	if err := configureWaivers(v, manifest.APIID, manifest.Validation.Waivers...); err != nil {
+		manifest.Fail(string(publisher.ErrCodeValidationFailed), err.Error(), "finalize")
+		_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+		return err
+	}
+	if err := configureLint(v, cfg); err != nil {
+		manifest.Fail(string(publisher.ErrCodeValidationFailed), err.Error(), "finalize")
+		_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+		return err
+	}
+	// Judge breaking changes as prepare did: the Avro compatibility mode and
+	// history, the JSON Schema mode and the Parquet policy.
+	configureBreaking(v, cfg, manifest.APIID, schemaDir)
 
 	// Re-run lint
 	manifest.Validation.Lint = publisher.ValidationSkipped
 	if info, statErr := os.Stat(schemaDir); statErr == nil && info.IsDir() {
-		if lintErr := v.Lint(schemaDir, schemaFormat); lintErr != nil {
+		lintFindings, lintErr := v.LintFindings(schemaDir, schemaFormat)
+		if lintErr == nil && validator.HasErrors(lintFindings) {
+			printFindings(lintFindings, false)
+			lintErr = fmt.Errorf("%d lint error(s)", countSeverity(lintFindings, validator.SeverityError))
+		}
+		if lintErr != nil {
 			manifest.Validation.Lint = publisher.ValidationFailed
 			manifest.Fail(string(publisher.ErrCodeValidationFailed), lintErr.Error(), "finalize")
 			_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
@@ -1429,7 +1620,28 @@ func releaseFinalizeAction(cmd *cobra.Command, _ []string) error {
 			latestPrev, _ := config.LatestVersion(versions, lineMajor)
 			if latestPrev != "" && latestPrev != manifest.RequestedVersion {
 				prevTag := config.DeriveTag(manifest.APIID, latestPrev)
-				if breakErr := v.Breaking(schemaDir, prevTag, schemaFormat); breakErr != nil {
+				// Compare with the schema at the tag as prepare does; the
+				// native checks only read baselines from disk.
+				var breakFindings []validator.Finding
+				report, breakErr := v.ClassifyChanges(schemaDir, prevTag, schemaFormat)
+				if breakErr == nil {
+					breakFindings = report.Findings
+				}
+				if breakErr != nil {
+					manifest.Validation.Breaking = publisher.ValidationFailed
+					manifest.Fail(string(publisher.ErrCodeValidationFailed), breakErr.Error(), "finalize")
+					_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
+					return &publisher.ReleaseError{
+						Code:    publisher.ErrCodeValidationFailed,
+						Message: fmt.Sprintf("breaking-change check against %s could not run: %v", prevTag, breakErr),
+					}
+				}
+				if expired := expiredWaivers(breakFindings); len(expired) > 0 {
+					return failExpiredWaivers(manifest, expired, "finalize")
+				}
+				if validator.HasErrors(breakFindings) {
+					printFindings(breakFindings, false)
+					breakErr = fmt.Errorf("%d breaking change(s)", countSeverity(breakFindings, validator.SeverityError))
 					manifest.Validation.Breaking = publisher.ValidationFailed
 					manifest.Fail(string(publisher.ErrCodeBreakingChange), breakErr.Error(), "finalize")
 					_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
@@ -1439,6 +1651,8 @@ func releaseFinalizeAction(cmd *cobra.Command, _ []string) error {
 						Hint:    "Create a new API line for breaking changes",
 					}
 				}
+				manifest.Validation.Waivers = v.AppliedWaivers()
+				printWaived(manifest.Validation.Waivers)
 				manifest.Validation.Breaking = publisher.ValidationPassed
 			}
 		}
@@ -1455,11 +1669,11 @@ func releaseFinalizeAction(cmd *cobra.Command, _ []string) error {
 			} else if !polResult.Passed() {
 				manifest.Validation.Policy = publisher.ValidationFailed
 				manifest.Fail(string(publisher.ErrCodePolicyFailed),
-					fmt.Sprintf("%d policy violation(s)", len(polResult.Violations)), "finalize")
+					fmt.Sprintf("%d policy violation(s)", len(polResult.Errors())), "finalize")
 				_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
 				return &publisher.ReleaseError{
 					Code:    publisher.ErrCodePolicyFailed,
-					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Violations)),
+					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Errors())),
 				}
 			} else {
 				manifest.Validation.Policy = publisher.ValidationPassed
@@ -1530,6 +1744,11 @@ func releaseFinalizeAction(cmd *cobra.Command, _ []string) error {
 		if len(manifest.Tags) > 0 {
 			message += "\nTags: " + catalog.FormatTagList(manifest.Tags)
 		}
+		// The release notes let `apx release history` show what each
+		// version changed from the tags alone.
+		if notes := manifest.ReleaseNotes(); notes != nil {
+			message += "\n\n" + notes.Annotation()
+		}
 		if err := tm.CreateTag(manifest.Tag, message, commitHash); err != nil {
 			manifest.Fail(string(publisher.ErrCodePushFailed), err.Error(), "finalize")
 			_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
@@ -1737,19 +1956,26 @@ func newReleaseHistoryCmd() *cobra.Command {
 		Long: `History shows all published versions for a given API ID, extracted
 from git tags. Versions are sorted newest-first.
 
+Each release finalized with release notes records in its tag what changed
+since the previous release; the CHANGES column summarizes it and --changes
+lists every change.
+
 Examples:
   apx release history proto/payments/ledger/v1
+  apx release history proto/payments/ledger/v1 --changes
   apx release history proto/payments/ledger/v1 --format json`,
 		Args: cobra.ExactArgs(1),
 		RunE: releaseHistoryAction,
 	}
 	cmd.Flags().String("format", "table", "Output format: table, json")
+	cmd.Flags().Bool("changes", false, "List the changes each release made")
 	return cmd
 }
 
 func releaseHistoryAction(cmd *cobra.Command, args []string) error {
 	apiID := args[0]
 	format, _ := cmd.Flags().GetString("format")
+	showChanges, _ := cmd.Flags().GetBool("changes")
 
 	if _, parseErr := config.ParseAPIID(apiID); parseErr != nil {
 		return parseErr
@@ -1770,18 +1996,25 @@ func releaseHistoryAction(cmd *cobra.Command, args []string) error {
 
 	// Parse and sort versions (newest first)
 	type versionEntry struct {
-		Version   string `json:"version"`
-		Tag       string `json:"tag"`
-		Lifecycle string `json:"lifecycle"`
+		Version   string                  `json:"version"`
+		Tag       string                  `json:"tag"`
+		Lifecycle string                  `json:"lifecycle"`
+		Notes     *publisher.ReleaseNotes `json:"notes,omitempty"`
 	}
 
 	entries := make([]versionEntry, 0, len(versions))
 	for _, v := range versions {
 		lifecycle := inferLifecycleFromVersion(v)
+		tag := config.DeriveTag(apiID, v)
+		var notes *publisher.ReleaseNotes
+		if body, err := tm.TagAnnotation(tag); err == nil {
+			notes = publisher.ParseReleaseNotes(body)
+		}
 		entries = append(entries, versionEntry{
 			Version:   v,
-			Tag:       config.DeriveTag(apiID, v),
+			Tag:       tag,
 			Lifecycle: lifecycle,
+			Notes:     notes,
 		})
 	}
 
@@ -1810,14 +2043,27 @@ func releaseHistoryAction(cmd *cobra.Command, args []string) error {
 	// Table format
 	ui.Info("Release history for %s:", apiID)
 	ui.Info("")
-	ui.Info("  %-20s %-14s %s", "VERSION", "LIFECYCLE", "TAG")
-	ui.Info("  %-20s %-14s %s", "-------", "---------", "---")
+	ui.Info("  %-20s %-14s %-16s %s", "VERSION", "LIFECYCLE", "CHANGES", "TAG")
+	ui.Info("  %-20s %-14s %-16s %s", "-------", "---------", "-------", "---")
 	for _, e := range entries {
 		lc := e.Lifecycle
 		if lc == "" {
 			lc = "-"
 		}
-		ui.Info("  %-20s %-14s %s", e.Version, lc, e.Tag)
+		changes := "-"
+		if e.Notes != nil {
+			changes = fmt.Sprintf("%s (%d)", e.Notes.ChangeLevel, len(e.Notes.Changes))
+		}
+		ui.Info("  %-20s %-14s %-16s %s", e.Version, lc, changes, e.Tag)
+		if showChanges && e.Notes != nil {
+			for _, c := range e.Notes.Changes {
+				if c.Breaking {
+					ui.Info("      BREAKING %s", c.Summary)
+				} else {
+					ui.Info("      %s", c.Summary)
+				}
+			}
+		}
 	}
 	ui.Info("")
 	ui.Info("Total: %d release(s)", len(entries))
@@ -2054,15 +2300,15 @@ func releasePromoteAction(cmd *cobra.Command, args []string) error {
 				ui.Warning("Policy check error: %v", polErr)
 			} else if !polResult.Passed() {
 				manifest.Validation.Policy = publisher.ValidationFailed
-				for _, v := range polResult.Violations {
+				for _, v := range polResult.Errors() {
 					ui.Error("[%s] %s", v.Rule, v.Message)
 				}
 				manifest.Fail(string(publisher.ErrCodePolicyFailed),
-					fmt.Sprintf("%d policy violation(s)", len(polResult.Violations)), "promote")
+					fmt.Sprintf("%d policy violation(s)", len(polResult.Errors())), "promote")
 				_ = publisher.WriteManifest(manifest, ".apx-release.yaml")
 				return &publisher.ReleaseError{
 					Code:    publisher.ErrCodePolicyFailed,
-					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Violations)),
+					Message: fmt.Sprintf("policy check failed: %d violation(s)", len(polResult.Errors())),
 				}
 			} else {
 				manifest.Validation.Policy = publisher.ValidationPassed
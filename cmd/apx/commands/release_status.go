@@ -278,7 +278,7 @@ func isSchemaFile(rel, format string) bool {
 	case "proto":
 		return ext == ".proto"
 	case "avro":
-		return ext == ".avsc"
+		return ext == ".avsc" || ext == ".avpr" || ext == ".avdl"
 	case "jsonschema":
 		return ext == ".json"
 	case "parquet":
@@ -188,4 +188,9 @@ func TestIsSchemaFile(t *testing.T) {
 	if isSchemaFile("identity.yaml", "proto") {
 		t.Error("proto: .yaml should not be schema")
 	}
+	for _, f := range []string{"orders.avsc", "orders.avpr", "orders.avdl"} {
+		if !isSchemaFile(f, "avro") {
+			t.Errorf("avro: %s should be schema", f)
+		}
+	}
 }
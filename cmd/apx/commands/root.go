@@ -1,10 +1,17 @@
 package commands
 
 import (
+	"os"
+
 	"github.com/infobloxopen/apx/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// offlineMode keeps tool resolution off the network: tools come from the
+// offline bundle or the local tool cache only. Set by --offline or
+// $APX_OFFLINE.
+var offlineMode bool
+
 // NewRootCmd creates the root cobra command with all subcommands registered.
 func NewRootCmd(version string) *cobra.Command {
 	cmd := &cobra.Command{
@@ -16,6 +23,7 @@ func NewRootCmd(version string) *cobra.Command {
 			verbose, _ := cmd.Flags().GetBool("verbose")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			noColor, _ := cmd.Flags().GetBool("no-color")
+			offline, _ := cmd.Flags().GetBool("offline")
 
 			if quiet {
 				ui.SetQuiet(true)
@@ -29,6 +37,7 @@ func NewRootCmd(version string) *cobra.Command {
 			if noColor {
 				ui.SetColorEnabled(false)
 			}
+			offlineMode = offline || os.Getenv("APX_OFFLINE") != ""
 			return nil
 		},
 		Version:       version,
@@ -45,6 +54,7 @@ func NewRootCmd(version string) *cobra.Command {
 	cmd.PersistentFlags().Bool("json", false, "output in JSON format")
 	cmd.PersistentFlags().Bool("no-color", false, "disable colored output")
 	cmd.PersistentFlags().String("config", "apx.yaml", "config file path")
+	cmd.PersistentFlags().Bool("offline", false, "never download tools; use the offline bundle and local tool cache")
 
 	// Preserve registration order in help output instead of sorting alphabetically.
 	cobra.EnableCommandSorting = false
@@ -56,6 +66,7 @@ func NewRootCmd(version string) *cobra.Command {
 		newBreakingCmd(),
 		newPathlintCmd(),
 		newSemverCmd(),
+		newDiffCmd(),
 		newGenCmd(),
 		newClientCmd(),
 		newPolicyCmd(),
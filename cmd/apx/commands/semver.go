@@ -36,6 +36,7 @@ Lifecycle pre-release mapping:
 	cmd.Flags().String("api-id", "", "API ID (e.g. proto/payments/ledger/v1)")
 	cmd.Flags().String("lifecycle", "", "Lifecycle state (experimental, beta, stable, deprecated, sunset)")
 	cmd.Flags().StringP("format", "f", "", "Schema format (proto, openapi, avro, jsonschema, parquet, crd)")
+	addEngineFlag(cmd)
 	return cmd
 }
 
@@ -65,10 +66,10 @@ func semverAction(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return suggestSemver(cfg, path, against, apiID, lifecycle, apiFormat, formatStr)
+	return suggestSemver(cmd, cfg, path, against, apiID, lifecycle, apiFormat, formatStr)
 }
 
-func suggestSemver(cfg *config.Config, path, against, apiID, lifecycle, apiFormat, formatFlag string) error {
+func suggestSemver(cmd *cobra.Command, cfg *config.Config, path, against, apiID, lifecycle, apiFormat, formatFlag string) error {
 	ui.Info("Analyzing changes in %s against %s...", path, against)
 
 	// --- Determine schema format ---
@@ -115,18 +116,39 @@ func suggestSemver(cfg *config.Config, path, against, apiID, lifecycle, apiForma
 		line = api.Line
 	}
 
-	// --- Run breaking-change detection ---
-	resolver := validator.NewToolchainResolver()
+	// --- Classify schema changes ---
+	resolver, err := newToolchainResolver(cfg)
+	if err != nil {
+		return err
+	}
 	v := validator.NewValidator(resolver)
-
-	hasBreaking := false
-	breakingErr := v.Breaking(absPath, against, format)
-	if breakingErr != nil {
-		hasBreaking = true
-		ui.Warning("Breaking changes detected: %v", breakingErr)
-	} else {
+	configureBreaking(v, cfg, apiID, absPath)
+	if err := configureWaivers(v, apiID); err != nil {
+		return err
+	}
+	if err := applyEngine(cmd, v); err != nil {
+		return err
+	}
+
+	// A comparison that could not run (missing tool, unreadable baseline) is
+	// an error, not evidence of a breaking change.
+	changes, classifyErr := v.ClassifyChanges(absPath, against, format)
+	if classifyErr != nil {
+		return fmt.Errorf("breaking-change analysis failed: %w", classifyErr)
+	}
+	hasBreaking := changes.Level == validator.ChangeBreaking
+	hasChanges := changes.Level != validator.ChangeNone
+	switch changes.Level {
+	case validator.ChangeBreaking:
+		ui.Warning("Breaking changes detected: %d", countSeverity(changes.Findings, validator.SeverityError))
+		printFindings(changes.Findings, true)
+	case validator.ChangeAdditive:
 		ui.Success("No breaking changes detected")
+	default:
+		ui.Success("No schema changes detected")
 	}
+	printWaived(v.AppliedWaivers())
+	printChanges(changes.Changes)
 
 	// --- Determine current latest version ---
 	current := ""
@@ -145,12 +167,6 @@ func suggestSemver(cfg *config.Config, path, against, apiID, lifecycle, apiForma
 		}
 	}
 
-	// Determine if there are additive changes (non-breaking but meaningful).
-	// If the breaking check passed without error but the against ref differs,
-	// treat as additive changes. If caller explicitly knows there are no
-	// schema changes, they would use a different workflow.
-	hasChanges := true // conservative: assume additive changes unless proven otherwise
-
 	// --- Suggest version ---
 	suggestion, suggestErr := config.SuggestVersion(current, hasBreaking, hasChanges, lifecycle, line)
 	if suggestion != nil {
@@ -167,6 +183,17 @@ func suggestSemver(cfg *config.Config, path, against, apiID, lifecycle, apiForma
 	return nil
 }
 
+// printChanges lists the schema changes behind a classification.
+func printChanges(changes []validator.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	ui.Info("Schema changes (%d):", len(changes))
+	for _, c := range changes {
+		ui.Info("  %s", c)
+	}
+}
+
 // nextLine increments a line version string for hint messages.
 func nextLine(line string) string {
 	major, err := config.LineMajor(line)
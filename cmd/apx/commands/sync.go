@@ -2,7 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/infobloxopen/apx/internal/config"
 	"github.com/infobloxopen/apx/internal/language"
 	"github.com/infobloxopen/apx/internal/overlay"
 	"github.com/infobloxopen/apx/internal/ui"
@@ -17,12 +22,18 @@ func newSyncCmd() *cobra.Command {
 
   Go:     updates go.work to reference generated overlay directories
   Python: runs 'pip install -e' for each Python overlay in the active virtualenv
+  TypeScript: registers each TypeScript overlay as a workspace package in
+          package.json "workspaces" (npm, yarn) or pnpm-workspace.yaml (pnpm)
 
 Without a language argument, all supported languages are synced.
 
 Use --clean to reverse the activation (deactivate overlays without deleting them):
   Go:     writes a minimal go.work with only the root module
   Python: runs 'pip uninstall' for each linked Python overlay
+  TypeScript: removes the TypeScript overlays from the workspace packages
+
+Use --prune to first remove overlays whose module or version is no longer
+locked in apx.lock, such as the old version left behind by 'apx update'.
 
 Examples:
   apx sync                                     # activate all languages
@@ -30,18 +41,22 @@ Examples:
   apx sync python                              # activate Python overlays only
   apx sync python proto/payments/ledger/v1     # activate one Python overlay
   apx sync --clean                             # deactivate all languages
-  apx sync --clean python                      # deactivate Python only`,
+  apx sync typescript                          # register TypeScript overlays as workspace packages
+  apx sync --clean python                      # deactivate Python only
+  apx sync --prune                             # remove overlays apx.lock no longer pins`,
 		Args: cobra.MaximumNArgs(2),
 		RunE: syncAction,
 	}
 	cmd.Flags().Bool("clean", false, "Deactivate overlays from package managers (reverse of sync)")
 	cmd.Flags().Bool("dry-run", false, "Show what would be done without making changes")
+	cmd.Flags().Bool("prune", false, "Remove overlays whose module or version is not locked in apx.lock")
 	return cmd
 }
 
 func syncAction(cmd *cobra.Command, args []string) error {
 	clean, _ := cmd.Flags().GetBool("clean")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	prune, _ := cmd.Flags().GetBool("prune")
 
 	var langFilter, moduleFilter string
 	if len(args) > 0 {
@@ -54,6 +69,16 @@ func syncAction(cmd *cobra.Command, args []string) error {
 	if langFilter != "" && language.Get(langFilter) == nil {
 		return fmt.Errorf("unknown language %q", langFilter)
 	}
+	if prune && clean {
+		return fmt.Errorf("--prune and --clean cannot be combined")
+	}
+
+	if prune {
+		cfg, _ := config.LoadRaw("")
+		if err := pruneOverlays(overlay.NewManager("."), cfg, langFilter, dryRun); err != nil {
+			return err
+		}
+	}
 
 	// Go: managed via go.work (not through the plugin Linker interface)
 	if langFilter == "" || langFilter == "go" {
@@ -75,6 +100,9 @@ func syncAction(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("updating go.work: %w", err)
 			}
 			ui.Success("Go: go.work updated")
+			if err := warnWorkConflicts(mgr); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -128,3 +156,97 @@ func syncAction(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// pruneOverlays reconciles the overlays of mgr with apx.lock. Overlays whose
+// module or version is no longer locked are removed, and overlays of
+// languages that language_targets does not enable are reported. A non-empty
+// lang limits both to that language. go.work is not updated.
+func pruneOverlays(mgr *overlay.Manager, cfg *config.Config, lang string, dryRun bool) error {
+	if _, err := os.Stat("apx.lock"); err != nil {
+		return fmt.Errorf("--prune needs apx.lock to reconcile overlays against: %w", err)
+	}
+	lock, err := loadLockFile("apx.lock")
+	if err != nil {
+		return err
+	}
+	locked := make(map[string]string, len(lock.Dependencies))
+	for apiID, dep := range lock.Dependencies {
+		locked[apiID] = dep.Ref
+	}
+	var enabled map[string]bool
+	if cfg != nil && len(cfg.LanguageTargets) > 0 {
+		enabled = map[string]bool{}
+		for name, target := range cfg.LanguageTargets {
+			enabled[name] = target.Enabled
+		}
+	}
+
+	rec, err := mgr.Reconcile(locked, enabled)
+	if err != nil {
+		return err
+	}
+	filter := func(overlays []overlay.Overlay) []overlay.Overlay {
+		var out []overlay.Overlay
+		for _, ov := range overlays {
+			if lang == "" || ov.Language == lang {
+				out = append(out, ov)
+			}
+		}
+		return out
+	}
+	rec.Orphaned = filter(rec.Orphaned)
+	rec.Disabled = filter(rec.Disabled)
+
+	for _, ov := range rec.Orphaned {
+		apiID, _ := overlay.ParseOverlayModule(ov.ModulePath)
+		reason := apiID + " is not in apx.lock"
+		if ref, ok := locked[apiID]; ok {
+			reason = apiID + " is locked at " + ref
+		}
+		if dryRun {
+			ui.Info("[dry-run] Would remove overlay %s (%s)", displayPath(ov.Path), reason)
+		} else {
+			ui.Info("Removing overlay %s (%s)", displayPath(ov.Path), reason)
+		}
+	}
+	if !dryRun {
+		if err := mgr.RemoveOrphans(rec); err != nil {
+			return err
+		}
+	}
+	if len(rec.Orphaned) == 0 {
+		ui.Info("No overlays to prune")
+	}
+
+	for _, ov := range rec.Disabled {
+		ui.Warning("Overlay %s is for %s, which is not enabled in language_targets", displayPath(ov.Path), ov.Language)
+	}
+	return nil
+}
+
+// warnWorkConflicts warns about API lines go.work resolves to more than one
+// overlay, which makes the version Go builds against ambiguous.
+func warnWorkConflicts(mgr *overlay.Manager) error {
+	conflicts, err := mgr.WorkConflicts()
+	if err != nil {
+		return err
+	}
+	for _, c := range conflicts {
+		paths := append([]string(nil), c.Paths...)
+		sort.Strings(paths)
+		ui.Warning("go.work uses %d overlays for %s: %s; run 'apx sync --prune' to remove the ones apx.lock no longer pins",
+			len(paths), c.APIID, strings.Join(paths, ", "))
+	}
+	return nil
+}
+
+// displayPath returns path relative to the working directory when it is
+// below it.
+func displayPath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
@@ -17,6 +17,8 @@ func newUnlinkCmd() *cobra.Command {
 		Long: `Remove the local overlay for a module and update go.mod to use the released version.
 
 This transitions from local development mode (overlay) to consuming the released module.
+Overlays activated by 'apx sync' (pip editable installs, npm/yarn/pnpm workspace
+packages) are deactivated before they are removed.
 
 Examples:
   apx unlink proto/payments/ledger/v1
@@ -39,6 +41,12 @@ func unlinkAction(cmd *cobra.Command, args []string) error {
 
 	mgr := overlay.NewManager(".")
 
+	// Deactivate the overlays in their package managers before removing them.
+	if err := unlinkOverlays(mgr, modulePath); err != nil {
+		ui.Error("Failed to list overlays: %v", err)
+		return err
+	}
+
 	if err := mgr.Remove(modulePath); err != nil {
 		ui.Error("Failed to remove overlay: %v", err)
 		return err
@@ -50,6 +58,30 @@ func unlinkAction(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// unlinkOverlays runs the Unlinker of each language with an overlay of
+// modulePath. Failures are warnings: the overlay is removed regardless.
+func unlinkOverlays(mgr *overlay.Manager, modulePath string) error {
+	overlays, err := mgr.List()
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, ov := range overlays {
+		if ov.ModulePath != modulePath || seen[ov.Language] {
+			continue
+		}
+		seen[ov.Language] = true
+		unlinker, ok := language.Get(ov.Language).(language.Unlinker)
+		if !ok {
+			continue
+		}
+		if err := unlinker.Unlink(".", modulePath); err != nil {
+			ui.Warning("%s: %v", ov.Language, err)
+		}
+	}
+	return nil
+}
+
 func printUnlinkHints(modulePath string) {
 	api, err := config.ParseAPIID(modulePath)
 	if err != nil {
@@ -40,8 +40,13 @@ func (g *angularGenerator) ToolchainAvailable() (bool, string) {
 }
 
 func (g *angularGenerator) Generate(ctx context.Context, gc GenerateContext) (Result, error) {
-	// Preflight: the generator shells out to npx.
-	if _, err := exec.LookPath("npx"); err != nil {
+	// Preflight: without a pinned ng-openapi-gen, the generator shells out
+	// to npx.
+	bin, err := resolveTool(gc, "ng-openapi-gen", ngOpenAPIGenVersion)
+	if err != nil {
+		return Result{}, err
+	}
+	if _, err := exec.LookPath("npx"); err != nil && bin == "" {
 		return Result{}, fmt.Errorf("Node.js/npx required for the typescript-angular client generator; install Node >=18: %w", err)
 	}
 
@@ -61,12 +66,15 @@ func (g *angularGenerator) Generate(ctx context.Context, gc GenerateContext) (Re
 	// Run the generator. `--index-file true` emits an index.ts barrel that
 	// re-exports the generated services, models, and functions.
 	ui.Info("Running ng-openapi-gen@%s ...", ngOpenAPIGenVersion)
-	pkgArg := fmt.Sprintf("ng-openapi-gen@%s", ngOpenAPIGenVersion)
-	cmd := exec.CommandContext(ctx, "npx", "--yes", pkgArg,
+	args := []string{
 		"--input", specAbs,
 		"--output", srcDir,
 		"--index-file", "true",
-	)
+	}
+	cmd := exec.CommandContext(ctx, "npx", append([]string{"--yes", "ng-openapi-gen@" + ngOpenAPIGenVersion}, args...)...)
+	if bin != "" {
+		cmd = exec.CommandContext(ctx, bin, args...)
+	}
 	cmd.Env = os.Environ()
 	out, err := cmd.CombinedOutput()
 	if err != nil {
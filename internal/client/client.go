@@ -10,7 +10,12 @@
 // command layer. This mirrors internal/language's plugin registry.
 package client
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
 
 // GenerateContext carries the inputs a Generator needs to produce a client
 // package for a single spec.
@@ -35,6 +40,30 @@ type GenerateContext struct {
 	// PackageVersion is the semantic version stamped into the package
 	// (e.g. "0.0.1"). Defaults to "0.0.0" when empty.
 	PackageVersion string
+
+	// Tools, when set, locates the pinned generator tool (e.g. from an
+	// offline toolchain bundle). A generator runs the tool it finds there,
+	// and otherwise falls back to fetching it with go run or npx.
+	Tools ToolResolver
+}
+
+// ToolResolver locates the binary of a pinned tool version. It is
+// implemented by validator.ToolchainResolver.
+type ToolResolver interface {
+	ResolveTool(name, version string) (string, error)
+}
+
+// resolveTool returns the binary gc.Tools finds for a tool, or "" when
+// there is no resolver or it does not have the tool.
+func resolveTool(gc GenerateContext, name, version string) (string, error) {
+	if gc.Tools == nil {
+		return "", nil
+	}
+	path, err := gc.Tools.ResolveTool(name, version)
+	if errors.Is(err, validator.ErrToolNotFound) {
+		return "", nil
+	}
+	return path, err
 }
 
 // Result reports what a Generator produced, enough to tell the user where the
@@ -82,6 +82,13 @@ func (g *goGenerator) Generate(ctx context.Context, gc GenerateContext) (Result,
 	ui.Info("Running oapi-codegen@%s ...", oapiCodegenVersion)
 	pkgArg := oapiCodegenModule + "@" + oapiCodegenVersion
 	cmd := exec.CommandContext(ctx, "go", "run", pkgArg, "-config", cfgPath, specAbs)
+	bin, err := resolveTool(gc, "oapi-codegen", oapiCodegenVersion)
+	if err != nil {
+		return Result{}, err
+	}
+	if bin != "" {
+		cmd = exec.CommandContext(ctx, bin, "-config", cfgPath, specAbs)
+	}
 	cmd.Env = os.Environ()
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -2,12 +2,16 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/infobloxopen/apx/internal/publisher"
+	"github.com/infobloxopen/apx/internal/validator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -171,3 +175,50 @@ func looksLikeNetworkErr(err error) bool {
 	}
 	return false
 }
+
+// fakeTools resolves every tool to one binary, or fails with err.
+type fakeTools struct {
+	path string
+	err  error
+}
+
+func (f fakeTools) ResolveTool(string, string) (string, error) { return f.path, f.err }
+
+// TestGoGeneratorUsesResolvedTool: a pinned oapi-codegen (e.g. from an offline
+// toolchain bundle) runs directly instead of through `go run`.
+func TestGoGeneratorUsesResolvedTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tool is a shell script")
+	}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "oapi-codegen")
+	argsFile := filepath.Join(dir, "args.txt")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0o755))
+
+	out := t.TempDir()
+	_, err := (&goGenerator{}).Generate(context.Background(), GenerateContext{
+		SpecPath:    goClientFixture(t),
+		OutputDir:   out,
+		PackageName: "github.com/acme/notes-client",
+		Tools:       fakeTools{path: bin},
+	})
+	require.NoError(t, err)
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(args), "-config "+filepath.Join(out, ".oapi-codegen.yaml")), string(args))
+
+	// A resolver failure other than "not found" (e.g. a checksum mismatch) is
+	// not papered over by falling back to `go run`.
+	_, err = (&goGenerator{}).Generate(context.Background(), GenerateContext{
+		SpecPath:    goClientFixture(t),
+		OutputDir:   t.TempDir(),
+		PackageName: "github.com/acme/notes-client",
+		Tools:       fakeTools{err: errors.New("checksum mismatch for oapi-codegen")},
+	})
+	require.ErrorContains(t, err, "checksum mismatch")
+
+	path, err := resolveTool(GenerateContext{Tools: fakeTools{err: fmt.Errorf("%w: oapi-codegen", validator.ErrToolNotFound)}}, "oapi-codegen", "v2.4.1")
+	require.NoError(t, err)
+	assert.Empty(t, path, "a missing tool falls back to go run")
+}
@@ -46,6 +46,8 @@ type VerifyOptions struct {
 	// WorkDir is the parent directory for throwaway per-generator output. When
 	// empty, Verify uses a temp dir it removes before returning.
 	WorkDir string
+	// Tools locates the pinned generator tools; see GenerateContext.Tools.
+	Tools ToolResolver
 }
 
 // GeneratorResult is the outcome of verifying a single generator.
@@ -133,6 +135,7 @@ func Verify(ctx context.Context, opts VerifyOptions) (VerifyReport, error) {
 			OutputDir:   filepath.Join(base, name),
 			PackageName: pkg,
 			Scope:       opts.Scope,
+			Tools:       opts.Tools,
 		}))
 	}
 	return report, nil
@@ -0,0 +1,185 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// goInitialisms are the words Go names spell in capitals.
+var goInitialisms = map[string]bool{
+	"Api": true, "Http": true, "Id": true, "Ip": true, "Json": true,
+	"Sql": true, "Uri": true, "Url": true, "Uuid": true,
+}
+
+// emitGo renders the model as Go structs with json tags, and avro or
+// parquet tags for those formats, in the package of the canonical import
+// path. The file is placed at the import path's directory within the
+// module, so it resolves through go.work like generated protobuf code.
+func emitGo(m *Model, req ModelRequest) (map[string][]byte, error) {
+	if req.Coords.Import == "" {
+		return nil, fmt.Errorf("no Go import path")
+	}
+	dir := strings.TrimPrefix(strings.TrimPrefix(req.Coords.Import, req.Coords.Module), "/")
+	pkg := goPackageName(req.Coords.Import)
+
+	var body strings.Builder
+	usesTime := false
+	for _, e := range m.Enums {
+		writeGoComment(&body, e.Name, e.Comment, "")
+		fmt.Fprintf(&body, "type %s string\n\n", e.Name)
+		if len(e.Values) > 0 {
+			body.WriteString("const (\n")
+			names := make([]string, len(e.Values))
+			for i, v := range e.Values {
+				names[i] = e.Name + goName(v)
+			}
+			for i, n := range uniqueNames(names) {
+				fmt.Fprintf(&body, "\t%s %s = %q\n", n, e.Name, e.Values[i])
+			}
+			body.WriteString(")\n\n")
+		}
+	}
+	for _, msg := range m.Messages {
+		writeGoComment(&body, msg.Name, msg.Comment, "")
+		fmt.Fprintf(&body, "type %s struct {\n", msg.Name)
+		names := make([]string, len(msg.Fields))
+		for i, f := range msg.Fields {
+			names[i] = goName(f.Name)
+		}
+		for i, n := range uniqueNames(names) {
+			f := msg.Fields[i]
+			if f.Comment != "" {
+				writeGoComment(&body, n, f.Comment, "\t")
+			}
+			typ := goType(f.Type)
+			usesTime = usesTime || strings.Contains(typ, "time.")
+			if f.Optional && goNeedsPointer(f.Type) {
+				typ = "*" + typ
+			}
+			fmt.Fprintf(&body, "\t%s %s `%s`\n", n, typ, goTags(m.Format, f))
+		}
+		body.WriteString("}\n\n")
+	}
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "// %s\n// Source: %s\n\npackage %s\n\n", generatedHeader, req.APIID, pkg)
+	if usesTime {
+		src.WriteString("import \"time\"\n\n")
+	}
+	src.WriteString(body.String())
+	out, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting Go source: %w", err)
+	}
+	return map[string][]byte{path.Join(dir, "models.apx.go"): out}, nil
+}
+
+// goPackageName is the package name Go derives from an import path: its
+// last element, as protoc-gen-go does for a go_package without a name.
+func goPackageName(importPath string) string {
+	base := path.Base(importPath)
+	var sb strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+	name := sb.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "models" + name
+	}
+	return name
+}
+
+// goName renders a schema name as an exported Go identifier.
+func goName(s string) string {
+	var sb strings.Builder
+	for _, w := range words(s) {
+		r := []rune(strings.ToLower(w))
+		word := strings.ToUpper(string(r[0])) + string(r[1:])
+		if goInitialisms[word] {
+			word = strings.ToUpper(word)
+		}
+		sb.WriteString(word)
+	}
+	name := sb.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	return name
+}
+
+// goType renders a type in Go.
+func goType(t Type) string {
+	switch t.Kind {
+	case KindString:
+		return "string"
+	case KindBool:
+		return "bool"
+	case KindInt32:
+		return "int32"
+	case KindInt64:
+		return "int64"
+	case KindFloat32:
+		return "float32"
+	case KindFloat64:
+		return "float64"
+	case KindBytes:
+		return "[]byte"
+	case KindTimestamp, KindDate:
+		return "time.Time"
+	case KindNamed:
+		return t.Name
+	case KindList:
+		return "[]" + goType(*t.Elem)
+	case KindMap:
+		return "map[string]" + goType(*t.Elem)
+	}
+	return "any"
+}
+
+// goNeedsPointer reports whether an optional field of the type needs a
+// pointer to tell absence from the zero value.
+func goNeedsPointer(t Type) bool {
+	switch t.Kind {
+	case KindAny, KindBytes, KindList, KindMap:
+		return false
+	}
+	return true
+}
+
+// goTags renders the struct tags of a field.
+func goTags(f validator.SchemaFormat, field Field) string {
+	tags := []string{fmt.Sprintf(`json:"%s"`, field.Name)}
+	if field.Optional {
+		tags[0] = fmt.Sprintf(`json:"%s,omitempty"`, field.Name)
+	}
+	switch f {
+	case validator.FormatAvro:
+		tags = append(tags, fmt.Sprintf(`avro:"%s"`, field.Name))
+	case validator.FormatParquet:
+		if field.Optional {
+			tags = append(tags, fmt.Sprintf(`parquet:"%s,optional"`, field.Name))
+		} else {
+			tags = append(tags, fmt.Sprintf(`parquet:"%s"`, field.Name))
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// writeGoComment writes a doc comment for a declaration.
+func writeGoComment(sb *strings.Builder, name, comment, indent string) {
+	if comment == "" {
+		return
+	}
+	for i, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		if i == 0 && !strings.HasPrefix(line, name+" ") {
+			line = name + ": " + line
+		}
+		fmt.Fprintf(sb, "%s// %s\n", indent, strings.TrimRight(line, " \t"))
+	}
+}
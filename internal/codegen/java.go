@@ -0,0 +1,126 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// javaKeywords are the reserved words Java identifiers must avoid.
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "default": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true, "final": true,
+	"finally": true, "float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true, "record": true,
+	"return": true, "short": true, "static": true, "strictfp": true, "super": true, "switch": true,
+	"synchronized": true, "this": true, "throw": true, "throws": true, "transient": true, "try": true,
+	"void": true, "volatile": true, "while": true, "var": true, "yield": true,
+}
+
+// emitJava renders the model as Java records and enums, one source file per
+// type under src/main/java in the package of the canonical coordinates.
+func emitJava(m *Model, req ModelRequest) (map[string][]byte, error) {
+	pkg := req.Coords.Import
+	if pkg == "" {
+		return nil, fmt.Errorf("no Java package")
+	}
+	dir := "src/main/java/" + strings.ReplaceAll(pkg, ".", "/")
+	header := fmt.Sprintf("// %s\n// Source: %s\npackage %s;\n\n", generatedHeader, req.APIID, pkg)
+
+	files := map[string][]byte{}
+	for _, e := range m.Enums {
+		var sb strings.Builder
+		sb.WriteString(header)
+		writeJavaDoc(&sb, e.Comment, "")
+		fmt.Fprintf(&sb, "public enum %s {\n", e.Name)
+		names := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			names[i] = strings.ToUpper(pythonName(v))
+		}
+		for i, n := range uniqueNames(names) {
+			sep := ","
+			if i == len(names)-1 {
+				sep = ";"
+			}
+			fmt.Fprintf(&sb, "    %s%s\n", n, sep)
+		}
+		sb.WriteString("}\n")
+		files[dir+"/"+e.Name+".java"] = []byte(sb.String())
+	}
+	for _, msg := range m.Messages {
+		var sb strings.Builder
+		sb.WriteString(header)
+		writeJavaDoc(&sb, msg.Comment, "")
+		names := make([]string, len(msg.Fields))
+		for i, f := range msg.Fields {
+			names[i] = javaName(f.Name)
+		}
+		names = uniqueNames(names)
+		params := make([]string, len(msg.Fields))
+		for i, f := range msg.Fields {
+			params[i] = fmt.Sprintf("    %s %s", javaType(f.Type), names[i])
+		}
+		if len(params) == 0 {
+			fmt.Fprintf(&sb, "public record %s() {\n}\n", msg.Name)
+		} else {
+			fmt.Fprintf(&sb, "public record %s(\n%s) {\n}\n", msg.Name, strings.Join(params, ",\n"))
+		}
+		files[dir+"/"+msg.Name+".java"] = []byte(sb.String())
+	}
+	return files, nil
+}
+
+// javaName renders a schema name as a camelCase Java identifier.
+func javaName(s string) string {
+	name := camelName(s)
+	if javaKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// javaType renders a type in Java. Record components are boxed, so an
+// absent optional value is null.
+func javaType(t Type) string {
+	switch t.Kind {
+	case KindString:
+		return "String"
+	case KindBool:
+		return "Boolean"
+	case KindInt32:
+		return "Integer"
+	case KindInt64:
+		return "Long"
+	case KindFloat32:
+		return "Float"
+	case KindFloat64:
+		return "Double"
+	case KindBytes:
+		return "byte[]"
+	case KindTimestamp:
+		return "java.time.Instant"
+	case KindDate:
+		return "java.time.LocalDate"
+	case KindNamed:
+		return t.Name
+	case KindList:
+		return "java.util.List<" + javaType(*t.Elem) + ">"
+	case KindMap:
+		return "java.util.Map<String, " + javaType(*t.Elem) + ">"
+	}
+	return "Object"
+}
+
+// writeJavaDoc writes a Javadoc comment.
+func writeJavaDoc(sb *strings.Builder, comment, indent string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.ReplaceAll(strings.TrimSpace(comment), "*/", "* /")
+	fmt.Fprintf(sb, "%s/**\n", indent)
+	for _, l := range strings.Split(comment, "\n") {
+		fmt.Fprintf(sb, "%s * %s\n", indent, strings.TrimRight(l, " \t"))
+	}
+	fmt.Fprintf(sb, "%s */\n", indent)
+}
@@ -0,0 +1,292 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the generation manifest apx gen writes at the
+// root of each overlay it generates.
+const ManifestFile = ".apx-gen.yaml"
+
+// manifestVersion is the current generation manifest schema version.
+const manifestVersion = 1
+
+// Manifest records what an overlay was generated from and what the
+// generation wrote. An overlay whose manifest matches the manifest a fresh
+// generation would record, and whose outputs are unchanged, is up to date.
+type Manifest struct {
+	Version    int               `yaml:"version"`
+	API        string            `yaml:"api"`
+	Language   string            `yaml:"language"`
+	Ref        string            `yaml:"ref,omitempty"`
+	Inputs     string            `yaml:"inputs"` // content hash of the schema files
+	Module     string            `yaml:"module,omitempty"`
+	Import     string            `yaml:"import,omitempty"`
+	Plugins    []ManifestPlugin  `yaml:"plugins,omitempty"`
+	APXVersion string            `yaml:"apx_version"`
+	Outputs    map[string]string `yaml:"outputs,omitempty"` // overlay-relative path -> content hash
+}
+
+// ManifestPlugin is a generator plugin recorded in a manifest.
+type ManifestPlugin struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Opt     string `yaml:"opt,omitempty"`
+}
+
+// NewManifest returns the manifest of a generation run, without outputs.
+func NewManifest(apiID, lang, ref, inputs, apxVersion string, coords config.LanguageCoords, plugins []Plugin) *Manifest {
+	m := &Manifest{
+		Version:    manifestVersion,
+		API:        apiID,
+		Language:   lang,
+		Ref:        ref,
+		Inputs:     inputs,
+		Module:     coords.Module,
+		Import:     coords.Import,
+		APXVersion: apxVersion,
+	}
+	for _, p := range plugins {
+		m.Plugins = append(m.Plugins, ManifestPlugin{Name: p.Name, Version: p.Version, Opt: p.Opt})
+	}
+	return m
+}
+
+// ReadManifest reads the generation manifest of an overlay. It returns nil
+// without an error when the overlay has none.
+func ReadManifest(overlayPath string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(overlayPath, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(overlayPath, ManifestFile), err)
+	}
+	for _, rel := range sortedKeys(m.Outputs) {
+		if _, ok := outputPath(overlayPath, rel); !ok {
+			return nil, fmt.Errorf("%s: output %q is outside the overlay", filepath.Join(overlayPath, ManifestFile), rel)
+		}
+	}
+	return &m, nil
+}
+
+// outputPath returns the file of the overlay-relative output rel. It
+// reports false for paths that would leave the overlay, such as absolute
+// paths or paths through "..", so a tampered manifest cannot make apx read
+// or delete files elsewhere.
+func outputPath(overlayPath, rel string) (string, bool) {
+	local := filepath.FromSlash(rel)
+	if !filepath.IsLocal(local) {
+		return "", false
+	}
+	return filepath.Join(overlayPath, local), true
+}
+
+// Snapshot records the files of an overlay before generation, so that
+// WriteManifest can tell the files a generation wrote from those it found.
+type Snapshot map[string]time.Time
+
+// TakeSnapshot records the files of an overlay and their modification
+// times. An overlay that does not exist yet has an empty snapshot.
+func TakeSnapshot(overlayPath string) (Snapshot, error) {
+	snap := Snapshot{}
+	err := walkFiles(overlayPath, skipOutputDir, func(rel, path string) error {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		snap[rel] = fi.ModTime()
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading overlay %s: %w", overlayPath, err)
+	}
+	return snap, nil
+}
+
+// WriteManifest records the files generation created or rewrote since the
+// before snapshot as the outputs of m, and writes m to the overlay. Files
+// the overlay already held, such as hand-written additions, are not
+// outputs: they are neither checked nor removed on regeneration.
+func WriteManifest(overlayPath string, m *Manifest, before Snapshot) error {
+	outputs := map[string]string{}
+	err := walkFiles(overlayPath, skipOutputDir, func(rel, path string) error {
+		if rel == ManifestFile {
+			return nil
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if mtime, ok := before[rel]; ok && mtime.Equal(fi.ModTime()) {
+			return nil
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		outputs[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("hashing outputs in %s: %w", overlayPath, err)
+	}
+	m.Outputs = outputs
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(overlayPath, ManifestFile), data, 0644)
+}
+
+// Stale reports why an overlay generated as m must be regenerated to match
+// want, or "" when it is up to date. A nil m, an overlay without a manifest,
+// is always stale. Outputs that were modified or removed since generation
+// make the overlay stale; files added next to them, such as build artifacts,
+// do not.
+func (m *Manifest) Stale(want *Manifest, overlayPath string) string {
+	switch {
+	case m == nil:
+		return "no generation manifest"
+	case m.Version != want.Version:
+		return "manifest version changed"
+	case m.Ref != want.Ref:
+		return fmt.Sprintf("ref changed from %s to %s", m.Ref, want.Ref)
+	case m.Inputs != want.Inputs:
+		return "schema inputs changed"
+	case m.Module != want.Module || m.Import != want.Import:
+		return "coordinates changed"
+	case !samePlugins(m.Plugins, want.Plugins):
+		return "plugins changed"
+	case m.APXVersion != want.APXVersion:
+		return fmt.Sprintf("apx version changed from %s", m.APXVersion)
+	}
+	for _, rel := range sortedKeys(m.Outputs) {
+		path, ok := outputPath(overlayPath, rel)
+		if !ok {
+			continue
+		}
+		got, err := hashFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return rel + " was removed"
+		}
+		if err != nil || got != m.Outputs[rel] {
+			return rel + " was modified"
+		}
+	}
+	return ""
+}
+
+// RemoveOutputs deletes the files m recorded as outputs, so types dropped
+// from the schema do not survive regeneration. Other files are kept.
+func (m *Manifest) RemoveOutputs(overlayPath string) error {
+	if m == nil {
+		return nil
+	}
+	for rel := range m.Outputs {
+		path, ok := outputPath(overlayPath, rel)
+		if !ok {
+			continue
+		}
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// HashInputs returns the content hash of the schema files under dir. Files
+// are hashed in path order, each with its relative path, so renames change
+// the hash too.
+func HashInputs(dir string) (string, error) {
+	h := sha256.New()
+	err := walkFiles(dir, nil, func(rel, path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "file:%s\n", rel)
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashing schema inputs in %s: %w", dir, err)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// skipOutputDir reports directories of an overlay that hold build and
+// install artifacts rather than generated code.
+func skipOutputDir(name string) bool {
+	return name == "node_modules" || name == "__pycache__" || name == ".git" || strings.HasSuffix(name, ".egg-info")
+}
+
+// walkFiles calls fn for each regular file under root in lexical order,
+// with its slash-separated path relative to root. Directories skipDir
+// reports are not descended into.
+func walkFiles(root string, skipDir func(name string) bool, fn func(rel, path string) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDir != nil && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), path)
+	})
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+func samePlugins(a, b []ManifestPlugin) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
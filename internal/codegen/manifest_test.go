@@ -0,0 +1,134 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestHashInputs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.proto": "a", "sub/b.proto": "b"})
+	first, err := HashInputs(dir)
+	require.NoError(t, err)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, first)
+
+	again, err := HashInputs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	require.NoError(t, os.Rename(filepath.Join(dir, "a.proto"), filepath.Join(dir, "c.proto")))
+	renamed, err := HashInputs(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, renamed, "renames change the hash")
+}
+
+func TestWriteManifest_RecordsOnlyGeneratedFiles(t *testing.T) {
+	overlay := filepath.Join(t.TempDir(), "overlay")
+	writeFiles(t, overlay, map[string]string{"notes.md": "hand-written"})
+	before, err := TakeSnapshot(overlay)
+	require.NoError(t, err)
+
+	writeFiles(t, overlay, map[string]string{
+		"go.mod":                     "module example.com/x\n",
+		"v1/models.apx.go":           "package v1\n",
+		"x.egg-info/PKG-INFO":        "build artifact",
+		"v1/__pycache__/m.cpython-3": "bytecode",
+	})
+	m := NewManifest("avro/x/y/v1", "go", "v1.0.0", "sha256:in", "apx dev", config.LanguageCoords{Module: "example.com/x"}, nil)
+	require.NoError(t, WriteManifest(overlay, m, before))
+
+	read, err := ReadManifest(overlay)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "v1/models.apx.go"}, sortedKeys(read.Outputs))
+	assert.Equal(t, "example.com/x", read.Module)
+	assert.Empty(t, read.Stale(m, overlay))
+}
+
+func TestReadManifest_Missing(t *testing.T) {
+	m, err := ReadManifest(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, m)
+	assert.Equal(t, "no generation manifest", m.Stale(&Manifest{}, t.TempDir()))
+}
+
+func TestManifestStale(t *testing.T) {
+	plugins := []Plugin{{Name: "protoc-gen-go", Version: "v1.64.0"}}
+	base := func() *Manifest {
+		return NewManifest("proto/a/b/v1", "go", "v1.0.0", "sha256:in", "apx 1.0", config.LanguageCoords{}, plugins)
+	}
+	tests := []struct {
+		name   string
+		change func(m *Manifest)
+		want   string
+	}{
+		{"ref", func(m *Manifest) { m.Ref = "v1.1.0" }, "ref changed from v1.0.0 to v1.1.0"},
+		{"inputs", func(m *Manifest) { m.Inputs = "sha256:other" }, "schema inputs changed"},
+		{"coordinates", func(m *Manifest) { m.Import = "example.com/b/v1" }, "coordinates changed"},
+		{"plugin version", func(m *Manifest) { m.Plugins[0].Version = "v1.65.0" }, "plugins changed"},
+		{"plugin opt", func(m *Manifest) { m.Plugins[0].Opt = "paths=source_relative" }, "plugins changed"},
+		{"apx version", func(m *Manifest) { m.APXVersion = "apx 1.1" }, "apx version changed from apx 1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := base()
+			tt.change(want)
+			assert.Equal(t, tt.want, base().Stale(want, t.TempDir()))
+		})
+	}
+}
+
+func TestManifestStale_Outputs(t *testing.T) {
+	overlay := t.TempDir()
+	before, err := TakeSnapshot(overlay)
+	require.NoError(t, err)
+	writeFiles(t, overlay, map[string]string{"a.go": "a", "b.go": "b"})
+	m := NewManifest("avro/x/y/v1", "go", "", "sha256:in", "apx dev", config.LanguageCoords{}, nil)
+	require.NoError(t, WriteManifest(overlay, m, before))
+
+	writeFiles(t, overlay, map[string]string{"extra.go": "not an output"})
+	assert.Empty(t, m.Stale(m, overlay), "added files do not make an overlay stale")
+
+	writeFiles(t, overlay, map[string]string{"a.go": "edited"})
+	assert.Equal(t, "a.go was modified", m.Stale(m, overlay))
+
+	require.NoError(t, os.Remove(filepath.Join(overlay, "a.go")))
+	assert.Equal(t, "a.go was removed", m.Stale(m, overlay))
+
+	require.NoError(t, m.RemoveOutputs(overlay))
+	assert.NoFileExists(t, filepath.Join(overlay, "b.go"))
+	assert.FileExists(t, filepath.Join(overlay, "extra.go"))
+}
+
+func TestManifest_OutputsOutsideOverlay(t *testing.T) {
+	root := t.TempDir()
+	overlay := filepath.Join(root, "overlay")
+	writeFiles(t, root, map[string]string{"victim.txt": "keep", "overlay/a.go": "a"})
+
+	writeFiles(t, overlay, map[string]string{ManifestFile: "version: 1\noutputs:\n  ../victim.txt: sha256:x\n"})
+	_, err := ReadManifest(overlay)
+	assert.ErrorContains(t, err, "outside the overlay")
+
+	m := &Manifest{Outputs: map[string]string{
+		"../victim.txt": "sha256:x",
+		filepath.ToSlash(filepath.Join(root, "victim.txt")): "sha256:x",
+		"a.go": "sha256:x",
+	}}
+	assert.Equal(t, "a.go was modified", m.Stale(m, overlay), "non-local outputs are skipped")
+	require.NoError(t, m.RemoveOutputs(overlay))
+	assert.FileExists(t, filepath.Join(root, "victim.txt"))
+	assert.NoFileExists(t, filepath.Join(overlay, "a.go"))
+}
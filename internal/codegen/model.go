@@ -0,0 +1,490 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// TypeKind classifies a field type of the native model.
+type TypeKind int
+
+const (
+	KindAny       TypeKind = iota // no native equivalent: unions, untyped schemas
+	KindString                    // strings, UUIDs, decimals
+	KindBool                      // booleans
+	KindInt32                     // 32-bit integers
+	KindInt64                     // 64-bit integers
+	KindFloat32                   // single precision floats
+	KindFloat64                   // double precision floats
+	KindBytes                     // byte strings and fixed-length binaries
+	KindTimestamp                 // instants: date-time, timestamp-millis, TIMESTAMP
+	KindDate                      // calendar dates
+	KindNamed                     // a message or enum of the model
+	KindList                      // arrays and repeated fields
+	KindMap                       // string-keyed maps
+)
+
+// Type is a field type of the native model.
+type Type struct {
+	Kind TypeKind
+	Name string // type name, for KindNamed
+	Elem *Type  // element type, for KindList and KindMap
+}
+
+// Field is a field of a message.
+type Field struct {
+	Name     string // as declared in the schema, and as serialized
+	Comment  string
+	Type     Type
+	Optional bool // may be absent or null
+}
+
+// Message is a record type: an Avro record, a JSON Schema object, a Parquet
+// message or group, or an OpenAPI component schema.
+type Message struct {
+	Name    string // type name, unique within the model
+	Comment string
+	Fields  []Field
+}
+
+// Enum is a string enumeration: an Avro enum or a JSON Schema string enum.
+type Enum struct {
+	Name    string
+	Comment string
+	Values  []string
+}
+
+// Model is the format-neutral set of types that native code is generated
+// from.
+type Model struct {
+	Format   validator.SchemaFormat
+	Messages []*Message
+	Enums    []*Enum
+}
+
+// Empty reports whether the model declares no types.
+func (m *Model) Empty() bool {
+	return len(m.Messages) == 0 && len(m.Enums) == 0
+}
+
+// LoadModel reads the schema files of format under dir into a model. It
+// builds on the element model that policy rules are evaluated over: the
+// messages and enums of every file become types, and the fields of each
+// message are mapped onto native types. Types are named after their
+// declaration; a type declared again under the same name is read once.
+func LoadModel(dir string, format validator.SchemaFormat) (*Model, error) {
+	elems, err := validator.LoadSchemaModel(dir, format)
+	if err != nil {
+		return nil, err
+	}
+	b := &modelBuilder{
+		model:    &Model{Format: format},
+		names:    map[string]string{},
+		messages: map[string]*Message{},
+	}
+	for _, e := range elems {
+		b.declare(e)
+	}
+	for _, e := range elems {
+		if e.Kind == validator.ElementField {
+			b.field(e)
+		}
+	}
+	return b.model, nil
+}
+
+// modelBuilder maps schema elements onto a model.
+type modelBuilder struct {
+	model *Model
+	// names maps the full name of each declared type, and the short name of
+	// an Avro type, to its type name.
+	names map[string]string
+	// messages maps the full name of a message element to its message, or
+	// to nil when it repeats an earlier declaration.
+	messages map[string]*Message
+	enum     *Enum // enum whose values are being read
+}
+
+// declare adds the type an element declares.
+func (b *modelBuilder) declare(e validator.Element) {
+	switch e.Kind {
+	case validator.ElementMessage, validator.ElementEnum:
+	case validator.ElementEnumValue:
+		if b.enum != nil && b.names[e.Parent] == b.enum.Name {
+			b.enum.Values = append(b.enum.Values, e.Name)
+		}
+		return
+	default:
+		return
+	}
+	name := b.typeName(e)
+	_, seen := b.names[e.FullName]
+	dup := seen || b.declared(name)
+	if !seen {
+		b.names[e.FullName] = name
+		if e.Format == validator.FormatAvro {
+			if _, ok := b.names[e.Name]; !ok {
+				b.names[e.Name] = name
+			}
+		}
+	}
+	b.enum = nil
+	if dup {
+		if e.Kind == validator.ElementMessage {
+			b.messages[e.FullName] = nil
+		}
+		return
+	}
+	if e.Kind == validator.ElementEnum {
+		b.enum = &Enum{Name: name, Comment: e.Comment}
+		b.model.Enums = append(b.model.Enums, b.enum)
+		return
+	}
+	msg := &Message{Name: name, Comment: e.Comment}
+	b.messages[e.FullName] = msg
+	b.model.Messages = append(b.model.Messages, msg)
+}
+
+// declared reports whether a type of the given name was added.
+func (b *modelBuilder) declared(name string) bool {
+	for _, m := range b.model.Messages {
+		if m.Name == name {
+			return true
+		}
+	}
+	for _, en := range b.model.Enums {
+		if en.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName names the type of a message or enum element. Avro types keep
+// their name; the nested types of other formats are named after their path,
+// e.g. the inline object of User.address becomes UserAddress.
+func (b *modelBuilder) typeName(e validator.Element) string {
+	if e.Format == validator.FormatAvro {
+		return exportedName(e.Name)
+	}
+	full := strings.ReplaceAll(e.FullName, "[]", ".item")
+	var sb strings.Builder
+	for _, part := range strings.Split(full, ".") {
+		sb.WriteString(exportedName(part))
+	}
+	return sb.String()
+}
+
+// field adds a field element to its message.
+func (b *modelBuilder) field(e validator.Element) {
+	msg, ok := b.messages[e.Parent]
+	if !ok || msg == nil {
+		return // a parameter, or a field of a repeated declaration
+	}
+	f := Field{Name: e.Name, Comment: e.Comment}
+	switch e.Format {
+	case validator.FormatAvro:
+		f.Type, f.Optional = b.avroType(e.Type)
+	case validator.FormatParquet:
+		f.Type = b.parquetType(e)
+		f.Optional = e.Nullable
+	default:
+		schema, _ := e.Attrs["schema"].(map[string]interface{})
+		var nullable bool
+		f.Type, nullable = b.schemaType(schema, e.FullName)
+		f.Optional = nullable || e.Nullable || !e.Required
+	}
+	msg.Fields = append(msg.Fields, f)
+}
+
+// named resolves a reference to a declared type.
+func (b *modelBuilder) named(ref string) (Type, bool) {
+	if name, ok := b.names[ref]; ok {
+		return Type{Kind: KindNamed, Name: name}, true
+	}
+	return Type{}, false
+}
+
+// avroType maps an Avro type label of the element model ("long",
+// "[null, string]", "array<Item>", "long(timestamp-millis)") onto a type,
+// reporting whether it is nullable.
+func (b *modelBuilder) avroType(label string) (Type, bool) {
+	label = strings.TrimSpace(label)
+	if strings.HasPrefix(label, "[") && strings.HasSuffix(label, "]") {
+		var branches []string
+		nullable := false
+		for _, part := range splitTopLevel(label[1 : len(label)-1]) {
+			if part == "null" {
+				nullable = true
+			} else {
+				branches = append(branches, part)
+			}
+		}
+		if len(branches) != 1 {
+			return Type{Kind: KindAny}, nullable
+		}
+		t, _ := b.avroType(branches[0])
+		return t, nullable
+	}
+	if inner, ok := cutWrapped(label, "array<"); ok {
+		elem, _ := b.avroType(inner)
+		return Type{Kind: KindList, Elem: &elem}, false
+	}
+	if inner, ok := cutWrapped(label, "map<"); ok {
+		elem, _ := b.avroType(inner)
+		return Type{Kind: KindMap, Elem: &elem}, false
+	}
+	base, logical := label, ""
+	if i := strings.Index(label, "("); i > 0 && strings.HasSuffix(label, ")") {
+		base, logical = label[:i], label[i+1:len(label)-1]
+	}
+	switch {
+	case strings.Contains(logical, "timestamp"):
+		return Type{Kind: KindTimestamp}, false
+	case logical == "date":
+		return Type{Kind: KindDate}, false
+	case logical == "decimal" || logical == "uuid":
+		return Type{Kind: KindString}, false
+	}
+	switch base {
+	case "null":
+		return Type{Kind: KindAny}, true
+	case "boolean":
+		return Type{Kind: KindBool}, false
+	case "int":
+		return Type{Kind: KindInt32}, false
+	case "long":
+		return Type{Kind: KindInt64}, false
+	case "float":
+		return Type{Kind: KindFloat32}, false
+	case "double":
+		return Type{Kind: KindFloat64}, false
+	case "bytes", "fixed":
+		return Type{Kind: KindBytes}, false
+	case "string":
+		return Type{Kind: KindString}, false
+	}
+	if t, ok := b.named(base); ok {
+		return t, false
+	}
+	// A fixed type, which the element model does not declare.
+	return Type{Kind: KindBytes}, false
+}
+
+// schemaType maps a JSON Schema (or OpenAPI schema) of the element at
+// fullName onto a type, reporting whether it is nullable. An inline object
+// or enum the element model declared at fullName is referenced by name.
+func (b *modelBuilder) schemaType(s map[string]interface{}, fullName string) (Type, bool) {
+	if s == nil {
+		return Type{Kind: KindAny}, false
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		if t, ok := b.named(ref[strings.LastIndex(ref, "/")+1:]); ok {
+			return t, false
+		}
+		return Type{Kind: KindAny}, false
+	}
+	nullable, _ := s["nullable"].(bool)
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, e := range t {
+			if es, ok := e.(string); ok {
+				if es == "null" {
+					nullable = true
+				} else {
+					types = append(types, es)
+				}
+			}
+		}
+	}
+	if t, ok := b.named(fullName); ok {
+		return t, nullable
+	}
+	if len(types) != 1 {
+		return Type{Kind: KindAny}, nullable
+	}
+	format, _ := s["format"].(string)
+	switch types[0] {
+	case "string":
+		switch format {
+		case "date-time":
+			return Type{Kind: KindTimestamp}, nullable
+		case "date":
+			return Type{Kind: KindDate}, nullable
+		case "byte", "binary":
+			return Type{Kind: KindBytes}, nullable
+		}
+		return Type{Kind: KindString}, nullable
+	case "integer":
+		if format == "int32" {
+			return Type{Kind: KindInt32}, nullable
+		}
+		return Type{Kind: KindInt64}, nullable
+	case "number":
+		if format == "float" {
+			return Type{Kind: KindFloat32}, nullable
+		}
+		return Type{Kind: KindFloat64}, nullable
+	case "boolean":
+		return Type{Kind: KindBool}, nullable
+	case "array":
+		items, _ := s["items"].(map[string]interface{})
+		elem, _ := b.schemaType(items, fullName+"[]")
+		return Type{Kind: KindList, Elem: &elem}, nullable
+	case "object":
+		elem := Type{Kind: KindAny}
+		if values, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			elem, _ = b.schemaType(values, fullName+"{}")
+		}
+		return Type{Kind: KindMap, Elem: &elem}, nullable
+	}
+	return Type{Kind: KindAny}, nullable
+}
+
+// parquetType maps a Parquet column onto a type: its logical type
+// annotation, when it has one, or else its physical type.
+func (b *modelBuilder) parquetType(e validator.Element) Type {
+	var t Type
+	annotation, _ := e.Attrs["logical_type"].(string)
+	annotation = strings.ToUpper(annotation)
+	physical := e.Type
+	if i := strings.Index(physical, "("); i > 0 {
+		physical = physical[:i]
+	}
+	switch {
+	case physical == "group":
+		if named, ok := b.named(e.FullName); ok {
+			t = named
+		} else {
+			t = Type{Kind: KindAny}
+		}
+	case strings.HasPrefix(annotation, "STRING"), strings.HasPrefix(annotation, "UTF8"),
+		strings.HasPrefix(annotation, "ENUM"), strings.HasPrefix(annotation, "JSON"),
+		strings.HasPrefix(annotation, "UUID"), strings.HasPrefix(annotation, "DECIMAL"):
+		t = Type{Kind: KindString}
+	case strings.HasPrefix(annotation, "DATE"):
+		t = Type{Kind: KindDate}
+	case strings.HasPrefix(annotation, "TIMESTAMP"):
+		t = Type{Kind: KindTimestamp}
+	default:
+		switch physical {
+		case "boolean":
+			t = Type{Kind: KindBool}
+		case "int32":
+			t = Type{Kind: KindInt32}
+		case "int64":
+			t = Type{Kind: KindInt64}
+		case "int96":
+			t = Type{Kind: KindTimestamp}
+		case "float":
+			t = Type{Kind: KindFloat32}
+		case "double":
+			t = Type{Kind: KindFloat64}
+		default:
+			t = Type{Kind: KindBytes}
+		}
+	}
+	if e.Repeated {
+		return Type{Kind: KindList, Elem: &t}
+	}
+	return t
+}
+
+// splitTopLevel splits a comma-separated list of type labels, leaving
+// commas nested in <> or [] alone.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '<', '[', '(':
+			depth++
+		case '>', ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// cutWrapped returns the inner label of "prefix...>".
+func cutWrapped(label, prefix string) (string, bool) {
+	if strings.HasPrefix(label, prefix) && strings.HasSuffix(label, ">") {
+		return label[len(prefix) : len(label)-1], true
+	}
+	return "", false
+}
+
+// words splits a schema name into its words: at non-alphanumeric runes and
+// at lower-to-upper case changes.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	prevLower := false
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(cur) > 0 {
+				out = append(out, string(cur))
+			}
+			cur, prevLower = nil, false
+			continue
+		case unicode.IsUpper(r) && prevLower:
+			out = append(out, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+		prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+	}
+	if len(cur) > 0 {
+		out = append(out, string(cur))
+	}
+	return out
+}
+
+// exportedName renders a schema name in PascalCase, as a valid identifier
+// in every target language.
+func exportedName(s string) string {
+	var sb strings.Builder
+	for _, w := range words(s) {
+		r := []rune(w)
+		sb.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// camelName renders a schema name in camelCase.
+func camelName(s string) string {
+	name := exportedName(s)
+	r := []rune(name)
+	return strings.ToLower(string(r[0])) + string(r[1:])
+}
+
+// uniqueNames makes each name of a declaration list unique, appending a
+// counter to repeats.
+func uniqueNames(names []string) []string {
+	seen := map[string]int{}
+	out := make([]string, len(names))
+	for i, n := range names {
+		seen[n]++
+		if seen[n] > 1 {
+			n = fmt.Sprintf("%s%d", n, seen[n])
+		}
+		out[i] = n
+	}
+	return out
+}
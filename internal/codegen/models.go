@@ -0,0 +1,80 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// generatedHeader marks every file apx writes from a model.
+const generatedHeader = "Code generated by apx gen. DO NOT EDIT."
+
+// ModelRequest describes one native model generation run.
+type ModelRequest struct {
+	Language  string                 // target language, e.g. "go"
+	Format    validator.SchemaFormat // schema format of the dependency
+	APIID     string                 // dependency API ID, e.g. "avro/payments/events/v1"
+	SchemaDir string                 // directory holding the dependency's schema files
+	Coords    config.LanguageCoords  // canonical coordinates in the target language
+	OutDir    string                 // overlay directory to generate into
+}
+
+// modelEmitter renders a model as source files of one language, keyed by
+// slash-separated path relative to the overlay.
+type modelEmitter func(m *Model, req ModelRequest) (map[string][]byte, error)
+
+// modelEmitters are the languages native models are generated for.
+var modelEmitters = map[string]modelEmitter{
+	"go":         emitGo,
+	"python":     emitPython,
+	"typescript": emitTypeScript,
+	"java":       emitJava,
+}
+
+// SupportsModels reports whether native models can be generated for lang.
+func SupportsModels(lang string) bool {
+	_, ok := modelEmitters[lang]
+	return ok
+}
+
+// GenerateModels generates native types for the messages and enums of a
+// non-protobuf schema (Avro, JSON Schema, Parquet or OpenAPI component
+// schemas) into the overlay, in the package its coordinates name. It
+// returns the files written, relative to the overlay, in path order.
+func GenerateModels(req ModelRequest) ([]string, error) {
+	emit, ok := modelEmitters[req.Language]
+	if !ok {
+		return nil, fmt.Errorf("native models are not supported for %s (supported: go, java, python, typescript)", req.Language)
+	}
+	model, err := LoadModel(req.SchemaDir, req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s schema of %s: %w", req.Format, req.APIID, err)
+	}
+	if model.Empty() {
+		return nil, nil
+	}
+	files, err := emit(model, req)
+	if err != nil {
+		return nil, fmt.Errorf("generating %s models for %s: %w", req.Language, req.APIID, err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for rel := range files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		target := filepath.Join(req.OutDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("creating %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, files[rel], 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", target, err)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,234 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderAvro = `{
+  "type": "record",
+  "name": "OrderPlaced",
+  "namespace": "acme.orders.v1",
+  "doc": "An order was placed.",
+  "fields": [
+    {"name": "order_id", "type": "string"},
+    {"name": "placed_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "PAID"]}},
+    {"name": "lines", "type": {"type": "array", "items": {
+      "type": "record", "name": "Line",
+      "fields": [{"name": "sku", "type": "string"}, {"name": "qty", "type": "int"}]
+    }}},
+    {"name": "note", "type": ["null", "string"], "default": null},
+    {"name": "tags", "type": {"type": "map", "values": "string"}}
+  ]
+}`
+
+const userJSONSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "User",
+  "type": "object",
+  "required": ["id", "address"],
+  "properties": {
+    "id": {"type": "string", "description": "Unique ID"},
+    "age": {"type": "integer", "format": "int32"},
+    "address": {"type": "object", "properties": {"city": {"type": "string"}}},
+    "roles": {"type": "array", "items": {"$ref": "#/$defs/Role"}},
+    "created": {"type": ["string", "null"], "format": "date-time"}
+  },
+  "$defs": {
+    "Role": {"type": "string", "enum": ["admin", "viewer"]}
+  }
+}`
+
+const eventsParquet = `message events {
+  required int64 id;
+  optional binary name (STRING);
+  repeated int32 scores;
+  optional group location {
+    required double lat;
+    required double lon;
+  }
+}
+`
+
+func writeSchema(t *testing.T, name, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	return dir
+}
+
+func findMessage(t *testing.T, m *Model, name string) *Message {
+	t.Helper()
+	for _, msg := range m.Messages {
+		if msg.Name == name {
+			return msg
+		}
+	}
+	t.Fatalf("no message %s in %+v", name, m.Messages)
+	return nil
+}
+
+func TestLoadModel_Avro(t *testing.T) {
+	m, err := LoadModel(writeSchema(t, "order.avsc", orderAvro), validator.FormatAvro)
+	require.NoError(t, err)
+
+	order := findMessage(t, m, "OrderPlaced")
+	assert.Equal(t, "An order was placed.", order.Comment)
+	require.Len(t, order.Fields, 6)
+	assert.Equal(t, Type{Kind: KindString}, order.Fields[0].Type)
+	assert.Equal(t, Type{Kind: KindTimestamp}, order.Fields[1].Type)
+	assert.Equal(t, Type{Kind: KindNamed, Name: "Status"}, order.Fields[2].Type)
+	assert.Equal(t, Type{Kind: KindList, Elem: &Type{Kind: KindNamed, Name: "Line"}}, order.Fields[3].Type)
+	assert.Equal(t, Type{Kind: KindString}, order.Fields[4].Type)
+	assert.True(t, order.Fields[4].Optional)
+	assert.False(t, order.Fields[0].Optional)
+	assert.Equal(t, Type{Kind: KindMap, Elem: &Type{Kind: KindString}}, order.Fields[5].Type)
+
+	line := findMessage(t, m, "Line")
+	assert.Equal(t, Type{Kind: KindInt32}, line.Fields[1].Type)
+	require.Len(t, m.Enums, 1)
+	assert.Equal(t, []string{"NEW", "PAID"}, m.Enums[0].Values)
+}
+
+func TestLoadModel_JSONSchema(t *testing.T) {
+	m, err := LoadModel(writeSchema(t, "user.json", userJSONSchema), validator.FormatJSONSchema)
+	require.NoError(t, err)
+
+	user := findMessage(t, m, "User")
+	require.Len(t, user.Fields, 5)
+	assert.Equal(t, "Unique ID", user.Fields[0].Comment)
+	assert.False(t, user.Fields[0].Optional, "listed in required")
+	assert.Equal(t, Type{Kind: KindInt32}, user.Fields[1].Type)
+	assert.True(t, user.Fields[1].Optional)
+	assert.Equal(t, Type{Kind: KindNamed, Name: "UserAddress"}, user.Fields[2].Type)
+	assert.Equal(t, Type{Kind: KindList, Elem: &Type{Kind: KindNamed, Name: "Role"}}, user.Fields[3].Type)
+	assert.Equal(t, Type{Kind: KindTimestamp}, user.Fields[4].Type)
+
+	findMessage(t, m, "UserAddress")
+	require.Len(t, m.Enums, 1)
+	assert.Equal(t, "Role", m.Enums[0].Name)
+	assert.Equal(t, []string{"admin", "viewer"}, m.Enums[0].Values)
+}
+
+func TestLoadModel_Parquet(t *testing.T) {
+	m, err := LoadModel(writeSchema(t, "events.parquet", eventsParquet), validator.FormatParquet)
+	require.NoError(t, err)
+
+	events := findMessage(t, m, "Events")
+	require.Len(t, events.Fields, 4)
+	assert.Equal(t, Type{Kind: KindInt64}, events.Fields[0].Type)
+	assert.Equal(t, Type{Kind: KindString}, events.Fields[1].Type)
+	assert.True(t, events.Fields[1].Optional)
+	assert.Equal(t, Type{Kind: KindList, Elem: &Type{Kind: KindInt32}}, events.Fields[2].Type)
+	assert.Equal(t, Type{Kind: KindNamed, Name: "EventsLocation"}, events.Fields[3].Type)
+	assert.Len(t, findMessage(t, m, "EventsLocation").Fields, 2)
+}
+
+func TestLoadModel_OpenAPIComponents(t *testing.T) {
+	m, err := LoadModel(filepath.Join("..", "validator", "testdata", "openapi", "petstore_v1.yaml"), validator.FormatOpenAPI)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.Messages)
+	for _, msg := range m.Messages {
+		assert.NotContains(t, msg.Name, " ", "operations are not types")
+	}
+}
+
+func TestGenerateModels(t *testing.T) {
+	schemaDir := writeSchema(t, "order.avsc", orderAvro)
+	tests := []struct {
+		lang     string
+		coords   config.LanguageCoords
+		file     string
+		contains []string
+	}{
+		{
+			lang:   "go",
+			coords: config.LanguageCoords{Module: "github.com/acme/apis/avro/orders/events", Import: "github.com/acme/apis/avro/orders/events/v1"},
+			file:   "v1/models.apx.go",
+			contains: []string{
+				"// Code generated by apx gen. DO NOT EDIT.",
+				"package v1",
+				`import "time"`,
+				"// OrderPlaced: An order was placed.",
+				"OrderID  string",
+				"`json:\"order_id\" avro:\"order_id\"`",
+				"PlacedAt time.Time",
+				"Note     *string",
+				"`json:\"note,omitempty\" avro:\"note\"`",
+				"Lines    []Line",
+				"StatusNew  Status = \"NEW\"",
+			},
+		},
+		{
+			lang:   "python",
+			coords: config.LanguageCoords{Import: "acme_apis.orders.events.v1"},
+			file:   "acme_apis/orders/events/v1/models.py",
+			contains: []string{
+				"class Status(str, enum.Enum):",
+				"    NEW = \"NEW\"",
+				"@dataclass\nclass OrderPlaced:\n    \"\"\"An order was placed.\"\"\"",
+				"    placed_at: datetime.datetime\n",
+				"    tags: Dict[str, str]\n    note: Optional[str] = None\n",
+			},
+		},
+		{
+			lang:   "typescript",
+			coords: config.LanguageCoords{Module: "@acme/orders-events-v1-proto"},
+			file:   "index.ts",
+			contains: []string{
+				`export type Status = "NEW" | "PAID";`,
+				"/** An order was placed. */\nexport interface OrderPlaced {",
+				"  lines: Array<Line>;",
+				"  note?: string | null;",
+			},
+		},
+		{
+			lang:   "java",
+			coords: config.LanguageCoords{Import: "com.acme.apis.orders.events.v1"},
+			file:   "src/main/java/com/acme/apis/orders/events/v1/OrderPlaced.java",
+			contains: []string{
+				"package com.acme.apis.orders.events.v1;",
+				"public record OrderPlaced(",
+				"    java.time.Instant placedAt,",
+				"    java.util.List<Line> lines,",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			out := t.TempDir()
+			files, err := GenerateModels(ModelRequest{
+				Language: tt.lang, Format: validator.FormatAvro, APIID: "avro/orders/events/v1",
+				SchemaDir: schemaDir, Coords: tt.coords, OutDir: out,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, files, tt.file)
+			data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(tt.file)))
+			require.NoError(t, err)
+			for _, want := range tt.contains {
+				assert.Contains(t, string(data), want)
+			}
+		})
+	}
+
+	_, err := GenerateModels(ModelRequest{Language: "rust", SchemaDir: schemaDir})
+	assert.ErrorContains(t, err, "native models are not supported for rust")
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "OrderID", goName("order_id"))
+	assert.Equal(t, "HTTPURL", goName("httpUrl"))
+	assert.Equal(t, "X2fa", goName("2fa"))
+	assert.Equal(t, "class_", pythonName("class"))
+	assert.Equal(t, "first_name", pythonName("first-name"))
+	assert.Equal(t, "default_", javaName("default"))
+	assert.Equal(t, "firstName", javaName("first_name"))
+	assert.Equal(t, "v1", goPackageName("github.com/acme/apis/avro/orders/events/v1"))
+}
@@ -0,0 +1,347 @@
+// Package codegen runs the code generators configured in apx.yaml
+// language_targets against a dependency's materialized schema, writing the
+// generated code into the dependency's overlay.
+package codegen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// Plugin is a protoc plugin entry of language_targets.<lang>.plugins.
+type Plugin struct {
+	Name    string // binary name, e.g. "protoc-gen-go"
+	Version string // pinned version, e.g. "v1.64.0"
+	Module  string // Go package to build the plugin from, when apx does not know it
+	Opt     string // comma-separated plugin options
+}
+
+// ParsePlugins reads the plugins of a language target. A plugin that names
+// a Go module is registered as a tool built from that module, so it can be
+// fetched and pinned like the built-in ones.
+func ParsePlugins(lang string, target config.LanguageTarget) ([]Plugin, error) {
+	var plugins []Plugin
+	for i, entry := range target.Plugins {
+		p := Plugin{
+			Name:    entry["name"],
+			Version: entry["version"],
+			Module:  entry["module"],
+			Opt:     entry["opt"],
+		}
+		if p.Name == "" {
+			return nil, fmt.Errorf("language_targets.%s.plugins[%d] has no name", lang, i)
+		}
+		if p.Version == "" {
+			return nil, fmt.Errorf("language_targets.%s.plugins[%d] (%s) has no version", lang, i, p.Name)
+		}
+		if p.Module != "" {
+			validator.RegisterGoTool(p.Name, p.Module)
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// ProtoRequest describes one protobuf generation run.
+type ProtoRequest struct {
+	Language  string                // target language, e.g. "go"
+	APIID     string                // dependency API ID, e.g. "proto/payments/ledger/v1"
+	SchemaDir string                // directory holding the dependency's .proto files
+	Coords    config.LanguageCoords // canonical coordinates in the target language
+	OutDir    string                // overlay directory to generate into
+	Plugins   []Plugin
+	Tools     *validator.ToolchainResolver
+	Imports   []ProtoImport // locked APIs the .proto files import
+}
+
+// ProtoImport is a locked API that a generated API imports. Its files are
+// staged so that imports resolve, but no code is generated for them.
+type ProtoImport struct {
+	APIID     string
+	SchemaDir string
+	Coords    config.LanguageCoords // canonical coordinates in the target language
+}
+
+// GenerateProto runs buf generate with the request's plugins, each at its
+// pinned version, over the .proto files of SchemaDir.
+//
+// The files are staged in a scratch module at the path the target language
+// derives its package layout from, and buf's managed mode points the
+// language's package options at the canonical coordinates. Generated Go
+// code therefore imports, and is imported by, its canonical path, which
+// go.work resolves to the overlay. The imported APIs are staged at their
+// API IDs, with their own canonical coordinates, and the scratch module
+// takes the deps of the schema's buf.yaml (with its buf.lock), so imports
+// of other APIs and of BSR modules such as googleapis resolve.
+func GenerateProto(req ProtoRequest) error {
+	if len(req.Plugins) == 0 {
+		return nil
+	}
+	tools := req.Tools
+	if tools == nil {
+		tools = validator.NewToolchainResolver()
+	}
+
+	work, err := os.MkdirTemp("", "apx-gen-*")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer os.RemoveAll(work)
+
+	layout := protoLayout(req.Language, req.APIID, req.Coords)
+	n, err := stageProtos(req.SchemaDir, filepath.Join(work, filepath.FromSlash(layout)), req.APIID, layout)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no .proto files found in %s", req.SchemaDir)
+	}
+	for _, imp := range req.Imports {
+		if _, err := stageProtos(imp.SchemaDir, filepath.Join(work, filepath.FromSlash(imp.APIID)), imp.APIID, imp.APIID); err != nil {
+			return err
+		}
+	}
+	if err := writeBufModule(work, req.SchemaDir); err != nil {
+		return err
+	}
+
+	template := bufGenTemplate{Version: "v2"}
+	var overrides []bufGenOverride
+	if override := managedOverride(req.Language, layout, req.Coords); override != nil {
+		overrides = append(overrides, *override)
+	}
+	for _, imp := range req.Imports {
+		if override := managedOverride(req.Language, imp.APIID, imp.Coords); override != nil {
+			overrides = append(overrides, *override)
+		}
+	}
+	if len(overrides) > 0 {
+		template.Managed = &bufGenManaged{Enabled: true, Override: overrides}
+	}
+	for _, p := range req.Plugins {
+		bin, err := tools.FetchTool(p.Name, p.Version)
+		if err != nil {
+			return fmt.Errorf("resolving plugin %s: %w", p.Name, err)
+		}
+		template.Plugins = append(template.Plugins, bufGenPlugin{
+			Local: bin,
+			Out:   ".",
+			Opt:   pluginOpts(req.Language, p, req.Coords),
+		})
+	}
+	data, err := yaml.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("rendering buf.gen.yaml: %w", err)
+	}
+	templatePath := filepath.Join(work, "buf.gen.yaml")
+	if err := os.WriteFile(templatePath, data, 0644); err != nil {
+		return fmt.Errorf("writing buf.gen.yaml: %w", err)
+	}
+
+	outDir, err := filepath.Abs(req.OutDir)
+	if err != nil {
+		return fmt.Errorf("resolving output directory: %w", err)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	bufPath, err := tools.ResolveTool("buf", tools.ToolVersion("buf"))
+	if err != nil {
+		return fmt.Errorf("failed to resolve buf: %w", err)
+	}
+	// --path limits generation to the API itself; the imported files are
+	// only read.
+	cmd := tools.Command(work, bufPath, "generate", work, "--path", layout, "--template", templatePath, "--output", outDir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("buf generate failed for %s: %w\nOutput: %s", req.APIID, err, out)
+	}
+	return nil
+}
+
+// protoLayout is the directory, relative to the scratch module, that the
+// .proto files are staged in. Python derives its package from the file
+// path, so it is the canonical import path; other languages take their
+// package from file options, and the API ID is kept.
+func protoLayout(lang, apiID string, coords config.LanguageCoords) string {
+	if lang == "python" && coords.Import != "" {
+		return strings.ReplaceAll(coords.Import, ".", "/")
+	}
+	return apiID
+}
+
+// stageProtos copies the .proto files under src into dst, rewriting imports
+// of the API's own files from apiID to layout. It returns the number of
+// files copied.
+func stageProtos(src, dst, apiID, layout string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".proto" {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if layout != apiID {
+			data = []byte(strings.ReplaceAll(string(data), `import "`+apiID+"/", `import "`+layout+"/"))
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		n++
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("staging .proto files from %s: %w", src, err)
+	}
+	return n, nil
+}
+
+// ImportedAPIs returns the APIs among apiIDs whose files the .proto files
+// under dir import, sorted.
+func ImportedAPIs(dir string, apiIDs []string) ([]string, error) {
+	found := map[string]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".proto" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, imp := range validator.ProtoImports(data) {
+			for _, id := range apiIDs {
+				if strings.HasPrefix(imp, id+"/") {
+					found[id] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading imports in %s: %w", dir, err)
+	}
+	ids := make([]string, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// writeBufModule writes the scratch module's buf.yaml, with the deps of the
+// buf.yaml nearest above schemaDir, and copies the buf.lock beside it. With
+// no deps, buf's defaults apply and nothing is written.
+func writeBufModule(work, schemaDir string) error {
+	dir, err := filepath.Abs(schemaDir)
+	if err != nil {
+		return err
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "buf.yaml"))
+		if err == nil {
+			var cfg struct {
+				Deps []string `yaml:"deps"`
+			}
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return fmt.Errorf("parsing %s: %w", filepath.Join(dir, "buf.yaml"), err)
+			}
+			if len(cfg.Deps) == 0 {
+				return nil
+			}
+			out, err := yaml.Marshal(map[string]interface{}{"version": "v2", "deps": cfg.Deps})
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(work, "buf.yaml"), out, 0644); err != nil {
+				return fmt.Errorf("writing buf.yaml: %w", err)
+			}
+			if lock, err := os.ReadFile(filepath.Join(dir, "buf.lock")); err == nil {
+				if err := os.WriteFile(filepath.Join(work, "buf.lock"), lock, 0644); err != nil {
+					return fmt.Errorf("writing buf.lock: %w", err)
+				}
+			}
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// managedOverride points the target language's package option at the
+// canonical coordinates, or returns nil when the language has none.
+func managedOverride(lang, layout string, coords config.LanguageCoords) *bufGenOverride {
+	if coords.Import == "" {
+		return nil
+	}
+	switch lang {
+	case "go":
+		return &bufGenOverride{FileOption: "go_package", Path: layout, Value: coords.Import}
+	case "java":
+		return &bufGenOverride{FileOption: "java_package", Path: layout, Value: coords.Import}
+	}
+	return nil
+}
+
+// pluginOpts returns the options of a plugin. Go plugins write files
+// relative to the canonical module root, which is the overlay root.
+func pluginOpts(lang string, p Plugin, coords config.LanguageCoords) []string {
+	var opts []string
+	if lang == "go" && coords.Module != "" {
+		opts = append(opts, "module="+coords.Module)
+	}
+	for _, o := range strings.Split(p.Opt, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			opts = append(opts, o)
+		}
+	}
+	return opts
+}
+
+// bufGenTemplate is a buf.gen.yaml v2 template.
+type bufGenTemplate struct {
+	Version string         `yaml:"version"`
+	Managed *bufGenManaged `yaml:"managed,omitempty"`
+	Plugins []bufGenPlugin `yaml:"plugins"`
+}
+
+type bufGenManaged struct {
+	Enabled  bool             `yaml:"enabled"`
+	Override []bufGenOverride `yaml:"override,omitempty"`
+}
+
+type bufGenOverride struct {
+	FileOption string `yaml:"file_option"`
+	Path       string `yaml:"path,omitempty"`
+	Value      string `yaml:"value"`
+}
+
+type bufGenPlugin struct {
+	Local string   `yaml:"local"`
+	Out   string   `yaml:"out"`
+	Opt   []string `yaml:"opt,omitempty"`
+}
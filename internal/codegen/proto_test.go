@@ -0,0 +1,76 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlugins(t *testing.T) {
+	plugins, err := ParsePlugins("go", config.LanguageTarget{
+		Enabled: true,
+		Plugins: []map[string]string{
+			{"name": "protoc-gen-go", "version": "v1.64.0"},
+			{"name": "protoc-gen-example", "version": "v0.1.0", "module": "example.com/cmd/protoc-gen-example", "opt": "a=b"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, plugins, 2)
+	assert.Equal(t, Plugin{Name: "protoc-gen-go", Version: "v1.64.0"}, plugins[0])
+	assert.Equal(t, "example.com/cmd/protoc-gen-example", plugins[1].Module)
+
+	_, err = ParsePlugins("go", config.LanguageTarget{Plugins: []map[string]string{{"name": "protoc-gen-go"}}})
+	assert.ErrorContains(t, err, "language_targets.go.plugins[0] (protoc-gen-go) has no version")
+	_, err = ParsePlugins("go", config.LanguageTarget{Plugins: []map[string]string{{"version": "v1"}}})
+	assert.ErrorContains(t, err, "has no name")
+}
+
+func TestPluginOpts(t *testing.T) {
+	coords := config.LanguageCoords{Module: "github.com/acme/apis/proto/payments/ledger"}
+	assert.Equal(t,
+		[]string{"module=github.com/acme/apis/proto/payments/ledger", "require_unimplemented_servers=false"},
+		pluginOpts("go", Plugin{Opt: " require_unimplemented_servers=false "}, coords))
+	assert.Empty(t, pluginOpts("python", Plugin{}, coords))
+}
+
+func TestManagedOverride(t *testing.T) {
+	coords := config.LanguageCoords{Import: "github.com/acme/apis/proto/payments/ledger/v1"}
+	assert.Equal(t,
+		&bufGenOverride{FileOption: "go_package", Path: "proto/payments/ledger/v1", Value: coords.Import},
+		managedOverride("go", "proto/payments/ledger/v1", coords))
+	assert.Nil(t, managedOverride("python", "acme_apis/payments/ledger/v1", coords))
+	assert.Nil(t, managedOverride("go", "proto/payments/ledger/v1", config.LanguageCoords{}))
+}
+
+func TestStageProtos_PythonLayout(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "ledger.proto"),
+		[]byte(`import "proto/payments/ledger/v1/types.proto";`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("docs"), 0644))
+
+	layout := protoLayout("python", "proto/payments/ledger/v1", config.LanguageCoords{Import: "acme_apis.payments.ledger.v1"})
+	require.Equal(t, "acme_apis/payments/ledger/v1", layout)
+
+	dst := filepath.Join(t.TempDir(), filepath.FromSlash(layout))
+	n, err := stageProtos(src, dst, "proto/payments/ledger/v1", layout)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	data, err := os.ReadFile(filepath.Join(dst, "ledger.proto"))
+	require.NoError(t, err)
+	assert.Equal(t, `import "acme_apis/payments/ledger/v1/types.proto";`, string(data))
+}
+
+func TestGenerateProto_NoProtos(t *testing.T) {
+	err := GenerateProto(ProtoRequest{
+		Language:  "go",
+		APIID:     "proto/payments/ledger/v1",
+		SchemaDir: t.TempDir(),
+		OutDir:    t.TempDir(),
+		Plugins:   []Plugin{{Name: "protoc-gen-go", Version: "v1.64.0"}},
+	})
+	assert.ErrorContains(t, err, "no .proto files found")
+}
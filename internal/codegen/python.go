@@ -0,0 +1,135 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pythonKeywords are the reserved words Python identifiers must avoid.
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true, "finally": true,
+	"for": true, "from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true,
+	"raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// emitPython renders the model as dataclasses and string enums in a
+// models.py module of the package the canonical import path names, which
+// the scaffolded pyproject.toml packages. Only the standard library is used.
+func emitPython(m *Model, req ModelRequest) (map[string][]byte, error) {
+	if req.Coords.Import == "" {
+		return nil, fmt.Errorf("no Python import path")
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n# Source: %s\n", generatedHeader, req.APIID)
+	sb.WriteString(`from __future__ import annotations
+
+import datetime
+import enum
+from dataclasses import dataclass
+from typing import Any, Dict, List, Optional
+`)
+	for _, e := range m.Enums {
+		fmt.Fprintf(&sb, "\n\nclass %s(str, enum.Enum):\n", e.Name)
+		writePythonDoc(&sb, e.Comment)
+		if len(e.Values) == 0 {
+			sb.WriteString("    pass\n")
+		}
+		names := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			names[i] = strings.ToUpper(pythonName(v))
+		}
+		for i, n := range uniqueNames(names) {
+			fmt.Fprintf(&sb, "    %s = %q\n", n, e.Values[i])
+		}
+	}
+	for _, msg := range m.Messages {
+		fmt.Fprintf(&sb, "\n\n@dataclass\nclass %s:\n", msg.Name)
+		writePythonDoc(&sb, msg.Comment)
+		if len(msg.Fields) == 0 {
+			sb.WriteString("    pass\n")
+		}
+		names := make([]string, len(msg.Fields))
+		for i, f := range msg.Fields {
+			names[i] = pythonName(f.Name)
+		}
+		names = uniqueNames(names)
+		// Fields with a default must follow those without one.
+		for _, optional := range []bool{false, true} {
+			for i, f := range msg.Fields {
+				if f.Optional != optional {
+					continue
+				}
+				if optional {
+					fmt.Fprintf(&sb, "    %s: Optional[%s] = None\n", names[i], pythonType(f.Type))
+				} else {
+					fmt.Fprintf(&sb, "    %s: %s\n", names[i], pythonType(f.Type))
+				}
+			}
+		}
+	}
+	dir := strings.ReplaceAll(req.Coords.Import, ".", "/")
+	return map[string][]byte{dir + "/models.py": []byte(sb.String())}, nil
+}
+
+// pythonName renders a schema name as a Python identifier, keeping it as
+// written when it already is one.
+func pythonName(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	name := sb.String()
+	if name == "" || pythonKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// pythonType renders a type as a Python annotation.
+func pythonType(t Type) string {
+	switch t.Kind {
+	case KindString:
+		return "str"
+	case KindBool:
+		return "bool"
+	case KindInt32, KindInt64:
+		return "int"
+	case KindFloat32, KindFloat64:
+		return "float"
+	case KindBytes:
+		return "bytes"
+	case KindTimestamp:
+		return "datetime.datetime"
+	case KindDate:
+		return "datetime.date"
+	case KindNamed:
+		return t.Name
+	case KindList:
+		return "List[" + pythonType(*t.Elem) + "]"
+	case KindMap:
+		return "Dict[str, " + pythonType(*t.Elem) + "]"
+	}
+	return "Any"
+}
+
+// writePythonDoc writes a class docstring.
+func writePythonDoc(sb *strings.Builder, comment string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.ReplaceAll(strings.TrimSpace(comment), `"""`, `\"\"\"`)
+	fmt.Fprintf(sb, "    \"\"\"%s\"\"\"\n\n", strings.ReplaceAll(comment, "\n", "\n    "))
+}
@@ -0,0 +1,87 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsIdentifierRe matches property names that need no quotes.
+var tsIdentifierRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// emitTypeScript renders the model as interfaces and string union types in
+// the index.ts of the overlay, the entry point of its npm package. Property
+// names are kept as serialized.
+func emitTypeScript(m *Model, req ModelRequest) (map[string][]byte, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// %s\n// Source: %s\n", generatedHeader, req.APIID)
+	for _, e := range m.Enums {
+		sb.WriteString("\n")
+		writeTSDoc(&sb, e.Comment, "")
+		values := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			values[i] = fmt.Sprintf("%q", v)
+		}
+		if len(values) == 0 {
+			values = []string{"string"}
+		}
+		fmt.Fprintf(&sb, "export type %s = %s;\n", e.Name, strings.Join(values, " | "))
+	}
+	for _, msg := range m.Messages {
+		sb.WriteString("\n")
+		writeTSDoc(&sb, msg.Comment, "")
+		fmt.Fprintf(&sb, "export interface %s {\n", msg.Name)
+		for _, f := range msg.Fields {
+			writeTSDoc(&sb, f.Comment, "  ")
+			name := f.Name
+			if !tsIdentifierRe.MatchString(name) {
+				name = fmt.Sprintf("%q", name)
+			}
+			if f.Optional {
+				fmt.Fprintf(&sb, "  %s?: %s | null;\n", name, tsType(f.Type))
+			} else {
+				fmt.Fprintf(&sb, "  %s: %s;\n", name, tsType(f.Type))
+			}
+		}
+		sb.WriteString("}\n")
+	}
+	return map[string][]byte{"index.ts": []byte(sb.String())}, nil
+}
+
+// tsType renders a type in TypeScript. Int64 values are numbers, as JSON
+// decodes them; bytes and dates are strings, as JSON encodes them.
+func tsType(t Type) string {
+	switch t.Kind {
+	case KindString, KindBytes, KindTimestamp, KindDate:
+		return "string"
+	case KindBool:
+		return "boolean"
+	case KindInt32, KindInt64, KindFloat32, KindFloat64:
+		return "number"
+	case KindNamed:
+		return t.Name
+	case KindList:
+		return "Array<" + tsType(*t.Elem) + ">"
+	case KindMap:
+		return "Record<string, " + tsType(*t.Elem) + ">"
+	}
+	return "unknown"
+}
+
+// writeTSDoc writes a JSDoc comment.
+func writeTSDoc(sb *strings.Builder, comment, indent string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.ReplaceAll(strings.TrimSpace(comment), "*/", "* /")
+	lines := strings.Split(comment, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(sb, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(sb, "%s/**\n", indent)
+	for _, l := range lines {
+		fmt.Fprintf(sb, "%s * %s\n", indent, strings.TrimRight(l, " \t"))
+	}
+	fmt.Fprintf(sb, "%s */\n", indent)
+}
@@ -21,6 +21,7 @@ type Config struct {
 	ModuleRoots       []string                  `yaml:"module_roots"`
 	LanguageTargets   map[string]LanguageTarget `yaml:"language_targets"`
 	Policy            Policy                    `yaml:"policy"`
+	Lint              Lint                      `yaml:"lint,omitempty"`
 	Release           ReleaseConfig             `yaml:"release"`
 	Tools             Tools                     `yaml:"tools"`
 	Execution         Execution                 `yaml:"execution"`
@@ -98,8 +99,64 @@ type Policy struct {
 		BreakingMode string `yaml:"breaking_mode,omitempty"`
 	} `yaml:"jsonschema,omitempty"`
 	Parquet struct {
-		AllowAdditiveNullableOnly bool `yaml:"allow_additive_nullable_only,omitempty"`
+		// AllowAdditiveNullableOnly is nil when apx.yaml does not set it;
+		// see ParquetAdditiveNullableOnly for the default.
+		AllowAdditiveNullableOnly *bool `yaml:"allow_additive_nullable_only,omitempty"`
 	} `yaml:"parquet,omitempty"`
+	Rules []PolicyRule `yaml:"rules,omitempty"`
+}
+
+// ParquetAdditiveNullableOnly reports whether Parquet schemas may only
+// evolve by adding nullable columns. It defaults to true when
+// policy.parquet.allow_additive_nullable_only is not set.
+func (p Policy) ParquetAdditiveNullableOnly() bool {
+	return p.Parquet.AllowAdditiveNullableOnly == nil || *p.Parquet.AllowAdditiveNullableOnly
+}
+
+// PolicyRule is a programmable policy rule: a CEL expression that every
+// schema element of the target kind must satisfy, e.g.
+//
+//	rules:
+//	  - id: rpc-comment
+//	    target: rpc
+//	    expr: element.comment != ""
+//	    message: rpc ${element.name} must be documented
+type PolicyRule struct {
+	ID       string `yaml:"id"`
+	Severity string `yaml:"severity,omitempty"` // error (default) or warning
+	Target   string `yaml:"target"`             // element kind: file, message, field, enum, enum_value, service, rpc
+	Format   string `yaml:"format,omitempty"`   // only evaluate elements of this schema format
+	Expr     string `yaml:"expr"`
+	// Message is reported for each element that fails the rule; ${expr}
+	// placeholders are evaluated against the element.
+	Message string `yaml:"message,omitempty"`
+}
+
+// Lint configures the native lint rules of each format, keyed by rule ID,
+// e.g.
+//
+//	lint:
+//	  avro:
+//	    rules:
+//	      avro-field-camel-case:
+//	        severity: warning
+//	        pattern: ^[a-z][a-zA-Z0-9_]*$
+type Lint struct {
+	Avro       LintFormat `yaml:"avro,omitempty"`
+	Parquet    LintFormat `yaml:"parquet,omitempty"`
+	JSONSchema LintFormat `yaml:"jsonschema,omitempty"`
+	CRD        LintFormat `yaml:"crd,omitempty"`
+}
+
+// LintFormat holds the lint rule settings of one format.
+type LintFormat struct {
+	Rules map[string]LintRule `yaml:"rules,omitempty"`
+}
+
+// LintRule overrides a native lint rule.
+type LintRule struct {
+	Severity string `yaml:"severity,omitempty"` // off, warning or error; empty keeps the default
+	Pattern  string `yaml:"pattern,omitempty"`  // naming rules: regular expression names must match
 }
 
 // ReleaseConfig represents release configuration
@@ -138,10 +195,27 @@ type Tools struct {
 	} `yaml:"avrotool"`
 }
 
+// Versions returns the tool versions set in apx.yaml, keyed by tool name.
+func (t Tools) Versions() map[string]string {
+	versions := map[string]string{}
+	for name, v := range map[string]string{
+		"buf":      t.Buf.Version,
+		"oasdiff":  t.OASDiff.Version,
+		"spectral": t.Spectral.Version,
+		"avrotool": t.AvroTool.Version,
+	} {
+		if v != "" {
+			versions[name] = v
+		}
+	}
+	return versions
+}
+
 // Execution represents execution configuration
 type Execution struct {
-	Mode           string `yaml:"mode"`
-	ContainerImage string `yaml:"container_image"`
+	Mode           string   `yaml:"mode"`
+	ContainerImage string   `yaml:"container_image"`
+	Env            []string `yaml:"env,omitempty"` // host variables passed into the container
 }
 
 // CatalogRegistry identifies a GHCR-hosted catalog to query for API discovery.
@@ -160,9 +234,9 @@ type LockFile struct {
 
 // ToolchainLock represents a locked toolchain version
 type ToolchainLock struct {
-	Version  string `yaml:"version"`
-	Checksum string `yaml:"checksum,omitempty"`
-	Path     string `yaml:"path,omitempty"` // For offline bundles
+	Version   string            `yaml:"version"`
+	Checksums map[string]string `yaml:"checksums,omitempty"` // sha256 of the binary by os/arch platform
+	Path      string            `yaml:"path,omitempty"`      // For offline bundles
 }
 
 // DependencyLock represents a locked schema dependency
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/infobloxopen/apx/pkg/githubauth"
 )
@@ -71,10 +72,82 @@ func MaterializeSpec(dep DependencyLock, apiID string) (specPath string, cleanup
 	}
 }
 
+// MaterializeSchema resolves the schema directory of a dependency, in any
+// format, to a directory on disk: beneath dep.Path for a path override, in a
+// checkout of dep.Git@dep.GitRef for a git override, or else in a checkout of
+// dep.Repo at its locked ref. Checkouts share MaterializeSpec's persistent
+// cache. Unlike MaterializeSpec, it also materializes released dependencies,
+// for code generation. The returned cleanup is always non-nil and safe to
+// call.
+func MaterializeSchema(dep DependencyLock, apiID string) (dir string, cleanup func() error, err error) {
+	noop := func() error { return nil }
+
+	var root, source string
+	switch {
+	case dep.Path != "":
+		root, err = filepath.Abs(dep.Path)
+		if err != nil {
+			return "", noop, fmt.Errorf("resolving override path %q: %w", dep.Path, err)
+		}
+		if fi, statErr := os.Stat(root); statErr != nil || !fi.IsDir() {
+			return "", noop, fmt.Errorf("override path %q is not an existing directory", dep.Path)
+		}
+		source = "path " + dep.Path
+	case dep.Git != "":
+		if root, err = materializeGit(dep, apiID); err != nil {
+			return "", noop, err
+		}
+		source = "git checkout " + dep.Git + "@" + dep.GitRef
+	default:
+		if root, err = materializeLocked(dep, apiID); err != nil {
+			return "", noop, err
+		}
+		source = dep.Repo + "@" + dep.Ref
+	}
+
+	resolved, err := resolveUnderRoot(root, apiID)
+	if err != nil {
+		return "", noop, fmt.Errorf("resolving schema for %q in %s: %w", apiID, source, err)
+	}
+	fi, err := os.Stat(resolved)
+	if err != nil {
+		return "", noop, fmt.Errorf("schema for %q not found in %s: %w", apiID, source, err)
+	}
+	if !fi.IsDir() {
+		resolved = filepath.Dir(resolved)
+	}
+	return resolved, noop, nil
+}
+
+// materializeLocked clones a released dependency's repo at its locked ref.
+// A bare version ref such as v1.2.3 is tried as the API's release tag
+// (<api-id>/v1.2.3) first, then as a tag or branch of its own.
+func materializeLocked(dep DependencyLock, apiID string) (string, error) {
+	if dep.Repo == "" || strings.Contains(dep.Repo, "<") {
+		return "", fmt.Errorf("dependency %s has no source repository in apx.lock", apiID)
+	}
+	if dep.Ref == "" || dep.Ref == "latest" {
+		return "", fmt.Errorf("dependency %s has no locked ref to materialize (run 'apx update' to pin a version)", apiID)
+	}
+	refs := []string{dep.Ref}
+	if !strings.Contains(dep.Ref, "/") {
+		refs = []string{apiID + "/" + dep.Ref, dep.Ref}
+	}
+	var errs []string
+	for _, ref := range refs {
+		dir, err := materializeGit(DependencyLock{Git: dep.Repo, GitRef: ref}, apiID)
+		if err == nil {
+			return dir, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return "", fmt.Errorf("materializing %s@%s: %s", apiID, dep.Ref, strings.Join(errs, "; "))
+}
+
 // resolveSpecInRoot finds the OpenAPI spec file for apiID within the override
 // root. It tries, in order:
 //
-//  1. ResolveAPIPath(apiID) evaluated with root as the working directory and as
+//  1. ResolveAPIPath(apiID) evaluated with root as the base directory and as
 //     a module_root — this locates the api-id directory (or a file, if the
 //     api-id happens to already be a spec-file path). If that yields a file, it
 //     is used directly; if it yields a directory, the directory is scanned for
@@ -86,8 +159,8 @@ func MaterializeSpec(dep DependencyLock, apiID string) (specPath string, cleanup
 func resolveSpecInRoot(root, apiID string) (string, error) {
 	// Resolve the api-id relative to the override root without disturbing the
 	// caller's cwd: run ResolveAPIPath with root injected as a module_root and
-	// chdir'd so its cwd-relative fallbacks (internal/apis, schemas, api) are
-	// evaluated under root.
+	// its cwd-relative fallbacks (internal/apis, schemas, api) evaluated under
+	// root.
 	if resolved, err := resolveUnderRoot(root, apiID); err == nil {
 		fi, statErr := os.Stat(resolved)
 		if statErr == nil {
@@ -117,24 +190,16 @@ func resolveSpecInRoot(root, apiID string) (string, error) {
 
 // resolveUnderRoot evaluates ResolveAPIPath as though root were the working
 // directory, so an api-id like "openapi/billing/invoices/v2" resolves to a
-// path beneath root regardless of the caller's cwd.
+// path beneath root regardless of the caller's cwd. The working directory
+// itself is left alone: dependencies are materialized concurrently, and the
+// process cwd is shared by every goroutine.
 func resolveUnderRoot(root, apiID string) (string, error) {
-	cfg := &Config{ModuleRoots: []string{root}}
-	// ResolveAPIPath's cwd-relative fallbacks require chdir; do it briefly and
-	// restore. This mirrors how the resolve tests exercise the fallbacks.
-	orig, err := os.Getwd()
+	absRoot, err := filepath.Abs(root)
 	if err != nil {
-		orig = ""
-	}
-	if chErr := os.Chdir(root); chErr != nil {
-		return "", chErr
+		return "", err
 	}
-	defer func() {
-		if orig != "" {
-			_ = os.Chdir(orig)
-		}
-	}()
-	return ResolveAPIPath(apiID, cfg)
+	cfg := &Config{ModuleRoots: []string{absRoot}}
+	return resolveAPIPathIn(absRoot, apiID, cfg)
 }
 
 // scanDirForSpec returns the first OpenAPI spec file found directly in dir,
@@ -165,6 +230,18 @@ func isOpenAPISpecFile(p string) bool {
 		strings.HasSuffix(l, ".json")
 }
 
+// checkoutLocks holds a mutex per cache checkout directory, so dependencies
+// materialized concurrently from one repository and ref clone it once.
+var checkoutLocks sync.Map
+
+// lockCheckout locks a cache checkout directory and returns its unlock.
+func lockCheckout(dir string) func() {
+	v, _ := checkoutLocks.LoadOrStore(dir, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
 // materializeGit clones dep.Git@dep.GitRef into a persistent cache directory and
 // returns the checkout path. Branches/tags are fetched with a shallow
 // --branch clone; if that fails (e.g. GitRef is a commit SHA, which --branch
@@ -181,6 +258,7 @@ func materializeGit(dep DependencyLock, apiID string) (string, error) {
 		return "", err
 	}
 	dir := filepath.Join(base, sanitizeForPath(dep.Git), sanitizeForPath(dep.GitRef))
+	defer lockCheckout(dir)()
 
 	url := normalizeGitURL(dep.Git)
 	// Auth is supplied transiently via `git -c http.extraHeader=...`, which is
@@ -209,12 +287,12 @@ func materializeGit(dep DependencyLock, apiID string) (string, error) {
 	// subcommand so `-c http.extraHeader` applies to the transport only.
 	shallow := exec.Command("git", append(append([]string{}, auth...),
 		"clone", "--depth", "1", "--branch", dep.GitRef, url, dir)...)
-	shallow.Env = os.Environ()
+	shallow.Env = gitEnv()
 	if out, cloneErr := shallow.CombinedOutput(); cloneErr != nil {
 		_ = os.RemoveAll(dir)
 		// GitRef may be a commit SHA (--branch rejects it): full clone + checkout.
 		full := exec.Command("git", append(append([]string{}, auth...), "clone", url, dir)...)
-		full.Env = os.Environ()
+		full.Env = gitEnv()
 		if out2, fullErr := full.CombinedOutput(); fullErr != nil {
 			return "", fmt.Errorf("git clone %s failed: %w\n%s\n%s",
 				dep.Git, fullErr, strings.TrimSpace(string(out)), strings.TrimSpace(string(out2)))
@@ -231,6 +309,12 @@ func materializeGit(dep DependencyLock, apiID string) (string, error) {
 	return dir, nil
 }
 
+// gitEnv is the environment of git transport commands: git must fail rather
+// than prompt for credentials.
+func gitEnv() []string {
+	return append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+}
+
 // isGitCheckout reports whether dir looks like a populated git working tree.
 func isGitCheckout(dir string) bool {
 	if fi, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
@@ -245,7 +329,7 @@ func isGitCheckout(dir string) bool {
 func refreshCheckout(dir, ref string, auth []string) error {
 	fetchArgs := append(append([]string{}, auth...), "-C", dir, "fetch", "--depth", "1", "origin", ref)
 	fetch := exec.Command("git", fetchArgs...)
-	fetch.Env = os.Environ()
+	fetch.Env = gitEnv()
 	if out, err := fetch.CombinedOutput(); err != nil {
 		return fmt.Errorf("git fetch: %w\n%s", err, strings.TrimSpace(string(out)))
 	}
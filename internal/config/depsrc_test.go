@@ -1,10 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -279,3 +281,153 @@ func TestSanitizeForPath(t *testing.T) {
 		assert.NotContains(t, got, "/", "sanitized %q must not contain a path separator", in)
 	}
 }
+
+// --- MaterializeSchema ------------------------------------------------------
+
+func TestMaterializeSchema_LocalPath(t *testing.T) {
+	root := t.TempDir()
+	apiID := "proto/payments/ledger/v1"
+	require.NoError(t, os.MkdirAll(filepath.Join(root, apiID), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, apiID, "ledger.proto"), []byte("syntax = \"proto3\";\n"), 0o644))
+
+	got, cleanup, err := MaterializeSchema(DependencyLock{Path: root}, apiID)
+	require.NoError(t, err)
+	require.NoError(t, cleanup())
+	assert.Equal(t, filepath.Join(root, apiID), got)
+}
+
+func TestMaterializeSchema_LockedReleaseTag(t *testing.T) {
+	skipGitCloneOnWindows(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	base := t.TempDir()
+	bare := filepath.Join(base, "apis.git")
+	work := filepath.Join(base, "work")
+	require.NoError(t, os.MkdirAll(bare, 0o755))
+	gitCmd(t, bare, "init", "--bare", "-b", "main")
+	gitCmd(t, base, "clone", bare, work)
+
+	apiID := "proto/payments/ledger/v1"
+	require.NoError(t, os.MkdirAll(filepath.Join(work, apiID), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(work, apiID, "ledger.proto"), []byte("syntax = \"proto3\";\n"), 0o644))
+	gitCmd(t, work, "add", "-A")
+	gitCmd(t, work, "commit", "-m", "ledger v1.2.3")
+	gitCmd(t, work, "tag", apiID+"/v1.2.3")
+	gitCmd(t, work, "push", "origin", "main", "--tags")
+	t.Setenv(depSrcCacheEnv, filepath.Join(base, "cache"))
+
+	// A bare version resolves to the API's release tag.
+	got, _, err := MaterializeSchema(DependencyLock{Repo: "file://" + bare, Ref: "v1.2.3"}, apiID)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(got, "ledger.proto"))
+	assert.Contains(t, got, filepath.FromSlash(apiID))
+
+	_, _, err = MaterializeSchema(DependencyLock{Repo: "file://" + bare, Ref: "v9.9.9"}, apiID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "materializing proto/payments/ledger/v1@v9.9.9")
+}
+
+func TestMaterializeSchema_ConcurrentSharedCheckout(t *testing.T) {
+	skipGitCloneOnWindows(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	base := t.TempDir()
+	bare := filepath.Join(base, "apis.git")
+	work := filepath.Join(base, "work")
+	require.NoError(t, os.MkdirAll(bare, 0o755))
+	gitCmd(t, bare, "init", "--bare", "-b", "main")
+	gitCmd(t, base, "clone", bare, work)
+
+	apiIDs := []string{"proto/payments/ledger/v1", "proto/payments/wallet/v1", "proto/users/profile/v1"}
+	for _, apiID := range apiIDs {
+		require.NoError(t, os.MkdirAll(filepath.Join(work, apiID), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(work, apiID, "api.proto"), []byte("syntax = \"proto3\";\n"), 0o644))
+	}
+	gitCmd(t, work, "add", "-A")
+	gitCmd(t, work, "commit", "-m", "apis")
+	gitCmd(t, work, "tag", "release-1")
+	gitCmd(t, work, "push", "origin", "main", "--tags")
+	t.Setenv(depSrcCacheEnv, filepath.Join(base, "cache"))
+
+	// Dependencies locked at one ref of one repository share a checkout;
+	// materializing them concurrently clones it once.
+	var wg sync.WaitGroup
+	errs := make([]error, len(apiIDs))
+	dirs := make([]string, len(apiIDs))
+	for i, apiID := range apiIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dirs[i], _, errs[i] = MaterializeSchema(DependencyLock{Repo: "file://" + bare, Ref: "release-1"}, apiID)
+		}()
+	}
+	wg.Wait()
+	for i := range apiIDs {
+		require.NoError(t, errs[i])
+		assert.FileExists(t, filepath.Join(dirs[i], "api.proto"))
+	}
+}
+
+func TestMaterializeSchema_ConcurrentPathOverrides(t *testing.T) {
+	// Path overrides are resolved without changing the working directory,
+	// which every concurrent generation shares. Run with -race.
+	base := t.TempDir()
+	base, _ = filepath.EvalSymlinks(base)
+	t.Chdir(base)
+
+	const deps = 24
+	for i := range deps {
+		dir := filepath.Join(base, "deps", fmt.Sprintf("svc%d", i), "avro", fmt.Sprintf("svc%d", i), "events", "v1")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "event.avsc"), []byte("{}"), 0o644))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, deps)
+	for i := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apiID := fmt.Sprintf("avro/svc%d/events/v1", i)
+			for range 200 {
+				dir, _, err := MaterializeSchema(DependencyLock{Path: fmt.Sprintf("./deps/svc%d", i)}, apiID)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if want := filepath.Join(base, "deps", fmt.Sprintf("svc%d", i), filepath.FromSlash(apiID)); dir != want {
+					errs[i] = fmt.Errorf("resolved %s, want %s", dir, want)
+					return
+				}
+				// Relative paths, like the overlays gen writes, stay
+				// anchored at the original working directory.
+				if err := os.MkdirAll(filepath.Join("out", apiID), 0o755); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range deps {
+		require.NoError(t, errs[i])
+	}
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, base, cwd)
+	assert.DirExists(t, filepath.Join(base, "out", "avro", "svc0", "events", "v1"))
+}
+
+func TestMaterializeSchema_NoSource(t *testing.T) {
+	_, _, err := MaterializeSchema(DependencyLock{Repo: "github.com/<org>/<repo>", Ref: "v1.0.0"}, "proto/x/y/v1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no source repository")
+
+	_, _, err = MaterializeSchema(DependencyLock{Repo: "github.com/acme/apis", Ref: "latest"}, "proto/x/y/v1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no locked ref")
+}
@@ -14,8 +14,16 @@ import (
 //
 // The returned path is always absolute.
 func ResolveAPIPath(arg string, cfg *Config) (string, error) {
+	return resolveAPIPathIn("", arg, cfg)
+}
+
+// resolveAPIPathIn is ResolveAPIPath with relative paths evaluated against
+// base instead of the working directory. An empty base is the working
+// directory. It never changes the working directory, so it is safe to call
+// from concurrent goroutines.
+func resolveAPIPathIn(base, arg string, cfg *Config) (string, error) {
 	// 1. If it already exists on disk, return it directly.
-	abs, err := filepath.Abs(arg)
+	abs, err := absIn(base, arg)
 	if err == nil {
 		if _, statErr := os.Stat(abs); statErr == nil {
 			return abs, nil
@@ -34,7 +42,7 @@ func ResolveAPIPath(arg string, cfg *Config) (string, error) {
 		// 3. Search module_roots from config.
 		if cfg != nil {
 			for _, root := range cfg.ModuleRoots {
-				candidate, _ := filepath.Abs(filepath.Join(root, relPath))
+				candidate, _ := absIn(base, filepath.Join(root, relPath))
 				if candidate != "" {
 					if _, statErr := os.Stat(candidate); statErr == nil {
 						return candidate, nil
@@ -47,7 +55,7 @@ func ResolveAPIPath(arg string, cfg *Config) (string, error) {
 		relPath = arg
 	}
 
-	// 4. Fall back to common patterns relative to cwd.
+	// 4. Fall back to common patterns relative to base (the cwd by default).
 	fallbacks := []string{
 		relPath,
 		filepath.Join("internal", "apis", relPath),
@@ -55,7 +63,7 @@ func ResolveAPIPath(arg string, cfg *Config) (string, error) {
 		filepath.Join("api", relPath),
 	}
 	for _, fb := range fallbacks {
-		candidate, _ := filepath.Abs(fb)
+		candidate, _ := absIn(base, fb)
 		if candidate != "" {
 			if _, statErr := os.Stat(candidate); statErr == nil {
 				return candidate, nil
@@ -66,6 +74,15 @@ func ResolveAPIPath(arg string, cfg *Config) (string, error) {
 	return "", fmt.Errorf("path does not exist: %s", arg)
 }
 
+// absIn returns the absolute form of path, evaluating a relative path
+// against base, or against the working directory when base is empty.
+func absIn(base, path string) (string, error) {
+	if base != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Abs(path)
+}
+
 // ResolveAPIFormat extracts the schema format from an API ID string.
 // Returns the format portion (e.g. "proto") if the argument parses as
 // an API ID, or empty string otherwise.
@@ -120,9 +120,44 @@ func buildV1Schema() SchemaVersion {
 				Required:    true,
 				Description: "Plugin version",
 			},
+			"module": {
+				Name:        "module",
+				Type:        TypeString,
+				Description: "Go package to install the plugin from, for plugins apx does not know",
+			},
+			"opt": {
+				Name:        "opt",
+				Type:        TypeString,
+				Description: "Comma-separated plugin options",
+			},
 		},
 	}
 
+	lintFormat := func(name, description string) FieldDef {
+		return FieldDef{
+			Name:        name,
+			Type:        TypeStruct,
+			Description: description,
+			Children: map[string]FieldDef{
+				"rules": {
+					Name:        "rules",
+					Type:        TypeMap,
+					Description: "Rule settings keyed by rule ID (see apx lint --list-rules)",
+					ItemDef: &FieldDef{
+						Name:        "rule",
+						Type:        TypeStruct,
+						Description: "Settings of one lint rule",
+						Children: map[string]FieldDef{
+							"severity": {Name: "severity", Type: TypeString, Description: "Rule severity; off disables the rule",
+								EnumValues: []string{"off", "warning", "error"}},
+							"pattern": {Name: "pattern", Type: TypeString, Description: "Naming rules: regular expression names must match"},
+						},
+					},
+				},
+			},
+		}
+	}
+
 	languageTargetValue := FieldDef{
 		Name:        "language_target",
 		Type:        TypeStruct,
@@ -262,7 +297,7 @@ func buildV1Schema() SchemaVersion {
 							Type:        TypeString,
 							Description: "Avro compatibility mode",
 							Default:     "BACKWARD",
-							EnumValues:  []string{"BACKWARD", "FORWARD", "FULL", "NONE"},
+							EnumValues:  []string{"BACKWARD", "FORWARD", "FULL", "NONE", "BACKWARD_TRANSITIVE", "FORWARD_TRANSITIVE", "FULL_TRANSITIVE"},
 						},
 					},
 				},
@@ -293,6 +328,37 @@ func buildV1Schema() SchemaVersion {
 						},
 					},
 				},
+				"rules": {
+					Name:        "rules",
+					Type:        TypeList,
+					Description: "Programmable rules: CEL expressions evaluated over the parsed schema model",
+					ItemDef: &FieldDef{
+						Name:        "rule",
+						Type:        TypeStruct,
+						Description: "A rule every schema element of the target kind must satisfy",
+						Children: map[string]FieldDef{
+							"id":       {Name: "id", Type: TypeString, Required: true, Description: "Rule identifier reported with each violation"},
+							"severity": {Name: "severity", Type: TypeString, Description: "Violation severity", Default: "error", EnumValues: []string{"error", "warning"}},
+							"target": {Name: "target", Type: TypeString, Required: true, Description: "Schema element kind the rule applies to",
+								EnumValues: []string{"file", "message", "field", "enum", "enum_value", "service", "rpc"}},
+							"format": {Name: "format", Type: TypeString, Description: "Only evaluate elements of this schema format",
+								EnumValues: []string{"proto", "openapi", "avro", "jsonschema", "parquet", "crd"}},
+							"expr":    {Name: "expr", Type: TypeString, Required: true, Description: "CEL expression over element that must be true"},
+							"message": {Name: "message", Type: TypeString, Description: "Violation message; ${expr} placeholders are evaluated"},
+						},
+					},
+				},
+			},
+		},
+		"lint": {
+			Name:        "lint",
+			Type:        TypeStruct,
+			Description: "Native lint rule settings by format",
+			Children: map[string]FieldDef{
+				"avro":       lintFormat("avro", "Avro lint rules"),
+				"parquet":    lintFormat("parquet", "Parquet lint rules"),
+				"jsonschema": lintFormat("jsonschema", "JSON Schema lint rules"),
+				"crd":        lintFormat("crd", "CRD lint rules"),
 			},
 		},
 		"release": {
@@ -390,6 +456,12 @@ func buildV1Schema() SchemaVersion {
 					Type:        TypeString,
 					Description: "Container image when mode=container",
 				},
+				"env": {
+					Name:        "env",
+					Type:        TypeList,
+					Description: "Host environment variables passed into the container",
+					ItemDef:     &FieldDef{Name: "name", Type: TypeString},
+				},
 			},
 		},
 		"api": {
@@ -593,8 +665,8 @@ func DefaultConfig() *Config {
 				BreakingMode string `yaml:"breaking_mode,omitempty"`
 			}{BreakingMode: "strict"},
 			Parquet: struct {
-				AllowAdditiveNullableOnly bool `yaml:"allow_additive_nullable_only,omitempty"`
-			}{AllowAdditiveNullableOnly: true},
+				AllowAdditiveNullableOnly *bool `yaml:"allow_additive_nullable_only,omitempty"`
+			}{AllowAdditiveNullableOnly: new(true)},
 		},
 		Release: ReleaseConfig{
 			TagFormat: "{subdir}/v{version}",
@@ -59,6 +59,17 @@ func TestDefaultConfig_RoundTrip(t *testing.T) {
 }
 
 // T028 extra: MarshalConfigString produces readable output with section spacing.
+func TestTools_Versions(t *testing.T) {
+	versions := DefaultConfig().Tools.Versions()
+	assert.Equal(t, map[string]string{
+		"buf":      "v1.66.1",
+		"oasdiff":  "v1.9.6",
+		"spectral": "v6.11.0",
+		"avrotool": "1.11.3",
+	}, versions)
+	assert.Empty(t, Tools{}.Versions())
+}
+
 func TestMarshalConfigString_Formatting(t *testing.T) {
 	cfg := DefaultConfig()
 	s, err := MarshalConfigString(cfg)
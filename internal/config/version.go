@@ -369,6 +369,72 @@ func SuggestVersion(current string, hasBreaking, hasChanges bool, lifecycle, lin
 	}, nil
 }
 
+// RequiredBump returns the bump a release needs for the classified changes
+// on a line with the given major version: breaking changes need a new major
+// line (MAJOR), except on v0 where they take a MINOR bump; additive changes
+// need MINOR; anything else is a PATCH.
+func RequiredBump(lineMajor int, hasBreaking, hasChanges bool) BumpKind {
+	switch {
+	case hasBreaking && lineMajor > 0:
+		return BumpMajor
+	case hasBreaking, hasChanges:
+		return BumpMinor
+	default:
+		return BumpPatch
+	}
+}
+
+// bumpRank orders bump kinds from smallest to largest.
+var bumpRank = map[BumpKind]int{BumpNone: 0, BumpPatch: 1, BumpMinor: 2, BumpMajor: 3}
+
+// ValidateVersionBump checks that requested bumps previous by exactly what
+// the classified changes call for. An under-bump hides changes from
+// consumers and an over-bump announces changes that are not there. Moving
+// from a prerelease to another prerelease or the final release of the same
+// version is not a bump and is not checked, nor is a version that does not
+// move forward (the release ratchet rejects those).
+func ValidateVersionBump(previous, requested string, hasBreaking, hasChanges bool) error {
+	prev, err := ParseSemVer(previous)
+	if err != nil {
+		return fmt.Errorf("invalid previous version %q: %w", previous, err)
+	}
+	next, err := ParseSemVer(requested)
+	if err != nil {
+		return fmt.Errorf("invalid version %q: %w", requested, err)
+	}
+	if CompareSemVer(next, prev) <= 0 {
+		return nil
+	}
+
+	var actual BumpKind
+	switch {
+	case next.Major != prev.Major:
+		actual = BumpMajor
+	case next.Minor != prev.Minor:
+		actual = BumpMinor
+	case next.Patch != prev.Patch:
+		actual = BumpPatch
+	default:
+		return nil // same version, prerelease iteration
+	}
+
+	required := RequiredBump(prev.Major, hasBreaking, hasChanges)
+	if required == BumpMajor && actual != BumpMajor {
+		return fmt.Errorf(
+			"breaking changes since %s cannot be released as %s; create a new API line v%d",
+			previous, requested, prev.Major+1)
+	}
+	switch {
+	case bumpRank[actual] < bumpRank[required]:
+		return fmt.Errorf("%s is a %s bump from %s, but the changes require a %s bump",
+			requested, actual, previous, required)
+	case bumpRank[actual] > bumpRank[required]:
+		return fmt.Errorf("%s is a %s bump from %s, but the changes only warrant a %s bump",
+			requested, actual, previous, required)
+	}
+	return nil
+}
+
 // lifecyclePrerelease returns the prerelease suffix for the given lifecycle state.
 // The counter parameter is appended (e.g. "alpha.1", "beta.2").
 func lifecyclePrerelease(lifecycle string, counter int) string {
@@ -407,3 +407,45 @@ func TestValidateVersionLine_V0(t *testing.T) {
 	assert.NoError(t, ValidateVersionLine("v0.1.0-alpha.1", "v0"))
 	assert.Error(t, ValidateVersionLine("v1.0.0", "v0"))
 }
+
+// ---------------------------------------------------------------------------
+// ValidateVersionBump
+// ---------------------------------------------------------------------------
+
+func TestValidateVersionBump(t *testing.T) {
+	tests := []struct {
+		name                string
+		previous, requested string
+		breaking, changes   bool
+		wantErr             string
+	}{
+		{name: "patch for no change", previous: "v1.2.3", requested: "v1.2.4"},
+		{name: "minor for additive", previous: "v1.2.3", requested: "v1.3.0", changes: true},
+		{name: "under-bump", previous: "v1.2.3", requested: "v1.2.4", changes: true, wantErr: "require a MINOR bump"},
+		{name: "over-bump", previous: "v1.2.3", requested: "v1.3.0", wantErr: "only warrant a PATCH bump"},
+		{name: "breaking on v1", previous: "v1.2.3", requested: "v1.3.0", breaking: true, changes: true, wantErr: "create a new API line v2"},
+		{name: "breaking on v0 is minor", previous: "v0.2.0", requested: "v0.3.0", breaking: true, changes: true},
+		{name: "prerelease iteration", previous: "v1.3.0-beta.1", requested: "v1.3.0-beta.2", changes: true},
+		{name: "prerelease to final", previous: "v1.3.0-beta.2", requested: "v1.3.0"},
+		{name: "minor prerelease for additive", previous: "v1.2.0", requested: "v1.3.0-alpha.1", changes: true},
+		{name: "not forward", previous: "v1.2.0", requested: "v1.1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVersionBump(tt.previous, tt.requested, tt.breaking, tt.changes)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRequiredBump(t *testing.T) {
+	assert.Equal(t, BumpMajor, RequiredBump(1, true, true))
+	assert.Equal(t, BumpMinor, RequiredBump(0, true, true))
+	assert.Equal(t, BumpMinor, RequiredBump(1, false, true))
+	assert.Equal(t, BumpPatch, RequiredBump(1, false, false))
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WaiversFile is the checked-in file, next to apx.yaml, that lists the
+// breaking changes an API is knowingly allowed to ship.
+const WaiversFile = "apx-waivers.yaml"
+
+// waiverDateLayout is the layout of a waiver's expiry date.
+const waiverDateLayout = "2006-01-02"
+
+// Waivers is the content of apx-waivers.yaml:
+//
+//	version: 1
+//	waivers:
+//	  - api_id: proto/payments/ledger/v1
+//	    rule: FIELD_NO_DELETE
+//	    path: ledger.proto
+//	    owner: payments-team
+//	    reason: memo was never populated; consumers confirmed
+//	    expires: 2026-12-31
+type Waivers struct {
+	Version int      `yaml:"version"`
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// Waiver allows one breaking-change finding — identified by API ID, rule
+// and element path — until it expires. Owner and Reason justify it; an
+// expired waiver fails the check instead of hiding the finding.
+type Waiver struct {
+	APIID string `yaml:"api_id" json:"api_id"`
+	Rule  string `yaml:"rule" json:"rule"`
+	// Path is the finding's element path (e.g. "User.email", "/pets GET"),
+	// or its file for checks that report no element path (e.g. buf).
+	Path    string `yaml:"path" json:"path"`
+	Owner   string `yaml:"owner" json:"owner"`
+	Reason  string `yaml:"reason" json:"reason"`
+	Expires string `yaml:"expires" json:"expires"` // YYYY-MM-DD, the last day the waiver applies
+}
+
+// Validate checks that every field of the waiver is set and the expiry is
+// a date.
+func (w Waiver) Validate() error {
+	for _, f := range []struct{ name, value string }{
+		{"api_id", w.APIID}, {"rule", w.Rule}, {"path", w.Path},
+		{"owner", w.Owner}, {"reason", w.Reason}, {"expires", w.Expires},
+	} {
+		if f.value == "" {
+			return fmt.Errorf("%s is required", f.name)
+		}
+	}
+	if _, err := ParseAPIID(w.APIID); err != nil {
+		return err
+	}
+	if _, err := time.Parse(waiverDateLayout, w.Expires); err != nil {
+		return fmt.Errorf("invalid expires %q: expected YYYY-MM-DD", w.Expires)
+	}
+	return nil
+}
+
+// Expired reports whether the waiver no longer applies at now. A waiver
+// applies through the whole of its expiry day (UTC).
+func (w Waiver) Expired(now time.Time) bool {
+	expires, err := time.Parse(waiverDateLayout, w.Expires)
+	if err != nil {
+		return true
+	}
+	return !now.UTC().Before(expires.AddDate(0, 0, 1))
+}
+
+// LoadWaivers reads and validates a waivers file. A missing file means no
+// waivers.
+func LoadWaivers(path string) ([]Waiver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var file Waivers
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if file.Version != 0 && file.Version != 1 {
+		return nil, fmt.Errorf("%s: unsupported version %d", path, file.Version)
+	}
+	for i, w := range file.Waivers {
+		if err := w.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: waiver %d: %w", path, i+1, err)
+		}
+	}
+	return file.Waivers, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWaivers(t *testing.T) {
+	dir := t.TempDir()
+
+	waivers, err := LoadWaivers(filepath.Join(dir, WaiversFile))
+	require.NoError(t, err)
+	assert.Nil(t, waivers, "a missing file means no waivers")
+
+	path := filepath.Join(dir, WaiversFile)
+	require.NoError(t, os.WriteFile(path, []byte(`version: 1
+waivers:
+  - api_id: proto/payments/ledger/v1
+    rule: FIELD_NO_DELETE
+    path: ledger.proto
+    owner: payments-team
+    reason: memo was never populated
+    expires: 2026-12-31
+`), 0o644))
+	waivers, err = LoadWaivers(path)
+	require.NoError(t, err)
+	require.Len(t, waivers, 1)
+	assert.Equal(t, Waiver{
+		APIID: "proto/payments/ledger/v1", Rule: "FIELD_NO_DELETE", Path: "ledger.proto",
+		Owner: "payments-team", Reason: "memo was never populated", Expires: "2026-12-31",
+	}, waivers[0])
+}
+
+func TestLoadWaivers_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing owner", "waivers:\n  - {api_id: proto/a/b/v1, rule: R, path: p, reason: r, expires: 2026-01-01}\n", "waiver 1: owner is required"},
+		{"bad date", "waivers:\n  - {api_id: proto/a/b/v1, rule: R, path: p, owner: o, reason: r, expires: next week}\n", "invalid expires"},
+		{"bad api id", "waivers:\n  - {api_id: ledger, rule: R, path: p, owner: o, reason: r, expires: 2026-01-01}\n", "waiver 1"},
+		{"version", "version: 2\n", "unsupported version 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), WaiversFile)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+			_, err := LoadWaivers(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWaiver_Expired(t *testing.T) {
+	w := Waiver{Expires: "2026-06-30"}
+	assert.False(t, w.Expired(time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, w.Expired(time.Date(2026, 6, 30, 23, 59, 59, 0, time.UTC)), "a waiver applies through its expiry day")
+	assert.True(t, w.Expired(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -49,6 +49,16 @@ func (g *goPlugin) UnlinkHint(ctx DerivationContext) *UnlinkHint {
 	}
 }
 
+// Scaffold implements Scaffolder — writes a go.mod declaring the canonical
+// module path.
+func (g *goPlugin) Scaffold(overlayPath string, ctx DerivationContext) error {
+	coords, err := g.DeriveCoords(ctx)
+	if err != nil {
+		return err
+	}
+	return overlay.ScaffoldGoModule(overlayPath, coords.Module)
+}
+
 // PostGen implements PostGenHook — runs go.work sync after Go code generation.
 func (g *goPlugin) PostGen(workDir string) error {
 	mgr := overlay.NewManager(workDir)
@@ -1,6 +1,8 @@
 package language
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/infobloxopen/apx/internal/config"
@@ -143,3 +145,18 @@ func TestDeriveGoImport(t *testing.T) {
 		})
 	}
 }
+
+func TestGoPlugin_Scaffold(t *testing.T) {
+	p := Get("go")
+	s, ok := p.(Scaffolder)
+	require.True(t, ok, "go plugin must implement Scaffolder")
+
+	api, err := config.ParseAPIID("proto/payments/ledger/v2")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, s.Scaffold(dir, DerivationContext{SourceRepo: "github.com/acme/apis", API: api}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "module github.com/acme/apis/proto/payments/ledger/v2\n")
+}
@@ -48,10 +48,16 @@ func (p *pythonPlugin) UnlinkHint(ctx DerivationContext) *UnlinkHint {
 }
 
 // Scaffold implements Scaffolder — creates pyproject.toml and __init__.py hierarchy.
+// Only protobuf overlays depend on grpcio and protobuf; the models generated
+// for other formats use the standard library.
 func (p *pythonPlugin) Scaffold(overlayPath string, ctx DerivationContext) error {
 	distName := derivePythonDistName(ctx.Org, ctx.API)
 	importRoot := derivePythonImport(ctx.Org, ctx.API)
-	return overlay.ScaffoldPythonPackage(overlayPath, distName, importRoot)
+	var deps []string
+	if ctx.API.Format == "proto" {
+		deps = overlay.PythonProtoDependencies
+	}
+	return overlay.ScaffoldPythonPackageWithDeps(overlayPath, distName, importRoot, deps)
 }
 
 // Link implements Linker — runs pip install -e for Python overlays.
@@ -2,9 +2,13 @@ package language
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/overlay"
+	"github.com/infobloxopen/apx/internal/ui"
 )
 
 func init() {
@@ -41,6 +45,128 @@ func (ts *typescriptPlugin) UnlinkHint(ctx DerivationContext) *UnlinkHint {
 	}
 }
 
+// Scaffold implements Scaffolder — creates package.json with the scoped npm name.
+// Native models are exported from index.ts; protobuf code is imported by subpath.
+func (ts *typescriptPlugin) Scaffold(overlayPath string, ctx DerivationContext) error {
+	entryPoint := "index.ts"
+	if ctx.API.Format == "proto" {
+		entryPoint = ""
+	}
+	return overlay.ScaffoldTypeScriptPackage(overlayPath, deriveNpmPackage(ctx.Org, ctx.API), entryPoint)
+}
+
+// Link implements Linker — registers TypeScript overlays as workspace packages
+// in package.json "workspaces" (npm, yarn) or pnpm-workspace.yaml (pnpm).
+// Without a filter, overlays that no longer exist are unregistered.
+func (ts *typescriptPlugin) Link(workDir, filterPath string) error {
+	mgr := overlay.NewManager(workDir)
+	overlays, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("listing overlays: %w", err)
+	}
+	prefix, err := workspaceEntry(workDir, mgr.Path("", "typescript"))
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, ov := range overlays {
+		if ov.Language != "typescript" {
+			continue
+		}
+		if filterPath != "" && ov.ModulePath != filterPath {
+			continue
+		}
+
+		// Only link overlays that have a package.json (scaffolded).
+		if _, err := os.Stat(filepath.Join(ov.Path, "package.json")); os.IsNotExist(err) {
+			ui.Warning("Skipping %s — no package.json (run 'apx gen typescript' first)", ov.ModulePath)
+			continue
+		}
+		entry, err := workspaceEntry(workDir, ov.Path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, entry)
+	}
+
+	if filterPath != "" && len(paths) == 0 {
+		return fmt.Errorf("no TypeScript overlay found for %s — run 'apx gen typescript' first", filterPath)
+	}
+
+	ws := overlay.FindNpmWorkspace(workDir)
+	if filterPath != "" {
+		prefix = "" // keep the other registered overlays
+	}
+	changed, err := ws.Register(paths, prefix)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", filepath.Base(ws.Path), err)
+	}
+
+	if len(paths) == 0 {
+		ui.Info("No TypeScript overlays to link. Run 'apx gen typescript' first.")
+		return nil
+	}
+	if changed {
+		ui.Info("Run '%s install' to link the workspace packages", ws.Manager)
+	}
+	ui.Success("Linked %d TypeScript overlay(s) in %s", len(paths), filepath.Base(ws.Path))
+	return nil
+}
+
+// Unlink implements Unlinker — removes TypeScript overlays from the workspace
+// packages. Without a filter, every registered overlay is removed.
+func (ts *typescriptPlugin) Unlink(workDir, filterPath string) error {
+	mgr := overlay.NewManager(workDir)
+	var paths []string
+	prefix := ""
+	if filterPath != "" {
+		entry, err := workspaceEntry(workDir, mgr.Path(filterPath, "typescript"))
+		if err != nil {
+			return err
+		}
+		paths = append(paths, entry)
+	} else {
+		entry, err := workspaceEntry(workDir, mgr.Path("", "typescript"))
+		if err != nil {
+			return err
+		}
+		prefix = entry
+	}
+
+	ws := overlay.FindNpmWorkspace(workDir)
+	unlinked, err := ws.Unregister(paths, prefix)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", filepath.Base(ws.Path), err)
+	}
+
+	if unlinked == 0 {
+		ui.Info("No linked TypeScript overlays found.")
+		return nil
+	}
+
+	ui.Success("Unlinked %d TypeScript overlay(s) from %s", unlinked, filepath.Base(ws.Path))
+	return nil
+}
+
+// workspaceEntry returns path as a workspace package entry: slash-separated
+// and relative to the workspace root.
+func workspaceEntry(workDir, path string) (string, error) {
+	root, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
 // ---------------------------------------------------------------------------
 // TypeScript / npm identity derivation (private to this plugin)
 // ---------------------------------------------------------------------------
@@ -48,10 +174,11 @@ func (ts *typescriptPlugin) UnlinkHint(ctx DerivationContext) *UnlinkHint {
 // deriveNpmPackage computes the scoped npm package name for an API.
 //
 // Rules:
-//   - Pattern: @<org>/<domain>-<name>-<line>-proto (4-part) or @<org>/<name>-<line>-proto (3-part)
-//   - Lowercased, hyphens join path segments, -proto suffix
+//   - Pattern: @<org>/<domain>-<name>-<line>-<format> (4-part) or @<org>/<name>-<line>-<format> (3-part)
+//   - Lowercased, hyphens join path segments, schema format suffix
 //   - Example: org="acme", proto/payments/ledger/v1 → "@acme/payments-ledger-v1-proto"
 //   - Example: org="acme", proto/orders/v1 (3-part) → "@acme/orders-v1-proto"
+//   - Example: org="acme", avro/orders/events/v1 → "@acme/orders-events-v1-avro"
 func deriveNpmPackage(org string, api *config.APIIdentity) string {
 	scope := strings.ToLower(org)
 	var parts []string
@@ -60,6 +187,10 @@ func deriveNpmPackage(org string, api *config.APIIdentity) string {
 	}
 	parts = append(parts, strings.ToLower(api.Name))
 	parts = append(parts, strings.ToLower(api.Line))
-	parts = append(parts, "proto")
+	format := strings.ToLower(api.Format)
+	if format == "" {
+		format = "proto"
+	}
+	parts = append(parts, format)
 	return "@" + scope + "/" + strings.Join(parts, "-")
 }
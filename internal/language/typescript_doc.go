@@ -12,10 +12,10 @@ func (t *typescriptPlugin) DocMeta() DocMeta {
 	return DocMeta{
 		SupportMatrix: map[string]string{
 			"published_artifact": "npm package",
-			"local_overlay":      "`npm link`",
-			"resolution":         "npm/yarn",
+			"local_overlay":      "npm/yarn/pnpm workspaces",
+			"resolution":         "npm/yarn/pnpm",
 			"codegen":            "protoc + ts-proto",
-			"dev_command":        "`npm link`",
+			"dev_command":        "`apx sync typescript`",
 			"unlink_hint":        "`npm install ...`",
 			"tier":               "Tier 2",
 		},
@@ -1,9 +1,12 @@
 package language
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/overlay"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,7 +46,86 @@ func TestTypescriptPlugin_UnlinkHint(t *testing.T) {
 	p := Get("typescript")
 	hint := p.UnlinkHint(DerivationContext{Org: "acme", API: api})
 	require.NotNil(t, hint)
-	assert.Contains(t, hint.Message, "npm install")
+	assert.Contains(t, hint.Message, "npm install @acme/payments-ledger-v1-proto")
+
+	api, _ = config.ParseAPIID("jsonschema/users/profile/v1")
+	hint = p.UnlinkHint(DerivationContext{Org: "acme", API: api})
+	require.NotNil(t, hint)
+	assert.Contains(t, hint.Message, "npm install @acme/users-profile-v1-jsonschema")
+}
+
+func TestTypescriptPlugin_ImplementsLinker(t *testing.T) {
+	p := Get("typescript")
+	_, ok := p.(Scaffolder)
+	assert.True(t, ok, "TypeScript plugin should implement Scaffolder")
+	_, ok = p.(Linker)
+	assert.True(t, ok, "TypeScript plugin should implement Linker")
+	_, ok = p.(Unlinker)
+	assert.True(t, ok, "TypeScript plugin should implement Unlinker")
+}
+
+func TestTypescriptPlugin_Scaffold(t *testing.T) {
+	p := Get("typescript").(Scaffolder)
+	tests := []struct {
+		apiID    string
+		wantName string
+		wantMain bool
+	}{
+		{"proto/payments/ledger/v1", "@acme/payments-ledger-v1-proto", false},
+		{"avro/orders/events/v1", "@acme/orders-events-v1-avro", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiID, func(t *testing.T) {
+			api, err := config.ParseAPIID(tt.apiID)
+			require.NoError(t, err)
+			dir := t.TempDir()
+			require.NoError(t, p.Scaffold(dir, DerivationContext{Org: "acme", API: api}))
+
+			data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"name": "`+tt.wantName+`"`)
+			if tt.wantMain {
+				assert.Contains(t, string(data), `"main": "index.ts"`)
+			} else {
+				assert.NotContains(t, string(data), `"main"`)
+			}
+		})
+	}
+}
+
+func TestTypescriptPlugin_LinkUnlink(t *testing.T) {
+	root := t.TempDir()
+	for _, mod := range []string{"proto/payments/ledger/v1", "proto/orders/v1"} {
+		dir := filepath.Join(root, "internal", "gen", "typescript", filepath.FromSlash(mod))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "x"}`), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package.json"),
+		[]byte(`{"name": "web", "private": true, "workspaces": ["apps/*"]}`), 0644))
+	p := Get("typescript")
+	ws := overlay.FindNpmWorkspace(root)
+
+	require.NoError(t, p.(Linker).Link(root, ""))
+	packages, err := ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"apps/*",
+		"internal/gen/typescript/proto/orders/v1",
+		"internal/gen/typescript/proto/payments/ledger/v1",
+	}, packages)
+
+	require.NoError(t, p.(Unlinker).Unlink(root, "proto/orders/v1"))
+	packages, err = ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/*", "internal/gen/typescript/proto/payments/ledger/v1"}, packages)
+
+	require.NoError(t, p.(Unlinker).Unlink(root, ""))
+	packages, err = ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/*"}, packages)
+
+	err = p.(Linker).Link(root, "proto/missing/v1")
+	assert.ErrorContains(t, err, "no TypeScript overlay found for proto/missing/v1")
 }
 
 // ---------------------------------------------------------------------------
@@ -81,6 +163,12 @@ func TestDeriveNpmPackage(t *testing.T) {
 			api:  &config.APIIdentity{Format: "proto", Domain: "payments", Name: "ledger", Line: "v1"},
 			want: "@acme-corp/payments-ledger-v1-proto",
 		},
+		{
+			name: "non-proto format suffix",
+			org:  "acme",
+			api:  &config.APIIdentity{Format: "avro", Domain: "orders", Name: "events", Line: "v1"},
+			want: "@acme/orders-events-v1-avro",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
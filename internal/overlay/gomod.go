@@ -0,0 +1,34 @@
+package overlay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScaffoldGoModule writes a go.mod declaring the canonical module path at the
+// root of a Go overlay, so go.work resolves canonical imports to the overlay.
+// An existing go.mod that already declares the module is left untouched, so
+// requirements added by `go mod tidy` survive regeneration.
+//
+// Parameters:
+//   - overlayPath: absolute path to the overlay directory (e.g. internal/gen/go/proto/payments/ledger/v1/)
+//   - modulePath: canonical Go module path (e.g. "github.com/acme/apis/proto/payments/ledger")
+func ScaffoldGoModule(overlayPath, modulePath string) error {
+	goModPath := filepath.Join(overlayPath, "go.mod")
+	if data, err := os.ReadFile(goModPath); err == nil {
+		first, _, _ := strings.Cut(string(data), "\n")
+		if strings.TrimSpace(first) == "module "+modulePath {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(overlayPath, 0755); err != nil {
+		return fmt.Errorf("creating overlay directory: %w", err)
+	}
+	content := fmt.Sprintf("module %s\n\ngo 1.24\n", modulePath)
+	if err := os.WriteFile(goModPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	return nil
+}
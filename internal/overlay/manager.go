@@ -51,6 +51,7 @@
 // Create: apx gen go → generates code into internal/gen/go/{path}@{version}/
 // Sync:   apx sync   → updates go.work with all Go overlay paths
 // Remove: apx unlink → deletes overlay, regenerates go.work
+// Prune:  apx sync --prune → deletes overlays apx.lock no longer pins
 //
 // # Language-Specific Behavior
 //
@@ -58,7 +59,8 @@
 // - Other languages: internal/gen/{language}/{modulePath}/
 //
 // Only Go overlays are added to go.work. Other languages use their own
-// resolution mechanisms (Python PYTHONPATH, Java classpath, etc.)
+// resolution mechanisms (Python PYTHONPATH, TypeScript workspace packages,
+// Java classpath, etc.)
 //
 // See /specs/001-align-docs-experience/overlays.md for detailed design documentation.
 package overlay
@@ -132,6 +134,22 @@ func NewManager(workspaceRoot string) *Manager {
 	}
 }
 
+// NewManagerAt creates an overlay manager that keeps overlays under
+// overlayDir instead of {workspaceRoot}/internal/gen/. go.work is still
+// written at the workspace root.
+func NewManagerAt(workspaceRoot, overlayDir string) *Manager {
+	return &Manager{
+		workspaceRoot: workspaceRoot,
+		overlayDir:    overlayDir,
+	}
+}
+
+// Path returns the overlay directory of a module in the given language,
+// whether or not it exists.
+func (m *Manager) Path(modulePath, language string) string {
+	return filepath.Join(m.overlayDir, language, modulePath)
+}
+
 // Create creates a new overlay for a module.
 //
 // Directory structure differs by language:
@@ -152,7 +170,7 @@ func NewManager(workspaceRoot string) *Manager {
 //	// Then call mgr.Sync() to update go.work
 func (m *Manager) Create(modulePath, language string) (*Overlay, error) {
 	// All languages get a language-specific subdirectory
-	overlayPath := filepath.Join(m.overlayDir, language, modulePath)
+	overlayPath := m.Path(modulePath, language)
 
 	if err := os.MkdirAll(overlayPath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create overlay directory: %w", err)
@@ -221,7 +239,7 @@ func (m *Manager) Remove(modulePath string) error {
 
 	if !removed {
 		// Try to remove from each language directory in case List() missed it
-		for _, lang := range []string{"go", "python", "java"} {
+		for _, lang := range []string{"go", "python", "java", "typescript"} {
 			overlayPath := filepath.Join(m.overlayDir, lang, modulePath)
 			if err := os.RemoveAll(overlayPath); err != nil && !os.IsNotExist(err) {
 				return fmt.Errorf("failed to remove overlay: %w", err)
@@ -260,7 +278,9 @@ func (m *Manager) pruneEmptyParents(removedPath, language string) {
 // List returns all overlays in the workspace.
 //
 // This scans the internal/gen/ directory tree and identifies actual overlay
-// directories. The structure is:
+// directories: those holding a package manifest (go.mod, pyproject.toml,
+// package.json), whose generated code may live in subdirectories, and
+// otherwise leaf directories. The structure is:
 //
 //	internal/gen/{language}/{modulePath}/
 //
@@ -319,20 +339,26 @@ func (m *Manager) List() ([]Overlay, error) {
 			}
 
 			hasSubdirs := false
+			hasMarker := false
 			for _, entry := range entries {
 				if entry.IsDir() {
 					hasSubdirs = true
-					break
+				} else if overlayMarkers[entry.Name()] {
+					hasMarker = true
 				}
 			}
 
-			// This is an overlay if it's a leaf directory (no subdirectories)
-			if !hasSubdirs {
+			// This is an overlay if it holds a package manifest (generated
+			// code may live in subdirectories) or is a leaf directory
+			if hasMarker || !hasSubdirs {
 				overlays = append(overlays, Overlay{
 					ModulePath: relPath,
 					Language:   language,
 					Path:       path,
 				})
+				if hasSubdirs {
+					return filepath.SkipDir
+				}
 			}
 
 			return nil
@@ -346,6 +372,14 @@ func (m *Manager) List() ([]Overlay, error) {
 	return overlays, nil
 }
 
+// overlayMarkers are the package manifests scaffolded at the root of an
+// overlay.
+var overlayMarkers = map[string]bool{
+	"go.mod":         true,
+	"pyproject.toml": true,
+	"package.json":   true,
+}
+
 // CreateOverlay creates a go.work overlay for a module
 func (m *Manager) CreateOverlay(canonicalImportPath, localPath string) error {
 	// Ensure overlay directory exists
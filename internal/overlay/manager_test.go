@@ -192,6 +192,54 @@ func TestListOverlays(t *testing.T) {
 	}
 }
 
+func TestListOverlays_GeneratedSubdirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManager(tmpDir)
+
+	ov, err := mgr.Create("proto/payments/ledger/v1", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Generated code lives in a package subdirectory of the module.
+	if err := os.WriteFile(filepath.Join(ov.Path, "go.mod"), []byte("module example.com/ledger\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(ov.Path, "v1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := mgr.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ModulePath != "proto/payments/ledger/v1" {
+		t.Errorf("expected the overlay root only, got %+v", list)
+	}
+}
+
+func TestNewManagerAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManagerAt(tmpDir, filepath.Join(tmpDir, "gen"))
+
+	ov, err := mgr.Create("proto/payments/ledger/v1", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(tmpDir, "gen", "go", "proto", "payments", "ledger", "v1"); ov.Path != want {
+		t.Errorf("overlay path = %s, want %s", ov.Path, want)
+	}
+	if err := mgr.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "./gen/go/proto/payments/ledger/v1") {
+		t.Errorf("expected go.work to use the custom overlay root, got:\n%s", content)
+	}
+}
+
 func TestSyncIdempotent(t *testing.T) {
 	tmpDir := t.TempDir()
 	mgr := NewManager(tmpDir)
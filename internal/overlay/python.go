@@ -17,16 +17,26 @@ build-backend = "setuptools.build_meta"
 name = "{{ .DistName }}"
 version = "0.0.0.dev0"
 requires-python = ">=3.9"
-dependencies = [
-    "grpcio>=1.60",
-    "protobuf>=4.25",
+dependencies = [{{ range .Dependencies }}
+    "{{ . }}",{{ end }}
 ]
 
 [tool.setuptools.packages.find]
 include = ["{{ .Namespace }}*"]
 `))
 
-// ScaffoldPythonPackage generates a Python package structure inside an overlay directory.
+// PythonProtoDependencies are the runtime dependencies of the protobuf and
+// gRPC code generated for a Python overlay.
+var PythonProtoDependencies = []string{"grpcio>=1.60", "protobuf>=4.25"}
+
+// ScaffoldPythonPackage generates a Python package structure for protobuf
+// code inside an overlay directory. See ScaffoldPythonPackageWithDeps.
+func ScaffoldPythonPackage(overlayPath, distName, importRoot string) error {
+	return ScaffoldPythonPackageWithDeps(overlayPath, distName, importRoot, PythonProtoDependencies)
+}
+
+// ScaffoldPythonPackageWithDeps generates a Python package structure inside
+// an overlay directory, declaring the given runtime dependencies.
 //
 // It creates:
 //   - pyproject.toml with PEP 621 metadata
@@ -36,7 +46,8 @@ include = ["{{ .Namespace }}*"]
 //   - overlayPath: absolute path to the overlay directory (e.g. internal/gen/python/proto/payments/ledger/v1/)
 //   - distName: PEP 625 distribution name (e.g. "acme-payments-ledger-v1")
 //   - importRoot: dotted import path (e.g. "acme_apis.payments.ledger.v1")
-func ScaffoldPythonPackage(overlayPath, distName, importRoot string) error {
+//   - dependencies: PEP 508 requirements (e.g. PythonProtoDependencies)
+func ScaffoldPythonPackageWithDeps(overlayPath, distName, importRoot string, dependencies []string) error {
 	// Write pyproject.toml
 	namespace := strings.SplitN(importRoot, ".", 2)[0]
 	pyprojectPath := filepath.Join(overlayPath, "pyproject.toml")
@@ -47,11 +58,13 @@ func ScaffoldPythonPackage(overlayPath, distName, importRoot string) error {
 	defer f.Close()
 
 	if err := pyprojectTmpl.Execute(f, struct {
-		DistName  string
-		Namespace string
+		DistName     string
+		Namespace    string
+		Dependencies []string
 	}{
-		DistName:  distName,
-		Namespace: namespace,
+		DistName:     distName,
+		Namespace:    namespace,
+		Dependencies: dependencies,
 	}); err != nil {
 		return fmt.Errorf("rendering pyproject.toml: %w", err)
 	}
@@ -86,3 +86,15 @@ func TestScaffoldPythonPackage_PyprojectFormat(t *testing.T) {
 	assert.True(t, strings.Contains(content, `"protobuf>=4.25"`))
 	assert.True(t, strings.Contains(content, `include = ["myorg_apis*"]`))
 }
+
+func TestScaffoldPythonPackageWithDeps_NoDependencies(t *testing.T) {
+	dir := t.TempDir()
+
+	err := ScaffoldPythonPackageWithDeps(dir, "myorg-events-click-v1", "myorg_apis.events.click.v1", nil)
+	require.NoError(t, err)
+
+	pyproject, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(pyproject), "dependencies = [\n]")
+	assert.NotContains(t, string(pyproject), "grpcio")
+}
@@ -0,0 +1,179 @@
+package overlay
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Reconciliation is the difference between the overlays on disk and the
+// dependencies locked in apx.lock.
+type Reconciliation struct {
+	// Orphaned overlays belong to a module that is no longer locked, or
+	// were generated for a version other than the locked one.
+	Orphaned []Overlay
+	// Disabled overlays belong to a language that is not enabled in
+	// language_targets.
+	Disabled []Overlay
+	// Conflicts are API lines go.work uses more than one overlay for.
+	Conflicts []WorkConflict
+}
+
+// WorkConflict is an API line that go.work resolves to several overlays,
+// typically two versions of one module left behind by an update.
+type WorkConflict struct {
+	APIID string   // e.g. "proto/payments/ledger/v1"
+	Paths []string // go.work use paths, e.g. "./internal/gen/go/proto/payments/ledger@v1.2.3"
+}
+
+// Reconcile compares the overlays on disk with the locked dependencies,
+// given as API ID to locked ref. When enabled is non-nil, overlays of
+// languages it does not list are reported as disabled. Reconcile changes
+// nothing; see RemoveOrphans.
+func (m *Manager) Reconcile(locked map[string]string, enabled map[string]bool) (*Reconciliation, error) {
+	overlays, err := m.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overlays: %w", err)
+	}
+
+	rec := &Reconciliation{}
+	for _, ov := range overlays {
+		apiID, version := ParseOverlayModule(ov.ModulePath)
+		ref, ok := locked[apiID]
+		if !ok || (version != "" && strings.TrimPrefix(version, "v") != strings.TrimPrefix(ref, "v")) {
+			rec.Orphaned = append(rec.Orphaned, ov)
+			continue
+		}
+		if enabled != nil && !enabled[ov.Language] {
+			rec.Disabled = append(rec.Disabled, ov)
+		}
+	}
+
+	if rec.Conflicts, err = m.WorkConflicts(); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// RemoveOrphans deletes the orphaned overlays of a reconciliation, along
+// with the parent directories they leave empty. go.work is not updated;
+// call Sync afterwards.
+func (m *Manager) RemoveOrphans(rec *Reconciliation) error {
+	for _, ov := range rec.Orphaned {
+		if err := os.RemoveAll(ov.Path); err != nil {
+			return fmt.Errorf("failed to remove overlay %s: %w", ov.Path, err)
+		}
+		m.pruneEmptyParents(ov.Path, ov.Language)
+	}
+	return nil
+}
+
+// WorkConflicts reports the API lines that go.work uses more than one Go
+// overlay for. Use paths outside the Go overlay root are ignored.
+func (m *Manager) WorkConflicts() ([]WorkConflict, error) {
+	uses, err := readWorkUses(filepath.Join(m.workspaceRoot, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+
+	goRoot, err := filepath.Abs(filepath.Join(m.overlayDir, "go"))
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(m.workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	byLine := map[string][]string{}
+	for _, use := range uses {
+		rel, err := filepath.Rel(goRoot, filepath.Join(root, filepath.FromSlash(use)))
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		apiID, _ := ParseOverlayModule(filepath.ToSlash(rel))
+		byLine[apiID] = append(byLine[apiID], use)
+	}
+
+	var conflicts []WorkConflict
+	for apiID, paths := range byLine {
+		if len(paths) > 1 {
+			conflicts = append(conflicts, WorkConflict{APIID: apiID, Paths: paths})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].APIID < conflicts[j].APIID })
+	return conflicts, nil
+}
+
+// ParseOverlayModule splits the module path of an overlay into the API ID
+// it belongs to and the version in its directory name, if any. Overlays
+// are laid out by API ID ("proto/payments/ledger/v1") or by module and
+// version ("proto/payments/ledger@v1.2.3", whose line is v1).
+func ParseOverlayModule(modulePath string) (apiID, version string) {
+	base, version, found := strings.Cut(modulePath, "@")
+	if !found {
+		return modulePath, ""
+	}
+	if isLineSegment(base[strings.LastIndex(base, "/")+1:]) {
+		return base, version
+	}
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return base + "/v" + major, version
+}
+
+// isLineSegment reports whether a path segment is an API line such as v1.
+func isLineSegment(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// readWorkUses returns the use paths of a go.work file, from both the
+// block and the single-line forms. A missing file has none.
+func readWorkUses(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var uses []string
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "":
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			uses = append(uses, strings.Trim(line, `"`))
+		case strings.HasPrefix(line, "use ") || strings.HasPrefix(line, "use("):
+			rest := strings.TrimSpace(strings.TrimPrefix(line, "use"))
+			if rest == "(" {
+				inBlock = true
+			} else {
+				uses = append(uses, strings.Trim(rest, `"`))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return uses, nil
+}
@@ -0,0 +1,133 @@
+package overlay
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseOverlayModule(t *testing.T) {
+	tests := []struct {
+		modulePath  string
+		wantAPIID   string
+		wantVersion string
+	}{
+		{"proto/payments/ledger/v1", "proto/payments/ledger/v1", ""},
+		{"proto/payments/ledger@v1.2.3", "proto/payments/ledger/v1", "v1.2.3"},
+		{"proto/payments/ledger@v2.0.0-beta.1", "proto/payments/ledger/v2", "v2.0.0-beta.1"},
+		{"proto/payments/ledger/v1@v1.2.3", "proto/payments/ledger/v1", "v1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			apiID, version := ParseOverlayModule(tt.modulePath)
+			if apiID != tt.wantAPIID || version != tt.wantVersion {
+				t.Errorf("ParseOverlayModule(%q) = %q, %q; want %q, %q",
+					tt.modulePath, apiID, version, tt.wantAPIID, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func writeMarker(t *testing.T, dir, name string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func modulePaths(overlays []Overlay) []string {
+	var paths []string
+	for _, ov := range overlays {
+		paths = append(paths, ov.Language+":"+ov.ModulePath)
+	}
+	return paths
+}
+
+func TestReconcile(t *testing.T) {
+	root := t.TempDir()
+	gen := filepath.Join(root, "internal", "gen")
+	writeMarker(t, filepath.Join(gen, "go", "proto", "payments", "ledger@v1.2.3"), "go.mod")
+	writeMarker(t, filepath.Join(gen, "go", "proto", "payments", "ledger@v1.3.0"), "go.mod")
+	writeMarker(t, filepath.Join(gen, "go", "proto", "billing", "invoice", "v1"), "go.mod")
+	writeMarker(t, filepath.Join(gen, "go", "proto", "users", "profile", "v1"), "go.mod")
+	writeMarker(t, filepath.Join(gen, "python", "proto", "users", "profile", "v1"), "pyproject.toml")
+
+	mgr := NewManager(root)
+	if err := mgr.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	locked := map[string]string{
+		"proto/payments/ledger/v1": "v1.3.0",
+		"proto/users/profile/v1":   "v1.0.0",
+	}
+	rec, err := mgr.Reconcile(locked, map[string]bool{"go": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantOrphaned := []string{"go:proto/billing/invoice/v1", "go:proto/payments/ledger@v1.2.3"}
+	if got := modulePaths(rec.Orphaned); !reflect.DeepEqual(got, wantOrphaned) {
+		t.Errorf("Orphaned = %v, want %v", got, wantOrphaned)
+	}
+	wantDisabled := []string{"python:proto/users/profile/v1"}
+	if got := modulePaths(rec.Disabled); !reflect.DeepEqual(got, wantDisabled) {
+		t.Errorf("Disabled = %v, want %v", got, wantDisabled)
+	}
+	if len(rec.Conflicts) != 1 || rec.Conflicts[0].APIID != "proto/payments/ledger/v1" || len(rec.Conflicts[0].Paths) != 2 {
+		t.Errorf("Conflicts = %+v, want both ledger versions", rec.Conflicts)
+	}
+
+	if err := mgr.RemoveOrphans(rec); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(gen, "go", "proto", "billing")); !os.IsNotExist(err) {
+		t.Error("expected empty parents of a removed overlay to be pruned")
+	}
+	if _, err := os.Stat(filepath.Join(gen, "go", "proto", "payments", "ledger@v1.3.0")); err != nil {
+		t.Errorf("expected locked overlay to be kept: %v", err)
+	}
+	if err := mgr.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := mgr.WorkConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts after pruning, got %+v", conflicts)
+	}
+}
+
+func TestReconcile_NoLanguageTargets(t *testing.T) {
+	root := t.TempDir()
+	writeMarker(t, filepath.Join(root, "internal", "gen", "python", "proto", "a", "b", "v1"), "pyproject.toml")
+
+	rec, err := NewManager(root).Reconcile(map[string]string{"proto/a/b/v1": "v1.0.0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Orphaned) != 0 || len(rec.Disabled) != 0 {
+		t.Errorf("expected nothing to reconcile, got %+v", rec)
+	}
+}
+
+func TestReadWorkUses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.work")
+	content := "go 1.24\n\nuse ./single // comment\n\nuse (\n\t.\n\t\"./quoted\"\n)\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uses, err := readWorkUses(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"./single", ".", "./quoted"}
+	if !reflect.DeepEqual(uses, want) {
+		t.Errorf("readWorkUses = %v, want %v", uses, want)
+	}
+}
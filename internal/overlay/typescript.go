@@ -0,0 +1,475 @@
+package overlay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// typescriptPackage is the package.json scaffolded in a TypeScript overlay.
+type typescriptPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Private bool   `json:"private"`
+	Main    string `json:"main,omitempty"`
+	Types   string `json:"types,omitempty"`
+}
+
+// ScaffoldTypeScriptPackage writes the package.json of a TypeScript overlay,
+// so that the overlay can be consumed as a workspace package under its
+// scoped npm name.
+//
+// Parameters:
+//   - overlayPath: path to the overlay directory (e.g. internal/gen/typescript/proto/payments/ledger/v1/)
+//   - packageName: scoped npm package name (e.g. "@acme/payments-ledger-v1-proto")
+//   - entryPoint: overlay-relative entry point (e.g. "index.ts"), or "" when
+//     the generated code is imported by subpath
+func ScaffoldTypeScriptPackage(overlayPath, packageName, entryPoint string) error {
+	data, err := marshalJSON(typescriptPackage{
+		Name:    packageName,
+		Version: "0.0.0-dev",
+		Private: true,
+		Main:    entryPoint,
+		Types:   entryPoint,
+	})
+	if err != nil {
+		return fmt.Errorf("rendering package.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(overlayPath, "package.json"), data, 0644); err != nil {
+		return fmt.Errorf("writing package.json: %w", err)
+	}
+	return nil
+}
+
+// NpmWorkspace is the workspace configuration of a JavaScript package
+// manager at the root of a project: the "workspaces" field of package.json
+// for npm and yarn, or the packages list of pnpm-workspace.yaml for pnpm.
+type NpmWorkspace struct {
+	Manager string // "npm", "yarn" or "pnpm"
+	Path    string // package.json or pnpm-workspace.yaml
+}
+
+// FindNpmWorkspace returns the workspace configuration of the project at
+// root. pnpm-workspace.yaml is used when it exists; otherwise the root
+// package.json, which need not exist yet. The package manager is inferred
+// from the configuration file and lockfiles.
+func FindNpmWorkspace(root string) NpmWorkspace {
+	if pnpm := filepath.Join(root, "pnpm-workspace.yaml"); fileExists(pnpm) {
+		return NpmWorkspace{Manager: "pnpm", Path: pnpm}
+	}
+	manager := "npm"
+	if fileExists(filepath.Join(root, "yarn.lock")) {
+		manager = "yarn"
+	}
+	return NpmWorkspace{Manager: manager, Path: filepath.Join(root, "package.json")}
+}
+
+// Packages returns the workspace package entries, which are paths or globs
+// relative to the project root. A missing configuration has none.
+func (w NpmWorkspace) Packages() ([]string, error) {
+	if w.Manager == "pnpm" {
+		doc, err := readYAMLDocument(w.Path)
+		if err != nil {
+			return nil, err
+		}
+		var packages []string
+		if seq := yamlMappingValue(doc, "packages"); seq != nil {
+			if err := seq.Decode(&packages); err != nil {
+				return nil, fmt.Errorf("parsing packages in %s: %w", w.Path, err)
+			}
+		}
+		return packages, nil
+	}
+
+	pkg, err := readJSONObject(w.Path)
+	if err != nil {
+		return nil, err
+	}
+	packages, _, err := pkg.workspaces()
+	if err != nil {
+		return nil, fmt.Errorf("parsing workspaces in %s: %w", w.Path, err)
+	}
+	return packages, nil
+}
+
+// Register adds overlay paths to the workspace packages. When prefix is
+// non-empty, overlay paths under prefix that are not among paths are
+// removed, so the overlays registered there are exactly paths. Other
+// entries, including globs under prefix, are kept.
+// It reports whether the configuration changed.
+func (w NpmWorkspace) Register(paths []string, prefix string) (bool, error) {
+	existing, err := w.Packages()
+	if err != nil {
+		return false, err
+	}
+	want := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		want[normalizeWorkspaceEntry(p)] = true
+	}
+
+	var packages []string
+	present := map[string]bool{}
+	for _, entry := range existing {
+		norm := normalizeWorkspaceEntry(entry)
+		if prefix != "" && underPrefix(norm, prefix) && !want[norm] {
+			continue
+		}
+		packages = append(packages, entry)
+		present[norm] = true
+	}
+	for _, p := range paths {
+		if norm := normalizeWorkspaceEntry(p); !present[norm] {
+			packages = append(packages, norm)
+			present[norm] = true
+		}
+	}
+
+	if equalStrings(existing, packages) {
+		return false, nil
+	}
+	return true, w.setPackages(packages)
+}
+
+// Unregister removes overlay paths from the workspace packages, along with
+// every overlay path under prefix when prefix is non-empty; globs are kept.
+// It returns the number of entries removed.
+func (w NpmWorkspace) Unregister(paths []string, prefix string) (int, error) {
+	if !fileExists(w.Path) {
+		return 0, nil
+	}
+	existing, err := w.Packages()
+	if err != nil {
+		return 0, err
+	}
+	drop := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		drop[normalizeWorkspaceEntry(p)] = true
+	}
+
+	var packages []string
+	for _, entry := range existing {
+		norm := normalizeWorkspaceEntry(entry)
+		if drop[norm] || (prefix != "" && underPrefix(norm, prefix)) {
+			continue
+		}
+		packages = append(packages, entry)
+	}
+
+	removed := len(existing) - len(packages)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, w.setPackages(packages)
+}
+
+// setPackages writes the workspace package entries, keeping the rest of the
+// configuration file as it is.
+func (w NpmWorkspace) setPackages(packages []string) error {
+	if w.Manager == "pnpm" {
+		doc, err := readYAMLDocument(w.Path)
+		if err != nil {
+			return err
+		}
+		// Reuse the existing item nodes so their comments are kept.
+		items := map[string]*yaml.Node{}
+		if old := yamlMappingValue(doc, "packages"); old != nil {
+			for _, item := range old.Content {
+				items[item.Value] = item
+			}
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, p := range packages {
+			item, ok := items[p]
+			if !ok {
+				item = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p}
+			}
+			seq.Content = append(seq.Content, item)
+		}
+		if len(packages) == 0 {
+			seq.Style = yaml.FlowStyle
+		}
+		setYAMLMappingValue(doc, "packages", seq)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("rendering %s: %w", w.Path, err)
+		}
+		return os.WriteFile(w.Path, buf.Bytes(), 0644)
+	}
+
+	pkg, err := readJSONObject(w.Path)
+	if err != nil {
+		return err
+	}
+	if pkg.keys == nil {
+		// A new root package.json; workspaces require a private root.
+		pkg.set("private", json.RawMessage("true"))
+	}
+	if err := pkg.setWorkspaces(packages); err != nil {
+		return err
+	}
+	data, err := pkg.marshal()
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", w.Path, err)
+	}
+	return os.WriteFile(w.Path, data, 0644)
+}
+
+// normalizeWorkspaceEntry returns a workspace entry as a clean,
+// slash-separated path without a leading "./".
+func normalizeWorkspaceEntry(entry string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(filepath.FromSlash(entry))), "./")
+}
+
+// underPrefix reports whether a normalized workspace entry is an overlay
+// path under prefix, as apx registers them. Globs such as prefix + "/**"
+// were written by hand and are never matched.
+func underPrefix(entry, prefix string) bool {
+	if strings.ContainsAny(entry, "*?[]{}!") {
+		return false
+	}
+	prefix = strings.TrimSuffix(normalizeWorkspaceEntry(prefix), "/")
+	return strings.HasPrefix(entry, prefix+"/")
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ---------------------------------------------------------------------------
+// package.json editing
+// ---------------------------------------------------------------------------
+
+// jsonObject is a JSON object that keeps the order of its fields, so that
+// rewriting a hand-maintained package.json does not reorder it.
+type jsonObject struct {
+	keys   []string
+	fields map[string]json.RawMessage
+}
+
+// readJSONObject reads the JSON object in a file. A missing file is an
+// empty object with nil keys.
+func readJSONObject(path string) (*jsonObject, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &jsonObject{fields: map[string]json.RawMessage{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj, err := parseJSONObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return obj, nil
+}
+
+func parseJSONObject(data []byte) (*jsonObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	obj := &jsonObject{keys: []string{}, fields: map[string]json.RawMessage{}}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		obj.set(tok.(string), value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *jsonObject) set(key string, value json.RawMessage) {
+	if _, ok := o.fields[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.fields[key] = value
+}
+
+func (o *jsonObject) delete(key string) {
+	if _, ok := o.fields[key]; !ok {
+		return
+	}
+	delete(o.fields, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// workspaces returns the workspace packages of a package.json, in either
+// the array form or the yarn object form ({"packages": [...]}), and whether
+// the object form is used.
+func (o *jsonObject) workspaces() (packages []string, objectForm bool, err error) {
+	raw, ok := o.fields["workspaces"]
+	if !ok {
+		return nil, false, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		ws, err := parseJSONObject(raw)
+		if err != nil {
+			return nil, true, err
+		}
+		if p, ok := ws.fields["packages"]; ok {
+			err = json.Unmarshal(p, &packages)
+		}
+		return packages, true, err
+	}
+	err = json.Unmarshal(raw, &packages)
+	return packages, false, err
+}
+
+// setWorkspaces replaces the workspace packages of a package.json, keeping
+// the form in use. An empty array-form field is removed.
+func (o *jsonObject) setWorkspaces(packages []string) error {
+	_, objectForm, err := o.workspaces()
+	if err != nil {
+		return err
+	}
+	if packages == nil {
+		packages = []string{}
+	}
+	list, err := json.Marshal(packages)
+	if err != nil {
+		return err
+	}
+	if !objectForm {
+		if len(packages) == 0 {
+			o.delete("workspaces")
+		} else {
+			o.set("workspaces", list)
+		}
+		return nil
+	}
+	ws, err := parseJSONObject(o.fields["workspaces"])
+	if err != nil {
+		return err
+	}
+	ws.set("packages", list)
+	data, err := ws.marshal()
+	if err != nil {
+		return err
+	}
+	o.set("workspaces", data)
+	return nil
+}
+
+// marshal renders the object with two-space indentation and a trailing
+// newline, the formatting npm itself writes.
+func (o *jsonObject) marshal() ([]byte, error) {
+	var compact bytes.Buffer
+	compact.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			compact.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		compact.Write(name)
+		compact.WriteByte(':')
+		if err := json.Compact(&compact, o.fields[key]); err != nil {
+			return nil, err
+		}
+	}
+	compact.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ---------------------------------------------------------------------------
+// pnpm-workspace.yaml editing
+// ---------------------------------------------------------------------------
+
+// readYAMLDocument reads a YAML file as a node tree, so that comments and
+// unrelated keys survive a rewrite. An empty file is an empty mapping.
+func readYAMLDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing %s: expected a mapping", path)
+	}
+	return &doc, nil
+}
+
+func yamlMappingValue(doc *yaml.Node, key string) *yaml.Node {
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setYAMLMappingValue(doc *yaml.Node, key string, value *yaml.Node) {
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			value.HeadComment = m.Content[i+1].HeadComment
+			value.LineComment = m.Content[i+1].LineComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
@@ -0,0 +1,162 @@
+package overlay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldTypeScriptPackage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ScaffoldTypeScriptPackage(dir, "@acme/payments-ledger-v1-proto", "index.ts"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	require.NoError(t, err)
+	var pkg map[string]any
+	require.NoError(t, json.Unmarshal(data, &pkg))
+	assert.Equal(t, "@acme/payments-ledger-v1-proto", pkg["name"])
+	assert.Equal(t, true, pkg["private"])
+	assert.Equal(t, "index.ts", pkg["main"])
+	assert.Equal(t, "index.ts", pkg["types"])
+}
+
+func TestScaffoldTypeScriptPackage_NoEntryPoint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ScaffoldTypeScriptPackage(dir, "@acme/orders-v1-proto", ""))
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"main"`)
+	assert.NotContains(t, string(data), `"types"`)
+}
+
+func TestFindNpmWorkspace(t *testing.T) {
+	root := t.TempDir()
+	assert.Equal(t, NpmWorkspace{Manager: "npm", Path: filepath.Join(root, "package.json")}, FindNpmWorkspace(root))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "yarn.lock"), nil, 0644))
+	assert.Equal(t, "yarn", FindNpmWorkspace(root).Manager)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pnpm-workspace.yaml"), nil, 0644))
+	assert.Equal(t, NpmWorkspace{Manager: "pnpm", Path: filepath.Join(root, "pnpm-workspace.yaml")}, FindNpmWorkspace(root))
+}
+
+func TestNpmWorkspace_PackageJSON(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "package.json")
+	original := "{\n  \"name\": \"web\",\n  \"workspaces\": [\"packages/*\", \"./internal/gen/typescript/proto/old/v1\"],\n  \"scripts\": {\"build\": \"tsc\"}\n}\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+	ws := FindNpmWorkspace(root)
+
+	changed, err := ws.Register([]string{"internal/gen/typescript/proto/payments/ledger/v1"}, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	packages, err := ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"packages/*", "internal/gen/typescript/proto/payments/ledger/v1"}, packages,
+		"stale overlays are replaced and other entries kept")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Regexp(t, `(?s)"name".*"workspaces".*"scripts"`, string(data), "field order is preserved")
+
+	changed, err = ws.Register([]string{"internal/gen/typescript/proto/payments/ledger/v1"}, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	removed, err := ws.Unregister(nil, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	packages, err = ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"packages/*"}, packages)
+}
+
+func TestNpmWorkspace_CreatesRootPackageJSON(t *testing.T) {
+	root := t.TempDir()
+	ws := FindNpmWorkspace(root)
+
+	_, err := ws.Register([]string{"internal/gen/typescript/proto/orders/v1"}, "")
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"private": true, "workspaces": ["internal/gen/typescript/proto/orders/v1"]}`, string(data))
+
+	removed, err := ws.Unregister([]string{"internal/gen/typescript/proto/orders/v1"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	data, err = os.ReadFile(filepath.Join(root, "package.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"private": true}`, string(data), "an emptied workspaces field is removed")
+}
+
+func TestNpmWorkspace_YarnObjectForm(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "package.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"private": true, "workspaces": {"packages": ["apps/*"], "nohoist": ["**/react"]}}`), 0644))
+	ws := FindNpmWorkspace(root)
+
+	_, err := ws.Register([]string{"internal/gen/typescript/proto/orders/v1"}, "internal/gen/typescript")
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"private": true, "workspaces": {"packages": ["apps/*", "internal/gen/typescript/proto/orders/v1"], "nohoist": ["**/react"]}}`, string(data))
+}
+
+func TestNpmWorkspace_Pnpm(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "pnpm-workspace.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# monorepo packages\npackages:\n  - apps/*\nonlyBuiltDependencies:\n  - esbuild\n"), 0644))
+	ws := FindNpmWorkspace(root)
+
+	_, err := ws.Register([]string{"internal/gen/typescript/proto/orders/v1"}, "internal/gen/typescript")
+	require.NoError(t, err)
+	packages, err := ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/*", "internal/gen/typescript/proto/orders/v1"}, packages)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# monorepo packages")
+	assert.Contains(t, string(data), "onlyBuiltDependencies:")
+
+	removed, err := ws.Unregister(nil, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	packages, err = ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/*"}, packages)
+}
+
+func TestNpmWorkspace_KeepsGlobs(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "package.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"workspaces": ["internal/gen/typescript/**", "internal/gen/typescript/proto/*/v1", "internal/gen/typescript/proto/stale/v1"]}`), 0644))
+	ws := FindNpmWorkspace(root)
+
+	// Only the overlay path apx added is replaced; the globs are kept.
+	_, err := ws.Register([]string{"internal/gen/typescript/proto/orders/v1"}, "internal/gen/typescript")
+	require.NoError(t, err)
+	packages, err := ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal/gen/typescript/**", "internal/gen/typescript/proto/*/v1", "internal/gen/typescript/proto/orders/v1"}, packages)
+
+	removed, err := ws.Unregister(nil, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	packages, err = ws.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal/gen/typescript/**", "internal/gen/typescript/proto/*/v1"}, packages)
+}
+
+func TestNpmWorkspace_UnregisterMissingConfig(t *testing.T) {
+	root := t.TempDir()
+	removed, err := FindNpmWorkspace(root).Unregister(nil, "internal/gen/typescript")
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+	assert.NoFileExists(t, filepath.Join(root, "package.json"))
+}
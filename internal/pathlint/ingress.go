@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/infobloxopen/apx/internal/validator"
 	"gopkg.in/yaml.v3"
 )
 
@@ -109,12 +109,19 @@ func decodeIngressManifest(text, source string) []Rule {
 
 // renderHelmChart shells out to `helm template` with default values plus
 // any --helm-set overrides. Returns rendered manifest YAML.
-func renderHelmChart(dir, release string, sets []string) (string, error) {
+func renderHelmChart(tools *validator.ToolchainResolver, dir, release string, sets []string) (string, error) {
 	args := []string{"template", release, dir}
 	for _, s := range sets {
 		args = append(args, "--set", s)
 	}
-	cmd := exec.Command("helm", args...)
+	if tools == nil {
+		tools = validator.NewToolchainResolver()
+	}
+	helm, err := tools.ResolveTool("helm", tools.ToolVersion("helm"))
+	if err != nil {
+		return "", err
+	}
+	cmd := tools.Command("", helm, args...)
 	var out, errBuf bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &errBuf
@@ -180,7 +187,7 @@ func loadIngressFromValuesFallback(dir string) ([]Rule, error) {
 // loadIngressInput dispatches on whether the given --ingress argument is
 // a chart directory or an already-rendered manifest file. A rendered
 // manifest file is parsed directly and requires no helm binary.
-func loadIngressInput(path, release string, helmSets []string) ([]Rule, error) {
+func loadIngressInput(path, release string, helmSets []string, tools *validator.ToolchainResolver) ([]Rule, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, fmt.Errorf("cannot stat %s: %w", path, err)
@@ -193,7 +200,7 @@ func loadIngressInput(path, release string, helmSets []string) ([]Rule, error) {
 		return decodeIngressManifest(string(data), path), nil
 	}
 
-	out, err := renderHelmChart(path, release, helmSets)
+	out, err := renderHelmChart(tools, path, release, helmSets)
 	if err == nil {
 		fmt.Fprintf(os.Stderr, "ingress source %s: rendered via helm template\n", path)
 		return decodeIngressManifest(out, fmt.Sprintf("helm template %s", path)), nil
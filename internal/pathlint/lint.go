@@ -5,6 +5,8 @@ import (
 	"io"
 	"sort"
 	"strings"
+
+	"github.com/infobloxopen/apx/internal/validator"
 )
 
 // Report holds the result of reconciling ingress rules against spec paths.
@@ -25,13 +27,14 @@ type Report struct {
 
 // Analyze loads every ingress source and spec file, computes coverage, and
 // returns a Report. releaseName is used when an --ingress argument is a chart
-// directory (rendered via `helm template`); rendered-manifest files need no
-// helm binary.
-func Analyze(ingressInputs, specInputs, helmSets []string, releaseName string) (*Report, error) {
+// directory (rendered via `helm template`, with the helm binary tools
+// resolves and where it runs tools, or a default resolver when tools is nil);
+// rendered-manifest files need no helm binary.
+func Analyze(ingressInputs, specInputs, helmSets []string, releaseName string, tools *validator.ToolchainResolver) (*Report, error) {
 	r := &Report{IngressInputs: ingressInputs, SpecInputs: specInputs}
 
 	for _, in := range ingressInputs {
-		rs, err := loadIngressInput(in, releaseName, helmSets)
+		rs, err := loadIngressInput(in, releaseName, helmSets, tools)
 		if err != nil {
 			return nil, err
 		}
@@ -53,7 +53,7 @@ func TestAnalyzeCoverageBuckets(t *testing.T) {
 	ingress := writeFile(t, dir, "ingress.yaml", ingressYAML)
 	spec := writeFile(t, dir, "spec.yaml", specYAML)
 
-	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test")
+	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -79,7 +79,7 @@ func TestReportWriteResultLine(t *testing.T) {
 	dir := t.TempDir()
 	ingress := writeFile(t, dir, "ingress.yaml", ingressYAML)
 	spec := writeFile(t, dir, "spec.yaml", specYAML)
-	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test")
+	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -131,7 +131,7 @@ paths:
         "200":
           description: ok
 `)
-	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test")
+	r, err := Analyze([]string{ingress}, []string{spec}, nil, "test", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
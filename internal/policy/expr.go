@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// Rule expressions are Common Expression Language programs
+// (CEL, https://github.com/google/cel-spec), compiled and type-checked by
+// cel-go. The environment declares one variable, element, holding a schema
+// model element with the fields of ruleElement; besides the CEL standard
+// library, the string extensions (lowerAscii, upperAscii, trim, split,
+// replace, ...) are available.
+//
+// The element fields are typed, so a misspelled field or an operator
+// applied to the wrong types is a compile error. attrs is a
+// map(string, dyn) whose values are only checked during evaluation.
+
+// ruleElement is a schema model element as rule expressions see it.
+type ruleElement struct {
+	Kind      string     `cel:"kind"`
+	Format    string     `cel:"format"`
+	File      string     `cel:"file"`
+	Line      int64      `cel:"line"`
+	Name      string     `cel:"name"`
+	FullName  string     `cel:"full_name"`
+	Parent    string     `cel:"parent"`
+	Namespace string     `cel:"namespace"`
+	Type      string     `cel:"type"`
+	Comment   string     `cel:"comment"`
+	Required  bool       `cel:"required"`
+	Nullable  bool       `cel:"nullable"`
+	Repeated  bool       `cel:"repeated"`
+	Attrs     *ruleAttrs `cel:"attrs"`
+}
+
+// ruleAttrs exposes Element.Attrs to CEL as a map(string, dyn). Native
+// struct fields cannot hold arbitrary values, but a field holding a CEL
+// value is passed through as is.
+type ruleAttrs struct{ traits.Mapper }
+
+var attrsType = cel.MapType(cel.StringType, cel.DynType)
+
+// Type implements ref.Val; the field type is declared from it.
+func (*ruleAttrs) Type() ref.Type { return attrsType }
+
+// elementValue exposes an element to expressions as the variable element.
+func elementValue(e validator.Element) *ruleElement {
+	attrs := e.Attrs
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	return &ruleElement{
+		Kind:      e.Kind,
+		Format:    string(e.Format),
+		File:      e.File,
+		Line:      int64(e.Line),
+		Name:      e.Name,
+		FullName:  e.FullName,
+		Parent:    e.Parent,
+		Namespace: e.Namespace,
+		Type:      e.Type,
+		Comment:   e.Comment,
+		Required:  e.Required,
+		Nullable:  e.Nullable,
+		Repeated:  e.Repeated,
+		Attrs:     &ruleAttrs{types.NewStringInterfaceMap(types.DefaultTypeAdapter, attrs)},
+	}
+}
+
+// ruleEnv is the CEL environment rule expressions are compiled in.
+var ruleEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		ext.NativeTypes(reflect.TypeFor[ruleElement](), ext.ParseStructTags(true)),
+		ext.Strings(),
+		cel.Variable("element", cel.ObjectType("policy.ruleElement")),
+	)
+})
+
+// compileExpr parses and type-checks src. A rule condition (cond) must
+// evaluate to a bool; a message placeholder may have any type.
+func compileExpr(src string, cond bool) (cel.Program, error) {
+	env, err := ruleEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating the expression environment: %w", err)
+	}
+	ast, iss := env.Compile(src)
+	if iss.Err() != nil {
+		return nil, issuesError(iss)
+	}
+	if out := ast.OutputType(); cond && !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression is %s, not bool", out)
+	}
+	return env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+}
+
+// issuesError reports compile errors with their 1-based line and column.
+func issuesError(iss *cel.Issues) error {
+	var msgs []string
+	for _, e := range iss.Errors() {
+		msgs = append(msgs, fmt.Sprintf("%d:%d: %s", e.Location.Line(), e.Location.Column()+1, e.Message))
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+// evalExpr evaluates a compiled expression on an element.
+func evalExpr(prg cel.Program, el *ruleElement) (ref.Val, error) {
+	out, _, err := prg.Eval(map[string]interface{}{"element": el})
+	return out, err
+}
+
+// evalBool evaluates a compiled rule condition, which must be a bool.
+func evalBool(prg cel.Program, el *ruleElement) (bool, error) {
+	out, err := evalExpr(prg, el)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression is %s, not bool", out.Type().TypeName())
+	}
+	return bool(b), nil
+}
+
+// formatValue renders a value for a violation message.
+func formatValue(v ref.Val) string {
+	switch x := v.(type) {
+	case types.Null:
+		return "null"
+	case types.String:
+		return string(x)
+	case types.Double:
+		return strconv.FormatFloat(float64(x), 'g', -1, 64)
+	case traits.Lister:
+		var parts []string
+		for it := x.Iterator(); it.HasNext() == types.True; {
+			parts = append(parts, formatValue(it.Next()))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case traits.Mapper:
+		var parts []string
+		for it := x.Iterator(); it.HasNext() == types.True; {
+			k := it.Next()
+			parts = append(parts, formatValue(k)+": "+formatValue(x.Get(k)))
+		}
+		sort.Strings(parts)
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	return fmt.Sprint(v.Value())
+}
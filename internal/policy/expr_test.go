@@ -0,0 +1,231 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// testElement is the element the expression tests evaluate against.
+func testElement() *ruleElement {
+	return elementValue(validator.Element{
+		Kind:     validator.ElementField,
+		Format:   validator.FormatProto,
+		File:     "ledger.proto",
+		Line:     12,
+		Name:     "amount_usd",
+		FullName: "acme.ledger.v1.Entry.amount_usd",
+		Type:     "int64",
+		Required: true,
+		Attrs: map[string]interface{}{
+			"number":  int64(3),
+			"weight":  2.5,
+			"options": map[string]interface{}{"deprecated": true},
+			"tags":    []interface{}{"billing", "public"},
+		},
+	})
+}
+
+// evalString compiles and evaluates src, returning the result's CEL type
+// and its rendering.
+func evalString(t *testing.T, src string) (string, string) {
+	t.Helper()
+	prg, err := compileExpr(src, false)
+	if err != nil {
+		t.Fatalf("compile %s: %v", src, err)
+	}
+	out, err := evalExpr(prg, testElement())
+	if err != nil {
+		t.Fatalf("eval %s: %v", src, err)
+	}
+	return out.Type().TypeName(), formatValue(out)
+}
+
+func TestEvalExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		typ  string
+		want string
+	}{
+		{`element.name == "amount_usd"`, "bool", "true"},
+		{`element.name.startsWith('amount') && element.type != 'google.type.Money'`, "bool", "true"},
+		{`element.name.matches("^(amount|price)_")`, "bool", "true"},
+		{`matches(element.name, "usd$")`, "bool", "true"},
+		{`element.comment != "" || element.required`, "bool", "true"},
+		{`size(element.name) == 10 && element.name.size() == 10`, "bool", "true"},
+		{`element.line / 5`, "int", "2"},
+		{`double(element.line) / 5.0`, "double", "2.4"},
+		{`-element.line`, "int", "-12"},
+		{`element.required ? "yes" : "no"`, "string", "yes"},
+		{`[1, 2] + [3] == [1, 2, 3]`, "bool", "true"},
+		{`{"a": 1}.a`, "int", "1"},
+		{`"a,b".split(",")`, "list", "[a, b]"},
+		{`string(element.line) + "x"`, "string", "12x"},
+		{`int("7") + int(2.9) == 9 && double(1) == 1.0`, "bool", "true"},
+		{`" Hi ".trim().lowerAscii() == 'hi'`, "bool", "true"},
+		{`r'\d+' == "\\d+"`, "bool", "true"},
+		{`0x10 == 16 && 1e3 == 1000.0 && 3u == uint(3)`, "bool", "true"},
+		{`"billing" < "public" && true > false`, "bool", "true"},
+		// attrs values are dynamic: they are checked during evaluation.
+		{`"options" in element.attrs`, "bool", "true"},
+		{`has(element.attrs.options) && !has(element.attrs.label)`, "bool", "true"},
+		{`element.attrs.options["deprecated"]`, "bool", "true"},
+		{`element.attrs.tags[1]`, "string", "public"},
+		{`element.attrs.number + 1`, "int", "4"},
+		{`element.attrs.number == 3.0`, "bool", "true"},
+		{`element.attrs.options`, "map", "{deprecated: true}"},
+		// && and || absorb an error on the side that does not decide.
+		{`false && element.attrs.missing`, "bool", "false"},
+		{`element.attrs.missing || true`, "bool", "true"},
+	}
+	for _, tt := range tests {
+		typ, got := evalString(t, tt.expr)
+		if typ != tt.typ || got != tt.want {
+			t.Errorf("%s = %s (%s), want %s (%s)", tt.expr, got, typ, tt.want, tt.typ)
+		}
+	}
+}
+
+func TestEvalExpr_Precedence(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`1 + 2 * 3`, "7"},
+		{`(1 + 2) * 3`, "9"},
+		{`10 - 4 - 3`, "3"}, // left-associative
+		{`20 / 2 / 5`, "2"},
+		{`7 % 4 * 2`, "6"},
+		{`-2 * 3`, "-6"},
+		{`1 + 2 == 3 && 2 < 3`, "true"},
+		{`true || false && false`, "true"}, // && binds tighter than ||
+		{`(true || false) && false`, "false"},
+		{`!true || true`, "true"}, // ! applies to its operand only
+		{`!(true || true)`, "false"},
+		{`1 in [1, 2] == true`, "true"},                           // relations are left-associative
+		{`false ? 1 : true ? 2 : 3`, "2"},                         // ?: is right-associative
+		{`true || false ? "or first" : "cond first"`, "or first"}, // ?: binds loosest
+		{`element.attrs.tags.size() + 1 * 2`, "4"},
+	}
+	for _, tt := range tests {
+		if _, got := evalString(t, tt.expr); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvalExpr_Macros(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`element.attrs.tags.exists(t, t == "public")`, "true"},
+		{`element.attrs.tags.all(t, t.size() > 6)`, "false"},
+		{`element.attrs.tags.exists_one(t, t.endsWith("c"))`, "true"},
+		{`element.attrs.tags.filter(t, t != "public")`, "[billing]"},
+		{`element.attrs.tags.map(t, t.upperAscii())`, "[BILLING, PUBLIC]"},
+		{`element.attrs.tags.map(t, t.startsWith("b"), t.size())`, "[7]"},
+		{`element.attrs.options.all(k, k == "deprecated")`, "true"},
+		{`[1, 2, 3].map(x, x * x).filter(y, y > 1)`, "[4, 9]"},
+		{`[].exists(x, x > 0)`, "false"},
+		{`[].all(x, x > 0)`, "true"},
+		{`has(element.name)`, "true"},
+		{`has({"a": 1}.b)`, "false"},
+	}
+	for _, tt := range tests {
+		if _, got := evalString(t, tt.expr); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileExpr_TypeErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// Declared fields are typed, so mistakes are caught before evaluation.
+		{`element.nmae == "x"`, "undefined field 'nmae'"},
+		{`elem.name`, "undeclared reference to 'elem'"},
+		{`element.name + 1`, "no matching overload for '_+_' applied to '(string, int)'"},
+		{`element.line / 5.0`, "no matching overload for '_/_' applied to '(int, double)'"},
+		{`element.line == 12.0`, "no matching overload for '_==_' applied to '(int, double)'"},
+		{`3u == 3`, "no matching overload for '_==_' applied to '(uint, int)'"},
+		{`element.required && "yes"`, "expected type 'bool' but found 'string'"},
+		{`element.name.startsWith()`, "no matching overload for 'startsWith'"},
+		{`frobnicate(element.name)`, "undeclared reference to 'frobnicate'"},
+		{`[1, 2].map(x, x + "a")`, "no matching overload for '_+_' applied to '(int, string)'"},
+		{`has(element)`, "invalid argument to has() macro"},
+		{`[1].all("x", true)`, "argument must be a simple name"},
+	}
+	for _, tt := range tests {
+		_, err := compileExpr(tt.expr, false)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestCompileExpr_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`element.name ==`, "1:16: Syntax error: mismatched input '<EOF>'"},
+		{`element.name == "x`, "1:17: Syntax error: token recognition error"},
+		{`(element.required`, "1:18: Syntax error: missing ')'"},
+		{`element.name element.type`, "1:14: Syntax error: mismatched input 'element' expecting <EOF>"},
+		{`element.name # b`, "1:14: Syntax error: token recognition error at: '#'; 1:16: Syntax error: extraneous input 'b'"},
+		{`element.nmae`, "1:8: undefined field 'nmae'"},
+		{"element.required &&\n  element.nope", "2:10: undefined field 'nope'"},
+	}
+	for _, tt := range tests {
+		_, err := compileExpr(tt.expr, false)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want prefix %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestEvalExpr_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`element.attrs.missing`, "no such key: missing"},
+		{`true && element.attrs.missing`, "no such key: missing"},
+		{`element.attrs.tags[0] + 1`, "no such overload"},
+		{`1 / (element.line - 12)`, "division by zero"},
+		{`element.attrs.tags[3]`, "index out of bounds: 3"},
+		{`element.name.matches(element.type + "(")`, "error parsing regexp"},
+	}
+	for _, tt := range tests {
+		prg, err := compileExpr(tt.expr, false)
+		if err != nil {
+			t.Errorf("compile %s: %v", tt.expr, err)
+			continue
+		}
+		_, err = evalExpr(prg, testElement())
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestCompileExpr_Condition(t *testing.T) {
+	if _, err := compileExpr(`element.name`, true); err == nil || !strings.Contains(err.Error(), "expression is string, not bool") {
+		t.Errorf("string condition: error %v", err)
+	}
+	if _, err := compileExpr(`element.name.matches("(")`, true); err == nil || !strings.Contains(err.Error(), "error parsing regexp") {
+		t.Errorf("constant regexp: error %v", err)
+	}
+	// A dyn condition is checked when it is evaluated.
+	prg, err := compileExpr(`element.attrs.tags[0]`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := evalBool(prg, testElement()); err == nil || !strings.Contains(err.Error(), "expression is string, not bool") {
+		t.Errorf("dyn condition: error %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 // Package policy implements policy evaluation for APX schema projects.
 // It checks organizational constraints such as forbidden proto options,
-// allowed plugins, and format-specific compatibility rules.
+// allowed plugins, and format-specific compatibility rules, and evaluates
+// programmable rules (CEL expressions) over a format-neutral schema model.
 package policy
 
 import (
@@ -17,9 +18,19 @@ import (
 
 // Violation represents a single policy rule violation.
 type Violation struct {
-	Rule    string // short rule identifier, e.g. "forbidden_proto_option"
-	File    string // file path relative to checked root
-	Message string // human-readable description
+	Rule     string             // short rule identifier, e.g. "forbidden_proto_option"
+	Severity validator.Severity // SeverityError when empty
+	File     string             // file path relative to checked root
+	Line     int                // 1-based line in File, 0 when not known
+	Message  string             // human-readable description
+}
+
+// severity returns the violation's severity, defaulting to error.
+func (v Violation) severity() validator.Severity {
+	if v.Severity == "" {
+		return validator.SeverityError
+	}
+	return v.Severity
 }
 
 // Result holds the outcome of a policy check.
@@ -28,12 +39,52 @@ type Result struct {
 	Checked    int // number of rules evaluated
 }
 
-// Passed returns true when no violations were found.
-func (r *Result) Passed() bool { return len(r.Violations) == 0 }
+// Passed returns true when no error-severity violations were found.
+func (r *Result) Passed() bool { return len(r.Errors()) == 0 }
+
+// Errors returns the violations that fail the check.
+func (r *Result) Errors() []Violation {
+	var errs []Violation
+	for _, v := range r.Violations {
+		if v.severity() == validator.SeverityError {
+			errs = append(errs, v)
+		}
+	}
+	return errs
+}
+
+// Findings converts the violations into validator findings so they can be
+// rendered by the same reporters as lint and breaking results.
+// root is the checked directory; relative violation files are joined onto it.
+func (r *Result) Findings(root string) []validator.Finding {
+	findings := make([]validator.Finding, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		file := v.File
+		if file != "" && !filepath.IsAbs(file) {
+			file = filepath.Join(root, file)
+		}
+		findings = append(findings, validator.Finding{
+			File:     file,
+			Line:     v.Line,
+			RuleID:   v.Rule,
+			Severity: v.severity(),
+			Message:  v.Message,
+		})
+	}
+	return findings
+}
 
 // Check evaluates all configured policy rules against the schemas found
 // at path and returns a structured result.
 func Check(pol config.Policy, path string) (*Result, error) {
+	return CheckAgainst(pol, path, "", nil)
+}
+
+// CheckAgainst is Check with a baseline for the rules that judge schema
+// evolution. against is a path on disk or a git ref, as for apx breaking;
+// when empty, those rules are skipped. resolver locates the tools those
+// rules need, as configured for the project; nil uses the defaults.
+func CheckAgainst(pol config.Policy, path, against string, resolver *validator.ToolchainResolver) (*Result, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolving path: %w", err)
@@ -95,8 +146,19 @@ func Check(pol config.Policy, path string) (*Result, error) {
 
 	// --- Parquet checks ---
 	if format == validator.FormatParquet || format == validator.FormatUnknown {
-		result.Checked++
-		checkParquetPolicy(pol.Parquet.AllowAdditiveNullableOnly, absPath, result)
+		if pol.ParquetAdditiveNullableOnly() && against != "" {
+			result.Checked++
+			if err := checkParquetPolicy(absPath, against, resolver, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// --- Programmable rules, for every format ---
+	if len(pol.Rules) > 0 {
+		if err := checkRules(pol.Rules, absPath, format, result); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
@@ -151,6 +213,7 @@ func checkForbiddenOptions(patterns []string, files []string, root string, resul
 					result.Violations = append(result.Violations, Violation{
 						Rule:    "forbidden_proto_option",
 						File:    rel,
+						Line:    lineNo,
 						Message: fmt.Sprintf("%s:%d: forbidden option %q matches pattern %q", rel, lineNo, optionName, re.String()),
 					})
 				}
@@ -191,6 +254,7 @@ func checkAllowedPlugins(allowed []string, dir string, result *Result) {
 			result.Violations = append(result.Violations, Violation{
 				Rule:    "allowed_proto_plugin",
 				File:    "buf.gen.yaml",
+				Line:    lineNo,
 				Message: fmt.Sprintf("buf.gen.yaml:%d: plugin %q is not in the allowed list %v", lineNo, pluginName, allowed),
 			})
 		}
@@ -247,13 +311,42 @@ func checkJSONSchemaBreakingMode(mode string, result *Result) {
 	}
 }
 
-// checkParquetPolicy checks parquet-specific policy constraints.
-// When AllowAdditiveNullableOnly is true, this is a configuration
-// declaration that restricts schema evolution — the check verifies
-// the setting is acknowledged.
-func checkParquetPolicy(allowAdditiveNullableOnly bool, dir string, result *Result) {
-	// The policy is a configuration constraint. For parquet files,
-	// we verify the presence of parquet schemas and note the active policy.
-	// Actual enforcement happens during breaking-change detection in the
-	// parquet validator's SetAdditiveNullableOnlyPolicy().
+// checkParquetPolicy enforces allow_additive_nullable_only: the Parquet
+// schemas under dir are compared with their baseline at against, and each
+// change other than adding a nullable column is a violation. Files are
+// paired by their path relative to dir; a baseline without the directory
+// (a first release) has nothing to compare.
+func checkParquetPolicy(dir, against string, resolver *validator.ToolchainResolver, result *Result) error {
+	if resolver == nil {
+		resolver = validator.NewToolchainResolver()
+	}
+	v := validator.NewValidator(resolver)
+	v.SetParquetAdditiveNullableOnly(true)
+	report, err := v.ClassifyChanges(dir, against, validator.FormatParquet)
+	if err != nil {
+		return fmt.Errorf("comparing Parquet schemas with %s: %w", against, err)
+	}
+	for _, f := range report.Findings {
+		if f.Severity != validator.SeverityError {
+			continue
+		}
+		rel := f.File
+		if filepath.IsAbs(rel) {
+			if r, err := filepath.Rel(dir, rel); err == nil {
+				rel = r
+			}
+		}
+		rel = filepath.ToSlash(rel)
+		loc := rel
+		if f.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", rel, f.Line)
+		}
+		result.Violations = append(result.Violations, Violation{
+			Rule:    "parquet_additive_nullable_only",
+			File:    rel,
+			Line:    f.Line,
+			Message: fmt.Sprintf("%s: %s (changed since %s)", loc, f.Message, against),
+		})
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package policy
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/infobloxopen/apx/internal/config"
@@ -50,6 +51,15 @@ message User {
 	if !found {
 		t.Error("expected forbidden_proto_option violation")
 	}
+
+	findings := result.Findings(dir)
+	if len(findings) != len(result.Violations) {
+		t.Fatalf("expected one finding per violation, got %d", len(findings))
+	}
+	f := findings[0]
+	if f.File != filepath.Join(dir, "test.proto") || f.Line != 9 || f.RuleID != "forbidden_proto_option" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
 }
 
 func TestCheck_ForbiddenProtoOptions_Clean(t *testing.T) {
@@ -288,3 +298,49 @@ func TestCheck_EmptyPolicy(t *testing.T) {
 		t.Errorf("expected 0 rules checked for empty policy; got %d", result.Checked)
 	}
 }
+
+func TestCheckAgainst_ParquetAdditiveNullableOnly(t *testing.T) {
+	base := t.TempDir()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(base, "events.parquet"), `message events {
+  required int32 id;
+  optional binary name (STRING);
+}
+`)
+	writeFile(t, filepath.Join(dir, "events.parquet"), `message events {
+  required int64 id;
+  optional binary name (STRING);
+  optional binary source (STRING);
+}
+`)
+
+	pol := config.Policy{} // the policy is on by default
+	result, err := CheckAgainst(pol, dir, base, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 1 || len(result.Violations) != 1 {
+		t.Fatalf("expected one violation of one rule, got %d rule(s) and %+v", result.Checked, result.Violations)
+	}
+	v := result.Violations[0]
+	want := `events.parquet:2: column "id" physical type changed from int32 to int64`
+	if v.Rule != "parquet_additive_nullable_only" || v.File != "events.parquet" || v.Line != 2 ||
+		!strings.HasPrefix(v.Message, want) {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+
+	// Without a baseline, or with the policy off, nothing is compared.
+	for _, tc := range []struct {
+		allow   bool
+		against string
+	}{{true, ""}, {false, base}} {
+		pol.Parquet.AllowAdditiveNullableOnly = &tc.allow
+		result, err := CheckAgainst(pol, dir, tc.against, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Checked != 0 || !result.Passed() {
+			t.Errorf("allow=%v against=%q: expected no check, got %d rule(s) and %+v", tc.allow, tc.against, result.Checked, result.Violations)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// compiledRule is a policy rule ready to evaluate.
+type compiledRule struct {
+	config.PolicyRule
+	severity validator.Severity
+	cond     cel.Program
+	message  []messagePart
+}
+
+// messagePart is literal text or a ${expr} placeholder of a rule message.
+type messagePart struct {
+	text string
+	expr cel.Program
+}
+
+// compileRule checks a rule's fields and compiles its expression and
+// message placeholders.
+func compileRule(r config.PolicyRule) (*compiledRule, error) {
+	if r.ID == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	known := false
+	for _, k := range validator.ElementKinds {
+		known = known || r.Target == k
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown target %q; must be one of %s", r.Target, strings.Join(validator.ElementKinds, ", "))
+	}
+	c := &compiledRule{PolicyRule: r, severity: validator.SeverityError}
+	switch r.Severity {
+	case "", "error":
+	case "warning":
+		c.severity = validator.SeverityWarning
+	default:
+		return nil, fmt.Errorf("invalid severity %q; must be error or warning", r.Severity)
+	}
+	if strings.TrimSpace(r.Expr) == "" {
+		return nil, fmt.Errorf("expr is required")
+	}
+	cond, err := compileExpr(r.Expr, true)
+	if err != nil {
+		return nil, fmt.Errorf("expr: %w", err)
+	}
+	c.cond = cond
+	if c.message, err = compileMessage(r.Message); err != nil {
+		return nil, fmt.Errorf("message: %w", err)
+	}
+	return c, nil
+}
+
+// compileMessage splits a message into text and ${expr} placeholders.
+func compileMessage(msg string) ([]messagePart, error) {
+	var parts []messagePart
+	for msg != "" {
+		i := strings.Index(msg, "${")
+		if i < 0 {
+			parts = append(parts, messagePart{text: msg})
+			break
+		}
+		j := strings.Index(msg[i:], "}")
+		if j < 0 {
+			return nil, fmt.Errorf("unterminated ${ placeholder")
+		}
+		expr, err := compileExpr(msg[i+2:i+j], false)
+		if err != nil {
+			return nil, fmt.Errorf("${%s}: %w", msg[i+2:i+j], err)
+		}
+		parts = append(parts, messagePart{text: msg[:i]}, messagePart{expr: expr})
+		msg = msg[i+j+1:]
+	}
+	return parts, nil
+}
+
+// applies reports whether the rule targets an element.
+func (r *compiledRule) applies(e validator.Element) bool {
+	return e.Kind == r.Target && (r.Format == "" || string(e.Format) == r.Format)
+}
+
+// render builds the violation message for an element.
+func (r *compiledRule) render(e validator.Element, el *ruleElement) string {
+	if len(r.message) == 0 {
+		return fmt.Sprintf("%s %s does not satisfy %s", e.Kind, e.FullName, r.Expr)
+	}
+	var b strings.Builder
+	for _, p := range r.message {
+		if p.expr == nil {
+			b.WriteString(p.text)
+			continue
+		}
+		v, err := evalExpr(p.expr, el)
+		if err != nil {
+			b.WriteString("<" + err.Error() + ">")
+			continue
+		}
+		b.WriteString(formatValue(v))
+	}
+	return b.String()
+}
+
+// checkRules evaluates the programmable policy rules over the schema model
+// of dir. An invalid rule is reported as a violation of that rule; each
+// element that does not satisfy a rule is a violation with the rule's
+// severity.
+func checkRules(rules []config.PolicyRule, dir string, format validator.SchemaFormat, result *Result) error {
+	var compiled []*compiledRule
+	for i, r := range rules {
+		c, err := compileRule(r)
+		if err != nil {
+			id := r.ID
+			if id == "" {
+				id = fmt.Sprintf("rules[%d]", i)
+			}
+			result.Violations = append(result.Violations, Violation{
+				Rule:    id,
+				Message: fmt.Sprintf("invalid policy rule %s: %v", id, err),
+			})
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	if len(compiled) == 0 {
+		return nil
+	}
+
+	elems, err := validator.LoadSchemaModel(dir, format)
+	if err != nil {
+		return fmt.Errorf("loading schema model: %w", err)
+	}
+	for _, r := range compiled {
+		result.Checked++
+		for _, e := range elems {
+			if !r.applies(e) {
+				continue
+			}
+			el := elementValue(e)
+			ok, err := evalBool(r.cond, el)
+			var msg string
+			switch {
+			case err != nil:
+				msg = fmt.Sprintf("rule %s cannot be evaluated on %s %s: %v", r.ID, e.Kind, e.FullName, err)
+			case ok:
+				continue
+			default:
+				msg = r.render(e, el)
+			}
+			if e.Line > 0 {
+				msg = fmt.Sprintf("%s:%d: %s", e.File, e.Line, msg)
+			} else {
+				msg = e.File + ": " + msg
+			}
+			result.Violations = append(result.Violations, Violation{
+				Rule:     r.ID,
+				Severity: r.severity,
+				File:     e.File,
+				Line:     e.Line,
+				Message:  msg,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck_Rules_Proto(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ledger.proto"), `syntax = "proto3";
+package acme.ledger.v1;
+
+import "google/type/money.proto";
+
+message Entry {
+  google.type.Money amount = 1;
+  int64 fee_amount = 2;
+}
+
+service Ledger {
+  // Creates an entry.
+  rpc Create(Entry) returns (Entry);
+  rpc Delete(Entry) returns (Entry);
+}
+`)
+	pol := config.Policy{Rules: []config.PolicyRule{
+		{ID: "rpc-comment", Target: "rpc", Expr: `element.comment != ""`, Message: "rpc ${element.name} must be documented"},
+		{ID: "money-type", Target: "field", Severity: "warning",
+			Expr:    `!element.name.endsWith("amount") || element.type == "google.type.Money"`,
+			Message: "${element.full_name} is ${element.type}, use google.type.Money"},
+	}}
+	result, err := Check(pol, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 2 {
+		t.Errorf("expected 2 rules evaluated, got %d", result.Checked)
+	}
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", result.Violations)
+	}
+	v := result.Violations[0]
+	if v.Rule != "rpc-comment" || v.File != "ledger.proto" || v.Line != 14 ||
+		v.Message != "ledger.proto:14: rpc Delete must be documented" {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	w := result.Violations[1]
+	if w.Rule != "money-type" || w.Severity != validator.SeverityWarning ||
+		!strings.HasSuffix(w.Message, "acme.ledger.v1.Entry.fee_amount is int64, use google.type.Money") {
+		t.Errorf("unexpected violation: %+v", w)
+	}
+	if result.Passed() {
+		t.Error("expected the error-severity violation to fail the check")
+	}
+	findings := result.Findings(dir)
+	if findings[0].Severity != validator.SeverityError || findings[1].Severity != validator.SeverityWarning {
+		t.Errorf("unexpected finding severities: %+v", findings)
+	}
+}
+
+func TestCheck_Rules_WarningsPass(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "user.avsc"),
+		`{"type":"record","name":"User","namespace":"org.example","fields":[{"name":"id","type":"string"}]}`)
+	pol := config.Policy{Rules: []config.PolicyRule{
+		{ID: "avro-namespace", Target: "message", Format: "avro", Severity: "warning",
+			Expr: `element.namespace == "com.acme" || element.namespace.startsWith("com.acme.")`},
+	}}
+	result, err := Check(pol, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Violations) != 1 || !result.Passed() {
+		t.Fatalf("expected one warning and a passing check, got %+v", result.Violations)
+	}
+	want := `user.avsc:1: message org.example.User does not satisfy element.namespace == "com.acme" || element.namespace.startsWith("com.acme.")`
+	if result.Violations[0].Message != want {
+		t.Errorf("message = %q, want %q", result.Violations[0].Message, want)
+	}
+}
+
+func TestCheck_Rules_FormatFilter(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "events.parquet"), `message events {
+  required int64 id;
+  optional binary name (STRING);
+}
+`)
+	pol := config.Policy{Rules: []config.PolicyRule{
+		// Only Avro elements are checked, so the Parquet columns are not.
+		{ID: "avro-only", Target: "field", Format: "avro", Expr: `false`},
+		{ID: "parquet-nullable-strings", Target: "field", Format: "parquet",
+			Expr: `!has(element.attrs.logical_type) || element.attrs.logical_type != "STRING" || element.nullable`},
+		{ID: "no-required", Target: "field", Format: "parquet", Expr: `!element.required`},
+	}}
+	result, err := Check(pol, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "no-required" || result.Violations[0].Line != 2 {
+		t.Fatalf("unexpected violations: %+v", result.Violations)
+	}
+}
+
+func TestCheck_Rules_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.proto"), "syntax = \"proto3\";\nmessage A { string id = 1; }\n")
+	pol := config.Policy{Rules: []config.PolicyRule{
+		{ID: "bad-target", Target: "table", Expr: "true"},
+		{ID: "bad-expr", Target: "field", Expr: "element.name =="},
+		{ID: "bad-severity", Target: "field", Severity: "fatal", Expr: "true"},
+		{Target: "field", Expr: "true"},
+		{ID: "eval-error", Target: "field", Expr: "element.attrs.missing"},
+	}}
+	result, err := Check(pol, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`invalid policy rule bad-target: unknown target "table"`,
+		"invalid policy rule bad-expr: expr: 1:16: Syntax error",
+		`invalid policy rule bad-severity: invalid severity "fatal"`,
+		"invalid policy rule rules[3]: id is required",
+		"a.proto:2: rule eval-error cannot be evaluated on field A.id: no such key: missing",
+	}
+	if len(result.Violations) != len(want) {
+		t.Fatalf("expected %d violations, got %+v", len(want), result.Violations)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(result.Violations[i].Message, w) {
+			t.Errorf("violation %d = %q, want prefix %q", i, result.Violations[i].Message, w)
+		}
+	}
+}
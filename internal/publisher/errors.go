@@ -65,6 +65,10 @@ const (
 	// current API line. A new major line is required.
 	ErrCodeBreakingChange ReleaseErrorCode = "BREAKING_CHANGE"
 
+	// ErrCodeVersionBumpMismatch means the requested version bumps the
+	// previous release by more or less than the schema changes call for.
+	ErrCodeVersionBumpMismatch ReleaseErrorCode = "VERSION_BUMP_MISMATCH"
+
 	// ErrCodeVersionLineMismatch means the version's major component does
 	// not match the API line's major version.
 	ErrCodeVersionLineMismatch ReleaseErrorCode = "VERSION_LINE_MISMATCH"
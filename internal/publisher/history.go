@@ -0,0 +1,54 @@
+package publisher
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+// ReleaseHistory reads the released versions of one schema file from an API
+// line's release tags. It backs the Avro *_TRANSITIVE compatibility modes.
+type ReleaseHistory struct {
+	tags     *TagManager
+	repoPath string
+	apiID    string
+	relPath  string // schema path relative to the repo root, slash-separated
+}
+
+// NewReleaseHistory creates a history for the schema at relPath (relative to
+// repoPath) of the API line apiID.
+func NewReleaseHistory(repoPath, apiID, relPath string) *ReleaseHistory {
+	return &ReleaseHistory{
+		tags:     NewTagManager(repoPath, ""),
+		repoPath: repoPath,
+		apiID:    apiID,
+		relPath:  path.Clean(relPath),
+	}
+}
+
+// Versions lists the API line's released versions, oldest first.
+func (h *ReleaseHistory) Versions() ([]string, error) {
+	versions, err := h.tags.ListVersionsForAPI(h.apiID)
+	if err != nil {
+		return nil, err
+	}
+	return config.SortVersions(versions), nil
+}
+
+// Load returns the schema as it was released in version. The file is read
+// at the schema's path in the working repo and, failing that, under the API
+// ID directory where the canonical repo stores it.
+func (h *ReleaseHistory) Load(version string) ([]byte, error) {
+	tag := config.DeriveTag(h.apiID, version)
+	candidates := []string{h.relPath, path.Join(h.apiID, path.Base(h.relPath))}
+	var lastErr error
+	for _, p := range candidates {
+		out, err := gitCommand(h.repoPath, "show", tag+":"+p)
+		if err == nil {
+			return []byte(out), nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("schema not found at %s: %w", tag, lastErr)
+}
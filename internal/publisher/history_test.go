@@ -0,0 +1,55 @@
+package publisher
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseHistory(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, out)
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+
+	schemaDir := filepath.Join(dir, "avro", "events", "user", "v1")
+	require.NoError(t, os.MkdirAll(schemaDir, 0o755))
+	release := func(content, version string) {
+		require.NoError(t, os.WriteFile(filepath.Join(schemaDir, "user.avsc"), []byte(content), 0o644))
+		run("add", ".")
+		run("commit", "-m", version)
+		run("tag", "avro/events/user/"+version, "-m", version)
+	}
+	release("first", "v1.0.0")
+	release("third", "v1.10.0")
+	release("second", "v1.2.0")
+
+	h := NewReleaseHistory(dir, "avro/events/user/v1", "avro/events/user/v1/user.avsc")
+	versions, err := h.Versions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0", "v1.2.0", "v1.10.0"}, versions)
+
+	data, err := h.Load("v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	// A schema kept outside the API ID directory in the working repo falls
+	// back to the canonical <api-id>/<file> location inside the tag.
+	h = NewReleaseHistory(dir, "avro/events/user/v1", "schemas/user.avsc")
+	data, err = h.Load("v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	_, err = h.Load("v9.9.9")
+	assert.Error(t, err)
+}
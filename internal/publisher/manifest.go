@@ -37,6 +37,14 @@ type ReleaseManifest struct {
 	// Release
 	RequestedVersion string `yaml:"requested_version" json:"requested_version"`
 
+	// PreviousVersion is the latest release on the line that prepare compared
+	// against, and ChangeLevel the classification of the schema changes since
+	// it (none, additive or breaking). Both are empty for a first release.
+	// Changes lists what changed; together they form the release notes.
+	PreviousVersion string          `yaml:"previous_version,omitempty" json:"previous_version,omitempty"`
+	ChangeLevel     string          `yaml:"change_level,omitempty" json:"change_level,omitempty"`
+	Changes         []ReleaseChange `yaml:"changes,omitempty" json:"changes,omitempty"`
+
 	// Tags are free-form catalog labels recorded for a first-party module at
 	// release (e.g. audience/product/family axes). They are the first-party
 	// counterpart to the tags an external_apis registration carries, and travel
@@ -88,6 +96,8 @@ type ValidationResults struct {
 	Policy    ValidationStatus `yaml:"policy" json:"policy"`
 	GoPackage ValidationStatus `yaml:"go_package,omitempty" json:"go_package,omitempty"`
 	GoMod     ValidationStatus `yaml:"go_mod,omitempty" json:"go_mod,omitempty"`
+	// Waivers are the breaking-change waivers that allowed the release.
+	Waivers []config.Waiver `yaml:"waivers,omitempty" json:"waivers,omitempty"`
 }
 
 // ValidationStatus is a typed string for validation outcomes.
@@ -252,6 +262,12 @@ func FormatManifestReport(m *ReleaseManifest) string {
 		if m.Validation.GoMod != "" {
 			lines = append(lines, fmt.Sprintf("  go_mod:    %s", m.Validation.GoMod))
 		}
+		for _, w := range m.Validation.Waivers {
+			lines = append(lines, fmt.Sprintf("  waived:    %s at %s until %s (%s)", w.Rule, w.Path, w.Expires, w.Owner))
+		}
+	}
+	if notes := m.ReleaseNotes(); notes != nil {
+		lines = append(lines, notes.reportLines()...)
 	}
 	if m.Error != nil {
 		lines = append(lines, fmt.Sprintf("Error:       [%s] %s", m.Error.Code, m.Error.Message))
@@ -0,0 +1,160 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReleaseChange is one entry of a release's notes: a schema element added,
+// removed or changed since the previous release on the line, as listed by
+// `apx diff`.
+type ReleaseChange struct {
+	Category string `yaml:"category,omitempty" json:"category,omitempty"` // e.g. field, rpc, operation; empty for a finding not tied to an element
+	Kind     string `yaml:"kind,omitempty" json:"kind,omitempty"`         // added, removed or changed
+	Summary  string `yaml:"summary" json:"summary"`                       // e.g. "added field User.email"
+	Breaking bool   `yaml:"breaking,omitempty" json:"breaking,omitempty"`
+}
+
+// ReleaseNotes are the auto-generated notes of a release: how its schema
+// changed since the previous release on the line. `apx release prepare`
+// computes them into the manifest, submit renders them into the PR body,
+// and finalize carries them into the release record and the annotated tag.
+type ReleaseNotes struct {
+	PreviousVersion string          `json:"previous_version"`
+	ChangeLevel     string          `json:"change_level"` // none, additive or breaking
+	Changes         []ReleaseChange `json:"changes"`
+}
+
+// ReleaseNotes returns the manifest's release notes, or nil for a release
+// that was not compared against a previous one (e.g. the first on a line).
+func (m *ReleaseManifest) ReleaseNotes() *ReleaseNotes {
+	if m.PreviousVersion == "" {
+		return nil
+	}
+	return &ReleaseNotes{PreviousVersion: m.PreviousVersion, ChangeLevel: m.ChangeLevel, Changes: m.Changes}
+}
+
+// ReleaseNotes returns the record's release notes, or nil when it has none.
+func (r *ReleaseRecord) ReleaseNotes() *ReleaseNotes {
+	if r.PreviousVersion == "" {
+		return nil
+	}
+	return &ReleaseNotes{PreviousVersion: r.PreviousVersion, ChangeLevel: r.ChangeLevel, Changes: r.Changes}
+}
+
+// Breaking returns the number of breaking changes.
+func (n *ReleaseNotes) Breaking() int {
+	count := 0
+	for _, c := range n.Changes {
+		if c.Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+// Summary returns a one-line summary, e.g. "additive, 2 changes since v1.2.0".
+func (n *ReleaseNotes) Summary() string {
+	switch len(n.Changes) {
+	case 0:
+		return fmt.Sprintf("%s, no schema changes since %s", n.ChangeLevel, n.PreviousVersion)
+	case 1:
+		return fmt.Sprintf("%s, 1 change since %s", n.ChangeLevel, n.PreviousVersion)
+	}
+	return fmt.Sprintf("%s, %d changes since %s", n.ChangeLevel, len(n.Changes), n.PreviousVersion)
+}
+
+// Markdown renders the notes as a section of the release PR body.
+func (n *ReleaseNotes) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Changes since %s\n\n", n.PreviousVersion)
+	fmt.Fprintf(&b, "**Change level:** %s", n.ChangeLevel)
+	if breaking := n.Breaking(); breaking > 0 {
+		fmt.Fprintf(&b, " (%d breaking)", breaking)
+	}
+	b.WriteString("\n")
+	if len(n.Changes) == 0 {
+		b.WriteString("\nNo schema changes.\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	for _, c := range n.Changes {
+		if c.Breaking {
+			fmt.Fprintf(&b, "- **Breaking:** %s\n", c.Summary)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", c.Summary)
+		}
+	}
+	return b.String()
+}
+
+// reportLines renders the notes for FormatManifestReport and
+// FormatRecordReport.
+func (n *ReleaseNotes) reportLines() []string {
+	lines := []string{fmt.Sprintf("Changes:     %s", n.Summary())}
+	for _, c := range n.Changes {
+		if c.Breaking {
+			lines = append(lines, "  "+breakingMark+c.Summary)
+		} else {
+			lines = append(lines, "  "+c.Summary)
+		}
+	}
+	return lines
+}
+
+// notesHeader starts the release notes in an annotated release tag.
+const notesHeader = "Changes since "
+
+// breakingMark prefixes a breaking change in an annotated release tag.
+const breakingMark = "BREAKING "
+
+// Annotation renders the notes for the body of the annotated release tag:
+//
+//	Changes since v1.2.0: additive
+//	- added field User.email
+//	- BREAKING removed field User.phone
+//
+// ParseReleaseNotes reads it back.
+func (n *ReleaseNotes) Annotation() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s: %s", notesHeader, n.PreviousVersion, n.ChangeLevel)
+	for _, c := range n.Changes {
+		b.WriteString("\n- ")
+		if c.Breaking {
+			b.WriteString(breakingMark)
+		}
+		b.WriteString(c.Summary)
+	}
+	return b.String()
+}
+
+// ParseReleaseNotes extracts the notes written by Annotation from an
+// annotated-tag body. It returns nil when the body carries none, as for
+// tags created before release notes were recorded. The category of a
+// change is not recorded in the tag; its kind is the first word.
+func ParseReleaseNotes(body string) *ReleaseNotes {
+	var notes *ReleaseNotes
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if notes == nil {
+			if rest, ok := strings.CutPrefix(line, notesHeader); ok {
+				previous, level, _ := strings.Cut(rest, ": ")
+				notes = &ReleaseNotes{PreviousVersion: previous, ChangeLevel: level}
+			}
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			break
+		}
+		change := ReleaseChange{Summary: item}
+		if summary, ok := strings.CutPrefix(item, breakingMark); ok {
+			change = ReleaseChange{Summary: summary, Breaking: true}
+		}
+		if kind, _, ok := strings.Cut(change.Summary, " "); ok && (kind == "added" || kind == "removed" || kind == "changed") {
+			change.Kind = kind
+		}
+		notes.Changes = append(notes.Changes, change)
+	}
+	return notes
+}
@@ -0,0 +1,73 @@
+package publisher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNotesManifest() *ReleaseManifest {
+	return &ReleaseManifest{
+		APIID:            "avro/users/profile/v1",
+		RequestedVersion: "v2.0.0",
+		PreviousVersion:  "v1.4.0",
+		ChangeLevel:      "breaking",
+		Changes: []ReleaseChange{
+			{Category: "field", Kind: "added", Summary: "added field User.email"},
+			{Category: "field", Kind: "changed", Summary: "changed field User.id: type (string -> long)", Breaking: true},
+		},
+	}
+}
+
+func TestReleaseNotes_FirstRelease(t *testing.T) {
+	m := &ReleaseManifest{APIID: "avro/users/profile/v1", RequestedVersion: "v1.0.0"}
+	assert.Nil(t, m.ReleaseNotes())
+	assert.Nil(t, NewReleaseRecord(m).ReleaseNotes())
+	assert.NotContains(t, FormatManifestReport(m), "Changes:")
+}
+
+func TestReleaseNotes_Markdown(t *testing.T) {
+	notes := testNotesManifest().ReleaseNotes()
+	require.NotNil(t, notes)
+	assert.Equal(t, "### Changes since v1.4.0\n\n"+
+		"**Change level:** breaking (1 breaking)\n\n"+
+		"- added field User.email\n"+
+		"- **Breaking:** changed field User.id: type (string -> long)\n", notes.Markdown())
+
+	none := &ReleaseNotes{PreviousVersion: "v1.4.0", ChangeLevel: "none"}
+	assert.Contains(t, none.Markdown(), "No schema changes.")
+	assert.Equal(t, "none, no schema changes since v1.4.0", none.Summary())
+}
+
+func TestReleaseNotes_AnnotationRoundTrip(t *testing.T) {
+	notes := testNotesManifest().ReleaseNotes()
+	annotation := notes.Annotation()
+	assert.Equal(t, "Changes since v1.4.0: breaking\n"+
+		"- added field User.email\n"+
+		"- BREAKING changed field User.id: type (string -> long)", annotation)
+
+	body := "Lifecycle: stable\nSource: github.com/acme/apis/avro/users/profile/v1\n\n" + annotation
+	parsed := ParseReleaseNotes(body)
+	require.NotNil(t, parsed)
+	assert.Equal(t, "v1.4.0", parsed.PreviousVersion)
+	assert.Equal(t, "breaking", parsed.ChangeLevel)
+	assert.Equal(t, []ReleaseChange{
+		{Kind: "added", Summary: "added field User.email"},
+		{Kind: "changed", Summary: "changed field User.id: type (string -> long)", Breaking: true},
+	}, parsed.Changes)
+
+	assert.Nil(t, ParseReleaseNotes("Lifecycle: stable\nSource: github.com/acme/apis/x"))
+}
+
+func TestReleaseNotes_Reports(t *testing.T) {
+	m := testNotesManifest()
+	report := FormatManifestReport(m)
+	assert.Contains(t, report, "Changes:     breaking, 2 changes since v1.4.0\n")
+	assert.Contains(t, report, "  BREAKING changed field User.id: type (string -> long)\n")
+
+	record := NewReleaseRecord(m)
+	assert.Equal(t, "v1.4.0", record.PreviousVersion)
+	assert.Equal(t, m.Changes, record.Changes)
+	assert.Contains(t, FormatRecordReport(record), "  added field User.email\n")
+}
@@ -218,6 +218,9 @@ func SubmitReleaseWithPR(
 		manifest.Tag,
 		manifest.SourceRepo, manifest.SourcePath,
 	)
+	if notes := manifest.ReleaseNotes(); notes != nil {
+		prBody += "\n\n" + strings.TrimRight(notes.Markdown(), "\n")
+	}
 	if prBodyExtra != "" {
 		prBody += "\n\n" + prBodyExtra
 	}
@@ -376,6 +376,53 @@ func TestSubmitReleaseWithPR_WithCIProvenance(t *testing.T) {
 	assert.Contains(t, capturedBody["body"], "github-actions")
 }
 
+func TestSubmitReleaseWithPR_ReleaseNotes(t *testing.T) {
+	stubGit(t, func(args ...string) (string, error) {
+		if isDiffQuiet(args) {
+			return "", fmt.Errorf("exit status 1") // staged changes present
+		}
+		return "", nil
+	})
+
+	var capturedBody map[string]string
+	client, _ := setupTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/apis/pulls":
+			fmt.Fprint(w, `[]`)
+		case r.Method == "POST" && r.URL.Path == "/repos/acme/apis/pulls":
+			_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+			fmt.Fprint(w, `{"number":78,"html_url":"https://github.com/acme/apis/pull/78","state":"open"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	snapshotDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(snapshotDir, "test.proto"), []byte("proto"), 0o644))
+
+	manifest := &ReleaseManifest{
+		APIID:            "proto/test/v1",
+		RequestedVersion: "v1.1.0",
+		CanonicalRepo:    "github.com/acme/apis",
+		CanonicalPath:    "proto/test/v1",
+		SourceRepo:       "github.com/acme/app",
+		SourcePath:       "proto/test/v1",
+		Tag:              "proto/test/v1.1.0",
+		PreviousVersion:  "v1.0.0",
+		ChangeLevel:      "additive",
+		Changes:          []ReleaseChange{{Category: "rpc", Kind: "added", Summary: "added rpc Test.Refund"}},
+	}
+
+	_, err := SubmitReleaseWithPR(client, manifest, snapshotDir, "**CI**: github-actions", "main")
+	require.NoError(t, err)
+	body := capturedBody["body"]
+	assert.Contains(t, body, "### Changes since v1.0.0")
+	assert.Contains(t, body, "- added rpc Test.Refund")
+	assert.Less(t, strings.Index(body, "Changes since"), strings.Index(body, "github-actions"),
+		"release notes precede the CI provenance")
+}
+
 // TestSubmitReleaseWithPR_BaseBranch verifies the resolved base branch (ARCH-271)
 // flows through to the created PR — a develop publish must target apis "develop",
 // not the hardcoded "main".
@@ -36,6 +36,12 @@ type ReleaseRecord struct {
 	Version string `yaml:"version" json:"version"`
 	Tag     string `yaml:"tag" json:"tag"`
 
+	// Release notes (copied from manifest): the schema changes since the
+	// previous release on the line
+	PreviousVersion string          `yaml:"previous_version,omitempty" json:"previous_version,omitempty"`
+	ChangeLevel     string          `yaml:"change_level,omitempty" json:"change_level,omitempty"`
+	Changes         []ReleaseChange `yaml:"changes,omitempty" json:"changes,omitempty"`
+
 	// Canonical destination
 	CanonicalRepo   string `yaml:"canonical_repo" json:"canonical_repo"`
 	CanonicalPath   string `yaml:"canonical_path" json:"canonical_path"`
@@ -76,27 +82,30 @@ type ReleaseArtifact struct {
 // NewReleaseRecord creates a ReleaseRecord from a finalized manifest.
 func NewReleaseRecord(m *ReleaseManifest) *ReleaseRecord {
 	return &ReleaseRecord{
-		SchemaVersion:  "1",
-		Kind:           "release-record",
-		APIID:          m.APIID,
-		Format:         m.Format,
-		Domain:         m.Domain,
-		Name:           m.Name,
-		Line:           m.Line,
-		Lifecycle:      m.Lifecycle,
-		SourceRepo:     m.SourceRepo,
-		SourcePath:     m.SourcePath,
-		SourceCommit:   m.SourceCommit,
-		Version:        m.RequestedVersion,
-		Tag:            m.Tag,
-		CanonicalRepo:  m.CanonicalRepo,
-		CanonicalPath:  m.CanonicalPath,
-		Languages:      m.Languages,
-		Validation:     m.Validation,
-		CatalogUpdated: false,
-		PreparedAt:     m.PreparedAt,
-		SubmittedAt:    m.SubmittedAt,
-		FinalizedAt:    time.Now().UTC().Format(time.RFC3339),
+		SchemaVersion:   "1",
+		Kind:            "release-record",
+		APIID:           m.APIID,
+		Format:          m.Format,
+		Domain:          m.Domain,
+		Name:            m.Name,
+		Line:            m.Line,
+		Lifecycle:       m.Lifecycle,
+		SourceRepo:      m.SourceRepo,
+		SourcePath:      m.SourcePath,
+		SourceCommit:    m.SourceCommit,
+		Version:         m.RequestedVersion,
+		Tag:             m.Tag,
+		PreviousVersion: m.PreviousVersion,
+		ChangeLevel:     m.ChangeLevel,
+		Changes:         m.Changes,
+		CanonicalRepo:   m.CanonicalRepo,
+		CanonicalPath:   m.CanonicalPath,
+		Languages:       m.Languages,
+		Validation:      m.Validation,
+		CatalogUpdated:  false,
+		PreparedAt:      m.PreparedAt,
+		SubmittedAt:     m.SubmittedAt,
+		FinalizedAt:     time.Now().UTC().Format(time.RFC3339),
 	}
 }
 
@@ -200,6 +209,12 @@ func FormatRecordReport(r *ReleaseRecord) string {
 		lines = append(lines, fmt.Sprintf("  lint:      %s", r.Validation.Lint))
 		lines = append(lines, fmt.Sprintf("  breaking:  %s", r.Validation.Breaking))
 		lines = append(lines, fmt.Sprintf("  policy:    %s", r.Validation.Policy))
+		for _, w := range r.Validation.Waivers {
+			lines = append(lines, fmt.Sprintf("  waived:    %s at %s until %s (%s)", w.Rule, w.Path, w.Expires, w.Owner))
+		}
+	}
+	if notes := r.ReleaseNotes(); notes != nil {
+		lines = append(lines, notes.reportLines()...)
 	}
 	if len(r.Artifacts) > 0 {
 		lines = append(lines, "Artifacts:")
@@ -88,6 +88,18 @@ func (m *TagManager) TagExists(tag string) (bool, error) {
 	return tags != "", nil
 }
 
+// TagAnnotation returns the body of an annotated tag's message (the lines
+// after the subject). A lightweight tag has none.
+func (m *TagManager) TagAnnotation(tag string) (string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(contents:body)", "refs/tags/"+tag)
+	cmd.Dir = m.repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to read tag %s: %w\nOutput: %s", tag, err, string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // ListTags returns all tags matching the given pattern (glob).
 // If pattern is empty, all tags are returned.
 func (m *TagManager) ListTags(pattern string) ([]string, error) {
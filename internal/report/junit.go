@@ -0,0 +1,90 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit renders one test case per finding, grouped into a suite named
+// after the tool. Error findings are failures; warnings and notes pass with
+// the message in system-out. A clean run yields a single passing case so
+// dashboards still record the check.
+func writeJUnit(w io.Writer, r Report) error {
+	suite := junitTestSuite{Name: r.Tool}
+	for _, f := range r.Findings {
+		file := r.relFile(f)
+		loc := f
+		loc.File = file
+		name := f.RuleID
+		if l := loc.Location(); l != "" {
+			name = fmt.Sprintf("%s %s", f.RuleID, l)
+		}
+		tc := junitTestCase{Name: name, ClassName: file}
+		if tc.ClassName == "" {
+			tc.ClassName = r.Tool
+		}
+		if f.Severity == validator.SeverityError {
+			tc.Failure = &junitFailure{
+				Message: f.Message,
+				Type:    f.RuleID,
+				Body:    loc.String(),
+			}
+			suite.Failures++
+		} else {
+			tc.SystemOut = fmt.Sprintf("[%s] %s", f.Severity, loc.String())
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	if len(suite.TestCases) == 0 {
+		suite.TestCases = append(suite.TestCases, junitTestCase{Name: r.Tool, ClassName: r.Tool})
+	}
+	suite.Tests = len(suite.TestCases)
+
+	doc := junitTestSuites{
+		Name:     r.Tool,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
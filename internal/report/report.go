@@ -0,0 +1,104 @@
+// Package report renders validator findings in machine-readable formats for
+// CI: SARIF 2.1.0 for GitHub code scanning, JUnit XML for test dashboards,
+// and plain JSON.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+// Format is a machine-readable output format selected with --output.
+type Format string
+
+const (
+	FormatSARIF Format = "sarif"
+	FormatJUnit Format = "junit"
+	FormatJSON  Format = "json"
+)
+
+// ParseFormat validates an --output value. The empty string means
+// human-readable output and is returned unchanged.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatSARIF, FormatJUnit, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported output format %q (supported: sarif, junit, json)", s)
+}
+
+// Report is the input to every encoder: the findings of one check run.
+type Report struct {
+	// Tool names the check that produced the findings, e.g. "apx lint".
+	Tool string
+	// BaseDir is the directory file locations are made relative to, so
+	// SARIF URIs line up with the repository checkout. Usually the working
+	// directory.
+	BaseDir string
+	// DefaultFile is used as the location of findings that carry no file
+	// of their own (e.g. whole-schema breaking changes).
+	DefaultFile string
+	Findings    []validator.Finding
+}
+
+// Write encodes r to w in the given format.
+func Write(w io.Writer, format Format, r Report) error {
+	switch format {
+	case FormatSARIF:
+		return writeSARIF(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	case FormatJSON:
+		return writeJSON(w, r)
+	}
+	return fmt.Errorf("unsupported output format %q", format)
+}
+
+// relFile returns the location of f relative to BaseDir in forward-slash
+// form. Files outside BaseDir keep their original path.
+func (r Report) relFile(f validator.Finding) string {
+	file := f.File
+	if file == "" {
+		file = r.DefaultFile
+	}
+	if file == "" {
+		return ""
+	}
+	if r.BaseDir != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(r.BaseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// jsonReport is the --output json document.
+type jsonReport struct {
+	Tool     string              `json:"tool"`
+	Passed   bool                `json:"passed"`
+	Errors   int                 `json:"errors"`
+	Warnings int                 `json:"warnings"`
+	Findings []validator.Finding `json:"findings"`
+}
+
+func writeJSON(w io.Writer, r Report) error {
+	out := jsonReport{
+		Tool:     r.Tool,
+		Passed:   !validator.HasErrors(r.Findings),
+		Errors:   len(validator.FilterSeverity(r.Findings, validator.SeverityError)),
+		Warnings: len(validator.FilterSeverity(r.Findings, validator.SeverityWarning)),
+		Findings: make([]validator.Finding, 0, len(r.Findings)),
+	}
+	for _, f := range r.Findings {
+		f.File = r.relFile(f)
+		out.Findings = append(out.Findings, f)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+func sampleReport() Report {
+	base := filepath.FromSlash("/repo")
+	return Report{
+		Tool:        "apx breaking",
+		BaseDir:     base,
+		DefaultFile: filepath.Join(base, "schemas", "user.avsc"),
+		Findings: []validator.Finding{
+			{
+				File:     filepath.Join(base, "proto", "a.proto"),
+				Line:     12,
+				Column:   3,
+				RuleID:   "FIELD_NO_DELETE",
+				Severity: validator.SeverityError,
+				Format:   validator.FormatProto,
+				Message:  "field 2 deleted",
+			},
+			{
+				RuleID:   "avro-field-type-changed",
+				Severity: validator.SeverityError,
+				Format:   validator.FormatAvro,
+				Path:     "User.id",
+				Message:  "type changed",
+				OldValue: "string",
+				NewValue: "long",
+			},
+			{
+				File:     filepath.Join(base, "proto", "a.proto"),
+				Line:     4,
+				RuleID:   "FIELD_NO_DELETE",
+				Severity: validator.SeverityWarning,
+				Message:  "advisory",
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, in := range []string{"", "sarif", "JUnit", " json "} {
+		if _, err := ParseFormat(in); err != nil {
+			t.Errorf("ParseFormat(%q) unexpected error: %v", in, err)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, sampleReport()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("expected rules to be de-duplicated to 2, got %d", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+
+	first := run.Results[0]
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "proto/a.proto" {
+		t.Errorf("expected repo-relative URI, got %q", loc.ArtifactLocation.URI)
+	}
+	if loc.Region.StartLine != 12 || loc.Region.StartColumn != 3 || first.Level != "error" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+
+	second := run.Results[1]
+	if second.Locations[0].PhysicalLocation.ArtifactLocation.URI != "schemas/user.avsc" {
+		t.Errorf("expected default file for finding without one, got %+v", second.Locations)
+	}
+	if second.Locations[0].PhysicalLocation.Region.StartLine != 1 {
+		t.Errorf("expected line 1 for finding without a line")
+	}
+	if second.RuleIndex != 1 || second.Properties["old_value"] != "string" {
+		t.Errorf("unexpected second result: %+v", second)
+	}
+	if run.Results[2].Level != "warning" || run.Results[2].RuleIndex != 0 {
+		t.Errorf("unexpected third result: %+v", run.Results[2])
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, sampleReport()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 3 || doc.Failures != 2 {
+		t.Errorf("expected 3 tests / 2 failures, got %d / %d", doc.Tests, doc.Failures)
+	}
+	tc := doc.Suites[0].TestCases[0]
+	if tc.ClassName != "proto/a.proto" || tc.Failure == nil || tc.Failure.Type != "FIELD_NO_DELETE" {
+		t.Errorf("unexpected first test case: %+v", tc)
+	}
+	if warn := doc.Suites[0].TestCases[2]; warn.Failure != nil || !strings.Contains(warn.SystemOut, "advisory") {
+		t.Errorf("warnings should pass with system-out, got %+v", warn)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, FormatJUnit, Report{Tool: "apx lint"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !strings.Contains(buf.String(), `tests="1" failures="0"`) {
+		t.Errorf("clean run should record one passing case, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, sampleReport()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	var out jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if out.Passed || out.Errors != 2 || out.Warnings != 1 {
+		t.Errorf("unexpected summary: %+v", out)
+	}
+	if out.Findings[0].File != "proto/a.proto" {
+		t.Errorf("expected relative file, got %q", out.Findings[0].File)
+	}
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/infobloxopen/apx/internal/validator"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifInfoURI = "https://github.com/infobloxopen/apx"
+)
+
+// The types below cover the subset of SARIF 2.1.0 that GitHub code scanning
+// reads: one run, its rules, and results with physical locations.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevel maps a finding severity to a SARIF result level.
+func sarifLevel(sev validator.Severity) string {
+	switch sev {
+	case validator.SeverityError:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+func writeSARIF(w io.Writer, r Report) error {
+	driver := sarifDriver{
+		Name:           r.Tool,
+		InformationURI: sarifInfoURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := map[string]int{}
+	results := make([]sarifResult, 0, len(r.Findings))
+
+	for _, f := range r.Findings {
+		ruleID := f.RuleID
+		if ruleID == "" {
+			ruleID = "apx"
+		}
+		idx, ok := ruleIndex[ruleID]
+		if !ok {
+			idx = len(driver.Rules)
+			ruleIndex[ruleID] = idx
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:               ruleID,
+				ShortDescription: sarifMessage{Text: ruleID},
+			})
+		}
+
+		res := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: idx,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+		}
+		if uri := r.relFile(f); uri != "" {
+			// Code scanning only annotates results with a line; findings
+			// without one (whole-file or whole-schema changes) are pinned
+			// to the first line of the file.
+			region := &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+			if region.StartLine <= 0 {
+				region = &sarifRegion{StartLine: 1}
+			}
+			res.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: uri},
+				Region:           region,
+			}}}
+		}
+		props := map[string]interface{}{}
+		if f.Format != "" {
+			props["format"] = string(f.Format)
+		}
+		if f.Path != "" {
+			props["path"] = f.Path
+		}
+		if f.OldValue != "" {
+			props["old_value"] = f.OldValue
+		}
+		if f.NewValue != "" {
+			props["new_value"] = f.NewValue
+		}
+		if len(props) > 0 {
+			res.Properties = props
+		}
+		results = append(results, res)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
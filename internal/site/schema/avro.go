@@ -3,8 +3,9 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
+
+	"github.com/infobloxopen/apx/internal/validator"
 )
 
 // avroRaw is the raw JSON structure for Avro schema parsing.
@@ -18,6 +19,15 @@ type avroRaw struct {
 	Items     json.RawMessage `json:"items"`   // array items
 }
 
+// avroProtocolRaw is the raw JSON structure of an Avro protocol (.avpr, or
+// .avdl translated by the validator).
+type avroProtocolRaw struct {
+	Protocol  string    `json:"protocol"`
+	Namespace string    `json:"namespace"`
+	Doc       string    `json:"doc"`
+	Types     []avroRaw `json:"types"`
+}
+
 type avroFieldRaw struct {
 	Name    string          `json:"name"`
 	Type    json.RawMessage `json:"type"`
@@ -25,18 +35,48 @@ type avroFieldRaw struct {
 	Doc     string          `json:"doc"`
 }
 
-// ExtractAvro parses an Avro schema file (.avsc) and returns its structure.
+// ExtractAvro parses an Avro schema (.avsc), protocol (.avpr) or IDL (.avdl)
+// file and returns its structure. A protocol is returned with Type
+// "protocol" and its named types in Types.
 func ExtractAvro(filePath string) (*AvroSchema, error) {
-	data, err := os.ReadFile(filePath)
+	data, err := validator.ReadAvroJSON(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading %s: %w", filePath, err)
 	}
 
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filePath, err)
+	}
+	if _, ok := probe["protocol"]; ok {
+		var proto avroProtocolRaw
+		if err := json.Unmarshal(data, &proto); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filePath, err)
+		}
+		schema := &AvroSchema{
+			Type:      "protocol",
+			Name:      proto.Protocol,
+			Namespace: proto.Namespace,
+			Doc:       proto.Doc,
+		}
+		for _, t := range proto.Types {
+			if t.Namespace == "" {
+				t.Namespace = proto.Namespace
+			}
+			schema.Types = append(schema.Types, *convertAvro(t))
+		}
+		return schema, nil
+	}
+
 	var raw avroRaw
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("parsing %s: %w", filePath, err)
 	}
+	return convertAvro(raw), nil
+}
 
+// convertAvro converts a raw record or enum schema.
+func convertAvro(raw avroRaw) *AvroSchema {
 	schema := &AvroSchema{
 		Type:      raw.Type,
 		Name:      raw.Name,
@@ -57,7 +97,7 @@ func ExtractAvro(filePath string) (*AvroSchema, error) {
 		schema.Fields = append(schema.Fields, field)
 	}
 
-	return schema, nil
+	return schema
 }
 
 // stringifyAvroType converts a raw JSON type into a human-readable string.
@@ -84,9 +124,10 @@ func stringifyAvroType(raw json.RawMessage) string {
 
 	// Try complex type (map/record/array/enum object)
 	var complex struct {
-		Type  string          `json:"type"`
-		Name  string          `json:"name"`
-		Items json.RawMessage `json:"items"`
+		Type   string          `json:"type"`
+		Name   string          `json:"name"`
+		Items  json.RawMessage `json:"items"`
+		Values json.RawMessage `json:"values"`
 	}
 	if err := json.Unmarshal(raw, &complex); err == nil {
 		switch complex.Type {
@@ -98,7 +139,7 @@ func stringifyAvroType(raw json.RawMessage) string {
 		case "array":
 			return "array<" + stringifyAvroType(complex.Items) + ">"
 		case "map":
-			return "map<" + stringifyAvroType(complex.Items) + ">"
+			return "map<" + stringifyAvroType(complex.Values) + ">"
 		case "enum":
 			if complex.Name != "" {
 				return "enum<" + complex.Name + ">"
@@ -138,3 +138,64 @@ func TestExtractAvro_FileNotFound(t *testing.T) {
 	_, err := ExtractAvro("/nonexistent/path.avsc")
 	assert.Error(t, err)
 }
+
+func TestExtractAvro_IDL(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "orders.avdl")
+	os.WriteFile(path, []byte(`/** Order service. */
+@namespace("com.acme.orders")
+protocol Orders {
+  enum Status { OPEN, CLOSED }
+
+  /** A customer order. */
+  record Order {
+    string id;
+    Status status;
+    map<long> counts;
+    string? note = null;
+  }
+
+  Order getOrder(string id);
+}
+`), 0o644)
+
+	schema, err := ExtractAvro(path)
+	require.NoError(t, err)
+	assert.Equal(t, "protocol", schema.Type)
+	assert.Equal(t, "Orders", schema.Name)
+	assert.Equal(t, "Order service.", schema.Doc)
+	require.Len(t, schema.Types, 2)
+
+	assert.Equal(t, "enum", schema.Types[0].Type)
+	assert.Equal(t, []string{"OPEN", "CLOSED"}, schema.Types[0].Symbols)
+
+	order := schema.Types[1]
+	assert.Equal(t, "Order", order.Name)
+	assert.Equal(t, "com.acme.orders", order.Namespace)
+	assert.Equal(t, "A customer order.", order.Doc)
+	require.Len(t, order.Fields, 4)
+	assert.Equal(t, "Status", order.Fields[1].Type)
+	assert.Equal(t, "map<long>", order.Fields[2].Type)
+	assert.Equal(t, "union<null, string>", order.Fields[3].Type)
+	assert.Equal(t, "null", order.Fields[3].Default)
+}
+
+func TestExtractAvro_ProtocolJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "orders.avpr")
+	os.WriteFile(path, []byte(`{
+		"protocol": "Orders",
+		"namespace": "com.acme.orders",
+		"types": [
+			{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "string"}]}
+		],
+		"messages": {}
+	}`), 0o644)
+
+	schema, err := ExtractAvro(path)
+	require.NoError(t, err)
+	assert.Equal(t, "protocol", schema.Type)
+	require.Len(t, schema.Types, 1)
+	assert.Equal(t, "Order", schema.Types[0].Name)
+	assert.Equal(t, "com.acme.orders", schema.Types[0].Namespace)
+}
@@ -10,7 +10,7 @@ import (
 var formatExtensions = map[string][]string{
 	"proto":      {".proto"},
 	"openapi":    {".yaml", ".yml", ".json"},
-	"avro":       {".avsc", ".json"},
+	"avro":       {".avsc", ".avpr", ".avdl", ".json"},
 	"jsonschema": {".json"},
 	"parquet":    {".parquet"},
 	"crd":        {".yaml", ".yml"},
@@ -6,6 +6,8 @@ import (
 	"os"
 	"regexp"
 	"strings"
+
+	"github.com/infobloxopen/apx/internal/validator"
 )
 
 var (
@@ -14,8 +16,13 @@ var (
 		`^\s*(required|optional|repeated)\s+([\w_]+)\s+([\w_]+)(?:\s*\(([^)]+)\))?\s*;`)
 )
 
-// ExtractParquet parses a Parquet message-notation schema file.
+// ExtractParquet parses a Parquet message-notation schema file. Binary
+// Parquet data files are read from their footer.
 func ExtractParquet(filePath string) (*ParquetSchema, error) {
+	if validator.IsParquetDataFile(filePath) {
+		return extractParquetFooter(filePath)
+	}
+
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading %s: %w", filePath, err)
@@ -79,3 +86,21 @@ func ExtractParquet(filePath string) (*ParquetSchema, error) {
 
 	return &result, nil
 }
+
+// extractParquetFooter summarizes the schema stored in a binary Parquet file.
+func extractParquetFooter(filePath string) (*ParquetSchema, error) {
+	info, err := validator.ExtractParquetInfo(filePath)
+	if err != nil {
+		return nil, err
+	}
+	result := &ParquetSchema{MessageName: info.MessageName}
+	for _, c := range info.Columns {
+		result.Columns = append(result.Columns, ParquetColumn{
+			Name:       c.Name,
+			PhysType:   c.PhysType,
+			Repetition: c.Repetition,
+			Annotation: c.Annotation,
+		})
+	}
+	return result, nil
+}
@@ -88,3 +88,26 @@ func TestExtractParquet_FileNotFound(t *testing.T) {
 	_, err := ExtractParquet("/nonexistent/path.parquet")
 	assert.Error(t, err)
 }
+
+func TestExtractParquet_DataFileFooter(t *testing.T) {
+	// Thrift compact FileMetaData for: message m { required binary id (UTF8); }
+	footer := []byte{
+		0x15, 0x02, // 1: version = 1
+		0x19, 0x2c, // 2: schema, list of 2 structs
+		0x48, 0x01, 'm', 0x15, 0x02, 0x00, // root: name "m", num_children 1
+		0x15, 0x0c, 0x25, 0x00, 0x18, 0x02, 'i', 'd', 0x25, 0x00, 0x00, // BYTE_ARRAY, REQUIRED, "id", UTF8
+		0x16, 0x00, // 3: num_rows = 0
+		0x00,
+	}
+	data := append([]byte("PAR1"), footer...)
+	data = append(data, byte(len(footer)), 0, 0, 0, 'P', 'A', 'R', '1')
+
+	path := filepath.Join(t.TempDir(), "sample.parquet")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	schema, err := ExtractParquet(path)
+	require.NoError(t, err)
+	assert.Equal(t, "m", schema.MessageName)
+	require.Len(t, schema.Columns, 1)
+	assert.Equal(t, ParquetColumn{Name: "id", PhysType: "binary", Repetition: "required", Annotation: "UTF8"}, schema.Columns[0])
+}
@@ -147,14 +147,16 @@ type OpenAPIProperty struct {
 
 // ── Avro ───────────────────────────────────────────────────────────────────
 
-// AvroSchema represents an Avro record or enum schema.
+// AvroSchema represents an Avro record or enum schema, or a protocol
+// (Type "protocol") whose named types are listed in Types.
 type AvroSchema struct {
-	Type      string      `json:"type"`
-	Name      string      `json:"name"`
-	Namespace string      `json:"namespace,omitempty"`
-	Doc       string      `json:"doc,omitempty"`
-	Fields    []AvroField `json:"fields,omitempty"`
-	Symbols   []string    `json:"symbols,omitempty"` // for enum types
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Doc       string       `json:"doc,omitempty"`
+	Fields    []AvroField  `json:"fields,omitempty"`
+	Symbols   []string     `json:"symbols,omitempty"` // for enum types
+	Types     []AvroSchema `json:"types,omitempty"`   // for protocols
 }
 
 // AvroField is a single field within an Avro record.
@@ -491,7 +491,9 @@
         types.push({ name: s.name, comment: "" });
       }
     } else if (format === "avro" && file.avro) {
-      types.push({ name: file.avro.name, comment: file.avro.doc || "" });
+      for (const t of avroTypes(file.avro)) {
+        types.push({ name: t.name, comment: t.doc || "" });
+      }
     } else if (format === "jsonschema" && file.jsonschema) {
       types.push({ name: file.jsonschema.title || file.filename, comment: file.jsonschema.description || "" });
     } else if (format === "parquet" && file.parquet) {
@@ -604,12 +606,20 @@
     }
   }
 
+  // avroTypes returns the named types of an Avro file: a protocol (.avpr,
+  // .avdl) lists them in types, a single schema is its own only type.
+  function avroTypes(avro) {
+    return avro.type === "protocol" ? (avro.types || []) : [avro];
+  }
+
   function addAvroChildren(children, avro, apiId) {
-    children.push({
-      kind: "type", icon: avro.type === "enum" ? "E" : "R", label: avro.name,
-      nodeId: "type:" + apiId + ":" + avro.name,
-      typeKind: avro.type === "enum" ? "enum" : "record",
-    });
+    for (const t of avroTypes(avro)) {
+      children.push({
+        kind: "type", icon: t.type === "enum" ? "E" : "R", label: t.name,
+        nodeId: "type:" + apiId + ":" + t.name,
+        typeKind: t.type === "enum" ? "enum" : "record",
+      });
+    }
   }
 
   function addJSONSchemaChildren(children, jsd, apiId, filename) {
@@ -1287,6 +1297,9 @@
   // ── Avro inline ──
 
   function renderAvroFileInline(avro, api) {
+    if (avro.type === "protocol") {
+      return avroTypes(avro).map(t => renderAvroFileInline(t, api)).join('');
+    }
     let html = '';
     html += typeAnchorHeader(avro.type === "enum" ? "enum" : "record", avro.name, api.id);
     if (avro.doc) html += '<div class="type-comment">' + esc(avro.doc) + '</div>';
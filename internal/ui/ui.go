@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
@@ -21,6 +22,9 @@ var (
 	// Output streams for testing
 	outputStream      io.Writer = os.Stdout
 	errorOutputStream io.Writer = os.Stderr
+
+	// outputMu keeps messages printed from concurrent goroutines whole
+	outputMu sync.Mutex
 )
 
 // InitializeFromEnv initializes UI settings from environment variables
@@ -53,6 +57,11 @@ func SetQuiet(q bool) {
 	quiet = q
 }
 
+// IsQuiet reports whether quiet mode is active.
+func IsQuiet() bool {
+	return quiet
+}
+
 // SetVerbose enables or disables verbose mode
 func SetVerbose(v bool) {
 	verbose = v
@@ -102,6 +111,9 @@ func print(level string, colorFunc func(string, ...interface{}) string, format s
 
 	message := fmt.Sprintf(format, args...)
 
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
 	// Choose appropriate output stream
 	output := outputStream
 	if level == "error" {
@@ -1,7 +1,9 @@
 package validator
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,7 +14,19 @@ import (
 // AvroValidator handles Avro schema validation
 type AvroValidator struct {
 	resolver          *ToolchainResolver
-	compatibilityMode string // BACKWARD, FORWARD, FULL, NONE
+	compatibilityMode string        // BACKWARD, FORWARD, FULL, NONE, or a *_TRANSITIVE variant
+	history           SchemaHistory // released versions, for *_TRANSITIVE modes
+	rules             *lintRuleSet  // lint rule settings from apx.yaml
+}
+
+// SchemaHistory supplies the previously released versions of a schema. The
+// Avro *_TRANSITIVE compatibility modes check the new schema against every
+// version it returns (Confluent Schema Registry semantics).
+type SchemaHistory interface {
+	// Versions lists the released versions, oldest first.
+	Versions() ([]string, error)
+	// Load returns the schema content as released in version.
+	Load(version string) ([]byte, error)
 }
 
 // NewAvroValidator creates a new Avro validator
@@ -28,37 +42,9 @@ func (v *AvroValidator) SetCompatibilityMode(mode string) {
 	v.compatibilityMode = mode
 }
 
-// avroSchema represents an Avro record schema.
-type avroSchema struct {
-	Type      string      `json:"type"`
-	Name      string      `json:"name"`
-	Namespace string      `json:"namespace"`
-	Fields    []avroField `json:"fields"`
-}
-
-// avroField represents a field within an Avro record.
-type avroField struct {
-	Name    string          `json:"name"`
-	Type    json.RawMessage `json:"type"`
-	Default json.RawMessage `json:"default"`
-	Doc     string          `json:"doc"`
-}
-
-// hasDefault returns true if the field has a default value defined.
-func (f avroField) hasDefault() bool {
-	return len(f.Default) > 0 && string(f.Default) != "null" || isNullableUnion(f.Type)
-}
-
-// isNullableUnion returns true if the type is a union that includes "null"
-// as its first element (the Avro convention for optional fields).
-func isNullableUnion(raw json.RawMessage) bool {
-	var union []json.RawMessage
-	if err := json.Unmarshal(raw, &union); err != nil || len(union) == 0 {
-		return false
-	}
-	var first string
-	_ = json.Unmarshal(union[0], &first)
-	return first == "null"
+// SetHistory sets the release history used by the *_TRANSITIVE modes.
+func (v *AvroValidator) SetHistory(h SchemaHistory) {
+	v.history = h
 }
 
 // validAvroTypes is the set of primitive Avro type names.
@@ -66,7 +52,7 @@ var validAvroTypes = map[string]bool{
 	"null": true, "boolean": true, "int": true, "long": true,
 	"float": true, "double": true, "bytes": true, "string": true,
 	"record": true, "enum": true, "array": true, "map": true,
-	"union": true, "fixed": true,
+	"union": true, "fixed": true, "error": true,
 }
 
 // avroCamelCaseRe matches valid camelCase identifiers (the Avro convention).
@@ -77,210 +63,301 @@ func isAvroCamelCase(s string) bool {
 	return avroCamelCaseRe.MatchString(s)
 }
 
+// avroPascalCaseRe matches PascalCase names, the opt-in record convention.
+var avroPascalCaseRe = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+// isAvroPascalCase returns true if the string follows PascalCase naming.
+func isAvroPascalCase(s string) bool {
+	return avroPascalCaseRe.MatchString(s)
+}
+
 // Lint validates Avro schema syntax using native Go parsing.
 // It collects all lint violations and returns them together.
 func (v *AvroValidator) Lint(path string) error {
+	findings, err := v.LintFindings(path)
+	return findingsError("avro lint errors", findings, err)
+}
+
+// LintFindings validates Avro schema syntax and returns every violation as a
+// Finding. The error is non-nil only when the schema could not be read.
+func (v *AvroValidator) LintFindings(path string) ([]Finding, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	data, err := os.ReadFile(absPath)
 	if err != nil {
-		return fmt.Errorf("reading %s: %w", path, err)
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var findings []Finding
+	add := func(rule, elem, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     path,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatAvro,
+			Path:     elem,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// IDL is linted as the protocol it translates to.
+	if isAvroIDL(absPath) {
+		proto, err := avroIDLToProtocol(data, filepath.Dir(absPath))
+		if err != nil {
+			add("avro-idl-syntax", "", "invalid Avro IDL: %v", err)
+			var idlErr *avroIDLError
+			if errors.As(err, &idlErr) {
+				findings[0].Line = idlErr.Line
+			}
+			return findings, nil
+		}
+		data = proto
 	}
 
 	// Must be valid JSON
 	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return fmt.Errorf("invalid JSON in Avro schema: %w", err)
+		add("avro-invalid-json", "", "invalid JSON in Avro schema: %v", err)
+		return findings, nil
 	}
 
+	if _, ok := raw["protocol"]; ok {
+		lintAvroProtocol(data, v.rules, add)
+		return findings, nil
+	}
+	lintAvroSchemaObject(raw, v.rules, add)
+	return findings, nil
+}
+
+// lintAvroSchemaObject checks a schema object: its type and, for records,
+// the name and fields. An x-apx-ignore attribute on the schema or on a
+// field lists rules that are not reported for it.
+func lintAvroSchemaObject(raw map[string]json.RawMessage, rules *lintRuleSet, add func(rule, elem, format string, args ...interface{})) {
+	add = ignoring(add, ignoredRules(raw["x-apx-ignore"]))
+
 	// Must have a "type" field — fatal, cannot continue without it
 	typeRaw, ok := raw["type"]
 	if !ok {
-		return fmt.Errorf("avro schema missing required 'type' field")
+		add("avro-missing-type", "", "avro schema missing required 'type' field")
+		return
 	}
 	var typeName string
 	if err := json.Unmarshal(typeRaw, &typeName); err != nil {
-		return fmt.Errorf("'type' must be a string: %w", err)
+		add("avro-invalid-type", "", "'type' must be a string: %v", err)
+		return
 	}
 	if !validAvroTypes[typeName] {
-		return fmt.Errorf("unknown avro type: %q", typeName)
+		add("avro-invalid-type", "", "unknown avro type: %q", typeName)
+		return
 	}
 
 	// Record schemas must have a "name" and valid "fields"
-	if typeName == "record" {
-		var violations []string
-
+	if typeName == "record" || typeName == "error" {
+		recordName := ""
 		nameRaw, ok := raw["name"]
 		if !ok {
-			violations = append(violations, "record schema missing required 'name' field")
+			add("avro-record-name", "", "record schema missing required 'name' field")
 		} else {
-			var name string
-			if err := json.Unmarshal(nameRaw, &name); err != nil || name == "" {
-				violations = append(violations, "'name' must be a non-empty string")
+			if err := json.Unmarshal(nameRaw, &recordName); err != nil || recordName == "" {
+				add("avro-record-name", "", "'name' must be a non-empty string")
+			} else if rules.enabled("avro-record-pascal-case") &&
+				!rules.nameOK("avro-record-pascal-case", recordName, isAvroPascalCase) {
+				add("avro-record-pascal-case", recordName, "record name %q should be PascalCase", recordName)
 			}
 		}
 
 		fieldsRaw, ok := raw["fields"]
 		if !ok {
-			violations = append(violations, "record schema missing required 'fields' array")
+			add("avro-record-fields", recordName, "record schema missing required 'fields' array")
 		} else {
 			var fields []json.RawMessage
 			if err := json.Unmarshal(fieldsRaw, &fields); err != nil {
-				violations = append(violations, fmt.Sprintf("'fields' must be an array: %v", err))
+				add("avro-record-fields", recordName, "'fields' must be an array: %v", err)
 			} else if len(fields) == 0 {
-				violations = append(violations, "record schema has empty 'fields' array")
+				add("avro-record-fields", recordName, "record schema has empty 'fields' array")
 			} else {
 				seen := make(map[string]int) // field name → field index
 				for i, fRaw := range fields {
 					var f map[string]json.RawMessage
 					if err := json.Unmarshal(fRaw, &f); err != nil {
-						violations = append(violations, fmt.Sprintf("field[%d] is not an object: %v", i, err))
+						add("avro-field-invalid", recordName, "field[%d] is not an object: %v", i, err)
 						continue
 					}
+					add := ignoring(add, ignoredRules(f["x-apx-ignore"]))
 
 					nameRaw, hasName := f["name"]
 					if !hasName {
-						violations = append(violations, fmt.Sprintf("field[%d] missing required 'name'", i))
+						add("avro-field-invalid", recordName, "field[%d] missing required 'name'", i)
 						continue
 					}
 					var fieldName string
 					if err := json.Unmarshal(nameRaw, &fieldName); err != nil {
-						violations = append(violations, fmt.Sprintf("field[%d] 'name' must be a string", i))
+						add("avro-field-invalid", recordName, "field[%d] 'name' must be a string", i)
 						continue
 					}
+					elem := avroElemPath(recordName, fieldName)
 
 					if _, ok := f["type"]; !ok {
-						violations = append(violations, fmt.Sprintf("field[%d] %q missing required 'type'", i, fieldName))
+						add("avro-field-invalid", elem, "field[%d] %q missing required 'type'", i, fieldName)
 					}
 
 					// Duplicate field name detection
 					if prevIdx, exists := seen[fieldName]; exists {
-						violations = append(violations, fmt.Sprintf(
-							"field[%d] duplicate field name %q (first defined at field[%d])", i, fieldName, prevIdx))
+						add("avro-field-duplicate", elem,
+							"field[%d] duplicate field name %q (first defined at field[%d])", i, fieldName, prevIdx)
 					}
 					seen[fieldName] = i
 
 					// Field naming convention: camelCase
-					if !isAvroCamelCase(fieldName) {
-						violations = append(violations, fmt.Sprintf(
-							"field[%d] name %q should be camelCase", i, fieldName))
+					if !rules.nameOK("avro-field-camel-case", fieldName, isAvroCamelCase) {
+						add("avro-field-camel-case", elem, "field[%d] name %q should be camelCase", i, fieldName)
 					}
 				}
 			}
 		}
-
-		if len(violations) > 0 {
-			return fmt.Errorf("avro lint errors:\n  %s", strings.Join(violations, "\n  "))
-		}
 	}
 
-	return nil
+}
+
+// avroElemPath joins a record name and a field name into an element path.
+func avroElemPath(record, field string) string {
+	if record == "" {
+		return field
+	}
+	return record + "." + field
 }
 
 // Breaking checks Avro schema compatibility between two versions.
 // path is the new schema; against is the old/baseline schema.
 func (v *AvroValidator) Breaking(path, against string) error {
+	findings, err := v.BreakingFindings(path, against)
+	return findingsError("avro compatibility violations", findings, err)
+}
+
+// BreakingFindings checks Avro schema compatibility between two versions and
+// returns every incompatibility as a Finding.
+func (v *AvroValidator) BreakingFindings(path, against string) ([]Finding, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 	absAgainst, err := filepath.Abs(against)
 	if err != nil {
-		return fmt.Errorf("failed to resolve against path: %w", err)
+		return nil, fmt.Errorf("failed to resolve against path: %w", err)
 	}
 
-	newData, err := os.ReadFile(absPath)
+	newData, err := ReadAvroJSON(absPath)
 	if err != nil {
-		return fmt.Errorf("reading new schema %s: %w", path, err)
+		return nil, fmt.Errorf("reading new schema %s: %w", path, err)
 	}
-	oldData, err := os.ReadFile(absAgainst)
+	oldData, err := ReadAvroJSON(absAgainst)
 	if err != nil {
-		return fmt.Errorf("reading old schema %s: %w", against, err)
+		return nil, fmt.Errorf("reading old schema %s: %w", against, err)
 	}
 
-	var newSchema, oldSchema avroSchema
-	if err := json.Unmarshal(newData, &newSchema); err != nil {
-		return fmt.Errorf("parsing new schema: %w", err)
+	newDoc, err := parseAvroDocument(newData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing new schema: %w", err)
 	}
-	if err := json.Unmarshal(oldData, &oldSchema); err != nil {
-		return fmt.Errorf("parsing old schema: %w", err)
+	oldDoc, err := parseAvroDocument(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing old schema: %w", err)
 	}
 
-	mode := v.compatibilityMode
+	mode := strings.ToUpper(v.compatibilityMode)
 	if mode == "" {
 		mode = "BACKWARD"
 	}
 
-	switch strings.ToUpper(mode) {
+	// BACKWARD_TRANSITIVE etc. check against the explicit baseline like
+	// their non-transitive base mode, then against every released version.
+	base, transitive := strings.CutSuffix(mode, "_TRANSITIVE")
+	if transitive && base == "NONE" {
+		base = mode // NONE has no transitive variant
+	}
+	findings, err := checkAvroDocuments(base, newDoc, oldDoc)
+	if err != nil {
+		return nil, fmt.Errorf("checking %s compatibility: %w", v.compatibilityMode, err)
+	}
+
+	if transitive {
+		prior, err := v.checkAvroHistory(base, newDoc, oldData, filepath.Dir(absPath))
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, prior...)
+	}
+	for i := range findings {
+		findings[i].File = path
+	}
+	return findings, nil
+}
+
+// checkAvroCompatibility applies a non-transitive compatibility mode to the
+// new schema and one earlier schema.
+func checkAvroCompatibility(mode string, newSchema, oldSchema *avroNode) ([]Finding, error) {
+	switch mode {
 	case "BACKWARD":
-		return checkAvroBackward(newSchema, oldSchema)
+		return resolveAvro(newSchema, oldSchema), nil
 	case "FORWARD":
-		return checkAvroBackward(oldSchema, newSchema)
+		return resolveAvro(oldSchema, newSchema), nil
 	case "FULL":
-		if err := checkAvroBackward(newSchema, oldSchema); err != nil {
-			return err
-		}
-		return checkAvroBackward(oldSchema, newSchema)
+		return dedupeFindings(append(resolveAvro(newSchema, oldSchema), resolveAvro(oldSchema, newSchema)...)), nil
 	case "NONE":
-		return nil
-	default:
-		return fmt.Errorf("unknown compatibility mode: %s", v.compatibilityMode)
+		return nil, nil
 	}
+	return nil, fmt.Errorf("unknown compatibility mode: %s", mode)
 }
 
-// checkAvroBackward verifies that reader can read data written by writer.
-// Avro BACKWARD compatibility: new schema (reader) can read data written by
-// old schema (writer).
-func checkAvroBackward(reader, writer avroSchema) error {
-	if reader.Type != "record" || writer.Type != "record" {
-		// Non-record types: skip structural field check
-		return nil
+// checkAvroHistory checks the new schema against every released version for
+// a *_TRANSITIVE mode. Each finding names the release it conflicts with.
+// A release identical to baseline, the schema already checked, is skipped so
+// its conflicts are not reported twice. Released IDL resolves its imports
+// from dir, the current schema's directory.
+func (v *AvroValidator) checkAvroHistory(mode string, newDoc *avroDocument, baseline []byte, dir string) ([]Finding, error) {
+	if v.history == nil {
+		return nil, fmt.Errorf("%s_TRANSITIVE compatibility needs the API's release history; run against an API ID with release tags", mode)
 	}
-
-	writerFields := make(map[string]avroField, len(writer.Fields))
-	for _, f := range writer.Fields {
-		writerFields[f.Name] = f
-	}
-	readerFields := make(map[string]avroField, len(reader.Fields))
-	for _, f := range reader.Fields {
-		readerFields[f.Name] = f
+	versions, err := v.history.Versions()
+	if err != nil {
+		return nil, fmt.Errorf("listing released versions: %w", err)
 	}
-
-	var violations []string
-
-	// For each reader field: if not in writer it must have a default so the
-	// reader knows what value to use for records written without it.
-	for _, rf := range reader.Fields {
-		if _, inWriter := writerFields[rf.Name]; !inWriter {
-			if !rf.hasDefault() {
-				violations = append(violations, fmt.Sprintf(
-					"field %q added to new schema without a default value "+
-						"(cannot read old data that is missing this field)",
-					rf.Name))
+	var findings []Finding
+	for _, version := range versions {
+		data, err := v.history.Load(version)
+		if err != nil {
+			return nil, fmt.Errorf("loading schema released as %s: %w", version, err)
+		}
+		if looksLikeAvroIDL(data) {
+			if data, err = avroIDLToProtocol(data, dir); err != nil {
+				return nil, fmt.Errorf("parsing schema released as %s: %w", version, err)
 			}
 		}
-	}
-
-	// For shared fields: types must match (simplified: compare JSON representation).
-	for _, rf := range reader.Fields {
-		wf, inWriter := writerFields[rf.Name]
-		if !inWriter {
+		if sameAvroJSON(data, baseline) {
 			continue
 		}
-		if string(rf.Type) != string(wf.Type) {
-			violations = append(violations, fmt.Sprintf(
-				"field %q type changed from %s to %s "+
-					"(type changes are not backward compatible)",
-				rf.Name, string(wf.Type), string(rf.Type)))
+		released, err := parseAvroDocument(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing schema released as %s: %w", version, err)
+		}
+		prior, _ := checkAvroDocuments(mode, newDoc, released)
+		for _, f := range prior {
+			f.Message = fmt.Sprintf("%s (against released %s)", f.Message, version)
+			findings = append(findings, f)
 		}
 	}
+	return findings, nil
+}
 
-	if len(violations) > 0 {
-		return fmt.Errorf("avro backward compatibility violations:\n  %s",
-			strings.Join(violations, "\n  "))
+// sameAvroJSON reports whether two schema documents are the same JSON,
+// ignoring insignificant whitespace.
+func sameAvroJSON(a, b []byte) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
 	}
-	return nil
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
 }
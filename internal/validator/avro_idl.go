@@ -0,0 +1,853 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// avroIDLError is a syntax error in an Avro IDL file.
+type avroIDLError struct {
+	Line int
+	Msg  string
+}
+
+func (e *avroIDLError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// avroIDLToProtocol translates Avro IDL (.avdl) into the equivalent protocol
+// JSON (.avpr), as `avro-tools idl` does, so IDL-authored schemas share the
+// protocol code path. dir resolves relative imports; when it is empty,
+// imports are an error. IDL files in schema mode (namespace/schema
+// statements and bare declarations, without a protocol block) become a
+// protocol with no messages.
+func avroIDLToProtocol(data []byte, dir string) ([]byte, error) {
+	p := &avroIDLParser{src: string(data), line: 1, dir: dir}
+	proto, err := p.parseFile()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(proto)
+}
+
+// avroIDLLogicalTypes maps IDL logical type keywords to their schemas.
+var avroIDLLogicalTypes = map[string]map[string]interface{}{
+	"date":               {"type": "int", "logicalType": "date"},
+	"time_ms":            {"type": "int", "logicalType": "time-millis"},
+	"timestamp_ms":       {"type": "long", "logicalType": "timestamp-millis"},
+	"local_timestamp_ms": {"type": "long", "logicalType": "local-timestamp-millis"},
+	"uuid":               {"type": "string", "logicalType": "uuid"},
+}
+
+// idlToken is one lexical token. kind is 'i' (identifier), 's' (string
+// literal) or the punctuation character itself.
+type idlToken struct {
+	kind byte
+	text string
+	line int
+}
+
+type avroIDLParser struct {
+	src  string
+	pos  int
+	line int
+	dir  string
+	doc  string // most recent /** doc comment */, consumed by the next declaration
+	tok  *idlToken
+
+	namespace string
+	types     []interface{}
+	messages  map[string]interface{}
+}
+
+func (p *avroIDLParser) errorf(format string, args ...interface{}) error {
+	return &avroIDLError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// skipSpace skips whitespace and comments, remembering doc comments.
+func (p *avroIDLParser) skipSpace() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			body := p.src[p.pos+2 : p.pos+2+end]
+			p.line += strings.Count(body, "\n")
+			p.pos += end + 4
+			if strings.HasPrefix(body, "*") {
+				p.doc = cleanAvroIDLDoc(body[1:])
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// cleanAvroIDLDoc strips comment decoration from a doc comment body.
+func cleanAvroIDLDoc(body string) string {
+	var lines []string
+	for _, l := range strings.Split(body, "\n") {
+		l = strings.TrimSpace(l)
+		l = strings.TrimSpace(strings.TrimPrefix(l, "*"))
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// takeDoc returns and clears the pending doc comment.
+func (p *avroIDLParser) takeDoc() string {
+	d := p.doc
+	p.doc = ""
+	return d
+}
+
+func (p *avroIDLParser) peek() (*idlToken, error) {
+	if p.tok != nil {
+		return p.tok, nil
+	}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.src) {
+		return &idlToken{kind: 0, line: p.line}, nil
+	}
+	start, c := p.pos, rune(p.src[p.pos])
+	t := &idlToken{line: p.line}
+	switch {
+	case c == '"':
+		var s string
+		dec := json.NewDecoder(strings.NewReader(p.src[p.pos:]))
+		if err := dec.Decode(&s); err != nil {
+			return nil, p.errorf("invalid string literal")
+		}
+		p.pos += int(dec.InputOffset())
+		t.kind, t.text = 's', s
+	case c == '`':
+		end := strings.IndexByte(p.src[p.pos+1:], '`')
+		if end < 0 {
+			return nil, p.errorf("unterminated quoted identifier")
+		}
+		t.kind, t.text = 'i', p.src[p.pos+1:p.pos+1+end]
+		p.pos += end + 2
+	case unicode.IsLetter(c) || c == '_' || unicode.IsDigit(c) || c == '-':
+		for p.pos < len(p.src) {
+			r := rune(p.src[p.pos])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-' {
+				break
+			}
+			p.pos++
+		}
+		t.kind, t.text = 'i', p.src[start:p.pos]
+	default:
+		p.pos++
+		t.kind, t.text = byte(c), string(c)
+	}
+	p.tok = t
+	return t, nil
+}
+
+func (p *avroIDLParser) next() (*idlToken, error) {
+	t, err := p.peek()
+	p.tok = nil
+	return t, err
+}
+
+// expect consumes a punctuation token.
+func (p *avroIDLParser) expect(kind byte) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != kind {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("expected %q, got %s", kind, t.describe())}
+	}
+	return nil
+}
+
+// ident consumes an identifier.
+func (p *avroIDLParser) ident() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if t.kind != 'i' {
+		return "", &avroIDLError{Line: t.line, Msg: fmt.Sprintf("expected identifier, got %s", t.describe())}
+	}
+	return t.text, nil
+}
+
+// accept consumes the next token if it is the given punctuation or keyword.
+func (p *avroIDLParser) accept(text string) (bool, error) {
+	t, err := p.peek()
+	if err != nil {
+		return false, err
+	}
+	if t.text == text && (t.kind == 'i' || len(text) == 1) {
+		p.tok = nil
+		return true, nil
+	}
+	return false, nil
+}
+
+func (t *idlToken) describe() string {
+	switch t.kind {
+	case 0:
+		return "end of file"
+	case 's':
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// jsonValue reads a JSON value (a default or annotation argument) at the
+// current position.
+func (p *avroIDLParser) jsonValue() (json.RawMessage, error) {
+	if p.tok != nil {
+		// Rewind an identifier-like literal (true, false, null, 1.5) that
+		// was peeked as a token.
+		p.pos -= len(p.tok.text)
+		if p.tok.kind == 's' {
+			return nil, p.errorf("internal error: string literal peeked before JSON value")
+		}
+		p.tok = nil
+	}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	var v json.RawMessage
+	dec := json.NewDecoder(strings.NewReader(p.src[p.pos:]))
+	if err := dec.Decode(&v); err != nil {
+		return nil, p.errorf("invalid JSON value: %v", err)
+	}
+	consumed := p.src[p.pos : p.pos+int(dec.InputOffset())]
+	p.line += strings.Count(consumed, "\n")
+	p.pos += len(consumed)
+	return v, nil
+}
+
+// annotations reads any @name(value) annotations.
+func (p *avroIDLParser) annotations() (map[string]json.RawMessage, error) {
+	var props map[string]json.RawMessage
+	for {
+		ok, err := p.accept("@")
+		if err != nil || !ok {
+			return props, err
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		v, err := p.jsonValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		if props == nil {
+			props = map[string]json.RawMessage{}
+		}
+		props[name] = v
+	}
+}
+
+// parseFile parses a whole IDL file.
+func (p *avroIDLParser) parseFile() (map[string]interface{}, error) {
+	p.messages = map[string]interface{}{}
+	proto := map[string]interface{}{}
+
+	props, err := p.annotations()
+	if err != nil {
+		return nil, err
+	}
+	if ns, ok := props["namespace"]; ok {
+		_ = json.Unmarshal(ns, &p.namespace)
+	}
+
+	isProtocol, err := p.accept("protocol")
+	if err != nil {
+		return nil, err
+	}
+	if isProtocol {
+		doc := p.takeDoc()
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+		proto["protocol"] = name
+		if doc != "" {
+			proto["doc"] = doc
+		}
+		for {
+			done, err := p.accept("}")
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				break
+			}
+			if err := p.declaration(true); err != nil {
+				return nil, err
+			}
+		}
+		if t, err := p.peek(); err != nil {
+			return nil, err
+		} else if t.kind != 0 {
+			return nil, &avroIDLError{Line: t.line, Msg: fmt.Sprintf("unexpected %s after protocol", t.describe())}
+		}
+	} else {
+		// Schema mode: namespace/schema statements and bare declarations.
+		for {
+			t, err := p.peek()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == 0 {
+				break
+			}
+			switch {
+			case t.kind == 'i' && t.text == "namespace":
+				p.next()
+				if p.namespace, err = p.ident(); err != nil {
+					return nil, err
+				}
+				if err := p.expect(';'); err != nil {
+					return nil, err
+				}
+			case t.kind == 'i' && t.text == "schema":
+				p.next()
+				main, err := p.typeExpr()
+				if err != nil {
+					return nil, err
+				}
+				if s, ok := main.(string); ok {
+					proto["protocol"] = s
+				}
+				if err := p.expect(';'); err != nil {
+					return nil, err
+				}
+			default:
+				if err := p.declaration(false); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if p.namespace != "" {
+		proto["namespace"] = p.namespace
+	}
+	proto["types"] = p.types
+	if len(p.messages) > 0 {
+		proto["messages"] = p.messages
+	}
+	if _, ok := proto["protocol"]; !ok {
+		proto["protocol"] = ""
+	}
+	return proto, nil
+}
+
+// declaration parses one import, named type or (in a protocol) message.
+func (p *avroIDLParser) declaration(inProtocol bool) error {
+	props, err := p.annotations()
+	if err != nil {
+		return err
+	}
+	doc := p.takeDoc()
+	t, err := p.peek()
+	if err != nil {
+		return err
+	}
+	if t.kind != 'i' {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("expected a declaration, got %s", t.describe())}
+	}
+
+	switch t.text {
+	case "import":
+		p.next()
+		return p.importFile()
+	case "record", "error":
+		p.next()
+		return p.record(t.text, props, doc)
+	case "enum":
+		p.next()
+		return p.enum(props, doc)
+	case "fixed":
+		p.next()
+		return p.fixed(props, doc)
+	}
+	if !inProtocol {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("expected record, enum, fixed or import, got %s", t.describe())}
+	}
+	return p.message(props, doc)
+}
+
+// named builds the common attributes of a named type.
+func (p *avroIDLParser) named(kind, name string, props map[string]json.RawMessage, doc string) map[string]interface{} {
+	s := map[string]interface{}{"type": kind, "name": name}
+	if ns, ok := props["namespace"]; ok {
+		s["namespace"] = ns
+	} else if p.namespace != "" && !strings.Contains(name, ".") {
+		s["namespace"] = p.namespace
+	}
+	if doc != "" {
+		s["doc"] = doc
+	}
+	for k, v := range props {
+		if k != "namespace" {
+			s[k] = v
+		}
+	}
+	return s
+}
+
+func (p *avroIDLParser) record(kind string, props map[string]json.RawMessage, doc string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+	fields := []interface{}{}
+	for {
+		done, err := p.accept("}")
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+		fs, err := p.fieldDecl()
+		if err != nil {
+			return err
+		}
+		fields = append(fields, fs...)
+	}
+	s := p.named(kind, name, props, doc)
+	s["fields"] = fields
+	p.types = append(p.types, s)
+	return nil
+}
+
+// fieldDecl parses "type name [= default] {, name [= default]};".
+func (p *avroIDLParser) fieldDecl() ([]interface{}, error) {
+	doc := p.takeDoc()
+	typ, err := p.typeExpr()
+	if err != nil {
+		return nil, err
+	}
+	var fields []interface{}
+	for {
+		props, err := p.annotations()
+		if err != nil {
+			return nil, err
+		}
+		if d := p.takeDoc(); d != "" {
+			doc = d
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		f := map[string]interface{}{"name": name, "type": typ}
+		if doc != "" {
+			f["doc"] = doc
+		}
+		for k, v := range props {
+			f[k] = v
+		}
+		if ok, err := p.accept("="); err != nil {
+			return nil, err
+		} else if ok {
+			def, err := p.jsonValue()
+			if err != nil {
+				return nil, err
+			}
+			f["default"] = def
+			f["type"] = avroIDLOptionalOrder(typ, def)
+		}
+		fields = append(fields, f)
+		if ok, err := p.accept(","); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+	}
+	return fields, p.expect(';')
+}
+
+// avroIDLOptionalOrder puts the non-null branch first for a "T?" field whose
+// default is not null, since a union default must match its first branch.
+func avroIDLOptionalOrder(typ interface{}, def json.RawMessage) interface{} {
+	u, ok := typ.(avroIDLOptional)
+	if !ok {
+		return typ
+	}
+	if strings.TrimSpace(string(def)) == "null" {
+		return []interface{}{"null", u.inner}
+	}
+	return []interface{}{u.inner, "null"}
+}
+
+// avroIDLOptional is a "T?" type; it marshals as the union [null, T].
+type avroIDLOptional struct{ inner interface{} }
+
+func (o avroIDLOptional) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{"null", o.inner})
+}
+
+// typeExpr parses a type: primitive, logical keyword, named reference,
+// array<T>, map<T>, union {...}, decimal(p,s), optionally annotated and
+// optionally followed by "?".
+func (p *avroIDLParser) typeExpr() (interface{}, error) {
+	props, err := p.annotations()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	var typ interface{}
+	switch name {
+	case "array", "map":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		inner, err := p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		key := "items"
+		if name == "map" {
+			key = "values"
+		}
+		typ = map[string]interface{}{"type": name, key: inner}
+	case "union":
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+		var branches []interface{}
+		for {
+			b, err := p.typeExpr()
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, b)
+			if ok, err := p.accept(","); err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+		}
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+		typ = branches
+	case "decimal":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		precision, err := p.jsonValue()
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "bytes", "logicalType": "decimal", "precision": precision}
+		if ok, err := p.accept(","); err != nil {
+			return nil, err
+		} else if ok {
+			scale, err := p.jsonValue()
+			if err != nil {
+				return nil, err
+			}
+			s["scale"] = scale
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		typ = s
+	case "void":
+		typ = "null"
+	default:
+		if lt, ok := avroIDLLogicalTypes[name]; ok {
+			s := make(map[string]interface{}, len(lt))
+			for k, v := range lt {
+				s[k] = v
+			}
+			typ = s
+		} else {
+			typ = name
+		}
+	}
+
+	if len(props) > 0 {
+		// Annotations on a type become schema properties, e.g.
+		// @logicalType("timestamp-micros") long.
+		s, ok := typ.(map[string]interface{})
+		if !ok {
+			s = map[string]interface{}{"type": typ}
+		}
+		for k, v := range props {
+			s[k] = v
+		}
+		typ = s
+	}
+
+	if ok, err := p.accept("?"); err != nil {
+		return nil, err
+	} else if ok {
+		typ = avroIDLOptional{inner: typ}
+	}
+	return typ, nil
+}
+
+func (p *avroIDLParser) enum(props map[string]json.RawMessage, doc string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+	symbols := []string{}
+	for {
+		if done, err := p.accept("}"); err != nil {
+			return err
+		} else if done {
+			break
+		}
+		sym, err := p.ident()
+		if err != nil {
+			return err
+		}
+		symbols = append(symbols, sym)
+		if _, err := p.accept(","); err != nil {
+			return err
+		}
+	}
+	s := p.named("enum", name, props, doc)
+	s["symbols"] = symbols
+	if ok, err := p.accept("="); err != nil {
+		return err
+	} else if ok {
+		def, err := p.ident()
+		if err != nil {
+			return err
+		}
+		s["default"] = def
+		if err := p.expect(';'); err != nil {
+			return err
+		}
+	}
+	p.types = append(p.types, s)
+	return nil
+}
+
+func (p *avroIDLParser) fixed(props map[string]json.RawMessage, doc string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	size, err := p.jsonValue()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(')'); err != nil {
+		return err
+	}
+	s := p.named("fixed", name, props, doc)
+	s["size"] = size
+	p.types = append(p.types, s)
+	return p.expect(';')
+}
+
+// message parses "ResponseType name(params) [throws E, ...] [oneway];".
+func (p *avroIDLParser) message(props map[string]json.RawMessage, doc string) error {
+	response, err := p.typeExpr()
+	if err != nil {
+		return err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	request := []interface{}{}
+	for {
+		if done, err := p.accept(")"); err != nil {
+			return err
+		} else if done {
+			break
+		}
+		typ, err := p.typeExpr()
+		if err != nil {
+			return err
+		}
+		pname, err := p.ident()
+		if err != nil {
+			return err
+		}
+		param := map[string]interface{}{"name": pname, "type": typ}
+		if ok, err := p.accept("="); err != nil {
+			return err
+		} else if ok {
+			def, err := p.jsonValue()
+			if err != nil {
+				return err
+			}
+			param["default"] = def
+			param["type"] = avroIDLOptionalOrder(typ, def)
+		}
+		request = append(request, param)
+		if _, err := p.accept(","); err != nil {
+			return err
+		}
+	}
+
+	msg := map[string]interface{}{"request": request, "response": response}
+	if doc != "" {
+		msg["doc"] = doc
+	}
+	for k, v := range props {
+		msg[k] = v
+	}
+	if ok, err := p.accept("throws"); err != nil {
+		return err
+	} else if ok {
+		var errs []interface{}
+		for {
+			e, err := p.ident()
+			if err != nil {
+				return err
+			}
+			errs = append(errs, e)
+			if ok, err := p.accept(","); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+		}
+		msg["errors"] = errs
+	}
+	if ok, err := p.accept("oneway"); err != nil {
+		return err
+	} else if ok {
+		msg["one-way"] = true
+	}
+	p.messages[name] = msg
+	return p.expect(';')
+}
+
+// importFile handles "import idl|protocol|schema "file";" by merging the
+// imported file's named types.
+func (p *avroIDLParser) importFile() error {
+	kind, err := p.ident()
+	if err != nil {
+		return err
+	}
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != 's' {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("expected import file name, got %s", t.describe())}
+	}
+	if err := p.expect(';'); err != nil {
+		return err
+	}
+	if p.dir == "" {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("cannot resolve import %q without a base directory", t.text)}
+	}
+	file := filepath.Join(p.dir, filepath.FromSlash(t.text))
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("import %q: %v", t.text, err)}
+	}
+
+	switch kind {
+	case "idl":
+		data, err = avroIDLToProtocol(data, filepath.Dir(file))
+		if err != nil {
+			return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("import %q: %v", t.text, err)}
+		}
+		fallthrough
+	case "protocol":
+		var proto struct {
+			Namespace string            `json:"namespace"`
+			Types     []json.RawMessage `json:"types"`
+			Messages  map[string]json.RawMessage
+		}
+		if err := json.Unmarshal(data, &proto); err != nil {
+			return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("import %q: %v", t.text, err)}
+		}
+		for _, raw := range proto.Types {
+			p.types = append(p.types, avroQualify(raw, proto.Namespace))
+		}
+		for name, m := range proto.Messages {
+			p.messages[name] = m
+		}
+	case "schema":
+		p.types = append(p.types, json.RawMessage(data))
+	default:
+		return &avroIDLError{Line: t.line, Msg: fmt.Sprintf("unknown import kind %q (want idl, protocol or schema)", kind)}
+	}
+	return nil
+}
+
+// avroQualify gives a named type from an imported protocol an explicit
+// namespace, so it keeps its identity when merged into another protocol.
+func avroQualify(raw json.RawMessage, ns string) json.RawMessage {
+	if ns == "" {
+		return raw
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return raw
+	}
+	if _, ok := obj["namespace"]; ok {
+		return raw
+	}
+	if name := avroString(obj["name"]); name == "" || strings.Contains(name, ".") {
+		return raw
+	}
+	obj["namespace"], _ = json.Marshal(ns)
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return raw
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package validator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAvroIDLToProtocol(t *testing.T) {
+	data, err := ReadAvroJSON("testdata/avro/idl/orders_v1.avdl")
+	if err != nil {
+		t.Fatalf("ReadAvroJSON: %v", err)
+	}
+	var proto struct {
+		Protocol  string                     `json:"protocol"`
+		Namespace string                     `json:"namespace"`
+		Doc       string                     `json:"doc"`
+		Types     []map[string]interface{}   `json:"types"`
+		Messages  map[string]json.RawMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &proto); err != nil {
+		t.Fatalf("translated IDL is not valid JSON: %v", err)
+	}
+	if proto.Protocol != "Orders" || proto.Namespace != "com.example.orders" || proto.Doc != "Order management service." {
+		t.Errorf("unexpected protocol header: %q %q %q", proto.Protocol, proto.Namespace, proto.Doc)
+	}
+
+	var names []string
+	for _, ty := range proto.Types {
+		names = append(names, ty["namespace"].(string)+"."+ty["name"].(string))
+	}
+	want := "com.example.common.Currency com.example.common.Md5 com.example.orders.LineItem com.example.orders.Order com.example.orders.OrderNotFound"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("types = %s, want %s (imported types first)", got, want)
+	}
+	if len(proto.Messages) != 2 {
+		t.Errorf("expected 2 messages, got %d", len(proto.Messages))
+	}
+
+	order, _ := json.Marshal(proto.Types[3]["fields"])
+	for _, frag := range []string{
+		`{"name":"createdAt","type":{"logicalType":"timestamp-millis","type":"long"}}`,
+		`{"default":null,"name":"note","type":["null","string"]}`,
+		`{"default":{},"name":"labels","type":{"type":"map","values":"string"}}`,
+	} {
+		if !strings.Contains(string(order), frag) {
+			t.Errorf("Order fields missing %s:\n%s", frag, order)
+		}
+	}
+	cancel := string(proto.Messages["cancelOrder"])
+	if !strings.Contains(cancel, `"one-way":true`) || !strings.Contains(cancel, `"response":"null"`) {
+		t.Errorf("unexpected cancelOrder message: %s", cancel)
+	}
+}
+
+func TestAvroIDLToProtocol_Syntax(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"optional with non-null default", `protocol P { record R { string? s = "x"; } }`, `"type":["string","null"]`},
+		{"union", `protocol P { record R { union { null, int, string } u = null; } }`, `"type":["null","int","string"]`},
+		{"multiple variables", `protocol P { record R { int a, b = 1; } }`, `{"default":1,"name":"b","type":"int"}`},
+		{"field annotations", `protocol P { record R { string @aliases(["old"]) @order("descending") s; } }`, `"aliases":["old"]`},
+		{"type annotation", `protocol P { record R { @logicalType("timestamp-micros") long t; } }`, `{"logicalType":"timestamp-micros","type":"long"}`},
+		{"quoted identifier", "protocol P { record R { string `error`; } }", `{"name":"error","type":"string"}`},
+		{"throws", `protocol P { error E { string m; } void f() throws E; }`, `"errors":["E"]`},
+		{"schema mode", "namespace a.b;\nschema R;\nrecord R { S s; }\nenum S { X }", `"protocol":"R"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := avroIDLToProtocol([]byte(tt.src), "")
+			if err != nil {
+				t.Fatalf("avroIDLToProtocol: %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("expected %s in:\n%s", tt.want, data)
+			}
+			if _, err := parseAvroDocument(data); err != nil {
+				t.Errorf("translated protocol does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestAvroValidator_IDLLint(t *testing.T) {
+	v := NewAvroValidator(&ToolchainResolver{})
+
+	for _, path := range []string{
+		"testdata/avro/idl/orders_v1.avdl",
+		"testdata/avro/idl/schema_mode.avdl",
+		"testdata/avro/orders_v1.avpr",
+	} {
+		findings, err := v.LintFindings(path)
+		if err != nil {
+			t.Fatalf("LintFindings(%s): %v", path, err)
+		}
+		if len(findings) != 0 {
+			t.Errorf("expected %s to lint clean, got %+v", path, findings)
+		}
+	}
+
+	findings, err := v.LintFindings("testdata/avro/idl/bad_syntax.avdl")
+	if err != nil {
+		t.Fatalf("LintFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "avro-idl-syntax" || findings[0].Line != 5 {
+		t.Errorf("expected one avro-idl-syntax finding on line 5, got %+v", findings)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "naming.avdl")
+	mustWrite(t, path, "protocol P {\n  record R {\n    string user_id;\n    Missing m;\n  }\n}\n")
+	findings, err = v.LintFindings(path)
+	if err != nil {
+		t.Fatalf("LintFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "avro-field-camel-case" || findings[0].Path != "R.user_id" {
+		t.Errorf("expected a camelCase finding for R.user_id, got %+v", findings)
+	}
+
+	mustWrite(t, path, "protocol P {\n  record R {\n    Missing m;\n  }\n}\n")
+	findings, err = v.LintFindings(path)
+	if err != nil {
+		t.Fatalf("LintFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "avro-protocol-invalid" || !strings.Contains(findings[0].Message, `unknown type "Missing"`) {
+		t.Errorf("expected an unresolved type finding, got %+v", findings)
+	}
+}
+
+func TestAvroValidator_IDLBreaking(t *testing.T) {
+	tests := []struct {
+		name    string
+		newPath string
+		oldPath string
+		mode    string
+		want    []string // rule@path
+	}{
+		{
+			name:    "compatible evolution",
+			newPath: "testdata/avro/idl/orders_v2_compatible.avdl",
+			oldPath: "testdata/avro/idl/orders_v1.avdl",
+			mode:    "FULL",
+		},
+		{
+			name:    "IDL against the equivalent protocol JSON",
+			newPath: "testdata/avro/idl/orders_v1.avdl",
+			oldPath: "testdata/avro/orders_v1.avpr",
+			mode:    "FULL",
+		},
+		{
+			name:    "breaking",
+			newPath: "testdata/avro/idl/orders_v2_breaking.avdl",
+			oldPath: "testdata/avro/idl/orders_v1.avdl",
+			mode:    "BACKWARD",
+			want: []string{
+				"avro-field-type-changed@Order.items[].quantity",
+				"avro-field-added-without-default@Order.region",
+				"avro-message-removed@cancelOrder",
+				"avro-field-added-without-default@getOrder.tenant",
+				"avro-field-type-changed@getOrder.response.items[].quantity",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewAvroValidator(&ToolchainResolver{})
+			v.SetCompatibilityMode(tt.mode)
+			findings, err := v.BreakingFindings(tt.newPath, tt.oldPath)
+			if err != nil {
+				t.Fatalf("BreakingFindings: %v", err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.RuleID+"@"+f.Path)
+				if f.File != tt.newPath {
+					t.Errorf("finding file = %q, want %q", f.File, tt.newPath)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestAvroValidator_ProtocolTypeRemoved(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.avdl")
+	newPath := filepath.Join(dir, "new.avdl")
+	mustWrite(t, oldPath, "protocol P { record A { int x; } record B { int y; } }")
+	mustWrite(t, newPath, "protocol P { record A { int x; } }")
+
+	for mode, wantRemoved := range map[string]bool{"BACKWARD": true, "FULL": true, "FORWARD": false, "NONE": false} {
+		v := NewAvroValidator(&ToolchainResolver{})
+		v.SetCompatibilityMode(mode)
+		findings, err := v.BreakingFindings(newPath, oldPath)
+		if err != nil {
+			t.Fatalf("%s: BreakingFindings: %v", mode, err)
+		}
+		removed := len(findings) == 1 && findings[0].RuleID == "avro-type-removed" && findings[0].Path == "B"
+		if removed != wantRemoved || (!wantRemoved && len(findings) != 0) {
+			t.Errorf("%s: got %+v, want type-removed=%v", mode, findings, wantRemoved)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// avroDocument is a parsed Avro schema file: either a single schema (.avsc)
+// or a protocol (.avpr, or .avdl translated to protocol JSON).
+type avroDocument struct {
+	Protocol string                  // protocol name; empty for a single schema
+	Schema   *avroNode               // single schema
+	Types    []*avroNode             // protocol named types, in declaration order
+	Messages map[string]*avroMessage // protocol messages by name
+}
+
+// avroMessage is a protocol message. Both sides are records named after the
+// message: Request holds the parameters, Response a "response" field and,
+// when errors are declared, an "errors" union. Resolution therefore reports
+// paths such as "getUser.id" (a parameter) or "getUser.response".
+type avroMessage struct {
+	Request  *avroNode
+	Response *avroNode
+}
+
+// isAvroIDL reports whether a file is Avro IDL, by extension.
+func isAvroIDL(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".avdl")
+}
+
+// ReadAvroJSON reads an Avro schema, protocol or IDL file and returns its
+// JSON form: .avsc and .avpr content as is, and .avdl translated to the
+// equivalent protocol JSON with imports resolved relative to the file.
+func ReadAvroJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !isAvroIDL(path) {
+		return data, nil
+	}
+	return avroIDLToProtocol(data, filepath.Dir(path))
+}
+
+// looksLikeAvroIDL sniffs content whose file name is unknown (such as a
+// released version loaded from git): JSON schemas start with '{', '[' or
+// '"', IDL never does.
+func looksLikeAvroIDL(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] != '{' && data[0] != '[' && data[0] != '"'
+}
+
+// parseAvroDocument parses schema or protocol JSON.
+func parseAvroDocument(data []byte) (*avroDocument, error) {
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(data, &probe) == nil {
+		if _, ok := probe["protocol"]; ok {
+			return parseAvroProtocol(data)
+		}
+	}
+	schema, err := parseAvroSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	return &avroDocument{Schema: schema}, nil
+}
+
+// parseAvroProtocol parses protocol JSON. Named types are declared in order,
+// so a type may reference any type declared before it.
+func parseAvroProtocol(data []byte) (*avroDocument, error) {
+	var proto struct {
+		Protocol  string            `json:"protocol"`
+		Namespace string            `json:"namespace"`
+		Types     []json.RawMessage `json:"types"`
+		Messages  map[string]struct {
+			Request  []map[string]json.RawMessage `json:"request"`
+			Response json.RawMessage              `json:"response"`
+			Errors   []json.RawMessage            `json:"errors"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &proto); err != nil {
+		return nil, err
+	}
+
+	p := &avroParser{named: map[string]*avroNode{}}
+	doc := &avroDocument{Protocol: proto.Protocol, Messages: map[string]*avroMessage{}}
+	types, err := p.parseTypes(proto.Types, proto.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	doc.Types = types
+
+	for name, m := range proto.Messages {
+		req := &avroNode{Kind: "record", Name: name}
+		for _, param := range m.Request {
+			pname := avroString(param["name"])
+			pt, err := p.parse(param["type"], proto.Namespace)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: parameter %s: %w", name, pname, err)
+			}
+			def, hasDef := param["default"]
+			req.Fields = append(req.Fields, &avroNodeField{Name: pname, Type: pt, HasDefault: hasDef, Default: def})
+		}
+
+		resp := &avroNode{Kind: "record", Name: name}
+		if len(m.Response) > 0 {
+			rt, err := p.parse(m.Response, proto.Namespace)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: response: %w", name, err)
+			}
+			resp.Fields = append(resp.Fields, &avroNodeField{Name: "response", Type: rt})
+		}
+		if len(m.Errors) > 0 {
+			errs := &avroNode{Kind: "union"}
+			for _, e := range m.Errors {
+				et, err := p.parse(e, proto.Namespace)
+				if err != nil {
+					return nil, fmt.Errorf("message %s: errors: %w", name, err)
+				}
+				errs.Branches = append(errs.Branches, et)
+			}
+			resp.Fields = append(resp.Fields, &avroNodeField{Name: "errors", Type: errs})
+		}
+		doc.Messages[name] = &avroMessage{Request: req, Response: resp}
+	}
+	return doc, nil
+}
+
+// parseTypes parses protocol types. IDL allows a type to be used before it
+// is declared, so a type that references an unknown name is retried once
+// the types after it are defined; the first error is reported only when no
+// remaining type can be parsed.
+func (p *avroParser) parseTypes(raws []json.RawMessage, ns string) ([]*avroNode, error) {
+	types := make([]*avroNode, len(raws))
+	pending := make([]int, len(raws))
+	for i := range raws {
+		pending[i] = i
+	}
+	for len(pending) > 0 {
+		var retry []int
+		var firstErr error
+		for _, i := range pending {
+			snapshot := make(map[string]*avroNode, len(p.named))
+			for k, v := range p.named {
+				snapshot[k] = v
+			}
+			n, err := p.parse(raws[i], ns)
+			if err != nil {
+				p.named = snapshot
+				retry = append(retry, i)
+				if firstErr == nil {
+					firstErr = fmt.Errorf("types[%d]: %w", i, err)
+				}
+				continue
+			}
+			if n.Name == "" {
+				return nil, fmt.Errorf("types[%d]: protocol types must be records, enums or fixed types", i)
+			}
+			types[i] = n
+		}
+		if len(retry) == len(pending) {
+			return nil, firstErr
+		}
+		pending = retry
+	}
+	return types, nil
+}
+
+// roots returns the types to compare: for a single schema the schema
+// itself; for a protocol the types no other type references (so nested
+// changes are reported once, under their outermost path), plus any type
+// only reachable through a reference cycle.
+func (d *avroDocument) roots() []*avroNode {
+	if d.Schema != nil {
+		return []*avroNode{d.Schema}
+	}
+	referenced := map[*avroNode]bool{}
+	for _, t := range d.Types {
+		avroWalk(t, func(n *avroNode) {
+			if n != t {
+				referenced[n] = true
+			}
+		}, map[*avroNode]bool{})
+	}
+	var roots []*avroNode
+	reached := map[*avroNode]bool{}
+	for _, t := range d.Types {
+		if !referenced[t] {
+			roots = append(roots, t)
+			avroWalk(t, func(*avroNode) {}, reached)
+		}
+	}
+	for _, t := range d.Types {
+		if !reached[t] {
+			roots = append(roots, t)
+			avroWalk(t, func(*avroNode) {}, reached)
+		}
+	}
+	return roots
+}
+
+// avroWalk visits n and every node below it once.
+func avroWalk(n *avroNode, visit func(*avroNode), seen map[*avroNode]bool) {
+	if n == nil || seen[n] {
+		return
+	}
+	seen[n] = true
+	visit(n)
+	for _, f := range n.Fields {
+		avroWalk(f.Type, visit, seen)
+	}
+	avroWalk(n.Items, visit, seen)
+	avroWalk(n.Values, visit, seen)
+	for _, b := range n.Branches {
+		avroWalk(b, visit, seen)
+	}
+}
+
+// lookup finds the type named name, directly or through an alias.
+func (d *avroDocument) lookup(name string) *avroNode {
+	types := d.Types
+	if d.Schema != nil {
+		types = []*avroNode{d.Schema}
+	}
+	for _, t := range types {
+		if t.Name == name {
+			return t
+		}
+	}
+	for _, t := range types {
+		for _, a := range t.Aliases {
+			if a == name {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// checkAvroDocuments applies a non-transitive compatibility mode to two
+// documents. Single schemas are compared directly. Protocol types are
+// matched by full name or alias, and a type that disappears is breaking
+// unless old readers never need to read it (FORWARD, NONE). Messages are
+// checked in the direction data flows: new servers read old requests, and
+// old clients read new responses and errors.
+func checkAvroDocuments(mode string, newDoc, oldDoc *avroDocument) ([]Finding, error) {
+	if newDoc.Schema != nil && oldDoc.Schema != nil {
+		return checkAvroCompatibility(mode, newDoc.Schema, oldDoc.Schema)
+	}
+	switch mode {
+	case "BACKWARD", "FORWARD", "FULL", "NONE":
+	default:
+		return nil, fmt.Errorf("unknown compatibility mode: %s", mode)
+	}
+
+	var findings []Finding
+	for _, old := range oldDoc.roots() {
+		if old.Name == "" {
+			continue
+		}
+		cur := newDoc.lookup(old.Name)
+		if cur == nil {
+			if mode == "BACKWARD" || mode == "FULL" {
+				findings = append(findings, Finding{
+					RuleID:   "avro-type-removed",
+					Severity: SeverityError,
+					Format:   FormatAvro,
+					Path:     avroShortName(old.Name),
+					OldValue: old.Name,
+					Message:  fmt.Sprintf("type %s was removed (data written with it can no longer be read)", old.Name),
+				})
+			}
+			continue
+		}
+		fs, _ := checkAvroCompatibility(mode, cur, old)
+		findings = append(findings, fs...)
+	}
+
+	if mode != "NONE" {
+		names := make([]string, 0, len(oldDoc.Messages))
+		for name := range oldDoc.Messages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			old := oldDoc.Messages[name]
+			cur, ok := newDoc.Messages[name]
+			if !ok {
+				findings = append(findings, Finding{
+					RuleID:   "avro-message-removed",
+					Severity: SeverityError,
+					Format:   FormatAvro,
+					Path:     name,
+					OldValue: name,
+					Message:  fmt.Sprintf("message %q was removed from protocol %s", name, oldDoc.Protocol),
+				})
+				continue
+			}
+			findings = append(findings, resolveAvro(cur.Request, old.Request)...)
+			findings = append(findings, resolveAvro(old.Response, cur.Response)...)
+		}
+	}
+	return dedupeFindings(findings), nil
+}
+
+// lintAvroProtocol lints each named type of a protocol with the same checks
+// as a standalone schema, then checks that every type reference resolves.
+func lintAvroProtocol(data []byte, rules *lintRuleSet, add func(rule, elem, format string, args ...interface{})) {
+	var proto struct {
+		Protocol json.RawMessage   `json:"protocol"`
+		Types    []json.RawMessage `json:"types"`
+	}
+	if err := json.Unmarshal(data, &proto); err != nil {
+		add("avro-invalid-json", "", "invalid Avro protocol: %v", err)
+		return
+	}
+	if err := json.Unmarshal(proto.Protocol, new(string)); err != nil {
+		add("avro-protocol-name", "", "'protocol' must be a string")
+	}
+
+	reported := false
+	counting := func(rule, elem, format string, args ...interface{}) {
+		reported = true
+		add(rule, elem, format, args...)
+	}
+	for _, t := range proto.Types {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(t, &obj); err != nil {
+			counting("avro-invalid-type", "", "protocol types must be schema objects, got %s", t)
+			continue
+		}
+		lintAvroSchemaObject(obj, rules, counting)
+	}
+	if reported {
+		return
+	}
+	if _, err := parseAvroProtocol(data); err != nil {
+		add("avro-protocol-invalid", "", "invalid Avro protocol: %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// avroNode is a parsed Avro schema. Named types (record, error, enum, fixed)
+// are shared by pointer, so references and recursive types resolve to the
+// same node.
+type avroNode struct {
+	Kind    string   // primitive name, or record, enum, array, map, union, fixed
+	Name    string   // full name of a named type
+	Aliases []string // full names of a named type's aliases
+	Logical string   // logicalType annotation, if any
+
+	Fields      []*avroNodeField // record
+	Symbols     []string         // enum
+	EnumDefault string           // enum
+	Items       *avroNode        // array
+	Values      *avroNode        // map
+	Branches    []*avroNode      // union
+	Size        int              // fixed
+}
+
+// avroNodeField is a field of a parsed Avro record.
+type avroNodeField struct {
+	Name       string
+	Aliases    []string
+	Type       *avroNode
+	HasDefault bool
+	Default    json.RawMessage
+}
+
+// avroPrimitives is the set of primitive Avro type names.
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroParser parses Avro schema JSON, tracking named types so that later
+// references (by short or full name) resolve to their definitions.
+type avroParser struct {
+	named map[string]*avroNode
+}
+
+// parseAvroSchema parses an Avro schema document (.avsc) into a node tree.
+func parseAvroSchema(data []byte) (*avroNode, error) {
+	p := &avroParser{named: map[string]*avroNode{}}
+	return p.parse(data, "")
+}
+
+func (p *avroParser) parse(raw json.RawMessage, ns string) (*avroNode, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty schema")
+	}
+	switch raw[0] {
+	case '"':
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return nil, err
+		}
+		return p.ref(name, ns)
+	case '[':
+		var branches []json.RawMessage
+		if err := json.Unmarshal(raw, &branches); err != nil {
+			return nil, err
+		}
+		n := &avroNode{Kind: "union"}
+		for _, b := range branches {
+			bn, err := p.parse(b, ns)
+			if err != nil {
+				return nil, err
+			}
+			n.Branches = append(n.Branches, bn)
+		}
+		return n, nil
+	case '{':
+		return p.parseObject(raw, ns)
+	}
+	return nil, fmt.Errorf("invalid schema: %s", raw)
+}
+
+func (p *avroParser) parseObject(raw json.RawMessage, ns string) (*avroNode, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	typeRaw, ok := obj["type"]
+	if !ok {
+		return nil, fmt.Errorf("schema object missing 'type'")
+	}
+	var kind string
+	if err := json.Unmarshal(typeRaw, &kind); err != nil {
+		// {"type": {...}} or {"type": [...]}: the type is itself a schema.
+		return p.parse(typeRaw, ns)
+	}
+	logical := avroString(obj["logicalType"])
+
+	switch kind {
+	case "record", "error":
+		name, recNS := avroFullName(obj, ns)
+		n := &avroNode{Kind: "record", Name: name, Aliases: avroAliases(obj, recNS), Logical: logical}
+		if err := p.define(n); err != nil {
+			return nil, err
+		}
+		var fields []map[string]json.RawMessage
+		if err := json.Unmarshal(obj["fields"], &fields); err != nil {
+			return nil, fmt.Errorf("record %s: 'fields' must be an array: %w", name, err)
+		}
+		for _, f := range fields {
+			fname := avroString(f["name"])
+			if fname == "" {
+				return nil, fmt.Errorf("record %s: field missing 'name'", name)
+			}
+			ft, err := p.parse(f["type"], recNS)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, fname, err)
+			}
+			def, hasDef := f["default"]
+			var aliases []string
+			_ = json.Unmarshal(f["aliases"], &aliases)
+			n.Fields = append(n.Fields, &avroNodeField{
+				Name:       fname,
+				Aliases:    aliases,
+				Type:       ft,
+				HasDefault: hasDef,
+				Default:    def,
+			})
+		}
+		return n, nil
+	case "enum":
+		name, enumNS := avroFullName(obj, ns)
+		n := &avroNode{Kind: "enum", Name: name, Aliases: avroAliases(obj, enumNS), Logical: logical}
+		if err := json.Unmarshal(obj["symbols"], &n.Symbols); err != nil {
+			return nil, fmt.Errorf("enum %s: 'symbols' must be an array of strings: %w", name, err)
+		}
+		n.EnumDefault = avroString(obj["default"])
+		return n, p.define(n)
+	case "fixed":
+		name, fixedNS := avroFullName(obj, ns)
+		n := &avroNode{Kind: "fixed", Name: name, Aliases: avroAliases(obj, fixedNS), Logical: logical}
+		if err := json.Unmarshal(obj["size"], &n.Size); err != nil {
+			return nil, fmt.Errorf("fixed %s: 'size' must be an integer: %w", name, err)
+		}
+		return n, p.define(n)
+	case "array":
+		items, err := p.parse(obj["items"], ns)
+		if err != nil {
+			return nil, fmt.Errorf("array items: %w", err)
+		}
+		return &avroNode{Kind: "array", Items: items, Logical: logical}, nil
+	case "map":
+		values, err := p.parse(obj["values"], ns)
+		if err != nil {
+			return nil, fmt.Errorf("map values: %w", err)
+		}
+		return &avroNode{Kind: "map", Values: values, Logical: logical}, nil
+	}
+	if avroPrimitives[kind] {
+		return &avroNode{Kind: kind, Logical: logical}, nil
+	}
+	// {"type": "com.example.Named"} is a reference to a named type.
+	return p.ref(kind, ns)
+}
+
+// define registers a named type. Redefining a name is an error.
+func (p *avroParser) define(n *avroNode) error {
+	if _, exists := p.named[n.Name]; exists {
+		return fmt.Errorf("type %s is defined more than once", n.Name)
+	}
+	p.named[n.Name] = n
+	return nil
+}
+
+// ref resolves a type name: a primitive, or a named type looked up first in
+// the enclosing namespace and then as a full name.
+func (p *avroParser) ref(name, ns string) (*avroNode, error) {
+	if avroPrimitives[name] {
+		return &avroNode{Kind: name}, nil
+	}
+	if !strings.Contains(name, ".") && ns != "" {
+		if n, ok := p.named[ns+"."+name]; ok {
+			return n, nil
+		}
+	}
+	if n, ok := p.named[name]; ok {
+		return n, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+// avroString decodes a JSON string, returning "" for anything else.
+func avroString(raw json.RawMessage) string {
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s
+}
+
+// avroFullName computes the full name and namespace of a named type per the
+// Avro spec: a dotted name is already full; otherwise the type's own
+// namespace attribute, or the enclosing namespace, qualifies it.
+func avroFullName(obj map[string]json.RawMessage, enclosing string) (string, string) {
+	name := avroString(obj["name"])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name, name[:i]
+	}
+	ns := enclosing
+	if raw, ok := obj["namespace"]; ok {
+		ns = avroString(raw)
+	}
+	if ns == "" {
+		return name, ""
+	}
+	return ns + "." + name, ns
+}
+
+// avroAliases returns the full names of a named type's aliases.
+func avroAliases(obj map[string]json.RawMessage, ns string) []string {
+	var aliases []string
+	if err := json.Unmarshal(obj["aliases"], &aliases); err != nil {
+		return nil
+	}
+	for i, a := range aliases {
+		if !strings.Contains(a, ".") && ns != "" {
+			aliases[i] = ns + "." + a
+		}
+	}
+	return aliases
+}
+
+// avroShortName strips the namespace from a full name.
+func avroShortName(full string) string {
+	if i := strings.LastIndex(full, "."); i >= 0 {
+		return full[i+1:]
+	}
+	return full
+}
+
+// label renders a node for messages and Finding values, e.g. "long",
+// "array<Item>", "[null, string]".
+func (n *avroNode) label() string {
+	switch n.Kind {
+	case "record", "enum", "fixed":
+		return avroShortName(n.Name)
+	case "array":
+		return "array<" + n.Items.label() + ">"
+	case "map":
+		return "map<" + n.Values.label() + ">"
+	case "union":
+		parts := make([]string, len(n.Branches))
+		for i, b := range n.Branches {
+			parts[i] = b.label()
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	if n.Logical != "" {
+		return n.Kind + "(" + n.Logical + ")"
+	}
+	return n.Kind
+}
+
+// avroPromotions lists the writer→reader primitive promotions allowed by the
+// Avro spec's schema resolution rules.
+var avroPromotions = map[string]map[string]bool{
+	"int":    {"long": true, "float": true, "double": true},
+	"long":   {"float": true, "double": true},
+	"float":  {"double": true},
+	"string": {"bytes": true},
+	"bytes":  {"string": true},
+}
+
+// avroMatches reports whether a (non-union) writer schema can be resolved by
+// a (non-union) reader schema at the top level, following the spec's
+// "schemas match" rules. Nested content is checked by the resolver.
+func avroMatches(reader, writer *avroNode) bool {
+	if reader.Kind == writer.Kind {
+		switch reader.Kind {
+		case "record", "enum", "fixed":
+			return avroNamesMatch(reader, writer)
+		}
+		return true
+	}
+	return avroPromotions[writer.Kind][reader.Kind]
+}
+
+// avroNamesMatch compares named types by unqualified name, also accepting a
+// reader alias that names the writer type.
+func avroNamesMatch(reader, writer *avroNode) bool {
+	if avroShortName(reader.Name) == avroShortName(writer.Name) {
+		return true
+	}
+	for _, a := range reader.Aliases {
+		if a == writer.Name || avroShortName(a) == avroShortName(writer.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// avroResolver applies the Avro spec's schema resolution rules to a reader
+// and writer schema pair and records every incompatibility as a Finding.
+type avroResolver struct {
+	findings []Finding
+	seen     map[[2]*avroNode]bool // record pairs already resolved (recursion guard)
+}
+
+// resolveAvro checks that data written with writer can be read with reader.
+func resolveAvro(reader, writer *avroNode) []Finding {
+	r := &avroResolver{seen: map[[2]*avroNode]bool{}}
+	path := ""
+	if reader.Name != "" {
+		path = avroShortName(reader.Name)
+	}
+	r.resolve(reader, writer, path)
+	return r.findings
+}
+
+func (r *avroResolver) add(sev Severity, rule, path, oldValue, newValue, format string, args ...interface{}) {
+	r.findings = append(r.findings, Finding{
+		RuleID:   rule,
+		Severity: sev,
+		Format:   FormatAvro,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		OldValue: oldValue,
+		NewValue: newValue,
+	})
+}
+
+func (r *avroResolver) resolve(reader, writer *avroNode, path string) {
+	// A writer union is resolved branch by branch: any branch may have been
+	// written, so every branch must be readable.
+	if writer.Kind == "union" {
+		for _, wb := range writer.Branches {
+			if reader.Kind == "union" {
+				r.resolveIntoUnion(reader, wb, path)
+				continue
+			}
+			if !avroMatches(reader, wb) {
+				r.add(SeverityError, "avro-union-branch-unmatched", path, writer.label(), reader.label(),
+					"%s: union branch %s written by %s cannot be read as %s",
+					avroPathLabel(path), wb.label(), writer.label(), reader.label())
+				continue
+			}
+			r.resolve(reader, wb, path)
+		}
+		return
+	}
+	if reader.Kind == "union" {
+		r.resolveIntoUnion(reader, writer, path)
+		return
+	}
+
+	if !avroMatches(reader, writer) {
+		rule := "avro-field-type-changed"
+		if reader.Kind == writer.Kind {
+			rule = "avro-named-type-mismatch"
+		}
+		r.add(SeverityError, rule, path, writer.label(), reader.label(),
+			"%s type changed from %s to %s (type changes are not backward compatible)",
+			avroPathLabel(path), writer.label(), reader.label())
+		return
+	}
+	if reader.Logical != writer.Logical {
+		r.add(SeverityWarning, "avro-logical-type-changed", path, writer.Logical, reader.Logical,
+			"%s: logical type changed from %q to %q (data is readable, but its interpretation changes)",
+			avroPathLabel(path), writer.Logical, reader.Logical)
+	}
+
+	switch reader.Kind {
+	case "record":
+		key := [2]*avroNode{reader, writer}
+		if r.seen[key] {
+			return
+		}
+		r.seen[key] = true
+		r.resolveRecord(reader, writer, path)
+	case "enum":
+		symbols := make(map[string]bool, len(reader.Symbols))
+		for _, s := range reader.Symbols {
+			symbols[s] = true
+		}
+		if reader.EnumDefault != "" {
+			return
+		}
+		for _, s := range writer.Symbols {
+			if !symbols[s] {
+				r.add(SeverityError, "avro-enum-symbol-removed", path, s, "",
+					"%s: enum symbol %q written by the writer schema is missing from %s, which has no default",
+					avroPathLabel(path), s, reader.label())
+			}
+		}
+	case "fixed":
+		if reader.Size != writer.Size {
+			r.add(SeverityError, "avro-fixed-size-changed", path,
+				fmt.Sprint(writer.Size), fmt.Sprint(reader.Size),
+				"%s: fixed %s size changed from %d to %d",
+				avroPathLabel(path), reader.label(), writer.Size, reader.Size)
+		}
+	case "array":
+		r.resolve(reader.Items, writer.Items, path+"[]")
+	case "map":
+		r.resolve(reader.Values, writer.Values, path+"{}")
+	}
+}
+
+// resolveIntoUnion resolves a non-union writer against the first matching
+// branch of a reader union.
+func (r *avroResolver) resolveIntoUnion(reader, writer *avroNode, path string) {
+	for _, rb := range reader.Branches {
+		if rb.Kind != "union" && avroMatches(rb, writer) {
+			r.resolve(rb, writer, path)
+			return
+		}
+	}
+	r.add(SeverityError, "avro-union-branch-unmatched", path, writer.label(), reader.label(),
+		"%s type changed from %s to %s (no union branch can read the written type)",
+		avroPathLabel(path), writer.label(), reader.label())
+}
+
+// resolveRecord matches reader fields to writer fields by name or reader
+// alias. Writer fields the reader lacks are ignored; reader fields the
+// writer lacks must carry a default.
+func (r *avroResolver) resolveRecord(reader, writer *avroNode, path string) {
+	writerFields := make(map[string]*avroNodeField, len(writer.Fields))
+	for _, f := range writer.Fields {
+		writerFields[f.Name] = f
+	}
+	for _, rf := range reader.Fields {
+		fpath := avroElemPath(path, rf.Name)
+		wf := writerFields[rf.Name]
+		for _, a := range rf.Aliases {
+			if wf != nil {
+				break
+			}
+			wf = writerFields[a]
+		}
+		if wf == nil {
+			if !rf.HasDefault {
+				r.add(SeverityError, "avro-field-added-without-default", fpath, "", rf.Type.label(),
+					"field %q added to new schema without a default value "+
+						"(cannot read old data that is missing this field)", fpath)
+			}
+			continue
+		}
+		r.resolve(rf.Type, wf.Type, fpath)
+	}
+}
+
+// avroPathLabel renders an element path for messages: the root schema by
+// name, anything below it as a field path.
+func avroPathLabel(path string) string {
+	if path == "" {
+		return "schema"
+	}
+	if !strings.ContainsAny(path, ".[{") {
+		return fmt.Sprintf("schema %q", path)
+	}
+	return fmt.Sprintf("field %q", path)
+}
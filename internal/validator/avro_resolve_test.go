@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"testing"
+)
+
+func TestResolveAvro(t *testing.T) {
+	tests := []struct {
+		name     string
+		writer   string
+		reader   string
+		wantRule string // "" means compatible (no error findings)
+		wantPath string
+	}{
+		{
+			name:     "nested array record field type change",
+			writer:   `{"type":"record","name":"Order","fields":[{"name":"items","type":{"type":"array","items":{"type":"record","name":"Item","fields":[{"name":"price","type":"double"}]}}}]}`,
+			reader:   `{"type":"record","name":"Order","fields":[{"name":"items","type":{"type":"array","items":{"type":"record","name":"Item","fields":[{"name":"price","type":"string"}]}}}]}`,
+			wantRule: "avro-field-type-changed",
+			wantPath: "Order.items[].price",
+		},
+		{
+			name:     "nested field added without default",
+			writer:   `{"type":"record","name":"Order","fields":[{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"}]}}]}`,
+			reader:   `{"type":"record","name":"Order","fields":[{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"id","type":"string"},{"name":"tier","type":"int"}]}}]}`,
+			wantRule: "avro-field-added-without-default",
+			wantPath: "Order.customer.tier",
+		},
+		{
+			name:   "int promoted to long",
+			writer: `{"type":"record","name":"R","fields":[{"name":"n","type":"int"}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"n","type":"long"}]}`,
+		},
+		{
+			name:     "long narrowed to int",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"n","type":"long"}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"n","type":"int"}]}`,
+			wantRule: "avro-field-type-changed",
+			wantPath: "R.n",
+		},
+		{
+			name:   "string read as bytes",
+			writer: `{"type":"record","name":"R","fields":[{"name":"b","type":"string"}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"b","type":"bytes"}]}`,
+		},
+		{
+			name:   "map values promoted",
+			writer: `{"type":"record","name":"R","fields":[{"name":"m","type":{"type":"map","values":"float"}}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"m","type":{"type":"map","values":"double"}}]}`,
+		},
+		{
+			name:     "enum symbol removed without default",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"s","type":{"type":"enum","name":"Status","symbols":["A","B","C"]}}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"s","type":{"type":"enum","name":"Status","symbols":["A","B"]}}]}`,
+			wantRule: "avro-enum-symbol-removed",
+			wantPath: "R.s",
+		},
+		{
+			name:   "enum symbol removed with reader default",
+			writer: `{"type":"record","name":"R","fields":[{"name":"s","type":{"type":"enum","name":"Status","symbols":["A","B","C"]}}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"s","type":{"type":"enum","name":"Status","symbols":["A","B"],"default":"A"}}]}`,
+		},
+		{
+			name:   "plain type widened to nullable union",
+			writer: `{"type":"record","name":"R","fields":[{"name":"e","type":"string"}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"e","type":["null","string"]}]}`,
+		},
+		{
+			name:     "nullable union narrowed to plain type",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"e","type":["null","string"]}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"e","type":"string"}]}`,
+			wantRule: "avro-union-branch-unmatched",
+			wantPath: "R.e",
+		},
+		{
+			name:     "union branch dropped",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"v","type":["null","int","string"]}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"v","type":["null","long"]}]}`,
+			wantRule: "avro-union-branch-unmatched",
+			wantPath: "R.v",
+		},
+		{
+			name:   "field renamed with alias",
+			writer: `{"type":"record","name":"R","fields":[{"name":"old","type":"string"}]}`,
+			reader: `{"type":"record","name":"R","fields":[{"name":"renamed","aliases":["old"],"type":"string"}]}`,
+		},
+		{
+			name:   "record renamed with alias",
+			writer: `{"type":"record","name":"Old","namespace":"ns","fields":[{"name":"a","type":"int"}]}`,
+			reader: `{"type":"record","name":"New","namespace":"ns","aliases":["Old"],"fields":[{"name":"a","type":"int"}]}`,
+		},
+		{
+			name:     "record renamed without alias",
+			writer:   `{"type":"record","name":"Old","fields":[{"name":"a","type":"int"}]}`,
+			reader:   `{"type":"record","name":"New","fields":[{"name":"a","type":"int"}]}`,
+			wantRule: "avro-named-type-mismatch",
+			wantPath: "New",
+		},
+		{
+			name:     "fixed size changed",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"h","type":{"type":"fixed","name":"Hash","size":16}}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"h","type":{"type":"fixed","name":"Hash","size":32}}]}`,
+			wantRule: "avro-fixed-size-changed",
+			wantPath: "R.h",
+		},
+		{
+			name:   "recursive named type reference",
+			writer: `{"type":"record","name":"Node","namespace":"x","fields":[{"name":"v","type":"int"},{"name":"next","type":["null","Node"]}]}`,
+			reader: `{"type":"record","name":"Node","namespace":"x","fields":[{"name":"v","type":"long"},{"name":"next","type":["null","x.Node"]}]}`,
+		},
+		{
+			name:     "named type reused by reference",
+			writer:   `{"type":"record","name":"R","fields":[{"name":"a","type":{"type":"record","name":"P","fields":[{"name":"x","type":"int"}]}},{"name":"b","type":"P"}]}`,
+			reader:   `{"type":"record","name":"R","fields":[{"name":"a","type":{"type":"record","name":"P","fields":[{"name":"x","type":"int"},{"name":"y","type":"int"}]}},{"name":"b","type":"P"}]}`,
+			wantRule: "avro-field-added-without-default",
+			wantPath: "R.a.y",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := parseAvroSchema([]byte(tt.writer))
+			if err != nil {
+				t.Fatalf("parse writer: %v", err)
+			}
+			reader, err := parseAvroSchema([]byte(tt.reader))
+			if err != nil {
+				t.Fatalf("parse reader: %v", err)
+			}
+			findings := resolveAvro(reader, writer)
+			errs := FilterSeverity(findings, SeverityError)
+			if tt.wantRule == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected compatible, got %+v", errs)
+				}
+				return
+			}
+			for _, f := range errs {
+				if f.RuleID == tt.wantRule && f.Path == tt.wantPath {
+					return
+				}
+			}
+			t.Fatalf("expected %s at %s, got %+v", tt.wantRule, tt.wantPath, findings)
+		})
+	}
+}
+
+func TestResolveAvro_LogicalTypeChangeWarns(t *testing.T) {
+	writer, _ := parseAvroSchema([]byte(`{"type":"record","name":"R","fields":[{"name":"ts","type":{"type":"long","logicalType":"timestamp-millis"}}]}`))
+	reader, _ := parseAvroSchema([]byte(`{"type":"record","name":"R","fields":[{"name":"ts","type":{"type":"long","logicalType":"timestamp-micros"}}]}`))
+	findings := resolveAvro(reader, writer)
+	if len(findings) != 1 || findings[0].Severity != SeverityWarning || findings[0].RuleID != "avro-logical-type-changed" {
+		t.Fatalf("expected a single logical-type warning, got %+v", findings)
+	}
+}
+
+func TestParseAvroSchema_Errors(t *testing.T) {
+	for _, schema := range []string{
+		`{"type":"record","name":"R","fields":[{"name":"a","type":"Missing"}]}`,
+		`{"type":"record","name":"R","fields":[{"name":"a","type":{"type":"record","name":"R","fields":[]}}]}`,
+		`{"type":"fixed","name":"F"}`,
+	} {
+		if _, err := parseAvroSchema([]byte(schema)); err == nil {
+			t.Errorf("expected parse error for %s", schema)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -212,3 +214,131 @@ func TestAvroValidator_SetCompatibilityMode(t *testing.T) {
 		}
 	}
 }
+
+func TestAvroValidator_BreakingFindings(t *testing.T) {
+	v := NewAvroValidator(&ToolchainResolver{})
+	v.SetCompatibilityMode("BACKWARD")
+
+	findings, err := v.BreakingFindings("testdata/avro/v2_multi_violation.avsc", "testdata/avro/v1.avsc")
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	rules := map[string]Finding{}
+	for _, f := range findings {
+		rules[f.RuleID] = f
+	}
+	typeChanged, ok := rules["avro-field-type-changed"]
+	if !ok {
+		t.Fatalf("expected avro-field-type-changed finding, got %+v", findings)
+	}
+	if typeChanged.OldValue != "string" || typeChanged.NewValue != "long" {
+		t.Errorf("expected string→long values, got %q→%q", typeChanged.OldValue, typeChanged.NewValue)
+	}
+	if _, ok := rules["avro-field-added-without-default"]; !ok {
+		t.Errorf("expected avro-field-added-without-default finding, got %+v", findings)
+	}
+
+	if _, err := v.BreakingFindings("testdata/avro/missing.avsc", "testdata/avro/v1.avsc"); err == nil {
+		t.Error("expected error for unreadable schema")
+	}
+}
+
+// fakeHistory is an in-memory SchemaHistory keyed by version.
+type fakeHistory struct {
+	versions []string
+	schemas  map[string]string
+}
+
+func (h fakeHistory) Versions() ([]string, error) { return h.versions, nil }
+
+func (h fakeHistory) Load(version string) ([]byte, error) {
+	return []byte(h.schemas[version]), nil
+}
+
+func TestAvroValidator_Breaking_Transitive(t *testing.T) {
+	// v1.0.0 had "legacy" as an int; v1.1.0 (the baseline) dropped it; the
+	// new schema re-adds it as a string. Compatible with the baseline, but
+	// not with data written by v1.0.0.
+	history := fakeHistory{
+		versions: []string{"v1.0.0", "v1.1.0"},
+		schemas: map[string]string{
+			"v1.0.0": `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"legacy","type":"int"}]}`,
+			"v1.1.0": `{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}`,
+		},
+	}
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "new.avsc")
+	oldPath := filepath.Join(dir, "old.avsc")
+	mustWrite(t, newPath, `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"legacy","type":"string","default":""}]}`)
+	mustWrite(t, oldPath, history.schemas["v1.1.0"])
+
+	v := NewAvroValidator(&ToolchainResolver{})
+	v.SetCompatibilityMode("BACKWARD")
+	if err := v.Breaking(newPath, oldPath); err != nil {
+		t.Fatalf("BACKWARD should only check the baseline, got: %v", err)
+	}
+
+	v.SetCompatibilityMode("BACKWARD_TRANSITIVE")
+	if _, err := v.BreakingFindings(newPath, oldPath); err == nil || !strings.Contains(err.Error(), "release history") {
+		t.Fatalf("expected missing-history error, got: %v", err)
+	}
+
+	v.SetHistory(history)
+	findings, err := v.BreakingFindings(newPath, oldPath)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Path != "User.legacy" || !strings.Contains(findings[0].Message, "released v1.0.0") {
+		t.Fatalf("expected one conflict with v1.0.0, got %+v", findings)
+	}
+
+	// FORWARD_TRANSITIVE: every released reader must read the new data.
+	// v1.0.0 requires "legacy" as an int, which the new schema writes as a string.
+	v.SetCompatibilityMode("FORWARD_TRANSITIVE")
+	findings, err = v.BreakingFindings(newPath, oldPath)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if !HasErrors(findings) {
+		t.Fatal("expected FORWARD_TRANSITIVE violation against v1.0.0")
+	}
+
+	v.SetCompatibilityMode("NONE_TRANSITIVE")
+	if _, err := v.BreakingFindings(newPath, oldPath); err == nil || !strings.Contains(err.Error(), "unknown compatibility mode") {
+		t.Errorf("expected unknown mode error for NONE_TRANSITIVE, got: %v", err)
+	}
+}
+
+func TestAvroValidator_Breaking_TransitiveBaselineIsRelease(t *testing.T) {
+	// The baseline is the latest release; its conflict must be reported
+	// once, not again as a conflict with the released copy.
+	history := fakeHistory{
+		versions: []string{"v1.0.0"},
+		schemas: map[string]string{
+			"v1.0.0": `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"legacy","type":"int"}]}`,
+		},
+	}
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "new.avsc")
+	oldPath := filepath.Join(dir, "old.avsc")
+	mustWrite(t, newPath, `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"legacy","type":"string"}]}`)
+	mustWrite(t, oldPath, "{\n  \"type\": \"record\", \"name\": \"User\",\n  \"fields\": [{\"name\": \"id\", \"type\": \"string\"}, {\"name\": \"legacy\", \"type\": \"int\"}]\n}\n")
+
+	v := NewAvroValidator(&ToolchainResolver{})
+	v.SetCompatibilityMode("BACKWARD_TRANSITIVE")
+	v.SetHistory(history)
+	findings, err := v.BreakingFindings(newPath, oldPath)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Path != "User.legacy" || strings.Contains(findings[0].Message, "released") {
+		t.Fatalf("expected the baseline conflict once, got %+v", findings)
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
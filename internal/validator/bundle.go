@@ -0,0 +1,431 @@
+package validator
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bundleManifestName is the manifest at the root of an offline toolchain
+// bundle.
+const bundleManifestName = "apx-bundle.yaml"
+
+// PlatformAny is the bundle platform of tools that run anywhere, such as
+// npm packages.
+const PlatformAny = "any"
+
+// BundleManifest describes the tools in an offline toolchain bundle.
+type BundleManifest struct {
+	Version int          `yaml:"version"`
+	Tools   []BundleTool `yaml:"tools"`
+}
+
+// BundleTool is one tool build in a bundle. Path is its file in the bundle:
+// the binary, or for platform any a .tar.gz of its install tree. SHA256 is
+// the checksum of that file.
+type BundleTool struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	Platform string `yaml:"platform"`
+	Path     string `yaml:"path"`
+	SHA256   string `yaml:"sha256"`
+}
+
+// Find returns the bundled build of a tool version for a platform, or the
+// build that runs on any platform.
+func (m *BundleManifest) Find(name, version, platform string) (BundleTool, bool) {
+	for _, t := range m.Tools {
+		if t.Name == name && t.Version == version && (t.Platform == platform || t.Platform == PlatformAny) {
+			return t, true
+		}
+	}
+	return BundleTool{}, false
+}
+
+// HostPlatform returns the os/arch platform APX runs on.
+func HostPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// ParsePlatform splits an os/arch platform such as linux/amd64.
+func ParsePlatform(platform string) (goos, goarch string, err error) {
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return "", "", fmt.Errorf("invalid platform %q (expected os/arch, such as linux/amd64)", platform)
+	}
+	return goos, goarch, nil
+}
+
+// BuildBundle writes an offline bundle of the resolver's tools for each
+// platform to out, a .tar.gz. Tools for the host come from FetchTool, so
+// they are verified against apx.lock; tools for other platforms are
+// downloaded or built for them. npm tools are bundled once, for any
+// platform. A tool that cannot be had for a platform is returned in skipped
+// rather than failing the bundle.
+func (r *ToolchainResolver) BuildBundle(out string, platforms []string) (manifest *BundleManifest, skipped []error, err error) {
+	for _, p := range platforms {
+		if _, _, err := ParsePlatform(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	work, err := os.MkdirTemp("", "apx-bundle-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(work)
+
+	manifest = &BundleManifest{Version: 1}
+	files := map[string]string{} // bundle path → local file
+	for _, name := range r.Tools() {
+		version := r.ToolVersion(name)
+		targets := platforms
+		if toolRegistry[name].npmPackage != "" {
+			targets = []string{PlatformAny}
+		}
+		for _, platform := range targets {
+			local, err := r.bundleBuild(name, version, platform, work)
+			if err != nil {
+				if platform == HostPlatform() && !errors.Is(err, ErrToolNotFound) {
+					return nil, nil, err
+				}
+				skipped = append(skipped, fmt.Errorf("%s %s for %s: %w", name, version, platform, err))
+				continue
+			}
+			sum, err := ComputeChecksum(local)
+			if err != nil {
+				return nil, nil, err
+			}
+			entry := path.Join(strings.ReplaceAll(platform, "/", "-"), filepath.Base(local))
+			files[entry] = local
+			manifest.Tools = append(manifest.Tools, BundleTool{
+				Name:     name,
+				Version:  version,
+				Platform: platform,
+				Path:     entry,
+				SHA256:   sum,
+			})
+		}
+	}
+
+	if err := writeBundle(out, manifest, files); err != nil {
+		return nil, nil, err
+	}
+	return manifest, skipped, nil
+}
+
+// bundleBuild returns the local file to bundle for a tool on a platform.
+func (r *ToolchainResolver) bundleBuild(name, version, platform, work string) (string, error) {
+	if platform == PlatformAny {
+		bin, err := r.FetchTool(name, version)
+		if err != nil {
+			return "", err
+		}
+		// The binary is <dir>/node_modules/.bin/<name>; the tree is <dir>.
+		tree := filepath.Dir(filepath.Dir(filepath.Dir(bin)))
+		archive := filepath.Join(work, PlatformAny, name+".tar.gz")
+		return archive, packTree(tree, archive)
+	}
+	if platform == HostPlatform() {
+		return r.FetchTool(name, version)
+	}
+	if r.offlineMode {
+		return "", fmt.Errorf("%w: %s (version %s)", ErrToolNotFound, name, version)
+	}
+	goos, goarch, _ := ParsePlatform(platform)
+	return installTool(name, version, goos, goarch, filepath.Join(work, goos+"-"+goarch, name))
+}
+
+// writeBundle writes the manifest and files of a bundle to a .tar.gz.
+func writeBundle(out string, manifest *BundleManifest, files map[string]string) error {
+	sort.Slice(manifest.Tools, func(i, j int) bool {
+		a, b := manifest.Tools[i], manifest.Tools[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Platform < b.Platform
+	})
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("encoding bundle manifest: %w", err)
+	}
+
+	if dir := filepath.Dir(out); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating bundle directory: %w", err)
+		}
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := tw.WriteHeader(&tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	for _, t := range manifest.Tools {
+		if err := addTarFile(tw, t.Path, files[t.Path], 0755); err != nil {
+			return fmt.Errorf("bundling %s: %w", t.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// addTarFile writes the regular file local to tw as name.
+func addTarFile(tw *tar.Writer, name, local string, mode int64) error {
+	in, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: info.Size()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, in)
+	return err
+}
+
+// packTree writes the directory tree at dir, keeping its symlinks, to a
+// .tar.gz at archive.
+func packTree(dir, archive string) error {
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return tw.WriteHeader(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0755})
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeSymlink, Linkname: target, Mode: 0777})
+		case info.Mode().IsRegular():
+			return addTarFile(tw, name, p, int64(info.Mode().Perm()))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("packing %s: %w", dir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// unpackTree extracts a .tar.gz written by packTree into dir.
+func unpackTree(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("opening gzip: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading tar: %w", err)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes the install directory", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				err = os.Symlink(hdr.Linkname, target)
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				err = writeFileFrom(tr, target, fs.FileMode(hdr.Mode).Perm())
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("extracting %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+// writeFileFrom writes a reader to a new file with the given permissions.
+func writeFileFrom(r io.Reader, path string, perm fs.FileMode) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ReadBundleManifest reads the manifest of an offline toolchain bundle.
+func ReadBundleManifest(bundle string) (*BundleManifest, error) {
+	var manifest BundleManifest
+	err := readBundleEntry(bundle, bundleManifestName, func(r io.Reader) error {
+		return yaml.NewDecoder(r).Decode(&manifest)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading toolchain bundle %s: %w", bundle, err)
+	}
+	return &manifest, nil
+}
+
+// readBundleEntry calls fn with the contents of a file in a bundle.
+func readBundleEntry(bundle, name string, fn func(io.Reader) error) error {
+	f, err := os.Open(bundle)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("opening gzip: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("%s not found in bundle", name)
+		}
+		if err != nil {
+			return fmt.Errorf("reading tar: %w", err)
+		}
+		if hdr.Name == name {
+			return fn(tr)
+		}
+	}
+}
+
+// bundledTool returns a tool from the offline bundle, or "" when the bundle
+// has no build of that version for the host. The tool is extracted into the
+// local cache, and must match the checksum in the bundle's manifest.
+func (r *ToolchainResolver) bundledTool(name, version string) (string, error) {
+	if r.bundle == nil {
+		manifest, err := ReadBundleManifest(r.bundlePath)
+		if err != nil {
+			return "", err
+		}
+		r.bundle = manifest
+	}
+	entry, ok := r.bundle.Find(name, version, HostPlatform())
+	if !ok {
+		return "", nil
+	}
+
+	dir := cacheDir(name, version)
+	bin := binaryPath(name, dir, runtime.GOOS)
+	mismatch := fmt.Errorf("checksum mismatch for %s %s in toolchain bundle %s", name, version, r.bundlePath)
+
+	if entry.Platform == PlatformAny {
+		if _, err := os.Stat(bin); err == nil {
+			return bin, nil
+		}
+		err := readBundleEntry(r.bundlePath, entry.Path, func(in io.Reader) error {
+			archive := filepath.Join(os.TempDir(), fmt.Sprintf("apx-%s-%d.tar.gz", name, os.Getpid()))
+			defer os.Remove(archive)
+			if err := writeFileFrom(in, archive, 0644); err != nil {
+				return err
+			}
+			if sum, err := ComputeChecksum(archive); err != nil {
+				return err
+			} else if !strings.EqualFold(sum, entry.SHA256) {
+				return mismatch
+			}
+			f, err := os.Open(archive)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_ = os.RemoveAll(dir)
+			return unpackTree(f, dir)
+		})
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("extracting %s from toolchain bundle: %w", name, err)
+		}
+		return bin, nil
+	}
+
+	if sum, err := ComputeChecksum(bin); err == nil && strings.EqualFold(sum, entry.SHA256) {
+		return bin, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+	err := readBundleEntry(r.bundlePath, entry.Path, func(in io.Reader) error {
+		return writeFileFrom(in, bin, 0755)
+	})
+	if err == nil {
+		var sum string
+		if sum, err = ComputeChecksum(bin); err == nil && !strings.EqualFold(sum, entry.SHA256) {
+			err = mismatch
+		}
+	}
+	if err != nil {
+		_ = os.Remove(bin)
+		return "", fmt.Errorf("extracting %s from toolchain bundle: %w", name, err)
+	}
+	return bin, nil
+}
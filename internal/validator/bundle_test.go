@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheNpmTool writes a fake npm install tree, with its .bin symlink, into
+// the local tool cache under home.
+func cacheNpmTool(t *testing.T, home, name, version, content string) {
+	t.Helper()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	pkg := filepath.Join(cacheDir(name, version), "node_modules", name)
+	require.NoError(t, os.MkdirAll(filepath.Join(pkg, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "bin", "main.js"), []byte(content), 0755))
+	bin := filepath.Join(cacheDir(name, version), "node_modules", ".bin")
+	require.NoError(t, os.MkdirAll(bin, 0755))
+	require.NoError(t, os.Symlink(filepath.Join("..", name, "bin", "main.js"), filepath.Join(bin, name)))
+}
+
+func TestParsePlatform(t *testing.T) {
+	goos, goarch, err := ParsePlatform("linux/arm64")
+	require.NoError(t, err)
+	require.Equal(t, "linux", goos)
+	require.Equal(t, "arm64", goarch)
+	for _, bad := range []string{"linux", "linux/", "/amd64", "linux/arm/v7"} {
+		_, _, err := ParsePlatform(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("npm trees are symlinked")
+	}
+	home := t.TempDir()
+	cacheTool(t, home, "buf", "v1.66.1", "#!/bin/sh\necho buf\n")
+	cacheNpmTool(t, home, "ng-openapi-gen", "1.0.5", "#!/usr/bin/env node\n")
+	out := filepath.Join(t.TempDir(), "tools.tar.gz")
+
+	r := NewToolchainResolver(WithOfflineMode(true))
+	manifest, skipped, err := r.BuildBundle(out, []string{HostPlatform(), "windows/arm64"})
+	require.NoError(t, err)
+
+	bundled := map[string]string{}
+	for _, tool := range manifest.Tools {
+		require.Len(t, tool.SHA256, 64)
+		bundled[tool.Name+" "+tool.Platform] = tool.Path
+	}
+	require.Equal(t, map[string]string{
+		"buf " + HostPlatform(): runtime.GOOS + "-" + runtime.GOARCH + "/" + filepath.Base(binaryPath("buf", "", runtime.GOOS)),
+		"ng-openapi-gen any":    "any/ng-openapi-gen.tar.gz",
+	}, bundled)
+	// buf for windows, and the uncached tools for both platforms.
+	require.Len(t, skipped, 9)
+	require.ErrorIs(t, skipped[0], ErrToolNotFound)
+
+	read, err := ReadBundleManifest(out)
+	require.NoError(t, err)
+	require.Equal(t, manifest, read)
+
+	// An empty cache is filled from the bundle.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	r = NewToolchainResolver(WithOfflineMode(true), WithBundlePath(out))
+	path, err := r.ResolveTool("buf", "v1.66.1")
+	require.NoError(t, err)
+	require.Equal(t, binaryPath("buf", cacheDir("buf", "v1.66.1"), runtime.GOOS), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho buf\n", string(data))
+
+	path, err = r.ResolveTool("ng-openapi-gen", "1.0.5")
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err, "the .bin symlink resolves into the extracted tree")
+	require.Equal(t, "#!/usr/bin/env node\n", string(data))
+
+	// A cached binary that differs from the bundle is replaced.
+	require.NoError(t, os.WriteFile(binaryPath("buf", cacheDir("buf", "v1.66.1"), runtime.GOOS), []byte("tampered"), 0755))
+	path, err = r.ResolveTool("buf", "v1.66.1")
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho buf\n", string(data))
+
+	// Other versions are not in the bundle.
+	_, err = r.ResolveTool("buf", "v1.67.0")
+	require.ErrorIs(t, err, ErrToolNotFound)
+}
+
+func TestBundle_ChecksumMismatch(t *testing.T) {
+	home := t.TempDir()
+	bin := cacheTool(t, home, "buf", "v1.66.1", "#!/bin/sh\necho buf\n")
+	out := filepath.Join(t.TempDir(), "tools.tar.gz")
+	entry := runtime.GOOS + "-" + runtime.GOARCH + "/" + filepath.Base(bin)
+	manifest := &BundleManifest{Version: 1, Tools: []BundleTool{{
+		Name:     "buf",
+		Version:  "v1.66.1",
+		Platform: HostPlatform(),
+		Path:     entry,
+		SHA256:   "0000000000000000000000000000000000000000000000000000000000000000",
+	}}}
+	require.NoError(t, writeBundle(out, manifest, map[string]string{entry: bin}))
+
+	t.Setenv("HOME", t.TempDir())
+	r := NewToolchainResolver(WithOfflineMode(true), WithBundlePath(out))
+	_, err := r.ResolveTool("buf", "v1.66.1")
+	require.ErrorContains(t, err, "checksum mismatch for buf v1.66.1 in toolchain bundle")
+	require.NoFileExists(t, binaryPath("buf", cacheDir("buf", "v1.66.1"), runtime.GOOS))
+}
+
+func TestBundle_Authoritative(t *testing.T) {
+	home := t.TempDir()
+	bin := cacheTool(t, home, "buf", "v1.66.1", "#!/bin/sh\necho buf\n")
+	out := filepath.Join(t.TempDir(), "tools.tar.gz")
+	manifest, _, err := NewToolchainResolver(WithOfflineMode(true)).BuildBundle(out, []string{HostPlatform()})
+	require.NoError(t, err)
+	require.NotEmpty(t, manifest.Tools)
+
+	// The bundle's manifest verifies the tool; an apx.lock pin that
+	// disagrees with it is not checked as well.
+	t.Setenv("HOME", t.TempDir())
+	r := NewToolchainResolver(WithOfflineMode(true), WithBundlePath(out), WithLock(map[string]config.ToolchainLock{
+		"buf": {Version: "v1.66.1", Checksums: pinFor(HostPlatform(), "sha256:0000")},
+	}))
+	path, err := r.ResolveTool("buf", "v1.66.1")
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	want, err := os.ReadFile(bin)
+	require.NoError(t, err)
+	require.Equal(t, want, data)
+}
+
+func TestBundle_FromEnvironment(t *testing.T) {
+	t.Setenv("APX_TOOLS_BUNDLE", "/tmp/tools.tar.gz")
+	require.Equal(t, "/tmp/tools.tar.gz", NewToolchainResolver().bundlePath)
+}
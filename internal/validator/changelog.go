@@ -0,0 +1,846 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ChangelogEntry is one semantic change between two versions of an API: a
+// message, field, RPC, operation, column or property that was added,
+// removed or changed.
+type ChangelogEntry struct {
+	Category  string   `json:"category"`            // e.g. message, field, rpc, operation, column, property
+	Kind      string   `json:"kind"`                // added, removed or changed
+	File      string   `json:"file,omitempty"`      // file relative to the compared path
+	Element   string   `json:"element"`             // qualified name, e.g. Order.amount or GET /pets
+	Attribute string   `json:"attribute,omitempty"` // changed aspect of the element, e.g. type or required
+	Old       string   `json:"old,omitempty"`
+	New       string   `json:"new,omitempty"`
+	Breaking  bool     `json:"breaking"`
+	Rules     []string `json:"rules,omitempty"` // breaking-change rules that flagged the entry
+}
+
+// String renders the entry for human-readable output, e.g.
+// "removed field Order.amount" or "changed column price: type (INT32 -> INT64)".
+func (e ChangelogEntry) String() string {
+	element := e.Element
+	if element == "" {
+		element = "(root)"
+	}
+	s := e.Kind + " " + e.Category + " " + element
+	if e.Attribute != "" {
+		s += ": " + e.Attribute
+	}
+	switch {
+	case e.Old != "" && e.New != "":
+		s += fmt.Sprintf(" (%s -> %s)", e.Old, e.New)
+	case e.Kind == "changed" && e.Old != "":
+		s += " (removed " + e.Old + ")"
+	case e.Kind == "changed" && e.New != "":
+		s += " (added " + e.New + ")"
+	case e.Old != "":
+		s += " (" + e.Old + ")"
+	case e.New != "":
+		s += " (" + e.New + ")"
+	}
+	return s
+}
+
+// Changelog is the semantic difference between two versions of an API.
+type Changelog struct {
+	Format  SchemaFormat     `json:"format"`
+	Level   ChangeLevel      `json:"level"`
+	Entries []ChangelogEntry `json:"changes"`
+	// Unmatched are breaking findings that could not be tied to an entry;
+	// they are reported alongside the entries so none is lost.
+	Unmatched []Finding `json:"unmatched_findings,omitempty"`
+	// Report is the classification the changelog was built from.
+	Report *ChangeReport `json:"-"`
+}
+
+// Breaking returns the number of breaking entries and unmatched findings.
+func (c *Changelog) Breaking() int {
+	n := len(c.Unmatched)
+	for _, e := range c.Entries {
+		if e.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// changelogCategories orders the categories of a changelog, outermost
+// elements first. Unlisted categories sort after these, alphabetically.
+var changelogCategories = []string{
+	"file", "package", "import", "option", "service", "rpc", "path", "operation",
+	"parameter", "request body", "response", "message", "record", "schema",
+	"definition", "enum", "enum value", "oneof", "field", "property", "column",
+	"resource", "version", "document",
+}
+
+func changelogCategoryRank(category string) int {
+	for i, c := range changelogCategories {
+		if c == category {
+			return i
+		}
+	}
+	return len(changelogCategories)
+}
+
+// Changelog compares two versions of the API at path and lists what changed
+// between them, element by element. from and to are each a path on disk or
+// a git ref (a release tag, branch or commit); for a ref, the files under
+// path are read as committed there. An entry is breaking when the format's
+// breaking check flags it.
+func (v *Validator) Changelog(path, from, to string, format SchemaFormat) (*Changelog, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	newPath, missing, cleanupNew, err := materializeBaseline(absPath, to)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanupNew()
+	if missing {
+		return nil, fmt.Errorf("no %s schema files at %s", format, to)
+	}
+	oldPath, missing, cleanupOld, err := materializeBaseline(absPath, from)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanupOld()
+	if missing {
+		// The API did not exist yet: compare against an empty directory,
+		// so every file is added.
+		if err := os.MkdirAll(oldPath, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := v.ClassifyChanges(newPath, oldPath, format)
+	if err != nil {
+		return nil, err
+	}
+	b := &changelogBuilder{format: format, oldPath: oldPath, newPath: newPath, trees: map[string]interface{}{}}
+	log := &Changelog{Format: format, Level: report.Level, Report: report}
+	log.Entries = b.entries(report.Changes)
+	log.Unmatched = b.markBreaking(log.Entries, report.Findings)
+	sort.SliceStable(log.Entries, func(i, j int) bool {
+		a, c := log.Entries[i], log.Entries[j]
+		if ra, rc := changelogCategoryRank(a.Category), changelogCategoryRank(c.Category); ra != rc {
+			return ra < rc
+		}
+		if a.Category != c.Category {
+			return a.Category < c.Category
+		}
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		return a.Element < c.Element
+	})
+	return log, nil
+}
+
+// changelogBuilder turns the structural changes of a ChangeReport into
+// changelog entries.
+type changelogBuilder struct {
+	format           SchemaFormat
+	oldPath, newPath string
+	trees            map[string]interface{} // normalized trees by file, loaded on demand
+}
+
+// tree returns the normalized tree of file rel on the old or new side.
+func (b *changelogBuilder) tree(rel string, old bool) interface{} {
+	root := b.newPath
+	if old {
+		root = b.oldPath
+	}
+	// Two single files are compared whatever their names.
+	file := root
+	if isDir(root) {
+		file = filepath.Join(root, filepath.FromSlash(rel))
+	}
+	if t, ok := b.trees[file]; ok {
+		return t
+	}
+	t, err := normalizedSchema(b.format, file)
+	if err != nil {
+		t = nil
+	}
+	b.trees[file] = t
+	return t
+}
+
+func (b *changelogBuilder) entries(changes []Change) []ChangelogEntry {
+	var out []ChangelogEntry
+	switch b.format {
+	case FormatProto:
+		out = protoChangelog(changes)
+	case FormatParquet:
+		out = parquetChangelog(changes)
+	default:
+		for _, c := range changes {
+			if c.Path == "" {
+				out = append(out, fileEntry(c))
+				continue
+			}
+			var e []ChangelogEntry
+			switch b.format {
+			case FormatAvro:
+				e = b.avroEntries(c)
+			case FormatOpenAPI:
+				e = b.openAPIEntries(c)
+			case FormatCRD:
+				e = crdEntries(c)
+			default:
+				e = []ChangelogEntry{schemaEntry(c, stripDocIndex(c.elems), "")}
+			}
+			for i := range e {
+				e[i].File = c.File
+			}
+			out = append(out, e...)
+		}
+	}
+	// An attribute added or removed changes an element that is still there.
+	for i := range out {
+		if out[i].Attribute != "" {
+			out[i].Kind = "changed"
+		}
+	}
+	return out
+}
+
+// stripDocIndex drops the [i] document index of a multi-document YAML file.
+func stripDocIndex(elems []string) []string {
+	if len(elems) > 0 && strings.HasPrefix(elems[0], "[") {
+		return elems[1:]
+	}
+	return elems
+}
+
+// fileEntry reports a whole file that was added or removed.
+func fileEntry(c Change) ChangelogEntry {
+	return ChangelogEntry{Category: "file", Kind: c.Kind, File: c.File, Element: c.File}
+}
+
+// ---------------------------------------------------------------------------
+// Proto
+// ---------------------------------------------------------------------------
+
+// protoFieldRe matches the name and number of a field or enum value.
+var protoFieldRe = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(-?(?:0x[0-9A-Fa-f]+|\d+))`)
+
+// protoChangelog maps changed proto declarations to entries. A declaration
+// whose enclosing block was itself added or removed is folded into it, and
+// an element removed and re-added with a different declaration (a field
+// whose type changed) becomes one changed entry.
+func protoChangelog(changes []Change) []ChangelogEntry {
+	blocks := map[string]string{} // "file|key" of added/removed declarations → kind
+	for _, c := range changes {
+		if c.Path != "" {
+			blocks[c.File+"|"+c.Path] = c.Kind
+		}
+	}
+	var out []ChangelogEntry
+	for _, c := range changes {
+		if c.Path == "" {
+			out = append(out, fileEntry(c))
+			continue
+		}
+		folded := false
+		parts := strings.Split(c.Path, " > ")
+		for i := 1; i < len(parts); i++ {
+			if blocks[c.File+"|"+strings.Join(parts[:i], " > ")] == c.Kind {
+				folded = true
+				break
+			}
+		}
+		if folded {
+			continue
+		}
+		e := protoEntry(parts, c.Kind)
+		e.File = c.File
+		out = append(out, e)
+	}
+	return mergeReplaced(out)
+}
+
+// protoEntry describes the declaration at the end of a proto block path.
+func protoEntry(parts []string, kind string) ChangelogEntry {
+	var scope []string
+	enclosing := ""
+	for _, p := range parts[:len(parts)-1] {
+		f := strings.Fields(p)
+		if len(f) < 2 {
+			continue
+		}
+		switch f[0] {
+		case "message", "enum", "service", "rpc", "extend":
+			scope = append(scope, protoDeclName(f[1]))
+			enclosing = f[0]
+		}
+	}
+	stmt := parts[len(parts)-1]
+	e := ChangelogEntry{Kind: kind}
+	setStmt := func() {
+		if kind == "removed" {
+			e.Old = stmt
+		} else {
+			e.New = stmt
+		}
+	}
+	f := strings.Fields(stmt)
+	head := ""
+	if len(f) > 0 {
+		head = f[0]
+	}
+	switch head {
+	case "message", "enum", "service", "oneof", "rpc", "extend":
+		e.Category = head
+		if head == "extend" {
+			e.Category = "extension"
+		}
+		name := ""
+		if len(f) > 1 {
+			name = protoDeclName(f[1])
+		}
+		e.Element = strings.Join(append(append([]string{}, scope...), name), ".")
+		if head == "rpc" {
+			setStmt()
+		}
+	case "syntax", "edition", "package", "import":
+		e.Category = head
+		e.Element = strings.Trim(strings.Join(f[1:], " "), "=\" ")
+		setStmt()
+	case "option", "reserved", "extensions":
+		if len(scope) == 0 {
+			e.Category = "option"
+			e.Element = strings.Join(f[1:], " ")
+			break
+		}
+		e.Category = enclosing
+		e.Element = strings.Join(scope, ".")
+		e.Kind = "changed"
+		e.Attribute = head
+		setStmt()
+	default:
+		m := protoFieldRe.FindStringSubmatch(stmt)
+		if m == nil {
+			e.Category = "statement"
+			e.Element = strings.Join(scope, ".")
+			setStmt()
+			break
+		}
+		e.Category = "field"
+		if enclosing == "enum" {
+			e.Category = "enum value"
+		}
+		e.Element = strings.Join(append(append([]string{}, scope...), m[1]), ".")
+		setStmt()
+	}
+	return e
+}
+
+// protoDeclName returns the name in a declaration token such as
+// "Get(GetRequest)" or "Order".
+func protoDeclName(tok string) string {
+	if i := strings.IndexAny(tok, "(<"); i >= 0 {
+		return tok[:i]
+	}
+	return tok
+}
+
+// mergeReplaced folds an element that was removed and added back with a
+// different declaration into a single changed entry, listed where the
+// removal was.
+func mergeReplaced(entries []ChangelogEntry) []ChangelogEntry {
+	key := func(e ChangelogEntry) string {
+		return e.File + "|" + e.Category + "|" + e.Element + "|" + e.Attribute
+	}
+	removed := map[string][]int{}
+	added := map[string][]int{}
+	for i, e := range entries {
+		switch e.Kind {
+		case "removed":
+			removed[key(e)] = append(removed[key(e)], i)
+		case "added":
+			added[key(e)] = append(added[key(e)], i)
+		}
+	}
+	var out []ChangelogEntry
+	for _, e := range entries {
+		k := key(e)
+		if len(removed[k]) != 1 || len(added[k]) != 1 {
+			out = append(out, e)
+			continue
+		}
+		if e.Kind == "removed" {
+			e.Kind = "changed"
+			e.New = entries[added[k][0]].New
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Parquet
+// ---------------------------------------------------------------------------
+
+// parquetChangelog maps changed columns to entries. Columns under an added
+// or removed group are folded into it.
+func parquetChangelog(changes []Change) []ChangelogEntry {
+	groups := map[string]string{}
+	for _, c := range changes {
+		groups[c.File+"|"+c.Path] = c.Kind
+	}
+	var out []ChangelogEntry
+	for _, c := range changes {
+		if c.Path == "" {
+			out = append(out, fileEntry(c))
+			continue
+		}
+		folded := false
+		for p := c.Path; strings.Contains(p, "."); {
+			p = p[:strings.LastIndex(p, ".")]
+			if groups[c.File+"|"+p] == c.Kind {
+				folded = true
+				break
+			}
+		}
+		if !folded {
+			out = append(out, ChangelogEntry{Category: "column", Kind: c.Kind, File: c.File, Element: c.Path, Old: c.Old, New: c.New})
+		}
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// JSON Schema, and the schemas embedded in CRDs and OpenAPI documents
+// ---------------------------------------------------------------------------
+
+// schemaEntry maps a change inside a JSON Schema to the property it
+// belongs to. elems are relative to the schema root; prefix qualifies the
+// element (a CRD version or an OpenAPI schema name).
+func schemaEntry(c Change, elems []string, prefix string) ChangelogEntry {
+	var props []string
+	category := "schema"
+	i := 0
+	for i < len(elems) {
+		switch e := elems[i]; {
+		case (e == "properties" || e == "patternProperties") && i+1 < len(elems):
+			props = append(props, elems[i+1])
+			category = "property"
+			i += 2
+			continue
+		case (e == "$defs" || e == "definitions") && i+1 < len(elems) && len(props) == 0:
+			props = append(props, elems[i+1])
+			category = "definition"
+			i += 2
+			continue
+		case e == "items" && len(props) > 0:
+			props[len(props)-1] += "[]"
+			i++
+			continue
+		case (e == "allOf" || e == "anyOf" || e == "oneOf") && i+2 < len(elems) && strings.HasPrefix(elems[i+1], "["):
+			// Composition branches are transparent.
+			i += 2
+			continue
+		}
+		break
+	}
+	element := strings.Join(props, ".")
+	if prefix != "" && element != "" {
+		element = prefix + "." + element
+	} else if prefix != "" {
+		element = prefix
+	}
+	rest := elems[i:]
+	entry := ChangelogEntry{Category: category, Kind: c.Kind, Element: element, Old: c.Old, New: c.New}
+	if name := c.New + c.Old; len(rest) == 1 && name != "" {
+		switch rest[0] {
+		case "required":
+			// A name added to or removed from the required list.
+			entry.Category = "property"
+			entry.Element = jsonSchemaJoin(element, name)
+			entry.Kind = "changed"
+			entry.Attribute = "required"
+			entry.Old, entry.New = "optional", "required"
+			if c.Kind == "removed" {
+				entry.Old, entry.New = "required", "optional"
+			}
+			return entry
+		case "enum":
+			entry.Category = "enum value"
+			return entry
+		}
+	}
+	if len(rest) > 0 {
+		entry.Attribute = joinElems(rest)
+	}
+	return entry
+}
+
+// crdEntries maps a change in a CustomResourceDefinition to its version
+// or, within a version's schema, to the property it belongs to. Property
+// elements are qualified by version, e.g. v1.spec.replicas.
+func crdEntries(c Change) []ChangelogEntry {
+	elems := stripDocIndex(c.elems)
+	if len(elems) >= 3 && elems[0] == "spec" && elems[1] == "versions" {
+		version := elems[2]
+		rest := elems[3:]
+		if len(rest) >= 2 && rest[0] == "schema" && rest[1] == "openAPIV3Schema" {
+			return []ChangelogEntry{schemaEntry(c, rest[2:], version)}
+		}
+		e := ChangelogEntry{Category: "version", Kind: c.Kind, Element: version, Old: c.Old, New: c.New}
+		if len(rest) > 0 {
+			e.Attribute = joinElems(rest)
+		}
+		return []ChangelogEntry{e}
+	}
+	return []ChangelogEntry{{Category: "resource", Kind: c.Kind, Element: joinElems(elems), Old: c.Old, New: c.New}}
+}
+
+// ---------------------------------------------------------------------------
+// Avro
+// ---------------------------------------------------------------------------
+
+// avroEntries maps a change in an Avro schema or protocol to the record,
+// field, enum symbol or protocol message it belongs to. Elements are named
+// like the compatibility findings: Record.field, Record.nested.field.
+func (b *changelogBuilder) avroEntries(c Change) []ChangelogEntry {
+	elems := c.elems
+	root, _ := b.tree(c.File, c.Kind == "removed").(map[string]interface{})
+	if root == nil {
+		root, _ = b.tree(c.File, true).(map[string]interface{})
+	}
+	var scope []string
+	category := "schema"
+	if _, isProtocol := root["protocol"]; isProtocol {
+		switch {
+		case len(elems) >= 2 && elems[0] == "types":
+			scope = []string{avroShortName(elems[1])}
+			category = "record"
+			elems = elems[2:]
+		case len(elems) >= 2 && elems[0] == "messages":
+			e := ChangelogEntry{Category: "message", Kind: c.Kind, Element: elems[1], Old: c.Old, New: c.New}
+			if len(elems) > 2 {
+				e.Attribute = joinElems(elems[2:])
+			}
+			return []ChangelogEntry{e}
+		default:
+			return []ChangelogEntry{{Category: "protocol", Kind: c.Kind, Element: joinElems(elems), Old: c.Old, New: c.New}}
+		}
+	} else if name, ok := root["name"].(string); ok {
+		scope = []string{avroShortName(name)}
+		category = "record"
+		if t, _ := root["type"].(string); t == "enum" || t == "fixed" {
+			category = t
+		}
+	}
+
+	i := 0
+	for i < len(elems) {
+		if elems[i] == "fields" && i+1 < len(elems) {
+			scope = append(scope, elems[i+1])
+			category = "field"
+			i += 2
+			continue
+		}
+		// Step through a field's type (and union branch, array items or
+		// map values) into a nested record.
+		j := i
+		for j < len(elems) && (elems[j] == "type" || elems[j] == "items" || elems[j] == "values" || strings.HasPrefix(elems[j], "[")) {
+			j++
+		}
+		if j > i && j+1 < len(elems) && elems[j] == "fields" {
+			i = j
+			continue
+		}
+		break
+	}
+	rest := elems[i:]
+	e := ChangelogEntry{Category: category, Kind: c.Kind, Element: strings.Join(scope, "."), Old: c.Old, New: c.New}
+	if len(rest) > 0 && rest[len(rest)-1] == "symbols" {
+		e.Category = "enum value"
+		return []ChangelogEntry{e}
+	}
+	if len(rest) > 0 {
+		e.Attribute = joinElems(rest)
+	}
+	return []ChangelogEntry{e}
+}
+
+// ---------------------------------------------------------------------------
+// OpenAPI
+// ---------------------------------------------------------------------------
+
+// openAPIEntries maps a change in an OpenAPI document to the operation,
+// parameter, request body, response or component schema it belongs to.
+// Operations are named like the native breaking findings: GET /pets.
+func (b *changelogBuilder) openAPIEntries(c Change) []ChangelogEntry {
+	elems := c.elems
+	entry := func(category, element string, rest []string) []ChangelogEntry {
+		e := ChangelogEntry{Category: category, Kind: c.Kind, Element: element, Old: c.Old, New: c.New}
+		if len(rest) > 0 {
+			e.Attribute = joinElems(rest)
+		}
+		return []ChangelogEntry{e}
+	}
+	switch {
+	case len(elems) == 2 && elems[0] == "paths":
+		// A whole path added or removed: one entry per operation.
+		item, _ := treeAt(b.tree(c.File, c.Kind == "removed"), elems).(map[string]interface{})
+		var out []ChangelogEntry
+		for _, m := range oasMethods {
+			if _, ok := item[m]; ok {
+				out = append(out, ChangelogEntry{Category: "operation", Kind: c.Kind, Element: strings.ToUpper(m) + " " + elems[1]})
+			}
+		}
+		if len(out) == 0 {
+			return entry("path", elems[1], nil)
+		}
+		return out
+	case len(elems) >= 3 && elems[0] == "paths" && isOASMethod(elems[2]):
+		op := strings.ToUpper(elems[2]) + " " + elems[1]
+		rest := elems[3:]
+		switch {
+		case len(rest) >= 2 && rest[0] == "parameters":
+			return entry("parameter", op+" "+oasParamLabel(rest[1]), rest[2:])
+		case len(rest) >= 1 && rest[0] == "requestBody":
+			return entry("request body", op, rest[1:])
+		case len(rest) >= 2 && rest[0] == "responses":
+			return entry("response", op+" "+rest[1], rest[2:])
+		}
+		return entry("operation", op, rest)
+	case len(elems) >= 4 && elems[0] == "paths" && elems[2] == "parameters":
+		return entry("parameter", elems[1]+" "+oasParamLabel(elems[3]), elems[4:])
+	case len(elems) >= 3 && elems[0] == "components" && elems[1] == "schemas",
+		len(elems) >= 2 && elems[0] == "definitions":
+		if elems[0] == "components" {
+			elems = elems[1:]
+		}
+		e := schemaEntry(c, elems[2:], elems[1])
+		if e.Category == "schema" && len(elems) == 2 {
+			e.Element = elems[1]
+		}
+		return []ChangelogEntry{e}
+	case len(elems) >= 3 && elems[0] == "components":
+		return entry("component", elems[1]+"."+elems[2], elems[3:])
+	}
+	return entry("document", joinElems(elems), nil)
+}
+
+func isOASMethod(key string) bool {
+	return containsString(oasMethods, key)
+}
+
+// oasParamLabel renders a parameter key ("query:limit") as "limit (query)".
+func oasParamLabel(key string) string {
+	if in, name, ok := strings.Cut(key, ":"); ok {
+		return name + " (" + in + ")"
+	}
+	return key
+}
+
+// treeAt returns the value at elems in a normalized tree, resolving named
+// array elements the way diffTrees keys them.
+func treeAt(v interface{}, elems []string) interface{} {
+	for _, e := range elems {
+		switch cur := v.(type) {
+		case map[string]interface{}:
+			v = cur[e]
+		case []interface{}:
+			if named := namedElements(cur); named != nil {
+				v = named[e]
+				continue
+			}
+			var i int
+			if _, err := fmt.Sscanf(e, "[%d]", &i); err != nil || i < 0 || i >= len(cur) {
+				return nil
+			}
+			v = cur[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// ---------------------------------------------------------------------------
+// Breaking findings
+// ---------------------------------------------------------------------------
+
+// markBreaking flags the entries that the error findings point at and
+// returns the findings that match no entry. A finding matches the entry
+// with the same element path, else the innermost entry containing it, else
+// the entries whose names its message quotes (buf reports no element path)
+// preferring those nested under its path, else everything nested under it.
+func (b *changelogBuilder) markBreaking(entries []ChangelogEntry, findings []Finding) []Finding {
+	var unmatched []Finding
+	for _, f := range findings {
+		// An expired waiver fails the check but is not a schema change.
+		if f.Severity != SeverityError || f.RuleID == RuleWaiverExpired {
+			continue
+		}
+		file := b.relFile(f.File)
+		var cands []int
+		for i, e := range entries {
+			if file == "" || e.File == "" || e.File == file {
+				cands = append(cands, i)
+			}
+		}
+		hits := matchFinding(entries, cands, f)
+		if len(hits) == 0 {
+			f.File = file
+			unmatched = append(unmatched, f)
+			continue
+		}
+		for _, i := range hits {
+			entries[i].Breaking = true
+			if f.RuleID != "" && !containsString(entries[i].Rules, f.RuleID) {
+				entries[i].Rules = append(entries[i].Rules, f.RuleID)
+			}
+		}
+	}
+	return unmatched
+}
+
+func matchFinding(entries []ChangelogEntry, cands []int, f Finding) []int {
+	var under []int
+	if f.Path != "" {
+		var exact []int
+		best := -1
+		for _, i := range cands {
+			el := entries[i].Element
+			switch {
+			case el == f.Path:
+				exact = append(exact, i)
+			case nestedElement(f.Path, el):
+				if best < 0 || len(el) > len(entries[best].Element) {
+					best = i
+				}
+			case nestedElement(el, f.Path), strings.HasSuffix(el, " "+f.Path):
+				// Nested, or an operation ("GET /pets") on a path ("/pets").
+				under = append(under, i)
+			}
+		}
+		if len(exact) > 0 {
+			return exact
+		}
+		if best >= 0 {
+			return []int{best}
+		}
+	}
+	if hits := quotedEntries(entries, under, f.Message); len(hits) > 0 {
+		return preferKind(entries, hits, f.RuleID)
+	}
+	if hits := quotedEntries(entries, cands, f.Message); len(hits) > 0 {
+		return preferKind(entries, hits, f.RuleID)
+	}
+	return preferKind(entries, under, f.RuleID)
+}
+
+// nestedElement reports whether element inner lies within element outer.
+func nestedElement(inner, outer string) bool {
+	if outer == "" || len(inner) <= len(outer) || !strings.HasPrefix(inner, outer) {
+		return false
+	}
+	return strings.ContainsRune(". [", rune(inner[len(outer)]))
+}
+
+// quotedNameRe matches the quoted names in a finding message.
+var quotedNameRe = regexp.MustCompile(`"([^"]+)"`)
+
+// elementWordRe splits an element into the names it is made of.
+var elementWordRe = regexp.MustCompile(`[^\s.()\[\]{}/]+`)
+
+// quotedEntries returns the entries among hits whose element names the
+// most of the names quoted in msg (a quoted "a.b" also names b).
+func quotedEntries(entries []ChangelogEntry, hits []int, msg string) []int {
+	names := map[string]bool{}
+	for _, m := range quotedNameRe.FindAllStringSubmatch(msg, -1) {
+		names[m[1]] = true
+		names[m[1][strings.LastIndex(m[1], ".")+1:]] = true
+	}
+	var out []int
+	bestScore := 0
+	for _, i := range hits {
+		score := 0
+		seen := map[string]bool{}
+		for _, w := range elementWordRe.FindAllString(entries[i].Element, -1) {
+			if names[w] && !seen[w] {
+				seen[w] = true
+				score++
+			}
+		}
+		switch {
+		case score == 0 || score < bestScore:
+		case score > bestScore:
+			bestScore = score
+			out = []int{i}
+		default:
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// preferKind narrows matched entries to the kind of change a rule names
+// (a "...-removed" rule to removals), when any entry is of that kind.
+func preferKind(entries []ChangelogEntry, hits []int, rule string) []int {
+	rule = strings.ToLower(rule)
+	kind := "changed"
+	switch {
+	case strings.Contains(rule, "removed") || strings.Contains(rule, "delete"):
+		kind = "removed"
+	case strings.Contains(rule, "added") || strings.HasPrefix(rule, "new-"):
+		kind = "added"
+	}
+	var out []int
+	for _, i := range hits {
+		if entries[i].Kind == kind {
+			out = append(out, i)
+		}
+	}
+	if len(out) == 0 {
+		return hits
+	}
+	return out
+}
+
+// relFile makes a finding's file relative to the compared path, like the
+// entries' files.
+func (b *changelogBuilder) relFile(file string) string {
+	if file == "" || !filepath.IsAbs(file) {
+		return filepath.ToSlash(file)
+	}
+	if !isDir(b.newPath) {
+		return filepath.Base(file)
+	}
+	newPath := b.newPath
+	if r, err := filepath.EvalSymlinks(newPath); err == nil {
+		newPath = r
+	}
+	if r, err := filepath.EvalSymlinks(file); err == nil {
+		file = r
+	}
+	if rel, err := filepath.Rel(newPath, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package validator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// changelogLines renders entries as "breaking|<entry>" or "ok|<entry>".
+func changelogLines(log *Changelog) []string {
+	var lines []string
+	for _, e := range log.Entries {
+		mark := "ok"
+		if e.Breaking {
+			mark = "breaking"
+		}
+		lines = append(lines, mark+"|"+e.String())
+	}
+	return lines
+}
+
+func assertChangelog(t *testing.T, log *Changelog, want []string) {
+	t.Helper()
+	got := changelogLines(log)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changelog:\n  got  %q\n  want %q", got, want)
+	}
+	if len(log.Unmatched) != 0 {
+		t.Errorf("unmatched findings: %v", log.Unmatched)
+	}
+}
+
+func TestChangelog_Formats(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    SchemaFormat
+		old, new  string
+		wantLevel ChangeLevel
+		want      []string
+	}{
+		{
+			name: "avro required field", format: FormatAvro,
+			old: "testdata/avro/v1.avsc", new: "testdata/avro/v2_breaking.avsc",
+			wantLevel: ChangeBreaking,
+			want:      []string{"breaking|added field User.phone"},
+		},
+		{
+			name: "avro optional field", format: FormatAvro,
+			old: "testdata/avro/v1.avsc", new: "testdata/avro/v2_backward.avsc",
+			wantLevel: ChangeAdditive,
+			want:      []string{"ok|added field User.email"},
+		},
+		{
+			name: "parquet nested columns", format: FormatParquet,
+			old: "testdata/parquet/nested_v1.parquet", new: "testdata/parquet/nested_v2_breaking.parquet",
+			wantLevel: ChangeBreaking,
+			want: []string{
+				"breaking|changed column address.geo.lat (required double -> required float)",
+				"breaking|removed column address.geo.lon (required double)",
+				"breaking|changed column balance (optional binary (DECIMAL(12,2)) -> optional binary (DECIMAL(12,4)))",
+				"breaking|changed column tags.list.element (optional binary (STRING) -> optional int32)",
+			},
+		},
+		{
+			name: "json schema properties", format: FormatJSONSchema,
+			old: "testdata/jsonschema/v1.json", new: "testdata/jsonschema/v2_breaking.json",
+			wantLevel: ChangeBreaking,
+			want: []string{
+				"breaking|changed property id: type (string -> integer)",
+				"breaking|changed property name: required (optional -> required)",
+			},
+		},
+		{
+			name: "openapi operations", format: FormatOpenAPI,
+			old: "testdata/openapi/petstore_v1.yaml", new: "testdata/openapi/petstore_v2_breaking.yaml",
+			wantLevel: ChangeBreaking,
+			want: []string{
+				"breaking|removed operation DELETE /pets/{petId}",
+				"breaking|removed operation GET /stores",
+				"breaking|changed parameter GET /pets status (query): schema.enum (removed pending)",
+				"breaking|added parameter GET /pets tenant (header)",
+				"ok|changed schema Pet: required",
+				"ok|added enum value Pet.kind (bird)",
+				"breaking|added property NewPet.owner",
+				"ok|changed property NewPet.owner: required (optional -> required)",
+				"breaking|changed property Pet.id: type (string -> integer)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(NewToolchainResolver())
+			v.SetOpenAPIEngine(OpenAPIEngineNative)
+			log, err := v.Changelog(tt.new, tt.old, tt.new, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if log.Level != tt.wantLevel {
+				t.Errorf("level = %s, want %s", log.Level, tt.wantLevel)
+			}
+			assertChangelog(t, log, tt.want)
+		})
+	}
+}
+
+func TestChangelog_CRD(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.yaml")
+	newFile := filepath.Join(dir, "new.yaml")
+	mustWrite(t, oldFile, validCRD)
+	changed := strings.Replace(validCRD, `                label:
+                  type: string
+                  maxLength: 64
+`, `                replicas:
+                  type: integer
+`, 1)
+	changed = strings.Replace(changed, "storage: true", "storage: false", 1)
+	mustWrite(t, newFile, changed)
+
+	log, err := NewValidator(NewToolchainResolver()).Changelog(newFile, oldFile, newFile, FormatCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertChangelog(t, log, []string{
+		"breaking|removed property v1.spec.label",
+		"ok|added property v1.spec.replicas",
+		"ok|changed version v1: storage (true -> false)",
+	})
+}
+
+func TestChangelog_GitRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	mustWrite(t, filepath.Join(dir, "README.md"), "apis\n")
+	git("add", "-A")
+	git("commit", "-qm", "init")
+	git("tag", "start")
+
+	module := filepath.Join(dir, "avro", "users", "v1")
+	if err := os.MkdirAll(module, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, filepath.Join(module, "user.avsc"), `{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}`)
+	git("add", "-A")
+	git("commit", "-qm", "v1.0.0")
+	git("tag", "v1.0.0")
+	mustWrite(t, filepath.Join(module, "user.avsc"), `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null}]}`)
+	mustWrite(t, filepath.Join(module, "status.avsc"), `{"type":"enum","name":"Status","symbols":["ACTIVE"]}`)
+	git("add", "-A")
+	git("commit", "-qm", "v1.1.0")
+	git("tag", "v1.1.0")
+	// The working tree moves on; the refs are what is compared.
+	if err := os.RemoveAll(module); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidator(NewToolchainResolver())
+	log, err := v.Changelog(module, "v1.0.0", "v1.1.0", FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.Level != ChangeAdditive {
+		t.Errorf("level = %s, want additive", log.Level)
+	}
+	assertChangelog(t, log, []string{
+		"ok|added file status.avsc",
+		"ok|added field User.email",
+	})
+
+	// Before the API existed, every file is new.
+	log, err = v.Changelog(module, "start", "v1.0.0", FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertChangelog(t, log, []string{"ok|added file user.avsc"})
+
+	if _, err := v.Changelog(module, "v1.0.0", "no-such-ref", FormatAvro); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
+
+func TestProtoChangelog(t *testing.T) {
+	old := []byte(`syntax = "proto3";
+package ledger.v1;
+
+service Ledger {
+  rpc Get(GetRequest) returns (Entry);
+}
+
+message Entry {
+  string id = 1;
+  int64 amount = 2;
+  string memo = 3;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+`)
+	updated := []byte(`syntax = "proto3";
+package ledger.v1;
+
+service Ledger {
+  rpc Get(GetRequest) returns (Entry);
+  rpc Refund(RefundRequest) returns (Entry);
+}
+
+message Entry {
+  string id = 1;
+  string amount = 2;
+}
+
+message RefundRequest {
+  string id = 1;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_SETTLED = 1;
+}
+`)
+	var changes []Change
+	diffTrees(&changes, nil, protoDeclarations(old), protoDeclarations(updated))
+	for i := range changes {
+		changes[i].File = "ledger.proto"
+	}
+	var got []string
+	for _, e := range protoChangelog(changes) {
+		got = append(got, e.String())
+	}
+	expect := []string{
+		"changed field Entry.amount (int64 amount = 2 -> string amount = 2)",
+		"removed field Entry.memo (string memo = 3)",
+		"added enum value Status.STATUS_SETTLED (STATUS_SETTLED = 1)",
+		"added message RefundRequest",
+		"added rpc Ledger.Refund (rpc Refund(RefundRequest) returns (Entry))",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("got\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(expect, "\n  "))
+	}
+}
+
+func TestMatchFinding_QuotedNames(t *testing.T) {
+	entries := []ChangelogEntry{
+		{Category: "message", Kind: "changed", Element: "Order", Attribute: "option"},
+		{Category: "field", Kind: "removed", Element: "Order.amount"},
+		{Category: "field", Kind: "added", Element: "Order.total"},
+	}
+	f := Finding{RuleID: "FIELD_NO_DELETE", Severity: SeverityError,
+		Message: `Previously present field "2" with name "amount" on message "Order" was deleted.`}
+	hits := matchFinding(entries, []int{0, 1, 2}, f)
+	if len(hits) != 1 || hits[0] != 1 {
+		t.Errorf("hits = %v, want [1]", hits)
+	}
+}
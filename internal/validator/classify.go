@@ -0,0 +1,739 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeLevel classifies the schema-level difference between two versions
+// of an API.
+type ChangeLevel string
+
+const (
+	ChangeNone     ChangeLevel = "none"     // no schema change (docs, comments or formatting only)
+	ChangeAdditive ChangeLevel = "additive" // schema changed without breaking changes
+	ChangeBreaking ChangeLevel = "breaking" // at least one error-severity breaking finding
+)
+
+// Change is one schema-level difference between two versions.
+type Change struct {
+	File string `json:"file"`           // file relative to the compared path
+	Kind string `json:"kind"`           // added, removed or changed
+	Path string `json:"path,omitempty"` // element within the file; empty for a whole file
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+
+	elems []string // Path split into map keys, array names and [i] indexes
+}
+
+// String renders the change for human-readable output.
+func (c Change) String() string {
+	where := c.File
+	if c.Path != "" {
+		where += ": " + c.Path
+	}
+	switch {
+	case c.Kind == "changed" && (c.Old != "" || c.New != ""):
+		return fmt.Sprintf("changed %s (%s -> %s)", where, c.Old, c.New)
+	case c.Kind == "added" && c.New != "":
+		return fmt.Sprintf("added %s (%s)", where, c.New)
+	case c.Kind == "removed" && c.Old != "":
+		return fmt.Sprintf("removed %s (%s)", where, c.Old)
+	}
+	return c.Kind + " " + where
+}
+
+// ChangeReport is the result of classifying a revision against a baseline.
+type ChangeReport struct {
+	Level    ChangeLevel `json:"level"`
+	Changes  []Change    `json:"changes"`  // structural differences, breaking or not
+	Findings []Finding   `json:"findings"` // breaking-change findings
+}
+
+// ClassifyChanges compares the schema at path against a baseline and
+// classifies the difference. against is a path on disk or a git ref; for a
+// ref, the files under path are read as committed at that ref. The breaking
+// check is the format's usual one; the structural diff ignores
+// documentation, comments and formatting, so a docs-only edit is ChangeNone.
+func (v *Validator) ClassifyChanges(path, against string, format SchemaFormat) (*ChangeReport, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	baseline, missing, cleanup, err := materializeBaseline(absPath, against)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	newFiles, err := schemaFiles(format, absPath)
+	if err != nil {
+		return nil, err
+	}
+	var oldFiles map[string]string
+	if !missing {
+		if oldFiles, err = schemaFiles(format, baseline); err != nil {
+			return nil, err
+		}
+		// Two files are compared with each other whatever their names.
+		if len(newFiles) == 1 && len(oldFiles) == 1 && !isDir(absPath) && !isDir(baseline) {
+			for rel := range newFiles {
+				for _, old := range oldFiles {
+					oldFiles = map[string]string{rel: old}
+				}
+			}
+		}
+	}
+
+	report := &ChangeReport{}
+	for _, rel := range sortedKeys(newFiles) {
+		oldFile, ok := oldFiles[rel]
+		if !ok {
+			report.Changes = append(report.Changes, Change{File: rel, Kind: "added"})
+			continue
+		}
+		changes, err := diffSchemaFile(format, oldFile, newFiles[rel])
+		if err != nil {
+			return nil, err
+		}
+		for i := range changes {
+			changes[i].File = rel
+		}
+		report.Changes = append(report.Changes, changes...)
+	}
+	for _, rel := range sortedKeys(oldFiles) {
+		if _, ok := newFiles[rel]; !ok {
+			report.Changes = append(report.Changes, Change{File: rel, Kind: "removed"})
+		}
+	}
+
+	if !missing {
+		findings, err := v.classifyBreaking(format, absPath, against, baseline, newFiles, oldFiles)
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = v.applyWaivers(findings)
+	}
+
+	switch {
+	case HasErrors(report.Findings):
+		report.Level = ChangeBreaking
+	case len(report.Changes) > 0:
+		report.Level = ChangeAdditive
+	default:
+		report.Level = ChangeNone
+	}
+	return report, nil
+}
+
+// classifyBreaking runs the format's breaking check. buf compares whole
+// modules and reads git refs itself; the other formats compare file by file,
+// pairing files by their path relative to the compared directory.
+func (v *Validator) classifyBreaking(format SchemaFormat, absPath, against, baseline string, newFiles, oldFiles map[string]string) ([]Finding, error) {
+	if format == FormatProto {
+		return v.breakingFindings(absPath, against, format)
+	}
+	var findings []Finding
+	for _, rel := range sortedKeys(newFiles) {
+		oldFile, ok := oldFiles[rel]
+		if !ok {
+			continue
+		}
+		fs, err := v.breakingFindings(newFiles[rel], oldFile, format)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fs...)
+	}
+	for _, rel := range sortedKeys(oldFiles) {
+		if _, ok := newFiles[rel]; !ok {
+			findings = append(findings, Finding{
+				File:     rel,
+				RuleID:   "schema-file-removed",
+				Severity: SeverityError,
+				Format:   format,
+				Message:  fmt.Sprintf("schema file %s was removed", rel),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// materializeBaseline returns a path holding the baseline of absPath. When
+// against exists on disk it is the baseline. Otherwise against is a git ref
+// and the files under absPath at that ref are written to a temp directory
+// with the same layout; missing is set when absPath did not exist at the
+// ref (a first release).
+func materializeBaseline(absPath, against string) (baseline string, missing bool, cleanup func(), err error) {
+	cleanup = func() {}
+	if _, statErr := os.Stat(against); statErr == nil {
+		return against, false, cleanup, nil
+	}
+
+	// absPath may not exist in the working tree (an API read only at refs);
+	// its nearest existing directory locates the repository.
+	dir := absPath
+	for !isDir(dir) && filepath.Dir(dir) != dir {
+		dir = filepath.Dir(dir)
+	}
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return "", false, cleanup, fmt.Errorf("baseline %q is not a path, and %s is not in a git repository", against, absPath)
+	}
+	if out, revErr := exec.Command("git", "-C", root, "rev-parse", "--verify", "--quiet", against+"^{commit}").Output(); revErr != nil || len(bytes.TrimSpace(out)) == 0 {
+		return "", false, cleanup, fmt.Errorf("baseline %q is neither a path nor a git ref", against)
+	}
+	resolved := absPath
+	if r, e := filepath.EvalSymlinks(absPath); e == nil {
+		resolved = r
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", false, cleanup, err
+	}
+	rel = filepath.ToSlash(rel)
+
+	out, err := exec.Command("git", "-C", root, "ls-tree", "-r", "--name-only", against, "--", rel).Output()
+	if err != nil {
+		return "", false, cleanup, fmt.Errorf("listing %s at %s: %w", rel, against, err)
+	}
+	tmp, err := os.MkdirTemp("", "apx-baseline-*")
+	if err != nil {
+		return "", false, cleanup, err
+	}
+	cleanup = func() { _ = os.RemoveAll(tmp) }
+	var files []string
+	for _, f := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	for _, f := range files {
+		content, err := gitShow(root, against, f)
+		if err != nil {
+			cleanup()
+			return "", false, func() {}, err
+		}
+		dest := filepath.Join(tmp, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			cleanup()
+			return "", false, func() {}, err
+		}
+		if err := os.WriteFile(dest, content, 0o644); err != nil {
+			cleanup()
+			return "", false, func() {}, err
+		}
+	}
+	return filepath.Join(tmp, filepath.FromSlash(rel)), len(files) == 0, cleanup, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// schemaFiles returns the schema files of a format at path, keyed by their
+// path relative to it. A file path yields itself, keyed by its base name.
+func schemaFiles(format SchemaFormat, path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	if !info.IsDir() {
+		files[filepath.Base(path)] = path
+		return files, nil
+	}
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := fi.Name()
+		if fi.IsDir() {
+			if p != path && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSchemaFileFor(format, name) {
+			return nil
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = p
+		return nil
+	})
+	return files, err
+}
+
+// isSchemaFileFor reports whether a file name holds a schema of format.
+func isSchemaFileFor(format SchemaFormat, name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	switch format {
+	case FormatProto:
+		return ext == ".proto"
+	case FormatAvro:
+		return ext == ".avsc" || ext == ".avpr" || ext == ".avdl"
+	case FormatParquet:
+		return ext == ".parquet"
+	case FormatJSONSchema:
+		return ext == ".json" || ext == ".yaml" || ext == ".yml"
+	case FormatOpenAPI, FormatCRD:
+		return isSpecCandidate(name)
+	}
+	return false
+}
+
+// diffSchemaFile diffs the normalized form of two versions of a file.
+func diffSchemaFile(format SchemaFormat, oldFile, newFile string) ([]Change, error) {
+	oldTree, err := normalizedSchema(format, oldFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", filepath.Base(oldFile), err)
+	}
+	newTree, err := normalizedSchema(format, newFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(newFile), err)
+	}
+	var changes []Change
+	diffTrees(&changes, nil, oldTree, newTree)
+	return changes, nil
+}
+
+// normalizedSchema reads a schema file into a comparable tree with
+// documentation, comments and formatting removed. Proto and Parquet files
+// become flat maps from element path to declaration.
+func normalizedSchema(format SchemaFormat, file string) (interface{}, error) {
+	switch format {
+	case FormatProto:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return protoDeclarations(data), nil
+	case FormatParquet:
+		msg, err := loadParquetSchema(file)
+		if err != nil {
+			return nil, err
+		}
+		cols := map[string]interface{}{}
+		flattenParquetColumns(cols, "", msg.Columns)
+		return cols, nil
+	case FormatAvro:
+		data, err := ReadAvroJSON(file)
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return stripSchemaDocs(v, ""), nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var docs []interface{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		v, err := yamlNodeValue(&node, "", map[string]int{})
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, stripSchemaDocs(v, ""))
+	}
+	if len(docs) == 1 {
+		return docs[0], nil
+	}
+	return docs, nil
+}
+
+// schemaDocKeys are keys that document a schema without changing it.
+var schemaDocKeys = map[string]bool{
+	"doc": true, "description": true, "title": true, "summary": true,
+	"example": true, "examples": true, "$comment": true, "externalDocs": true,
+	"info": true, // OpenAPI document metadata (title, version, contact)
+}
+
+// schemaNameContainers are keys whose values map user-chosen names to
+// schemas, so a member named "description" is not documentation.
+var schemaNameContainers = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true,
+	"dependentSchemas": true, "schemas": true, "responses": true, "parameters": true,
+	"requestBodies": true, "headers": true, "paths": true, "content": true,
+	"callbacks": true, "links": true, "securitySchemes": true, "webhooks": true,
+	"variables": true,
+}
+
+// stripSchemaDocs removes documentation keys from a parsed document.
+func stripSchemaDocs(v interface{}, parentKey string) interface{} {
+	switch cur := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(cur))
+		for k, child := range cur {
+			if schemaDocKeys[k] && !schemaNameContainers[parentKey] {
+				continue
+			}
+			out[k] = stripSchemaDocs(child, k)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(cur))
+		for i, child := range cur {
+			out[i] = stripSchemaDocs(child, "")
+		}
+		return out
+	}
+	return v
+}
+
+// flattenParquetColumns maps each column's dotted path to its declaration.
+func flattenParquetColumns(into map[string]interface{}, prefix string, cols []*parquetColumn) {
+	for _, c := range cols {
+		path := jsonSchemaJoin(prefix, c.Name)
+		decl := c.Repetition + " " + c.typeLabel()
+		if c.Annotation != "" {
+			decl += " (" + c.Annotation + ")"
+		}
+		into[path] = decl
+		flattenParquetColumns(into, path, c.Children)
+	}
+}
+
+// protoDeclarations maps each proto statement to its enclosing blocks,
+// ignoring comments and whitespace. A statement is keyed by its block path
+// and its normalized text, e.g. "message Order > string id = 1".
+func protoDeclarations(data []byte) map[string]interface{} {
+	decls := map[string]interface{}{}
+	var stack []string
+	var cur []string
+	flush := func() string {
+		s := strings.Join(cur, " ")
+		cur = cur[:0]
+		return s
+	}
+	key := func(stmt string) string {
+		return strings.Join(append(append([]string{}, stack...), stmt), " > ")
+	}
+	for _, tok := range protoWords(protoTokens(data)) {
+		switch tok {
+		case ";":
+			if stmt := flush(); stmt != "" {
+				decls[key(stmt)] = ""
+			}
+		case "{":
+			header := flush()
+			decls[key(header)] = ""
+			stack = append(stack, header)
+		case "}":
+			if stmt := flush(); stmt != "" {
+				decls[key(stmt)] = ""
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		default:
+			cur = append(cur, tok)
+		}
+	}
+	return decls
+}
+
+// protoToken is a token of proto source with its line and the comments
+// attached to it, following protoc's rules: the comment block directly above
+// a token (no blank line between) leads it, and a comment after a token on
+// the same line trails it.
+type protoToken struct {
+	text     string
+	line     int
+	spaced   bool // preceded by whitespace or a comment
+	leading  string
+	trailing string
+}
+
+// protoTokens splits proto source into tokens, attaching comments.
+// Identifiers keep their dots ("google.type.Money"), string literals keep
+// their quotes, and every other punctuation character is a token.
+func protoTokens(data []byte) []protoToken {
+	src := string(data)
+	var toks []protoToken
+	var pending []string
+	pendingEnd := 0
+	line := 1
+	spaced := true
+
+	addComment := func(text string, start, end int) {
+		if n := len(toks); n > 0 && toks[n-1].line == start && len(pending) == 0 {
+			if toks[n-1].trailing == "" {
+				toks[n-1].trailing = text
+			}
+			return
+		}
+		if len(pending) > 0 && start > pendingEnd+1 {
+			pending = nil // a blank line detaches the earlier comments
+		}
+		pending = append(pending, text)
+		pendingEnd = end
+	}
+	addToken := func(text string) {
+		t := protoToken{text: text, line: line, spaced: spaced}
+		spaced = false
+		if len(pending) > 0 && pendingEnd >= line-1 {
+			t.leading = strings.Join(pending, "\n")
+		}
+		pending = nil
+		toks = append(toks, t)
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			spaced = true
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			spaced = true
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			spaced = true
+			j := strings.IndexByte(src[i:], '\n')
+			if j < 0 {
+				j = len(src) - i
+			}
+			text := strings.TrimPrefix(src[i+2:i+j], "/")
+			addComment(strings.TrimSpace(text), line, line)
+			i += j
+		case strings.HasPrefix(src[i:], "/*"):
+			spaced = true
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				j = len(src) - i - 2
+			}
+			body := src[i+2 : i+2+j]
+			start := line
+			line += strings.Count(body, "\n")
+			lines := strings.Split(body, "\n")
+			for k, l := range lines {
+				lines[k] = strings.TrimPrefix(strings.TrimSpace(l), "*")
+				lines[k] = strings.TrimSpace(lines[k])
+			}
+			addComment(strings.TrimSpace(strings.Join(lines, "\n")), start, line)
+			i += j + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			addToken(src[i : j+1])
+			i = j + 1
+		case c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || src[j] >= '0' && src[j] <= '9' ||
+				src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z') {
+				j++
+			}
+			addToken(src[i:j])
+			i = j
+		default:
+			addToken(string(c))
+			i++
+		}
+	}
+	return toks
+}
+
+// protoWords joins the tokens of proto source that are not separated by
+// whitespace or comments back into words, so that a declaration is keyed
+// by its text however it is commented. String literals and ;, { and } are
+// words of their own.
+func protoWords(toks []protoToken) []string {
+	var words []string
+	glue := false
+	for _, t := range toks {
+		sep := t.text == ";" || t.text == "{" || t.text == "}" || t.text[0] == '"' || t.text[0] == '\''
+		if glue && !t.spaced && !sep {
+			words[len(words)-1] += t.text
+		} else {
+			words = append(words, t.text)
+		}
+		glue = !sep
+	}
+	return words
+}
+
+// diffTrees records the differences between two normalized trees. Maps are
+// compared by key, arrays of named objects (Avro fields, OpenAPI
+// parameters) by name, arrays of scalars (enums, required lists) as sets,
+// and other arrays by position.
+func diffTrees(changes *[]Change, elems []string, oldV, newV interface{}) {
+	if reflect.DeepEqual(oldV, newV) {
+		return
+	}
+	add := func(kind string, elems []string, oldVal, newVal string) {
+		*changes = append(*changes, Change{Kind: kind, Path: joinElems(elems), Old: oldVal, New: newVal, elems: elems})
+	}
+	switch o := oldV.(type) {
+	case map[string]interface{}:
+		n, ok := newV.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(o) {
+			child := appendElem(elems, k)
+			if nv, ok := n[k]; ok {
+				diffTrees(changes, child, o[k], nv)
+			} else {
+				add("removed", child, scalarLabel(o[k]), "")
+			}
+		}
+		for _, k := range sortedKeys(n) {
+			if _, ok := o[k]; !ok {
+				add("added", appendElem(elems, k), "", scalarLabel(n[k]))
+			}
+		}
+		return
+	case []interface{}:
+		n, ok := newV.([]interface{})
+		if !ok {
+			break
+		}
+		if oldNamed, newNamed := namedElements(o), namedElements(n); oldNamed != nil && newNamed != nil {
+			diffTrees(changes, elems, oldNamed, newNamed)
+			return
+		}
+		if allScalars(o) && allScalars(n) {
+			oldSet, newSet := scalarSet(o), scalarSet(n)
+			for _, k := range sortedKeys(oldSet) {
+				if _, ok := newSet[k]; !ok {
+					add("removed", elems, k, "")
+				}
+			}
+			for _, k := range sortedKeys(newSet) {
+				if _, ok := oldSet[k]; !ok {
+					add("added", elems, "", k)
+				}
+			}
+			return
+		}
+		for i := 0; i < len(o) || i < len(n); i++ {
+			child := appendElem(elems, fmt.Sprintf("[%d]", i))
+			switch {
+			case i >= len(n):
+				add("removed", child, "", "")
+			case i >= len(o):
+				add("added", child, "", "")
+			default:
+				diffTrees(changes, child, o[i], n[i])
+			}
+		}
+		return
+	}
+	add("changed", elems, scalarLabel(oldV), scalarLabel(newV))
+}
+
+// appendElem returns elems with elem appended, without sharing the backing
+// array of elems.
+func appendElem(elems []string, elem string) []string {
+	return append(append(make([]string, 0, len(elems)+1), elems...), elem)
+}
+
+// joinElems renders elems as a dotted path; [i] indexes attach to the
+// element before them.
+func joinElems(elems []string) string {
+	var b strings.Builder
+	for _, e := range elems {
+		if b.Len() > 0 && !strings.HasPrefix(e, "[") {
+			b.WriteByte('.')
+		}
+		b.WriteString(e)
+	}
+	return b.String()
+}
+
+// namedElements keys an array of objects by their "name" (and "in", for
+// OpenAPI parameters). It returns nil unless every element is named and
+// names are unique.
+func namedElements(arr []interface{}) map[string]interface{} {
+	if len(arr) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(arr))
+	for _, e := range arr {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		name, ok := m["name"].(string)
+		if !ok {
+			return nil
+		}
+		if in, ok := m["in"].(string); ok {
+			name = in + ":" + name
+		}
+		if _, dup := out[name]; dup {
+			return nil
+		}
+		out[name] = e
+	}
+	return out
+}
+
+func allScalars(arr []interface{}) bool {
+	for _, e := range arr {
+		switch e.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func scalarSet(arr []interface{}) map[string]bool {
+	set := make(map[string]bool, len(arr))
+	for _, e := range arr {
+		set[scalarLabel(e)] = true
+	}
+	return set
+}
+
+// scalarLabel renders a scalar for a change; composite values render empty.
+func scalarLabel(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassifyChanges_Files(t *testing.T) {
+	const base = `{"type":"record","name":"User","doc":"A user","fields":[{"name":"id","type":"string"}]}`
+	tests := []struct {
+		name      string
+		format    SchemaFormat
+		ext       string
+		old, new  string
+		wantLevel ChangeLevel
+	}{
+		{
+			name: "avro docs only", format: FormatAvro, ext: ".avsc", old: base,
+			new:       `{"type":"record","name":"User","doc":"A registered user","fields":[{"name":"id","type":"string","doc":"ID"}]}`,
+			wantLevel: ChangeNone,
+		},
+		{
+			name: "avro optional field", format: FormatAvro, ext: ".avsc", old: base,
+			new:       `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"email","type":["null","string"],"default":null}]}`,
+			wantLevel: ChangeAdditive,
+		},
+		{
+			name: "avro required field", format: FormatAvro, ext: ".avsc", old: base,
+			new:       `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"email","type":"string"}]}`,
+			wantLevel: ChangeBreaking,
+		},
+		{
+			name: "json schema property named description", format: FormatJSONSchema, ext: ".json",
+			old:       `{"type":"object","description":"x","properties":{"id":{"type":"string"}}}`,
+			new:       `{"type":"object","description":"y","properties":{"id":{"type":"string"},"description":{"type":"string"}}}`,
+			wantLevel: ChangeAdditive,
+		},
+		{
+			name: "parquet column comment and order", format: FormatParquet, ext: ".parquet",
+			old:       "message m {\n  required binary id (STRING);\n  optional int32 n;\n}\n",
+			new:       "message m {\n  optional int32 n;\n  required binary id (STRING);\n}\n",
+			wantLevel: ChangeNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldFile := filepath.Join(dir, "old"+tt.ext)
+			newFile := filepath.Join(dir, "new"+tt.ext)
+			mustWrite(t, oldFile, tt.old)
+			mustWrite(t, newFile, tt.new)
+			report, err := NewValidator(NewToolchainResolver()).ClassifyChanges(newFile, oldFile, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Level != tt.wantLevel {
+				t.Errorf("level = %s, want %s (changes %v, findings %v)", report.Level, tt.wantLevel, report.Changes, report.Findings)
+			}
+			if tt.wantLevel == ChangeNone && len(report.Changes) != 0 {
+				t.Errorf("expected no changes, got %v", report.Changes)
+			}
+		})
+	}
+}
+
+func TestClassifyChanges_GitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	module := filepath.Join(dir, "jsonschema", "users", "v1")
+	if err := os.MkdirAll(module, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, filepath.Join(module, "user.json"), `{"type":"object","properties":{"id":{"type":"string"}}}`)
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-qm", "v1")
+	git("tag", "v1.0.0")
+
+	v := NewValidator(NewToolchainResolver())
+	report, err := v.ClassifyChanges(module, "v1.0.0", FormatJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != ChangeNone {
+		t.Fatalf("unchanged module: level %s, changes %v", report.Level, report.Changes)
+	}
+
+	mustWrite(t, filepath.Join(module, "user.json"), `{"type":"object","properties":{"id":{"type":"string"},"name":{"type":"string"}}}`)
+	mustWrite(t, filepath.Join(module, "group.json"), `{"type":"object"}`)
+	report, err = v.ClassifyChanges(module, "v1.0.0", FormatJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != ChangeAdditive {
+		t.Fatalf("level %s, want additive (findings %v)", report.Level, report.Findings)
+	}
+	want := map[string]bool{"added group.json": true, "added user.json: properties.name": true}
+	for _, c := range report.Changes {
+		if !want[c.String()] {
+			t.Errorf("unexpected change %q", c)
+		}
+		delete(want, c.String())
+	}
+	for c := range want {
+		t.Errorf("missing change %q", c)
+	}
+
+	if err := os.Remove(filepath.Join(module, "user.json")); err != nil {
+		t.Fatal(err)
+	}
+	report, err = v.ClassifyChanges(module, "v1.0.0", FormatJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != ChangeBreaking {
+		t.Fatalf("removed file: level %s, want breaking", report.Level)
+	}
+
+	if _, err := v.ClassifyChanges(module, "no-such-ref", FormatJSONSchema); err == nil {
+		t.Error("expected an error for an unknown baseline")
+	}
+}
+
+func TestProtoDeclarations(t *testing.T) {
+	old := []byte(`syntax = "proto3";
+// Orders.
+message Order {
+  string id = 1; // the id
+}
+`)
+	same := []byte(`syntax = "proto3";
+
+/* An order. */
+message Order { string id = 1; }
+`)
+	added := []byte(`syntax = "proto3";
+message Order {
+  string id = 1;
+  string note = 2;
+}
+`)
+	var changes []Change
+	diffTrees(&changes, nil, protoDeclarations(old), protoDeclarations(same))
+	if len(changes) != 0 {
+		t.Errorf("comment and layout changes: got %v", changes)
+	}
+	diffTrees(&changes, nil, protoDeclarations(old), protoDeclarations(added))
+	if len(changes) != 1 || changes[0].Kind != "added" || changes[0].Path != "message Order > string note = 2" {
+		t.Errorf("got %v", changes)
+	}
+}
+
+func TestProtoWords(t *testing.T) {
+	src := []byte("message A { // c\n  map<string,int64> m=1 [deprecated=true];\n  option (x).y = \"a b\";/* c */int32 n = 2;\n}\n")
+	want := []string{"message", "A", "{", "map<string,int64>", "m=1", "[deprecated=true]", ";",
+		"option", "(x).y", "=", `"a b"`, ";", "int32", "n", "=", "2", ";", "}"}
+	got := protoWords(protoTokens(src))
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("protoWords = %q, want %q", got, want)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -273,66 +272,79 @@ var validCRDTypes = map[string]bool{
 // Lint validates a CRD manifest against Kubernetes structural-schema rules and
 // CRD conventions. It collects all violations and returns them together.
 func (v *CRDValidator) Lint(path string) error {
+	findings, err := v.LintFindings(path)
+	return findingsError("CRD lint errors", findings, err)
+}
+
+// LintFindings validates a CRD manifest and returns every violation as a
+// Finding. The error is non-nil only when no CRD could be loaded.
+func (v *CRDValidator) LintFindings(path string) ([]Finding, error) {
 	doc, file, err := loadCRD(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var violations []string
-	add := func(format string, args ...interface{}) {
-		violations = append(violations, fmt.Sprintf(format, args...))
+	var findings []Finding
+	add := func(rule, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     file,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatCRD,
+			Message:  fmt.Sprintf(format, args...),
+		})
 	}
 
 	// --- Envelope ---
 	if doc.APIVersion != "apiextensions.k8s.io/v1" {
 		if strings.HasPrefix(doc.APIVersion, "apiextensions.k8s.io/v1beta1") {
-			add("apiVersion apiextensions.k8s.io/v1beta1 is not supported; migrate the CRD to apiextensions.k8s.io/v1")
+			add("crd-api-version", "apiVersion apiextensions.k8s.io/v1beta1 is not supported; migrate the CRD to apiextensions.k8s.io/v1")
 		} else {
-			add("apiVersion must be apiextensions.k8s.io/v1, got %q", doc.APIVersion)
+			add("crd-api-version", "apiVersion must be apiextensions.k8s.io/v1, got %q", doc.APIVersion)
 		}
 	}
 
 	spec := doc.Spec
 	if spec.Group == "" {
-		add("spec.group is required")
+		add("crd-group", "spec.group is required")
 	} else if !strings.Contains(spec.Group, ".") {
-		add("spec.group %q should be a DNS subdomain (e.g. example.com)", spec.Group)
+		add("crd-group", "spec.group %q should be a DNS subdomain (e.g. example.com)", spec.Group)
 	}
 	if spec.Names.Kind == "" {
-		add("spec.names.kind is required")
+		add("crd-names", "spec.names.kind is required")
 	}
 	if spec.Names.Plural == "" {
-		add("spec.names.plural is required")
+		add("crd-names", "spec.names.plural is required")
 	} else if spec.Names.Plural != strings.ToLower(spec.Names.Plural) {
-		add("spec.names.plural %q must be lowercase", spec.Names.Plural)
+		add("crd-names", "spec.names.plural %q must be lowercase", spec.Names.Plural)
 	}
 	if spec.Scope != "Namespaced" && spec.Scope != "Cluster" {
-		add("spec.scope must be Namespaced or Cluster, got %q", spec.Scope)
+		add("crd-scope", "spec.scope must be Namespaced or Cluster, got %q", spec.Scope)
 	}
 	// metadata.name must be <plural>.<group>
 	if spec.Names.Plural != "" && spec.Group != "" {
 		want := spec.Names.Plural + "." + spec.Group
 		if doc.Metadata.Name != want {
-			add("metadata.name must be %q (<plural>.<group>), got %q", want, doc.Metadata.Name)
+			add("crd-metadata-name", "metadata.name must be %q (<plural>.<group>), got %q", want, doc.Metadata.Name)
 		}
 	}
 
 	// --- Versions ---
 	if len(spec.Versions) == 0 {
-		add("spec.versions must declare at least one version")
+		add("crd-versions", "spec.versions must declare at least one version")
 	}
 	storageCount := 0
 	servedCount := 0
 	seenNames := map[string]bool{}
 	for i, ver := range spec.Versions {
 		if ver.Name == "" {
-			add("spec.versions[%d].name is required", i)
+			add("crd-version-name", "spec.versions[%d].name is required", i)
 		} else {
 			if !IsCRDVersion(ver.Name) {
-				add("spec.versions[%d].name %q is not a valid Kubernetes version (want v<major>[alpha|beta<n>])", i, ver.Name)
+				add("crd-version-name", "spec.versions[%d].name %q is not a valid Kubernetes version (want v<major>[alpha|beta<n>])", i, ver.Name)
 			}
 			if seenNames[ver.Name] {
-				add("spec.versions[%d].name %q is duplicated", i, ver.Name)
+				add("crd-version-name", "spec.versions[%d].name %q is duplicated", i, ver.Name)
 			}
 			seenNames[ver.Name] = true
 		}
@@ -344,37 +356,34 @@ func (v *CRDValidator) Lint(path string) error {
 		}
 		// Structural schema is required per version in apiextensions v1.
 		if ver.Schema.OpenAPIV3Schema == nil {
-			add("spec.versions[%d] (%s) is missing schema.openAPIV3Schema", i, ver.Name)
+			add("crd-schema-missing", "spec.versions[%d] (%s) is missing schema.openAPIV3Schema", i, ver.Name)
 			continue
 		}
 		root := ver.Schema.OpenAPIV3Schema
 		if root.Type != "object" {
-			add("spec.versions[%d] (%s): root schema type must be object, got %q", i, ver.Name, root.Type)
+			add("crd-root-type", "spec.versions[%d] (%s): root schema type must be object, got %q", i, ver.Name, root.Type)
 		}
 		ctx := &structuralCtx{version: ver.Name, add: add}
 		checkStructural(root, fmt.Sprintf("versions[%d](%s).openAPIV3Schema", i, ver.Name), ctx)
 	}
 	if len(spec.Versions) > 0 {
 		if storageCount == 0 {
-			add("exactly one version must set storage: true (found none)")
+			add("crd-storage-version", "exactly one version must set storage: true (found none)")
 		} else if storageCount > 1 {
-			add("exactly one version must set storage: true (found %d)", storageCount)
+			add("crd-storage-version", "exactly one version must set storage: true (found %d)", storageCount)
 		}
 		if servedCount == 0 {
-			add("at least one version must set served: true")
+			add("crd-served-version", "at least one version must set served: true")
 		}
 	}
 
-	if len(violations) > 0 {
-		sort.Strings(violations)
-		return fmt.Errorf("CRD lint errors in %s:\n  - %s", file, strings.Join(violations, "\n  - "))
-	}
-	return nil
+	SortFindings(findings)
+	return findings, nil
 }
 
 type structuralCtx struct {
 	version string
-	add     func(string, ...interface{})
+	add     func(rule, format string, args ...interface{})
 }
 
 // checkStructural walks a schema node enforcing the core Kubernetes structural
@@ -393,15 +402,15 @@ func checkStructural(s *jsonSchema, path string, ctx *structuralCtx) {
 
 	if s.Type == "" {
 		if !escape && len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 {
-			ctx.add("%s: missing type (every structural schema node needs a type, or x-kubernetes-preserve-unknown-fields / x-kubernetes-int-or-string)", path)
+			ctx.add("crd-structural-type", "%s: missing type (every structural schema node needs a type, or x-kubernetes-preserve-unknown-fields / x-kubernetes-int-or-string)", path)
 		}
 	} else if !validCRDTypes[s.Type] {
-		ctx.add("%s: invalid type %q", path, s.Type)
+		ctx.add("crd-structural-type", "%s: invalid type %q", path, s.Type)
 	}
 
 	if s.Type == "object" {
 		if len(s.Properties) > 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
-			ctx.add("%s: properties and additionalProperties (schema form) are mutually exclusive in a structural schema", path)
+			ctx.add("crd-structural-properties", "%s: properties and additionalProperties (schema form) are mutually exclusive in a structural schema", path)
 		}
 		for name, prop := range s.Properties {
 			checkStructural(prop, path+".properties."+name, ctx)
@@ -413,7 +422,7 @@ func checkStructural(s *jsonSchema, path string, ctx *structuralCtx) {
 
 	if s.Type == "array" {
 		if s.Items == nil && !escape {
-			ctx.add("%s: array must specify items", path)
+			ctx.add("crd-structural-items", "%s: array must specify items", path)
 		}
 		checkStructural(s.Items, path+".items", ctx)
 	}
@@ -452,23 +461,37 @@ func checkStructural(s *jsonSchema, path string, ctx *structuralCtx) {
 // Conversion-webhook and storage-migration behavior is out of scope (the
 // catalog captures the declared contract, not runtime behavior).
 func (v *CRDValidator) Breaking(path, against string) error {
-	newDoc, _, err := loadCRD(path)
+	findings, err := v.BreakingFindings(path, against)
+	return findingsError("breaking changes detected (served-version incompatibility)", findings, err)
+}
+
+// BreakingFindings checks for served-version incompatibilities between two CRD
+// manifests and returns each one as a Finding.
+func (v *CRDValidator) BreakingFindings(path, against string) ([]Finding, error) {
+	newDoc, file, err := loadCRD(path)
 	if err != nil {
-		return fmt.Errorf("reading new CRD: %w", err)
+		return nil, fmt.Errorf("reading new CRD: %w", err)
 	}
 	oldDoc, _, err := loadCRD(against)
 	if err != nil {
 		// No baseline (e.g. first release, or a git-tag path that is not a
 		// file) — nothing to compare against.
 		if errors.Is(err, os.ErrNotExist) || strings.Contains(err.Error(), "no CustomResourceDefinition") {
-			return nil
+			return nil, nil
 		}
-		return fmt.Errorf("reading baseline CRD: %w", err)
+		return nil, fmt.Errorf("reading baseline CRD: %w", err)
 	}
 
-	var breaking []string
-	add := func(format string, args ...interface{}) {
-		breaking = append(breaking, fmt.Sprintf(format, args...))
+	var findings []Finding
+	add := func(rule, elem, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     file,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatCRD,
+			Path:     elem,
+			Message:  fmt.Sprintf(format, args...),
+		})
 	}
 
 	oldVersions := map[string]crdVersion{}
@@ -489,11 +512,11 @@ func (v *CRDValidator) Breaking(path, against string) error {
 			continue
 		}
 		if _, ok := newVersions[name]; !ok {
-			add("served version %s was removed", name)
+			add("crd-version-removed", name, "served version %s was removed", name)
 			continue
 		}
 		if newVer := newVersions[name]; !newVer.isServed() {
-			add("version %s is no longer served", name)
+			add("crd-version-unserved", name, "version %s is no longer served", name)
 		}
 	}
 
@@ -522,17 +545,16 @@ func (v *CRDValidator) Breaking(path, against string) error {
 		)
 	}
 
-	if len(breaking) > 0 {
-		sort.Strings(breaking)
-		return fmt.Errorf("breaking changes detected (served-version incompatibility):\n  - %s",
-			strings.Join(breaking, "\n  - "))
-	}
-	return nil
+	SortFindings(findings)
+	return findings, nil
 }
 
+// findingFunc records a breaking-change finding for the schema element elem.
+type findingFunc func(rule, elem, format string, args ...interface{})
+
 // diffSchema compares an old and new schema node and records breaking changes.
 // It walks required fields, properties, items, and per-field constraints.
-func diffSchema(oldS, newS *jsonSchema, path string, add func(string, ...interface{})) {
+func diffSchema(oldS, newS *jsonSchema, path string, add findingFunc) {
 	if oldS == nil || newS == nil {
 		return
 	}
@@ -540,19 +562,19 @@ func diffSchema(oldS, newS *jsonSchema, path string, add func(string, ...interfa
 	// Type narrowing: a changed type is breaking. int-or-string relaxations
 	// (adding the extension) are additive and handled below.
 	if oldS.Type != "" && newS.Type != "" && oldS.Type != newS.Type {
-		add("%s: type changed from %q to %q", path, oldS.Type, newS.Type)
+		add("crd-type-changed", path, "%s: type changed from %q to %q", path, oldS.Type, newS.Type)
 	}
 
 	// Dropping preserve-unknown-fields tightens the contract.
 	if oldS.preservesUnknown() && !newS.preservesUnknown() {
-		add("%s: x-kubernetes-preserve-unknown-fields was removed (previously accepted fields may now be pruned/rejected)", path)
+		add("crd-preserve-unknown-removed", path, "%s: x-kubernetes-preserve-unknown-fields was removed (previously accepted fields may now be pruned/rejected)", path)
 	}
 
 	// New required fields are breaking.
 	oldReq := stringSet(oldS.Required)
 	for _, r := range newS.Required {
 		if !oldReq[r] {
-			add("%s.%s: became required", path, r)
+			add("crd-field-required", path+"."+r, "%s.%s: became required", path, r)
 		}
 	}
 
@@ -562,7 +584,7 @@ func diffSchema(oldS, newS *jsonSchema, path string, add func(string, ...interfa
 		if !ok {
 			// Removal only matters if the parent did not open up to unknown fields.
 			if !newS.preservesUnknown() {
-				add("%s.%s: field was removed", path, name)
+				add("crd-field-removed", path+"."+name, "%s.%s: field was removed", path, name)
 			}
 			continue
 		}
@@ -585,45 +607,45 @@ func diffSchema(oldS, newS *jsonSchema, path string, add func(string, ...interfa
 
 // diffConstraints records tightened validation constraints, which reject
 // payloads that the old schema accepted.
-func diffConstraints(oldS, newS *jsonSchema, path string, add func(string, ...interface{})) {
+func diffConstraints(oldS, newS *jsonSchema, path string, add findingFunc) {
 	// String length.
 	if tightenedUpperInt(oldS.MaxLength, newS.MaxLength) {
-		add("%s: maxLength tightened (%s → %s)", path, i64s(oldS.MaxLength), i64s(newS.MaxLength))
+		add("crd-constraint-tightened", path, "%s: maxLength tightened (%s → %s)", path, i64s(oldS.MaxLength), i64s(newS.MaxLength))
 	}
 	if tightenedLowerInt(oldS.MinLength, newS.MinLength) {
-		add("%s: minLength tightened (%s → %s)", path, i64s(oldS.MinLength), i64s(newS.MinLength))
+		add("crd-constraint-tightened", path, "%s: minLength tightened (%s → %s)", path, i64s(oldS.MinLength), i64s(newS.MinLength))
 	}
 	// Array length.
 	if tightenedUpperInt(oldS.MaxItems, newS.MaxItems) {
-		add("%s: maxItems tightened (%s → %s)", path, i64s(oldS.MaxItems), i64s(newS.MaxItems))
+		add("crd-constraint-tightened", path, "%s: maxItems tightened (%s → %s)", path, i64s(oldS.MaxItems), i64s(newS.MaxItems))
 	}
 	if tightenedLowerInt(oldS.MinItems, newS.MinItems) {
-		add("%s: minItems tightened (%s → %s)", path, i64s(oldS.MinItems), i64s(newS.MinItems))
+		add("crd-constraint-tightened", path, "%s: minItems tightened (%s → %s)", path, i64s(oldS.MinItems), i64s(newS.MinItems))
 	}
 	// Numeric bounds.
 	if tightenedUpperFloat(oldS.Maximum, newS.Maximum) {
-		add("%s: maximum tightened (%s → %s)", path, f64s(oldS.Maximum), f64s(newS.Maximum))
+		add("crd-constraint-tightened", path, "%s: maximum tightened (%s → %s)", path, f64s(oldS.Maximum), f64s(newS.Maximum))
 	}
 	if tightenedLowerFloat(oldS.Minimum, newS.Minimum) {
-		add("%s: minimum tightened (%s → %s)", path, f64s(oldS.Minimum), f64s(newS.Minimum))
+		add("crd-constraint-tightened", path, "%s: minimum tightened (%s → %s)", path, f64s(oldS.Minimum), f64s(newS.Minimum))
 	}
 	// Pattern: adding or changing a regex can reject previously-valid values.
 	if oldS.Pattern != newS.Pattern && newS.Pattern != "" {
 		if oldS.Pattern == "" {
-			add("%s: pattern %q was added", path, newS.Pattern)
+			add("crd-pattern-changed", path, "%s: pattern %q was added", path, newS.Pattern)
 		} else {
-			add("%s: pattern changed (%q → %q)", path, oldS.Pattern, newS.Pattern)
+			add("crd-pattern-changed", path, "%s: pattern changed (%q → %q)", path, oldS.Pattern, newS.Pattern)
 		}
 	}
 	// Enum: adding an enum where none existed, or removing values, is breaking.
 	diffEnum(oldS, newS, path, add)
 }
 
-func diffEnum(oldS, newS *jsonSchema, path string, add func(string, ...interface{})) {
+func diffEnum(oldS, newS *jsonSchema, path string, add findingFunc) {
 	oldEnum := enumSet(oldS.Enum)
 	newEnum := enumSet(newS.Enum)
 	if len(oldEnum) == 0 && len(newEnum) > 0 {
-		add("%s: enum constraint was added (previously any value was allowed)", path)
+		add("crd-enum-added", path, "%s: enum constraint was added (previously any value was allowed)", path)
 		return
 	}
 	if len(oldEnum) == 0 {
@@ -631,7 +653,7 @@ func diffEnum(oldS, newS *jsonSchema, path string, add func(string, ...interface
 	}
 	for val := range oldEnum {
 		if !newEnum[val] {
-			add("%s: enum value %s was removed", path, val)
+			add("crd-enum-value-removed", path, "%s: enum value %s was removed", path, val)
 		}
 	}
 }
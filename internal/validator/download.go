@@ -7,15 +7,20 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 )
 
-// toolSpec describes how to download a tool from GitHub releases.
+// toolSpec describes how to download a tool from GitHub releases, or how to
+// build or install it when it publishes no binaries.
 type toolSpec struct {
 	// Org/Repo on GitHub (e.g. "bufbuild/buf").
 	repo string
+	// url returns the download URL of a release asset. If nil, the asset is
+	// downloaded from the GitHub release of repo.
+	url func(version, asset string) string
 	// assetName returns the release asset filename for a version/os/arch
 	// combination, or "" when that platform is not published. Asset naming
 	// is tool-specific (each project's release pipeline picks its own OS and
@@ -23,6 +28,12 @@ type toolSpec struct {
 	assetName func(version, goos, goarch string) string
 	// binaryName inside the archive. If empty, defaults to the tool name.
 	binaryName string
+	// goModule is the Go package the tool is built from with `go install`,
+	// for tools that publish no binaries.
+	goModule string
+	// npmPackage is the npm package the tool is installed from. An npm
+	// install is a node_modules tree that runs on any platform with Node.js.
+	npmPackage string
 }
 
 // toolRegistry maps tool names to their download specs.
@@ -37,6 +48,43 @@ var toolRegistry = map[string]toolSpec{
 		assetName:  oasdiffAssetName,
 		binaryName: "oasdiff",
 	},
+	"spectral": {
+		repo:       "stoplightio/spectral",
+		assetName:  spectralAssetName,
+		binaryName: "spectral",
+	},
+	"helm": {
+		url:        func(_, asset string) string { return "https://get.helm.sh/" + asset },
+		assetName:  helmAssetName,
+		binaryName: "helm",
+	},
+	"oapi-codegen": {
+		goModule:   "github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen",
+		binaryName: "oapi-codegen",
+	},
+	"ng-openapi-gen": {
+		npmPackage: "ng-openapi-gen",
+		binaryName: "ng-openapi-gen",
+	},
+	"protoc-gen-go": {
+		goModule: "google.golang.org/protobuf/cmd/protoc-gen-go",
+	},
+	"protoc-gen-go-grpc": {
+		goModule: "google.golang.org/grpc/cmd/protoc-gen-go-grpc",
+	},
+	"protoc-gen-connect-go": {
+		goModule: "connectrpc.com/connect/cmd/protoc-gen-connect-go",
+	},
+}
+
+// RegisterGoTool registers a tool that is built from a Go package with
+// `go install`, such as a protoc plugin named in language_targets. A tool
+// that is already registered keeps its source.
+func RegisterGoTool(name, module string) {
+	if _, ok := toolRegistry[name]; ok {
+		return
+	}
+	toolRegistry[name] = toolSpec{goModule: module}
 }
 
 // bufAssetName maps to buf's release assets: buf-{OS}-{arch}[.tar.gz|.exe].
@@ -92,6 +140,46 @@ func oasdiffAssetName(version, goos, goarch string) string {
 	}
 }
 
+// spectralAssetName maps to spectral's release assets: bare binaries named
+// spectral-{linux|macos}-{x64|arm64}, and a single x64 spectral-windows.exe.
+func spectralAssetName(_, goos, goarch string) string {
+	var arch string
+	switch goarch {
+	case "amd64":
+		arch = "x64"
+	case "arm64":
+		arch = "arm64"
+	default:
+		return ""
+	}
+	switch goos {
+	case "linux":
+		return "spectral-linux-" + arch
+	case "darwin":
+		return "spectral-macos-" + arch
+	case "windows":
+		if goarch == "amd64" {
+			return "spectral-windows.exe"
+		}
+	}
+	return ""
+}
+
+// helmAssetName maps to helm's release archives on get.helm.sh:
+// helm-{version}-{os}-{arch}.tar.gz with Go-style names. Windows ships zip
+// archives, which are not supported.
+func helmAssetName(version, goos, goarch string) string {
+	switch goos {
+	case "linux", "darwin":
+	default:
+		return ""
+	}
+	if goarch != "amd64" && goarch != "arm64" {
+		return ""
+	}
+	return fmt.Sprintf("helm-%s-%s-%s.tar.gz", version, goos, goarch)
+}
+
 // cacheDir returns the directory where downloaded tools are cached.
 func cacheDir(name, version string) string {
 	home, err := os.UserHomeDir()
@@ -101,36 +189,66 @@ func cacheDir(name, version string) string {
 	return filepath.Join(home, ".apx", "tools", name, version)
 }
 
+// binaryPath returns the path of a tool's binary in dir, an install
+// directory for goos: the binary itself, or the entry point of an npm
+// install tree.
+func binaryPath(name, dir, goos string) string {
+	spec := toolRegistry[name]
+	binName := spec.binaryName
+	if binName == "" {
+		binName = name
+	}
+	if spec.npmPackage != "" {
+		return filepath.Join(dir, "node_modules", ".bin", binName)
+	}
+	if goos == "windows" {
+		binName += ".exe"
+	}
+	return filepath.Join(dir, binName)
+}
+
 // downloadTool downloads a tool binary from GitHub releases and caches it.
 // Returns the path to the cached binary.
 func downloadTool(name, version string) (string, error) {
-	spec, ok := toolRegistry[name]
-	if !ok {
+	if _, ok := toolRegistry[name]; !ok {
 		return "", fmt.Errorf("no download source registered for tool: %s", name)
 	}
 
 	dir := cacheDir(name, version)
-	binName := spec.binaryName
-	if binName == "" {
-		binName = name
-	}
-	if runtime.GOOS == "windows" {
-		binName += ".exe"
-	}
-	binPath := filepath.Join(dir, binName)
+	binPath := binaryPath(name, dir, runtime.GOOS)
 
 	// Already cached?
 	if _, err := os.Stat(binPath); err == nil {
 		return binPath, nil
 	}
 
-	goos := runtime.GOOS
-	goarch := runtime.GOARCH
+	return installTool(name, version, runtime.GOOS, runtime.GOARCH, dir)
+}
+
+// installTool downloads, builds or installs a tool for a platform into dir
+// and returns the path to its binary.
+func installTool(name, version, goos, goarch, dir string) (string, error) {
+	spec, ok := toolRegistry[name]
+	if !ok {
+		return "", fmt.Errorf("no download source registered for tool: %s", name)
+	}
+	binPath := binaryPath(name, dir, goos)
+
+	switch {
+	case spec.goModule != "":
+		return binPath, goInstall(spec.goModule, version, goos, goarch, binPath)
+	case spec.npmPackage != "":
+		return binPath, npmInstall(spec.npmPackage, version, dir)
+	}
+
 	archive := spec.assetName(version, goos, goarch)
 	if archive == "" {
 		return "", fmt.Errorf("%s has no published release asset for %s/%s", name, goos, goarch)
 	}
 	url := fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", spec.repo, version, archive)
+	if spec.url != nil {
+		url = spec.url(version, archive)
+	}
 
 	resp, err := http.Get(url)
 	if err != nil {
@@ -147,7 +265,7 @@ func downloadTool(name, version string) (string, error) {
 	}
 
 	if strings.HasSuffix(archive, ".tar.gz") || strings.HasSuffix(archive, ".tgz") {
-		if err := extractFromTarGz(resp.Body, binName, binPath); err != nil {
+		if err := extractFromTarGz(resp.Body, filepath.Base(binPath), binPath); err != nil {
 			return "", fmt.Errorf("extracting %s from archive: %w", name, err)
 		}
 	} else {
@@ -164,6 +282,71 @@ func downloadTool(name, version string) (string, error) {
 	return binPath, nil
 }
 
+// goInstall builds a Go tool for a platform with `go install` and moves the
+// binary to binPath. The build uses a scratch GOPATH, since go install
+// refuses to put cross-compiled binaries in GOBIN, but shares the module
+// cache. -trimpath and -buildvcs=false keep build paths and VCS stamps out
+// of the binary, so that builds with the same Go toolchain match the
+// checksums pinned in apx.lock.
+func goInstall(module, version, goos, goarch, binPath string) error {
+	modCache, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return fmt.Errorf("building %s needs the Go toolchain: %w", module, err)
+	}
+	gopath, err := os.MkdirTemp("", "apx-go-install-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(gopath)
+
+	cmd := exec.Command("go", "install", "-trimpath", "-buildvcs=false", module+"@"+version)
+	cmd.Env = append(os.Environ(),
+		"GOPATH="+gopath,
+		"GOMODCACHE="+strings.TrimSpace(string(modCache)),
+		"GOBIN=",
+		"GOOS="+goos,
+		"GOARCH="+goarch,
+		"CGO_ENABLED=0",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("building %s@%s for %s/%s: %w\n%s", module, version, goos, goarch, err, out)
+	}
+
+	// go install puts host binaries in bin/ and cross-compiled ones in
+	// bin/<os>_<arch>/.
+	built := filepath.Join(gopath, "bin", filepath.Base(binPath))
+	if _, err := os.Stat(built); err != nil {
+		built = filepath.Join(gopath, "bin", goos+"_"+goarch, filepath.Base(binPath))
+	}
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	in, err := os.Open(built)
+	if err != nil {
+		return fmt.Errorf("building %s: %w", module, err)
+	}
+	defer in.Close()
+	if err := downloadToFile(in, binPath); err != nil {
+		return err
+	}
+	return os.Chmod(binPath, 0755)
+}
+
+// npmInstall installs an npm package, and its dependencies, into
+// dir/node_modules.
+func npmInstall(pkg, version, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	cmd := exec.Command("npm", "install", "--prefix", dir, "--no-save", "--no-audit", "--no-fund",
+		pkg+"@"+strings.TrimPrefix(version, "v"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("installing %s@%s with npm: %w\n%s", pkg, version, err, out)
+	}
+	return nil
+}
+
 // extractFromTarGz extracts a single file from a tar.gz archive.
 func extractFromTarGz(r io.Reader, targetName, destPath string) error {
 	gz, err := gzip.NewReader(r)
@@ -59,6 +59,73 @@ func TestOasdiffAssetName(t *testing.T) {
 	}
 }
 
+func TestSpectralAssetName(t *testing.T) {
+	tests := []struct {
+		goos, goarch, want string
+	}{
+		{"linux", "amd64", "spectral-linux-x64"},
+		{"linux", "arm64", "spectral-linux-arm64"},
+		{"darwin", "arm64", "spectral-macos-arm64"},
+		{"windows", "amd64", "spectral-windows.exe"},
+		{"windows", "arm64", ""},
+		{"freebsd", "amd64", ""},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, spectralAssetName("v6.15.0", tt.goos, tt.goarch), "%s/%s", tt.goos, tt.goarch)
+	}
+}
+
+func TestHelmAssetName(t *testing.T) {
+	require.Equal(t, "helm-v3.17.3-linux-arm64.tar.gz", helmAssetName("v3.17.3", "linux", "arm64"))
+	require.Equal(t, "helm-v3.17.3-darwin-amd64.tar.gz", helmAssetName("v3.17.3", "darwin", "amd64"))
+	require.Equal(t, "", helmAssetName("v3.17.3", "windows", "amd64"))
+	require.Equal(t, "https://get.helm.sh/helm-v3.17.3-linux-amd64.tar.gz",
+		toolRegistry["helm"].url("v3.17.3", "helm-v3.17.3-linux-amd64.tar.gz"))
+}
+
+func TestBinaryPath(t *testing.T) {
+	require.Equal(t, filepath.Join("d", "buf"), binaryPath("buf", "d", "linux"))
+	require.Equal(t, filepath.Join("d", "buf.exe"), binaryPath("buf", "d", "windows"))
+	require.Equal(t, filepath.Join("d", "node_modules", ".bin", "ng-openapi-gen"), binaryPath("ng-openapi-gen", "d", "windows"))
+}
+
+func TestInstallTool_ForPlatform(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "archive.tar.gz")
+	createTestTarGz(t, archivePath, "linux-arm64/test-tool", "#!/bin/sh\necho test-tool")
+	archiveData, err := os.ReadFile(archivePath)
+	require.NoError(t, err)
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write(archiveData)
+	}))
+	defer server.Close()
+
+	toolRegistry["test-tool"] = toolSpec{
+		url: func(_, asset string) string { return server.URL + "/" + asset },
+		assetName: func(version, goos, goarch string) string {
+			return "test-tool-" + version + "-" + goos + "-" + goarch + ".tar.gz"
+		},
+	}
+	defer delete(toolRegistry, "test-tool")
+
+	dir := filepath.Join(tmpDir, "linux-arm64")
+	path, err := installTool("test-tool", "v0.1.0", "linux", "arm64", dir)
+	require.NoError(t, err)
+	require.Equal(t, "/test-tool-v0.1.0-linux-arm64.tar.gz", requested)
+	require.Equal(t, filepath.Join(dir, "test-tool"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho test-tool", string(data))
+
+	_, err = installTool("test-tool", "v0.1.0", "windows", "amd64", filepath.Join(tmpDir, "windows-amd64"))
+	require.ErrorContains(t, err, `binary "test-tool.exe" not found in archive`, "Windows binaries are .exe files")
+	_, err = installTool("helm", "v3.17.3", "windows", "amd64", filepath.Join(tmpDir, "helm"))
+	require.ErrorContains(t, err, "no published release asset for windows/amd64")
+}
+
 func TestCacheDir(t *testing.T) {
 	dir := cacheDir("buf", "v1.66.1")
 	require.Contains(t, dir, filepath.Join(".apx", "tools", "buf", "v1.66.1"))
@@ -156,6 +223,13 @@ func TestToolRegistryHasRequiredTools(t *testing.T) {
 	require.Contains(t, toolRegistry, "oasdiff")
 	require.Equal(t, "bufbuild/buf", toolRegistry["buf"].repo)
 	require.Equal(t, "Tufin/oasdiff", toolRegistry["oasdiff"].repo)
+	require.Equal(t, "stoplightio/spectral", toolRegistry["spectral"].repo)
+	require.NotNil(t, toolRegistry["helm"].url)
+	require.NotEmpty(t, toolRegistry["oapi-codegen"].goModule)
+	require.NotEmpty(t, toolRegistry["ng-openapi-gen"].npmPackage)
+	for name := range defaultToolVersions {
+		require.Contains(t, toolRegistry, name, "every default tool can be fetched")
+	}
 }
 
 // createTestTarGz creates a tar.gz file containing a single file.
@@ -0,0 +1,186 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+// Execution modes of config.Execution.Mode.
+const (
+	ExecutionLocal     = "local"
+	ExecutionContainer = "container"
+)
+
+// containerRuntimeEnv names a container runtime to use instead of looking
+// for docker, then podman, on PATH.
+const containerRuntimeEnv = "APX_CONTAINER_RUNTIME"
+
+// Executor builds the commands that run external tools such as buf,
+// spectral, oasdiff and helm.
+type Executor interface {
+	// Command returns the command that runs program with args in dir. An
+	// empty dir is the current directory.
+	Command(dir, program string, args ...string) *exec.Cmd
+}
+
+// NewExecutor returns the executor for an execution config: the host for
+// mode local (the default), or a container runtime for mode container.
+func NewExecutor(cfg config.Execution) (Executor, error) {
+	switch cfg.Mode {
+	case "", ExecutionLocal:
+		return LocalExecutor{}, nil
+	case ExecutionContainer:
+		if cfg.ContainerImage == "" {
+			return nil, fmt.Errorf("execution.container_image is required when execution.mode is %s", ExecutionContainer)
+		}
+		rt, err := findContainerRuntime()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range cfg.Env {
+			if !envName.MatchString(name) {
+				return nil, fmt.Errorf("execution.env: invalid variable name %q", name)
+			}
+		}
+		return &ContainerExecutor{Runtime: rt, Image: cfg.ContainerImage, Env: cfg.Env}, nil
+	}
+	return nil, fmt.Errorf("unknown execution.mode %q (expected %s or %s)", cfg.Mode, ExecutionLocal, ExecutionContainer)
+}
+
+// findContainerRuntime resolves $APX_CONTAINER_RUNTIME, or else docker or
+// podman from PATH.
+func findContainerRuntime() (string, error) {
+	if rt := os.Getenv(containerRuntimeEnv); rt != "" {
+		path, err := exec.LookPath(rt)
+		if err != nil {
+			return "", fmt.Errorf("container runtime %s (from %s): %w", rt, containerRuntimeEnv, err)
+		}
+		return path, nil
+	}
+	for _, name := range []string{"docker", "podman"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("execution.mode %s needs docker or podman on PATH (or set %s)", ExecutionContainer, containerRuntimeEnv)
+}
+
+// LocalExecutor runs tools directly on the host.
+type LocalExecutor struct{}
+
+// Command runs program on the host.
+func (LocalExecutor) Command(dir, program string, args ...string) *exec.Cmd {
+	cmd := exec.Command(program, args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// ContainerExecutor runs tools inside a pinned image with docker or podman,
+// so every machine uses the tool versions baked into the image. The
+// workspace is mounted at its host path, so absolute paths in arguments and
+// in tool output mean the same thing inside and outside the container. On
+// Linux the tool runs as the host user, so files it writes into the
+// workspace are not owned by root. The runtime passes the container's exit
+// code through.
+type ContainerExecutor struct {
+	Runtime string // path to the docker or podman binary
+	Image   string
+	Env     []string // host variables to pass in, besides proxy and CA settings
+}
+
+// Command runs program in a new container of the image.
+func (e *ContainerExecutor) Command(dir, program string, args ...string) *exec.Cmd {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	run := []string{"run", "--rm"}
+	for _, m := range containerMounts(dir, args) {
+		run = append(run, "-v", m+":"+m)
+	}
+	run = append(run, "-w", dir)
+	if runtime.GOOS == "linux" {
+		run = append(run, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	// Name the variables only: the runtime copies their values from its own
+	// environment, which keeps secrets out of the process list.
+	for _, name := range passthroughEnv(e.Env) {
+		run = append(run, "-e", name)
+	}
+	run = append(run, e.Image, program)
+	cmd := exec.Command(e.Runtime, append(run, args...)...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// containerMounts lists the host directories a tool run needs: the git
+// working tree around dir (or dir itself), and the directories of absolute
+// path arguments outside it, such as baseline files materialized in the
+// temp directory.
+func containerMounts(dir string, args []string) []string {
+	root := dir
+	if top, err := gitTopLevel(dir); err == nil {
+		root = top
+	}
+	mounts := []string{root}
+	covered := func(p string) bool {
+		for _, m := range mounts {
+			if p == m || strings.HasPrefix(p, m+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, arg := range append([]string{dir}, args...) {
+		if !filepath.IsAbs(arg) {
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			continue
+		}
+		p := arg
+		if !info.IsDir() {
+			p = filepath.Dir(arg)
+		}
+		if !covered(p) {
+			mounts = append(mounts, p)
+		}
+	}
+	return mounts
+}
+
+// forwardedEnv are the variables passed into containers without being
+// named in execution.env: the proxy and CA settings tools need to reach the
+// network from where the host does.
+var forwardedEnv = []string{
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY",
+	"http_proxy", "https_proxy", "no_proxy", "all_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR", "NODE_EXTRA_CA_CERTS", "REQUESTS_CA_BUNDLE",
+}
+
+// envName matches the variable names a container runtime accepts.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// passthroughEnv returns the sorted names of the variables passed into
+// containers: those of forwardedEnv and extra that are set on the host.
+func passthroughEnv(extra []string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range append(append([]string{}, forwardedEnv...), extra...) {
+		if _, ok := os.LookupEnv(name); !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+// fakeRuntime writes a container runtime that prints its arguments, one per
+// line, and exits with $FAKE_EXIT.
+func fakeRuntime(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake runtime is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "docker")
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done\nexit ${FAKE_EXIT:-0}\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewExecutor(t *testing.T) {
+	rt := fakeRuntime(t)
+	t.Setenv("APX_CONTAINER_RUNTIME", rt)
+
+	for _, mode := range []string{"", "local"} {
+		e, err := NewExecutor(config.Execution{Mode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := e.(LocalExecutor); !ok {
+			t.Errorf("mode %q: expected a local executor, got %T", mode, e)
+		}
+	}
+
+	e, err := NewExecutor(config.Execution{Mode: "container", ContainerImage: "ghcr.io/acme/apx-tools:1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := e.(*ContainerExecutor)
+	if !ok || c.Runtime != rt || c.Image != "ghcr.io/acme/apx-tools:1.0" {
+		t.Errorf("unexpected container executor: %#v", e)
+	}
+
+	tests := []struct {
+		cfg  config.Execution
+		want string
+	}{
+		{config.Execution{Mode: "container"}, "execution.container_image is required"},
+		{config.Execution{Mode: "vm"}, `unknown execution.mode "vm"`},
+	}
+	for _, tt := range tests {
+		if _, err := NewExecutor(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewExecutor(%+v): error %v, want %q", tt.cfg, err, tt.want)
+		}
+	}
+
+	if _, err := NewExecutor(config.Execution{Mode: "container", ContainerImage: "img", Env: []string{"NOT-A-NAME"}}); err == nil || !strings.Contains(err.Error(), `execution.env: invalid variable name "NOT-A-NAME"`) {
+		t.Errorf("expected an error for an invalid variable name, got %v", err)
+	}
+	e, err = NewExecutor(config.Execution{Mode: "container", ContainerImage: "img", Env: []string{"NPM_TOKEN"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := e.(*ContainerExecutor); len(c.Env) != 1 || c.Env[0] != "NPM_TOKEN" {
+		t.Errorf("Env = %v, want [NPM_TOKEN]", c.Env)
+	}
+
+	t.Setenv("APX_CONTAINER_RUNTIME", "no-such-runtime-apx")
+	if _, err := NewExecutor(config.Execution{Mode: "container", ContainerImage: "img"}); err == nil {
+		t.Error("expected an error for a missing runtime")
+	}
+}
+
+func TestContainerExecutor_Command(t *testing.T) {
+	rt := fakeRuntime(t)
+	t.Setenv("APX_TEST_TOKEN", "secret")
+	t.Setenv("APX_TEST_OTHER", "other")
+	t.Setenv("HTTPS_PROXY", "http://proxy:3128")
+	dir := t.TempDir()
+	outside := t.TempDir()
+	base := filepath.Join(outside, "base.yaml")
+	if err := os.WriteFile(base, []byte("openapi: 3.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &ContainerExecutor{Runtime: rt, Image: "tools:1", Env: []string{"APX_TEST_TOKEN"}}
+	cmd := e.Command(dir, "oasdiff", "breaking", base, filepath.Join(dir, "missing.yaml"))
+	if cmd.Dir != dir {
+		t.Errorf("Dir = %q, want %q", cmd.Dir, dir)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Split(strings.TrimSpace(string(out)), "\n")
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"run --rm",
+		"-v " + dir + ":" + dir,
+		"-v " + outside + ":" + outside,
+		"-w " + dir,
+		"-e APX_TEST_TOKEN",
+		"-e HTTPS_PROXY",
+		"tools:1 oasdiff breaking " + base,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("runtime arguments %q do not contain %q", joined, want)
+		}
+	}
+	// Only proxy and CA settings and the variables execution.env names are
+	// passed in.
+	for _, unwanted := range []string{"secret", "APX_TEST_OTHER", "-e PATH", "-e HOME", "-e FAKE_EXIT"} {
+		if strings.Contains(joined, unwanted) {
+			t.Errorf("unexpected %q in runtime arguments: %q", unwanted, joined)
+		}
+	}
+	user := fmt.Sprintf("--user %d:%d", os.Getuid(), os.Getgid())
+	if got := strings.Contains(joined, user); got != (runtime.GOOS == "linux") {
+		t.Errorf("runtime arguments %q: contains %q = %v on %s", joined, user, got, runtime.GOOS)
+	}
+	if strings.Count(joined, "-v ") != 2 {
+		t.Errorf("expected two mounts, got %q", joined)
+	}
+
+	// The runtime's exit code is the tool's.
+	t.Setenv("FAKE_EXIT", "3")
+	err = e.Command(dir, "buf", "lint").Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("expected exit code 3, got %v", err)
+	}
+}
+
+func TestToolchainResolver_ContainerMode(t *testing.T) {
+	r := NewToolchainResolver(WithExecutor(&ContainerExecutor{Runtime: "docker", Image: "tools:1"}))
+	path, err := r.ResolveTool("spectral", "v6.15.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "spectral" {
+		t.Errorf("expected the bare tool name, got %q", path)
+	}
+	cmd := r.Command("/work", path, "lint")
+	if cmd.Args[0] != "docker" || cmd.Dir != "/work" {
+		t.Errorf("expected the tool to run through the runtime, got %v", cmd.Args)
+	}
+}
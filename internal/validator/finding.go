@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity classifies how a Finding affects the outcome of a check.
+type Severity string
+
+const (
+	// SeverityError findings fail the check.
+	SeverityError Severity = "error"
+	// SeverityWarning findings are reported but do not fail the check.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo findings are informational only.
+	SeverityInfo Severity = "info"
+)
+
+// Finding is a single structured lint or breaking-change result. Every
+// validator reports its problems as findings so callers (apx lint, apx
+// breaking, apx semver, release prepare/finalize) can decide on real data
+// instead of on whether an error came back.
+type Finding struct {
+	File     string       `json:"file,omitempty" yaml:"file,omitempty"`
+	Line     int          `json:"line,omitempty" yaml:"line,omitempty"`
+	Column   int          `json:"column,omitempty" yaml:"column,omitempty"`
+	RuleID   string       `json:"rule_id" yaml:"rule_id"`
+	Severity Severity     `json:"severity" yaml:"severity"`
+	Format   SchemaFormat `json:"format,omitempty" yaml:"format,omitempty"`
+	// Path locates the finding inside the schema (e.g. "Order.items[].price",
+	// "/pets GET"), independent of the file position.
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Message  string `json:"message" yaml:"message"`
+	OldValue string `json:"old_value,omitempty" yaml:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty" yaml:"new_value,omitempty"`
+}
+
+// Location renders the file position of a finding as file[:line[:column]],
+// or "line N" when only a line is known. Returns "" when nothing is known.
+func (f Finding) Location() string {
+	switch {
+	case f.File != "" && f.Line > 0 && f.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	case f.File != "" && f.Line > 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	case f.File != "":
+		return f.File
+	case f.Line > 0:
+		return fmt.Sprintf("line %d", f.Line)
+	}
+	return ""
+}
+
+// String renders the finding as a single human-readable line.
+func (f Finding) String() string {
+	if loc := f.Location(); loc != "" {
+		return loc + ": " + f.Message
+	}
+	return f.Message
+}
+
+// HasErrors reports whether any finding has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterSeverity returns the findings with the given severity.
+func FilterSeverity(findings []Finding, sev Severity) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if f.Severity == sev {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// SortFindings orders findings by file, line, column, then message so output
+// is stable regardless of map iteration order inside the validators.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Message < b.Message
+	})
+}
+
+// FindingsError is the error returned by the Lint and Breaking entry points
+// when a check produced error-severity findings. It carries the findings so
+// callers can recover them with errors.As.
+type FindingsError struct {
+	Summary  string
+	Findings []Finding
+}
+
+func (e *FindingsError) Error() string {
+	var lines []string
+	for _, f := range e.Findings {
+		if f.Severity == SeverityError {
+			lines = append(lines, f.String())
+		}
+	}
+	return fmt.Sprintf("%s:\n  - %s", e.Summary, strings.Join(lines, "\n  - "))
+}
+
+// findingsError converts the result of a findings-based check into the error
+// returned by the Lint and Breaking entry points: err when the check could not
+// run, a *FindingsError when any finding is an error, nil otherwise.
+func findingsError(summary string, findings []Finding, err error) error {
+	if err != nil {
+		return err
+	}
+	if !HasErrors(findings) {
+		return nil
+	}
+	return &FindingsError{Summary: summary, Findings: findings}
+}
+
+// dedupeFindings drops findings that repeat an earlier finding's rule, path
+// and message, keeping the first occurrence (e.g. when a FULL compatibility
+// check reports the same problem in both directions).
+func dedupeFindings(findings []Finding) []Finding {
+	seen := make(map[[3]string]bool, len(findings))
+	out := findings[:0]
+	for _, f := range findings {
+		key := [3]string{f.RuleID, f.Path, f.Message}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, f)
+	}
+	return out
+}
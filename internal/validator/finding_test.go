@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFinding_Location(t *testing.T) {
+	tests := []struct {
+		f    Finding
+		want string
+	}{
+		{Finding{File: "a.proto", Line: 3, Column: 5}, "a.proto:3:5"},
+		{Finding{File: "a.proto", Line: 3}, "a.proto:3"},
+		{Finding{File: "a.proto"}, "a.proto"},
+		{Finding{Line: 7}, "line 7"},
+		{Finding{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Location(); got != tt.want {
+			t.Errorf("Location() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFindingsError(t *testing.T) {
+	findings := []Finding{
+		{File: "x.avsc", Severity: SeverityWarning, Message: "just a warning"},
+		{File: "x.avsc", Severity: SeverityError, Message: "real problem"},
+	}
+
+	err := findingsError("avro lint errors", findings, nil)
+	var fe *FindingsError
+	if !errors.As(err, &fe) {
+		t.Fatalf("expected *FindingsError, got %T", err)
+	}
+	if len(fe.Findings) != 2 {
+		t.Errorf("expected findings to be preserved, got %d", len(fe.Findings))
+	}
+	if !strings.Contains(err.Error(), "x.avsc: real problem") {
+		t.Errorf("error should list error findings, got: %s", err)
+	}
+	if strings.Contains(err.Error(), "just a warning") {
+		t.Errorf("error should not list warnings, got: %s", err)
+	}
+
+	if err := findingsError("s", findings[:1], nil); err != nil {
+		t.Errorf("warnings alone should not produce an error, got: %v", err)
+	}
+	runErr := errors.New("tool missing")
+	if err := findingsError("s", findings, runErr); err != runErr {
+		t.Errorf("run error should take precedence, got: %v", err)
+	}
+}
+
+func TestSortFindings(t *testing.T) {
+	findings := []Finding{
+		{File: "b", Line: 1},
+		{File: "a", Line: 9},
+		{File: "a", Line: 2, Message: "y"},
+		{File: "a", Line: 2, Message: "x"},
+	}
+	SortFindings(findings)
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.File+f.Message)
+	}
+	if strings.Join(got, ",") != "ax,ay,a,b" {
+		t.Errorf("unexpected order: %v", got)
+	}
+	if !HasErrors([]Finding{{Severity: SeverityError}}) || HasErrors(findings) {
+		t.Error("HasErrors mismatch")
+	}
+}
@@ -10,12 +10,18 @@ import (
 
 // JSONSchemaValidator handles JSON Schema validation
 type JSONSchemaValidator struct {
-	resolver *ToolchainResolver
+	resolver     *ToolchainResolver
+	breakingMode string // strict (default) or lenient
 }
 
 // NewJSONSchemaValidator creates a new JSON Schema validator
 func NewJSONSchemaValidator(resolver *ToolchainResolver) *JSONSchemaValidator {
-	return &JSONSchemaValidator{resolver: resolver}
+	return &JSONSchemaValidator{resolver: resolver, breakingMode: JSONSchemaStrict}
+}
+
+// SetBreakingMode sets the breaking-change mode: "strict" or "lenient".
+func (v *JSONSchemaValidator) SetBreakingMode(mode string) {
+	v.breakingMode = mode
 }
 
 // recognizedSchemaDrafts is the set of well-known JSON Schema draft URIs.
@@ -39,14 +45,21 @@ var validJSONSchemaTypes = map[string]bool{
 // Lint validates JSON Schema syntax using native Go parsing. If path is a
 // directory, all *.json files under it are linted recursively.
 func (v *JSONSchemaValidator) Lint(path string) error {
+	findings, err := v.LintFindings(path)
+	return findingsError("JSON Schema lint errors", findings, err)
+}
+
+// LintFindings validates JSON Schema syntax and returns every violation as a
+// Finding. If path is a directory, all *.json files under it are linted.
+func (v *JSONSchemaValidator) LintFindings(path string) ([]Finding, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	info, err := os.Stat(absPath)
 	if err != nil {
-		return fmt.Errorf("reading %s: %w", path, err)
+		return nil, fmt.Errorf("reading %s: %w", path, err)
 	}
 
 	if info.IsDir() {
@@ -56,8 +69,9 @@ func (v *JSONSchemaValidator) Lint(path string) error {
 }
 
 // lintDir walks a directory and lints every *.json file.
-func (v *JSONSchemaValidator) lintDir(dir string) error {
+func (v *JSONSchemaValidator) lintDir(dir string) ([]Finding, error) {
 	found := false
+	var findings []Finding
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
@@ -66,48 +80,65 @@ func (v *JSONSchemaValidator) lintDir(dir string) error {
 			return nil
 		}
 		found = true
-		if lintErr := v.lintFile(path); lintErr != nil {
+		fileFindings, lintErr := v.lintFile(path)
+		if lintErr != nil {
 			return fmt.Errorf("%s: %w", path, lintErr)
 		}
+		findings = append(findings, fileFindings...)
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !found {
-		return fmt.Errorf("no .json files found in %s", dir)
+		return nil, fmt.Errorf("no .json files found in %s", dir)
 	}
-	return nil
+	return findings, nil
 }
 
 // lintFile validates a single JSON Schema file.
-func (v *JSONSchemaValidator) lintFile(absPath string) error {
+func (v *JSONSchemaValidator) lintFile(absPath string) ([]Finding, error) {
 	data, err := os.ReadFile(absPath)
 	if err != nil {
-		return fmt.Errorf("reading %s: %w", absPath, err)
+		return nil, fmt.Errorf("reading %s: %w", absPath, err)
+	}
+
+	var findings []Finding
+	add := func(rule, keyword, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     absPath,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatJSONSchema,
+			Path:     keyword,
+			Message:  fmt.Sprintf(format, args...),
+		})
 	}
 
 	// Must be valid JSON
 	var schema map[string]json.RawMessage
 	if err := json.Unmarshal(data, &schema); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
+		add("jsonschema-invalid-json", "", "invalid JSON: %v", err)
+		return findings, nil
 	}
 
+	// x-apx-ignore lists rules not reported for this schema
+	add = ignoring(add, ignoredRules(schema["x-apx-ignore"]))
+
 	// $schema keyword, if present, must be a recognized draft URI
 	if raw, ok := schema["$schema"]; ok {
 		var schemaURI string
 		if err := json.Unmarshal(raw, &schemaURI); err != nil {
-			return fmt.Errorf("'$schema' must be a string")
-		}
-		if !recognizedSchemaDrafts[schemaURI] {
-			return fmt.Errorf("unrecognized '$schema' URI: %s", schemaURI)
+			add("jsonschema-draft", "$schema", "'$schema' must be a string")
+		} else if !recognizedSchemaDrafts[schemaURI] {
+			add("jsonschema-draft", "$schema", "unrecognized '$schema' URI: %s", schemaURI)
 		}
 	}
 
 	// type keyword, if present, must be a valid type string or array of type strings
 	if raw, ok := schema["type"]; ok {
 		if err := validateJSONSchemaType(raw); err != nil {
-			return fmt.Errorf("invalid 'type': %w", err)
+			add("jsonschema-invalid-type", "type", "invalid 'type': %v", err)
 		}
 	}
 
@@ -115,7 +146,7 @@ func (v *JSONSchemaValidator) lintFile(absPath string) error {
 	if raw, ok := schema["properties"]; ok {
 		var props map[string]json.RawMessage
 		if err := json.Unmarshal(raw, &props); err != nil {
-			return fmt.Errorf("'properties' must be an object: %w", err)
+			add("jsonschema-invalid-keyword", "properties", "'properties' must be an object: %v", err)
 		}
 	}
 
@@ -123,7 +154,7 @@ func (v *JSONSchemaValidator) lintFile(absPath string) error {
 	if raw, ok := schema["required"]; ok {
 		var req []string
 		if err := json.Unmarshal(raw, &req); err != nil {
-			return fmt.Errorf("'required' must be an array of strings: %w", err)
+			add("jsonschema-invalid-keyword", "required", "'required' must be an array of strings: %v", err)
 		}
 	}
 
@@ -134,12 +165,12 @@ func (v *JSONSchemaValidator) lintFile(absPath string) error {
 			// Also allow array form for tuple validation
 			var arr []json.RawMessage
 			if err2 := json.Unmarshal(raw, &arr); err2 != nil {
-				return fmt.Errorf("'items' must be a schema object or array of schema objects")
+				add("jsonschema-invalid-keyword", "items", "'items' must be a schema object or array of schema objects")
 			}
 		}
 	}
 
-	return nil
+	return findings, nil
 }
 
 // validateJSONSchemaType validates the value of a JSON Schema "type" keyword.
@@ -171,127 +202,37 @@ func validateJSONSchemaType(raw json.RawMessage) error {
 // files using native Go comparison. No external tools required.
 //
 // A change is breaking if a payload valid under the old schema could be
-// rejected by the new schema. Detected breaking changes:
-//   - Property removed from "properties"
-//   - Property type changed (e.g., string → integer)
-//   - New field added to "required"
-//   - Type of the root schema changed
+// rejected by the new schema. The comparison recurses through properties,
+// items, additionalProperties and allOf/anyOf/oneOf, following local and
+// relative-file $refs. Property removals and type changes are always
+// breaking; in strict mode (the default) so are added required fields,
+// narrowed enums and type unions, tightened min/max/pattern/length
+// constraints and closed additionalProperties. Lenient mode reports those
+// as warnings.
 func (v *JSONSchemaValidator) Breaking(path, against string) error {
-	oldSchema, err := loadJSONSchemaFile(against)
-	if err != nil {
-		// Baseline doesn't exist — new schema, nothing to compare.
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return fmt.Errorf("reading baseline schema: %w", err)
-	}
-
-	newSchema, err := loadJSONSchemaFile(path)
-	if err != nil {
-		return fmt.Errorf("reading new schema: %w", err)
-	}
-
-	var breaking []string
-
-	// Check root type change.
-	if oldType, newType := jsonSchemaType(oldSchema), jsonSchemaType(newSchema); oldType != "" && newType != "" && oldType != newType {
-		breaking = append(breaking, fmt.Sprintf("root type changed from %q to %q", oldType, newType))
-	}
-
-	// Check properties.
-	oldProps := jsonSchemaProperties(oldSchema)
-	newProps := jsonSchemaProperties(newSchema)
-	for name := range oldProps {
-		if _, exists := newProps[name]; !exists {
-			breaking = append(breaking, fmt.Sprintf("property %q removed", name))
-			continue
-		}
-		oldPropType := jsonSchemaType(oldProps[name])
-		newPropType := jsonSchemaType(newProps[name])
-		if oldPropType != "" && newPropType != "" && oldPropType != newPropType {
-			breaking = append(breaking, fmt.Sprintf("property %q type changed from %q to %q", name, oldPropType, newPropType))
-		}
-	}
+	findings, err := v.BreakingFindings(path, against)
+	return findingsError("breaking changes detected", findings, err)
+}
 
-	// Check required fields added.
-	oldRequired := jsonSchemaRequired(oldSchema)
-	newRequired := jsonSchemaRequired(newSchema)
-	for field := range newRequired {
-		if !oldRequired[field] {
-			breaking = append(breaking, fmt.Sprintf("field %q added to required", field))
-		}
+// BreakingFindings detects backward-incompatible changes between two JSON
+// Schema files and returns each one as a Finding.
+func (v *JSONSchemaValidator) BreakingFindings(path, against string) ([]Finding, error) {
+	if _, err := os.Stat(against); os.IsNotExist(err) {
+		// Baseline doesn't exist — new schema, nothing to compare.
+		return nil, nil
 	}
 
-	if len(breaking) > 0 {
-		return fmt.Errorf("breaking changes detected:\n  - %s", strings.Join(breaking, "\n  - "))
+	mode := strings.ToLower(v.breakingMode)
+	switch mode {
+	case "", JSONSchemaStrict, JSONSchemaLenient:
+	default:
+		return nil, fmt.Errorf("unknown JSON Schema breaking mode: %s", v.breakingMode)
 	}
-	return nil
-}
 
-// loadJSONSchemaFile reads and parses a JSON Schema file.
-func loadJSONSchemaFile(path string) (map[string]json.RawMessage, error) {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return nil, err
-	}
-	data, err := os.ReadFile(absPath)
+	findings, err := diffJSONSchemaFiles(against, path, path, mode == JSONSchemaLenient)
 	if err != nil {
 		return nil, err
 	}
-	var schema map[string]json.RawMessage
-	if err := json.Unmarshal(data, &schema); err != nil {
-		return nil, fmt.Errorf("invalid JSON in %s: %w", path, err)
-	}
-	return schema, nil
-}
-
-// jsonSchemaType extracts the "type" string from a schema object.
-// Returns empty string if not present or not a simple string type.
-func jsonSchemaType(schema map[string]json.RawMessage) string {
-	raw, ok := schema["type"]
-	if !ok {
-		return ""
-	}
-	var t string
-	if err := json.Unmarshal(raw, &t); err != nil {
-		return ""
-	}
-	return t
-}
-
-// jsonSchemaProperties extracts the "properties" map from a schema object.
-func jsonSchemaProperties(schema map[string]json.RawMessage) map[string]map[string]json.RawMessage {
-	raw, ok := schema["properties"]
-	if !ok {
-		return nil
-	}
-	var props map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &props); err != nil {
-		return nil
-	}
-	result := make(map[string]map[string]json.RawMessage, len(props))
-	for name, propRaw := range props {
-		var prop map[string]json.RawMessage
-		if err := json.Unmarshal(propRaw, &prop); err == nil {
-			result[name] = prop
-		}
-	}
-	return result
-}
-
-// jsonSchemaRequired extracts the "required" array as a set.
-func jsonSchemaRequired(schema map[string]json.RawMessage) map[string]bool {
-	raw, ok := schema["required"]
-	if !ok {
-		return nil
-	}
-	var req []string
-	if err := json.Unmarshal(raw, &req); err != nil {
-		return nil
-	}
-	set := make(map[string]bool, len(req))
-	for _, r := range req {
-		set[r] = true
-	}
-	return set
+	SortFindings(findings)
+	return findings, nil
 }
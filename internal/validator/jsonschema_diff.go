@@ -0,0 +1,682 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSON Schema breaking modes (policy.jsonschema.breaking_mode).
+const (
+	// JSONSchemaStrict treats every change that can reject a previously valid
+	// payload as breaking.
+	JSONSchemaStrict = "strict"
+	// JSONSchemaLenient blocks only property removals and type changes; other
+	// tightenings are reported as warnings.
+	JSONSchemaLenient = "lenient"
+)
+
+// jsonSchemaDoc is one loaded JSON Schema document.
+type jsonSchemaDoc struct {
+	file string // absolute path
+	root interface{}
+}
+
+// jsonSchemaLoader loads schema documents on demand and resolves $ref
+// pointers between them. Each file is read once.
+type jsonSchemaLoader struct {
+	docs map[string]*jsonSchemaDoc
+}
+
+func newJSONSchemaLoader() *jsonSchemaLoader {
+	return &jsonSchemaLoader{docs: map[string]*jsonSchemaDoc{}}
+}
+
+func (l *jsonSchemaLoader) load(file string) (*jsonSchemaDoc, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	if doc, ok := l.docs[abs]; ok {
+		return doc, nil
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	var root interface{}
+	switch strings.ToLower(filepath.Ext(abs)) {
+	case ".yaml", ".yml":
+		// YAML documents (OpenAPI specs and the files they $ref) are
+		// converted to the JSON data model.
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("invalid YAML in %s: %w", file, err)
+		}
+		if root, err = yamlNodeValue(&node, "", map[string]int{}); err != nil {
+			return nil, fmt.Errorf("invalid YAML in %s: %w", file, err)
+		}
+	default:
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("invalid JSON in %s: %w", file, err)
+		}
+	}
+	doc := &jsonSchemaDoc{file: abs, root: root}
+	l.docs[abs] = doc
+	return doc, nil
+}
+
+// jsonSchemaNode is a schema together with the document it lives in, so
+// relative $refs inside it resolve against the right file.
+type jsonSchemaNode struct {
+	doc    *jsonSchemaDoc
+	schema map[string]interface{} // nil for the boolean schema true
+	never  bool                   // the boolean schema false
+	loc    string                 // file#pointer when reached through $ref
+}
+
+func newJSONSchemaNode(doc *jsonSchemaDoc, v interface{}) jsonSchemaNode {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		return jsonSchemaNode{doc: doc, schema: s}
+	case bool:
+		return jsonSchemaNode{doc: doc, never: !s}
+	}
+	return jsonSchemaNode{doc: doc}
+}
+
+// deref follows $ref until it reaches a schema without one. Keywords next to
+// a $ref (allowed since draft 2019-09) are layered over the target.
+func (l *jsonSchemaLoader) deref(n jsonSchemaNode) (jsonSchemaNode, error) {
+	for depth := 0; n.schema != nil; depth++ {
+		ref, ok := n.schema["$ref"].(string)
+		if !ok {
+			return n, nil
+		}
+		if depth > 32 {
+			return n, fmt.Errorf("$ref chain too deep at %q", ref)
+		}
+		target, err := l.resolveRef(n.doc, ref)
+		if err != nil {
+			return n, err
+		}
+		if len(n.schema) > 1 && target.schema != nil {
+			merged := make(map[string]interface{}, len(target.schema)+len(n.schema))
+			for k, v := range target.schema {
+				merged[k] = v
+			}
+			for k, v := range n.schema {
+				if k != "$ref" {
+					merged[k] = v
+				}
+			}
+			target.schema = merged
+		}
+		n = target
+	}
+	return n, nil
+}
+
+// resolveRef resolves a local ("#/$defs/X"), anchor ("#name") or relative
+// file ("common.json#/$defs/X") reference from doc.
+func (l *jsonSchemaLoader) resolveRef(doc *jsonSchemaDoc, ref string) (jsonSchemaNode, error) {
+	filePart, frag, _ := strings.Cut(ref, "#")
+	target := doc
+	if filePart != "" {
+		if strings.Contains(filePart, "://") {
+			return jsonSchemaNode{}, fmt.Errorf("remote $ref %q is not supported", ref)
+		}
+		d, err := l.load(filepath.Join(filepath.Dir(doc.file), filepath.FromSlash(filePart)))
+		if err != nil {
+			return jsonSchemaNode{}, fmt.Errorf("resolving $ref %q: %w", ref, err)
+		}
+		target = d
+	}
+
+	var v interface{}
+	switch {
+	case frag == "":
+		v = target.root
+	case strings.HasPrefix(frag, "/"):
+		v = target.root
+		for _, tok := range strings.Split(frag[1:], "/") {
+			tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+			switch cur := v.(type) {
+			case map[string]interface{}:
+				v = cur[tok]
+			case []interface{}:
+				i, err := strconv.Atoi(tok)
+				if err != nil || i < 0 || i >= len(cur) {
+					v = nil
+				} else {
+					v = cur[i]
+				}
+			default:
+				v = nil
+			}
+			if v == nil {
+				return jsonSchemaNode{}, fmt.Errorf("$ref %q does not resolve", ref)
+			}
+		}
+	default:
+		v = findJSONSchemaAnchor(target.root, frag)
+		if v == nil {
+			return jsonSchemaNode{}, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+	}
+	n := newJSONSchemaNode(target, v)
+	n.loc = target.file + "#" + frag
+	return n, nil
+}
+
+// findJSONSchemaAnchor finds the subschema declaring $anchor name (or the
+// draft-07 style "$id": "#name").
+func findJSONSchemaAnchor(v interface{}, name string) interface{} {
+	switch cur := v.(type) {
+	case map[string]interface{}:
+		if cur["$anchor"] == name || cur["$id"] == "#"+name {
+			return cur
+		}
+		for _, child := range cur {
+			if found := findJSONSchemaAnchor(child, name); found != nil {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, child := range cur {
+			if found := findJSONSchemaAnchor(child, name); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// jsonSchemaDiff compares two schemas recursively. A change is breaking if
+// a payload valid under the old schema could be rejected by the new one.
+type jsonSchemaDiff struct {
+	loader   *jsonSchemaLoader
+	lenient  bool
+	file     string
+	findings []Finding
+	seen     map[string]bool // old|new $ref locations already compared
+}
+
+// add records a finding. Structural changes (removals, type changes) are
+// always errors; tightenings are errors in strict mode and warnings in
+// lenient mode.
+func (d *jsonSchemaDiff) add(structural bool, rule, path, oldVal, newVal, format string, args ...interface{}) {
+	sev := SeverityError
+	if !structural && d.lenient {
+		sev = SeverityWarning
+	}
+	d.findings = append(d.findings, Finding{
+		File:     d.file,
+		RuleID:   rule,
+		Severity: sev,
+		Format:   FormatJSONSchema,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		OldValue: oldVal,
+		NewValue: newVal,
+	})
+}
+
+// jsonSchemaJoin appends a property name to an element path.
+func jsonSchemaJoin(path, elem string) string {
+	if path == "" {
+		return elem
+	}
+	return path + "." + elem
+}
+
+// jsonSchemaSubject names an element path in messages.
+func jsonSchemaSubject(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return fmt.Sprintf("property %q", path)
+}
+
+func (d *jsonSchemaDiff) compare(oldN, newN jsonSchemaNode, path string) {
+	oldN, err := d.loader.deref(oldN)
+	if err != nil {
+		d.add(false, "jsonschema-unresolved-ref", path, "", "", "%s: old schema: %v", jsonSchemaSubject(path), err)
+		return
+	}
+	newN, err = d.loader.deref(newN)
+	if err != nil {
+		d.add(true, "jsonschema-unresolved-ref", path, "", "", "%s: %v", jsonSchemaSubject(path), err)
+		return
+	}
+	if oldN.loc != "" && newN.loc != "" {
+		key := oldN.loc + "|" + newN.loc
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+	}
+
+	if oldN.never {
+		return // nothing was valid before, so nothing can break
+	}
+	if newN.never {
+		d.add(true, "jsonschema-type-changed", path, "true", "false", "%s now rejects every value", jsonSchemaSubject(path))
+		return
+	}
+	if newN.schema == nil {
+		return // new schema accepts everything
+	}
+	oldS := oldN.schema
+	if oldS == nil {
+		oldS = map[string]interface{}{}
+	}
+	newS := newN.schema
+
+	if !d.compareTypes(oldS, newS, path) {
+		return
+	}
+	d.compareEnum(oldS, newS, path)
+	d.compareConstraints(oldS, newS, path)
+	d.compareObject(oldN, newN, oldS, newS, path)
+	d.compareItems(oldN, newN, oldS, newS, path)
+	d.compareCombinators(oldN, newN, oldS, newS, path)
+}
+
+// jsonSchemaTypes returns the "type" keyword as a set, or nil when absent.
+func jsonSchemaTypes(s map[string]interface{}) []string {
+	switch t := s["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var out []string
+		for _, e := range t {
+			if str, ok := e.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func jsonSchemaTypeLabel(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
+
+// compareTypes reports type changes and narrowing. It returns false when
+// the types no longer overlap, in which case nested checks are skipped.
+func (d *jsonSchemaDiff) compareTypes(oldS, newS map[string]interface{}, path string) bool {
+	oldT, newT := jsonSchemaTypes(oldS), jsonSchemaTypes(newS)
+	if newT == nil {
+		return true
+	}
+	if oldT == nil {
+		d.add(false, "jsonschema-type-narrowed", path, "", jsonSchemaTypeLabel(newT),
+			"%s is now restricted to type %s", jsonSchemaSubject(path), jsonSchemaTypeLabel(newT))
+		return true
+	}
+	accepts := make(map[string]bool, len(newT))
+	for _, t := range newT {
+		accepts[t] = true
+	}
+	var lost []string
+	for _, t := range oldT {
+		if !accepts[t] && !(t == "integer" && accepts["number"]) {
+			lost = append(lost, t)
+		}
+	}
+	switch {
+	case len(lost) == 0:
+		return true
+	case len(lost) == len(oldT):
+		d.add(true, "jsonschema-type-changed", path, jsonSchemaTypeLabel(oldT), jsonSchemaTypeLabel(newT),
+			"%s type changed from %q to %q", jsonSchemaSubject(path), jsonSchemaTypeLabel(oldT), jsonSchemaTypeLabel(newT))
+		return false
+	}
+	d.add(false, "jsonschema-type-narrowed", path, jsonSchemaTypeLabel(oldT), jsonSchemaTypeLabel(newT),
+		"%s type narrowed from %s to %s (no longer accepts %s)",
+		jsonSchemaSubject(path), jsonSchemaTypeLabel(oldT), jsonSchemaTypeLabel(newT), strings.Join(lost, ", "))
+	return true
+}
+
+// jsonSchemaEnum returns the allowed values (enum, or const as a single
+// value) in canonical JSON form, and whether the schema restricts values.
+func jsonSchemaEnum(s map[string]interface{}) ([]string, bool) {
+	var values []interface{}
+	if e, ok := s["enum"].([]interface{}); ok {
+		values = e
+	} else if c, ok := s["const"]; ok {
+		values = []interface{}{c}
+	} else {
+		return nil, false
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		b, _ := json.Marshal(v)
+		out = append(out, string(b))
+	}
+	return out, true
+}
+
+func (d *jsonSchemaDiff) compareEnum(oldS, newS map[string]interface{}, path string) {
+	newE, newHas := jsonSchemaEnum(newS)
+	if !newHas {
+		return
+	}
+	oldE, oldHas := jsonSchemaEnum(oldS)
+	if !oldHas {
+		d.add(false, "jsonschema-enum-narrowed", path, "", strings.Join(newE, ", "),
+			"%s is now restricted to enum [%s]", jsonSchemaSubject(path), strings.Join(newE, ", "))
+		return
+	}
+	allowed := make(map[string]bool, len(newE))
+	for _, v := range newE {
+		allowed[v] = true
+	}
+	var removed []string
+	for _, v := range oldE {
+		if !allowed[v] {
+			removed = append(removed, v)
+		}
+	}
+	if len(removed) > 0 {
+		d.add(false, "jsonschema-enum-narrowed", path, strings.Join(removed, ", "), "",
+			"%s enum no longer allows %s", jsonSchemaSubject(path), strings.Join(removed, ", "))
+	}
+}
+
+// Numeric bounds: a higher lower bound or a lower upper bound rejects
+// values that used to be valid.
+var (
+	jsonSchemaLowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	jsonSchemaUpperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+func (d *jsonSchemaDiff) compareConstraints(oldS, newS map[string]interface{}, path string) {
+	bound := func(kw string, lower bool) {
+		nv, ok := newS[kw].(float64)
+		if !ok {
+			return
+		}
+		ov, had := oldS[kw].(float64)
+		tightened := !had || (lower && nv > ov) || (!lower && nv < ov)
+		if !tightened {
+			return
+		}
+		oldLabel := "unset"
+		if had {
+			oldLabel = strconv.FormatFloat(ov, 'g', -1, 64)
+		}
+		newLabel := strconv.FormatFloat(nv, 'g', -1, 64)
+		d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, kw), oldLabel, newLabel,
+			"%s %s tightened from %s to %s", jsonSchemaSubject(path), kw, oldLabel, newLabel)
+	}
+	for _, kw := range jsonSchemaLowerBounds {
+		bound(kw, true)
+	}
+	for _, kw := range jsonSchemaUpperBounds {
+		bound(kw, false)
+	}
+
+	if nv, ok := newS["multipleOf"].(float64); ok {
+		ov, had := oldS["multipleOf"].(float64)
+		if !had || ov/nv != float64(int64(ov/nv)) {
+			d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, "multipleOf"),
+				fmt.Sprint(oldS["multipleOf"]), fmt.Sprint(nv),
+				"%s multipleOf changed to %v", jsonSchemaSubject(path), nv)
+		}
+	}
+	if np, ok := newS["pattern"].(string); ok {
+		if op, _ := oldS["pattern"].(string); op != np {
+			d.add(false, "jsonschema-pattern-changed", jsonSchemaJoin(path, "pattern"), op, np,
+				"%s pattern changed from %q to %q", jsonSchemaSubject(path), op, np)
+		}
+	}
+	if nf, ok := newS["format"].(string); ok {
+		if of, _ := oldS["format"].(string); of != nf {
+			d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, "format"), of, nf,
+				"%s format changed from %q to %q", jsonSchemaSubject(path), of, nf)
+		}
+	}
+	if nu, _ := newS["uniqueItems"].(bool); nu {
+		if ou, _ := oldS["uniqueItems"].(bool); !ou {
+			d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, "uniqueItems"), "false", "true",
+				"%s now requires unique items", jsonSchemaSubject(path))
+		}
+	}
+}
+
+// jsonSchemaStringSet returns an array-of-strings keyword as a set.
+func jsonSchemaStringSet(v interface{}) map[string]bool {
+	arr, _ := v.([]interface{})
+	set := make(map[string]bool, len(arr))
+	for _, e := range arr {
+		if s, ok := e.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d *jsonSchemaDiff) compareObject(oldN, newN jsonSchemaNode, oldS, newS map[string]interface{}, path string) {
+	oldProps, _ := oldS["properties"].(map[string]interface{})
+	newProps, _ := newS["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(oldProps) {
+		child := jsonSchemaJoin(path, name)
+		np, ok := newProps[name]
+		if !ok {
+			d.add(true, "jsonschema-property-removed", child, "", "", "property %q removed", child)
+			continue
+		}
+		d.compare(newJSONSchemaNode(oldN.doc, oldProps[name]), newJSONSchemaNode(newN.doc, np), child)
+	}
+
+	oldReq := jsonSchemaStringSet(oldS["required"])
+	newReq := jsonSchemaStringSet(newS["required"])
+	for _, name := range sortedKeys(newReq) {
+		if !oldReq[name] {
+			child := jsonSchemaJoin(path, name)
+			d.add(false, "jsonschema-required-added", child, "", "", "field %q added to required", child)
+		}
+	}
+
+	oldAP, oldHas := oldS["additionalProperties"]
+	newAP, newHas := newS["additionalProperties"]
+	if !newHas || newAP == true {
+		return
+	}
+	if oldAP == false {
+		return
+	}
+	elem := jsonSchemaJoin(path, "{*}")
+	if newAP == false {
+		d.add(false, "jsonschema-additional-properties-closed", elem, "open", "false",
+			"%s no longer allows additional properties", jsonSchemaSubject(path))
+		return
+	}
+	if !oldHas || oldAP == true {
+		d.add(false, "jsonschema-additional-properties-closed", elem, "open", "schema",
+			"%s now constrains additional properties", jsonSchemaSubject(path))
+		return
+	}
+	d.compare(newJSONSchemaNode(oldN.doc, oldAP), newJSONSchemaNode(newN.doc, newAP), elem)
+}
+
+func (d *jsonSchemaDiff) compareItems(oldN, newN jsonSchemaNode, oldS, newS map[string]interface{}, path string) {
+	// Tuple forms: prefixItems (2020-12) or items as an array (draft-07).
+	tuple := func(s map[string]interface{}) []interface{} {
+		if p, ok := s["prefixItems"].([]interface{}); ok {
+			return p
+		}
+		p, _ := s["items"].([]interface{})
+		return p
+	}
+	oldTuple, newTuple := tuple(oldS), tuple(newS)
+	for i := 0; i < len(oldTuple) && i < len(newTuple); i++ {
+		d.compare(newJSONSchemaNode(oldN.doc, oldTuple[i]), newJSONSchemaNode(newN.doc, newTuple[i]),
+			fmt.Sprintf("%s[%d]", path, i))
+	}
+
+	newItems, newIsSchema := newS["items"].(map[string]interface{})
+	if !newIsSchema {
+		if b, ok := newS["items"].(bool); !ok || b {
+			return
+		}
+	}
+	elem := path + "[]"
+	oldItems, oldHas := oldS["items"]
+	if !oldHas || oldItems == true {
+		d.add(false, "jsonschema-constraint-tightened", elem, "", "",
+			"%s now constrains its array items", jsonSchemaSubject(path))
+		return
+	}
+	if _, isTuple := oldItems.([]interface{}); isTuple {
+		return
+	}
+	var newItemsVal interface{} = newItems
+	if !newIsSchema {
+		newItemsVal = newS["items"]
+	}
+	d.compare(newJSONSchemaNode(oldN.doc, oldItems), newJSONSchemaNode(newN.doc, newItemsVal), elem)
+}
+
+func (d *jsonSchemaDiff) compareCombinators(oldN, newN jsonSchemaNode, oldS, newS map[string]interface{}, path string) {
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		oldList, _ := oldS[kw].([]interface{})
+		newList, _ := newS[kw].([]interface{})
+		pairs, removed, added := matchJSONSchemaBranches(oldList, newList)
+		for _, p := range pairs {
+			d.compare(newJSONSchemaNode(oldN.doc, oldList[p[0]]), newJSONSchemaNode(newN.doc, newList[p[1]]),
+				jsonSchemaJoin(path, fmt.Sprintf("%s[%d]", kw, p[1])))
+		}
+		switch {
+		case kw == "allOf" && len(oldList) > 0:
+			for _, i := range added {
+				d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, fmt.Sprintf("%s[%d]", kw, i)), "", jsonSchemaBranchLabel(newList[i]),
+					"%s gained allOf constraint %s", jsonSchemaSubject(path), jsonSchemaBranchLabel(newList[i]))
+			}
+		case kw == "allOf" && len(newList) > 0:
+			d.add(false, "jsonschema-constraint-tightened", jsonSchemaJoin(path, kw), "0", fmt.Sprint(len(newList)),
+				"%s gained %d allOf constraint(s)", jsonSchemaSubject(path), len(newList))
+		case kw != "allOf" && len(newList) > 0:
+			// Added alternatives only widen what validates; removed ones are
+			// reported individually.
+			for _, i := range removed {
+				d.add(false, "jsonschema-branch-removed", jsonSchemaJoin(path, fmt.Sprintf("%s[%d]", kw, i)), jsonSchemaBranchLabel(oldList[i]), "",
+					"%s %s no longer allows %s", jsonSchemaSubject(path), kw, jsonSchemaBranchLabel(oldList[i]))
+			}
+		}
+	}
+}
+
+// matchJSONSchemaBranches pairs combinator branches across versions so that
+// reordering is not mistaken for a change. Identical branches pair first,
+// then branches sharing a $ref or title (compared recursively by the
+// caller), then whatever remains in order of appearance. It returns the
+// [old, new] index pairs that need a recursive comparison plus the old and
+// new indexes left unpaired.
+func matchJSONSchemaBranches(oldList, newList []interface{}) (pairs [][2]int, removed, added []int) {
+	oldUsed := make([]bool, len(oldList))
+	newUsed := make([]bool, len(newList))
+	pair := func(same func(o, n interface{}) bool, record bool) {
+		for i, o := range oldList {
+			if oldUsed[i] {
+				continue
+			}
+			for j, n := range newList {
+				if !newUsed[j] && same(o, n) {
+					oldUsed[i], newUsed[j] = true, true
+					if record {
+						pairs = append(pairs, [2]int{i, j})
+					}
+					break
+				}
+			}
+		}
+	}
+	pair(reflect.DeepEqual, false)
+	for _, key := range []string{"$ref", "title"} {
+		pair(func(o, n interface{}) bool {
+			k := jsonSchemaBranchKey(o, key)
+			return k != "" && k == jsonSchemaBranchKey(n, key)
+		}, true)
+	}
+	// Branches that carry an identity of their own are only paired with
+	// a branch of the same identity; the rest pair up in order.
+	pair(func(o, n interface{}) bool { return !jsonSchemaBranchNamed(o) && !jsonSchemaBranchNamed(n) }, true)
+	for i, used := range oldUsed {
+		if !used {
+			removed = append(removed, i)
+		}
+	}
+	for j, used := range newUsed {
+		if !used {
+			added = append(added, j)
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool { return pairs[a][1] < pairs[b][1] })
+	return pairs, removed, added
+}
+
+func jsonSchemaBranchKey(branch interface{}, key string) string {
+	s, _ := branch.(map[string]interface{})
+	v, _ := s[key].(string)
+	return v
+}
+
+func jsonSchemaBranchNamed(branch interface{}) bool {
+	return jsonSchemaBranchKey(branch, "$ref") != "" || jsonSchemaBranchKey(branch, "title") != ""
+}
+
+// jsonSchemaBranchLabel names a combinator branch in findings.
+func jsonSchemaBranchLabel(branch interface{}) string {
+	if ref := jsonSchemaBranchKey(branch, "$ref"); ref != "" {
+		return ref
+	}
+	if title := jsonSchemaBranchKey(branch, "title"); title != "" {
+		return strconv.Quote(title)
+	}
+	s, _ := branch.(map[string]interface{})
+	if types := jsonSchemaTypes(s); types != nil {
+		return jsonSchemaTypeLabel(types)
+	}
+	b, _ := json.Marshal(branch)
+	return string(b)
+}
+
+// diffJSONSchemaFiles compares two schema files. file is recorded on every
+// finding.
+func diffJSONSchemaFiles(oldFile, newFile, file string, lenient bool) ([]Finding, error) {
+	loader := newJSONSchemaLoader()
+	oldDoc, err := loader.load(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := loader.load(newFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading new schema: %w", err)
+	}
+	d := &jsonSchemaDiff{loader: loader, lenient: lenient, file: file, seen: map[string]bool{}}
+	d.compare(newJSONSchemaNode(oldDoc, oldDoc.root), newJSONSchemaNode(newDoc, newDoc.root), "")
+	return d.findings, nil
+}
@@ -0,0 +1,259 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONSchemaBreakingFindings_Deep(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		mode     string
+		wantRule string // "" means no findings
+		wantPath string
+		wantSev  Severity
+	}{
+		{
+			name:     "nested property removed",
+			old:      `{"type":"object","properties":{"a":{"type":"object","properties":{"b":{"type":"string"}}}}}`,
+			new:      `{"type":"object","properties":{"a":{"type":"object","properties":{}}}}`,
+			wantRule: "jsonschema-property-removed", wantPath: "a.b", wantSev: SeverityError,
+		},
+		{
+			name:     "type changed behind local $ref",
+			old:      `{"properties":{"id":{"$ref":"#/$defs/Id"}},"$defs":{"Id":{"type":"string"}}}`,
+			new:      `{"properties":{"id":{"$ref":"#/$defs/Id"}},"$defs":{"Id":{"type":"integer"}}}`,
+			wantRule: "jsonschema-type-changed", wantPath: "id", wantSev: SeverityError,
+		},
+		{
+			name:     "draft-07 definitions ref",
+			old:      `{"properties":{"id":{"$ref":"#/definitions/Id"}},"definitions":{"Id":{"type":"string","maxLength":10}}}`,
+			new:      `{"properties":{"id":{"$ref":"#/definitions/Id"}},"definitions":{"Id":{"type":"string","maxLength":5}}}`,
+			wantRule: "jsonschema-constraint-tightened", wantPath: "id.maxLength", wantSev: SeverityError,
+		},
+		{
+			name: "integer widened to number is compatible",
+			old:  `{"properties":{"n":{"type":"integer"}}}`,
+			new:  `{"properties":{"n":{"type":"number"}}}`,
+		},
+		{
+			name:     "type union narrowed",
+			old:      `{"properties":{"n":{"type":["string","null"]}}}`,
+			new:      `{"properties":{"n":{"type":"string"}}}`,
+			wantRule: "jsonschema-type-narrowed", wantPath: "n", wantSev: SeverityError,
+		},
+		{
+			name:     "enum narrowed",
+			old:      `{"properties":{"s":{"enum":["a","b","c"]}}}`,
+			new:      `{"properties":{"s":{"enum":["a","b"]}}}`,
+			wantRule: "jsonschema-enum-narrowed", wantPath: "s", wantSev: SeverityError,
+		},
+		{
+			name: "enum widened is compatible",
+			old:  `{"properties":{"s":{"enum":["a"]}}}`,
+			new:  `{"properties":{"s":{"enum":["a","b"]}}}`,
+		},
+		{
+			name:     "minimum raised",
+			old:      `{"properties":{"n":{"type":"integer","minimum":0}}}`,
+			new:      `{"properties":{"n":{"type":"integer","minimum":1}}}`,
+			wantRule: "jsonschema-constraint-tightened", wantPath: "n.minimum", wantSev: SeverityError,
+		},
+		{
+			name: "maximum raised is compatible",
+			old:  `{"properties":{"n":{"type":"integer","maximum":10}}}`,
+			new:  `{"properties":{"n":{"type":"integer","maximum":20}}}`,
+		},
+		{
+			name:     "pattern added",
+			old:      `{"properties":{"s":{"type":"string"}}}`,
+			new:      `{"properties":{"s":{"type":"string","pattern":"^[a-z]+$"}}}`,
+			wantRule: "jsonschema-pattern-changed", wantPath: "s.pattern", wantSev: SeverityError,
+		},
+		{
+			name:     "additionalProperties closed",
+			old:      `{"type":"object"}`,
+			new:      `{"type":"object","additionalProperties":false}`,
+			wantRule: "jsonschema-additional-properties-closed", wantPath: "{*}", wantSev: SeverityError,
+		},
+		{
+			name:     "array item type changed",
+			old:      `{"properties":{"tags":{"type":"array","items":{"type":"string"}}}}`,
+			new:      `{"properties":{"tags":{"type":"array","items":{"type":"integer"}}}}`,
+			wantRule: "jsonschema-type-changed", wantPath: "tags[]", wantSev: SeverityError,
+		},
+		{
+			name:     "oneOf branch removed",
+			old:      `{"oneOf":[{"type":"string"},{"type":"integer"}]}`,
+			new:      `{"oneOf":[{"type":"string"}]}`,
+			wantRule: "jsonschema-branch-removed", wantPath: "oneOf[1]", wantSev: SeverityError,
+		},
+		{
+			name:     "middle anyOf branch removed",
+			old:      `{"anyOf":[{"type":"string"},{"type":"integer"},{"type":"boolean"}]}`,
+			new:      `{"anyOf":[{"type":"string"},{"type":"boolean"}]}`,
+			wantRule: "jsonschema-branch-removed", wantPath: "anyOf[1]", wantSev: SeverityError,
+		},
+		{
+			name:     "reordered ref branch compared with its match",
+			old:      `{"$defs":{"A":{"type":"object"},"B":{"type":"object"}},"oneOf":[{"$ref":"#/$defs/A"},{"$ref":"#/$defs/B","required":["id"]}]}`,
+			new:      `{"$defs":{"A":{"type":"object"},"B":{"type":"object"}},"oneOf":[{"$ref":"#/$defs/B","required":["id","kind"]},{"$ref":"#/$defs/A"}]}`,
+			wantRule: "jsonschema-required-added", wantPath: "oneOf[0].kind", wantSev: SeverityError,
+		},
+		{
+			name:     "titled branch compared with its match",
+			old:      `{"oneOf":[{"title":"card","type":"string"},{"title":"cash","type":"integer"}]}`,
+			new:      `{"oneOf":[{"title":"cash","type":"integer"},{"title":"card","type":"string","maxLength":16}]}`,
+			wantRule: "jsonschema-constraint-tightened", wantPath: "oneOf[1].maxLength", wantSev: SeverityError,
+		},
+		{
+			name:     "allOf constraint added",
+			old:      `{"allOf":[{"required":["a"]}]}`,
+			new:      `{"allOf":[{"title":"b","required":["b"]},{"required":["a"]}]}`,
+			wantRule: "jsonschema-constraint-tightened", wantPath: "allOf[0]", wantSev: SeverityError,
+		},
+		{
+			name:     "allOf member tightened",
+			old:      `{"allOf":[{"properties":{"a":{"type":"string"}}}]}`,
+			new:      `{"allOf":[{"properties":{"a":{"type":"string"}},"required":["a"]}]}`,
+			wantRule: "jsonschema-required-added", wantPath: "allOf[0].a", wantSev: SeverityError,
+		},
+		{
+			name:     "lenient downgrades tightening to warning",
+			old:      `{"properties":{"s":{"enum":["a","b"]}}}`,
+			new:      `{"properties":{"s":{"enum":["a"]}}}`,
+			mode:     JSONSchemaLenient,
+			wantRule: "jsonschema-enum-narrowed", wantPath: "s", wantSev: SeverityWarning,
+		},
+		{
+			name:     "lenient still blocks removal",
+			old:      `{"properties":{"a":{"type":"string"}}}`,
+			new:      `{"properties":{}}`,
+			mode:     JSONSchemaLenient,
+			wantRule: "jsonschema-property-removed", wantPath: "a", wantSev: SeverityError,
+		},
+		{
+			name: "recursive $ref terminates",
+			old:  `{"$ref":"#/$defs/Node","$defs":{"Node":{"type":"object","properties":{"next":{"$ref":"#/$defs/Node"}}}}}`,
+			new:  `{"$ref":"#/$defs/Node","$defs":{"Node":{"type":"object","properties":{"next":{"$ref":"#/$defs/Node"}}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldPath := filepath.Join(dir, "old.json")
+			newPath := filepath.Join(dir, "new.json")
+			mustWrite(t, oldPath, tt.old)
+			mustWrite(t, newPath, tt.new)
+
+			v := NewJSONSchemaValidator(&ToolchainResolver{})
+			if tt.mode != "" {
+				v.SetBreakingMode(tt.mode)
+			}
+			findings, err := v.BreakingFindings(newPath, oldPath)
+			if err != nil {
+				t.Fatalf("BreakingFindings() error = %v", err)
+			}
+			if tt.wantRule == "" {
+				if len(findings) != 0 {
+					t.Fatalf("expected no findings, got %v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 {
+				t.Fatalf("expected 1 finding, got %v", findings)
+			}
+			f := findings[0]
+			if f.RuleID != tt.wantRule || f.Path != tt.wantPath || f.Severity != tt.wantSev {
+				t.Errorf("got %s %s %s, want %s %s %s", f.RuleID, f.Path, f.Severity, tt.wantRule, tt.wantPath, tt.wantSev)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaBreakingFindings_CombinatorCompatible(t *testing.T) {
+	tests := []struct {
+		name, old, new string
+	}{
+		{
+			name: "branches reordered",
+			old:  `{"oneOf":[{"type":"string"},{"type":"integer"},{"$ref":"#/$defs/A"}],"$defs":{"A":{"type":"object"}}}`,
+			new:  `{"oneOf":[{"$ref":"#/$defs/A"},{"type":"integer"},{"type":"string"}],"$defs":{"A":{"type":"object"}}}`,
+		},
+		{
+			name: "anyOf branch added",
+			old:  `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+			new:  `{"anyOf":[{"type":"boolean"},{"type":"string"},{"type":"integer"}]}`,
+		},
+		{
+			name: "allOf constraint removed",
+			old:  `{"allOf":[{"required":["a"]},{"required":["b"]}]}`,
+			new:  `{"allOf":[{"required":["b"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldPath := filepath.Join(dir, "old.json")
+			newPath := filepath.Join(dir, "new.json")
+			mustWrite(t, oldPath, tt.old)
+			mustWrite(t, newPath, tt.new)
+
+			v := NewJSONSchemaValidator(&ToolchainResolver{})
+			findings, err := v.BreakingFindings(newPath, oldPath)
+			if err != nil {
+				t.Fatalf("BreakingFindings() error = %v", err)
+			}
+			if len(findings) != 0 {
+				t.Fatalf("expected no findings, got %v", findings)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaBreakingFindings_RelativeFileRef(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	mustWrite(t, filepath.Join(oldDir, "common.json"), `{"$defs":{"Money":{"type":"object","properties":{"amount":{"type":"number"},"currency":{"type":"string"}}}}}`)
+	mustWrite(t, filepath.Join(newDir, "common.json"), `{"$defs":{"Money":{"type":"object","properties":{"amount":{"type":"number"}}}}}`)
+	root := `{"type":"object","properties":{"price":{"$ref":"common.json#/$defs/Money"}}}`
+	mustWrite(t, filepath.Join(oldDir, "order.json"), root)
+	mustWrite(t, filepath.Join(newDir, "order.json"), root)
+
+	v := NewJSONSchemaValidator(&ToolchainResolver{})
+	findings, err := v.BreakingFindings(filepath.Join(newDir, "order.json"), filepath.Join(oldDir, "order.json"))
+	if err != nil {
+		t.Fatalf("BreakingFindings() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "jsonschema-property-removed" || findings[0].Path != "price.currency" {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+}
+
+func TestJSONSchemaBreakingFindings_UnresolvedRef(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+	mustWrite(t, oldPath, `{"properties":{"a":{"type":"string"}}}`)
+	mustWrite(t, newPath, `{"properties":{"a":{"$ref":"#/$defs/Missing"}}}`)
+
+	findings, err := NewJSONSchemaValidator(&ToolchainResolver{}).BreakingFindings(newPath, oldPath)
+	if err != nil {
+		t.Fatalf("BreakingFindings() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "jsonschema-unresolved-ref" {
+		t.Fatalf("unexpected findings: %v", findings)
+	}
+}
+
+func TestJSONSchemaBreakingFindings_UnknownMode(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "s.json")
+	mustWrite(t, p, `{}`)
+	v := NewJSONSchemaValidator(&ToolchainResolver{})
+	v.SetBreakingMode("loose")
+	if _, err := v.BreakingFindings(p, p); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,303 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+// SeverityOff is the configured severity of a lint rule that is turned off.
+const SeverityOff Severity = "off"
+
+// LintRule is a native lint rule with a stable ID. apx.yaml can turn it off,
+// change its severity and, for a naming rule, replace its convention with a
+// regular expression.
+type LintRule struct {
+	ID       string
+	Format   SchemaFormat
+	Severity Severity // default severity; SeverityOff for opt-in rules
+	Naming   bool     // checks names against a convention a pattern can replace
+	Summary  string
+}
+
+// lintRules is the registry of native lint rules, by format.
+var lintRules = []LintRule{
+	{ID: "avro-invalid-json", Format: FormatAvro, Severity: SeverityError, Summary: "schema is valid JSON"},
+	{ID: "avro-idl-syntax", Format: FormatAvro, Severity: SeverityError, Summary: "Avro IDL parses"},
+	{ID: "avro-missing-type", Format: FormatAvro, Severity: SeverityError, Summary: "schema has a type"},
+	{ID: "avro-invalid-type", Format: FormatAvro, Severity: SeverityError, Summary: "type is a known Avro type"},
+	{ID: "avro-record-name", Format: FormatAvro, Severity: SeverityError, Summary: "record has a name"},
+	{ID: "avro-record-fields", Format: FormatAvro, Severity: SeverityError, Summary: "record has a non-empty fields array"},
+	{ID: "avro-field-invalid", Format: FormatAvro, Severity: SeverityError, Summary: "field is an object with a name and type"},
+	{ID: "avro-field-duplicate", Format: FormatAvro, Severity: SeverityError, Summary: "field names are unique within a record"},
+	{ID: "avro-field-camel-case", Format: FormatAvro, Severity: SeverityError, Naming: true, Summary: "field names are camelCase"},
+	{ID: "avro-record-pascal-case", Format: FormatAvro, Severity: SeverityOff, Naming: true, Summary: "record names are PascalCase"},
+	{ID: "avro-protocol-name", Format: FormatAvro, Severity: SeverityError, Summary: "protocol name is a string"},
+	{ID: "avro-protocol-invalid", Format: FormatAvro, Severity: SeverityError, Summary: "protocol types and messages resolve"},
+
+	{ID: "parquet-syntax", Format: FormatParquet, Severity: SeverityError, Summary: "message notation parses"},
+	{ID: "parquet-physical-type", Format: FormatParquet, Severity: SeverityError, Summary: "physical types and lengths are valid"},
+	{ID: "parquet-logical-type", Format: FormatParquet, Severity: SeverityError, Summary: "logical type annotations are valid for their physical type"},
+	{ID: "parquet-message-name", Format: FormatParquet, Severity: SeverityError, Naming: true, Summary: "message name is PascalCase or snake_case"},
+	{ID: "parquet-message-empty", Format: FormatParquet, Severity: SeverityError, Summary: "message has columns"},
+	{ID: "parquet-column-snake-case", Format: FormatParquet, Severity: SeverityError, Naming: true, Summary: "column names are snake_case"},
+	{ID: "parquet-column-duplicate", Format: FormatParquet, Severity: SeverityError, Summary: "column names are unique within a group"},
+	{ID: "parquet-group-empty", Format: FormatParquet, Severity: SeverityError, Summary: "groups have fields"},
+	{ID: "parquet-list-structure", Format: FormatParquet, Severity: SeverityError, Summary: "LIST groups use the three-level structure"},
+	{ID: "parquet-map-structure", Format: FormatParquet, Severity: SeverityError, Summary: "MAP groups use the three-level structure"},
+
+	{ID: "jsonschema-invalid-json", Format: FormatJSONSchema, Severity: SeverityError, Summary: "schema is valid JSON"},
+	{ID: "jsonschema-draft", Format: FormatJSONSchema, Severity: SeverityError, Summary: "$schema names a known draft"},
+	{ID: "jsonschema-invalid-type", Format: FormatJSONSchema, Severity: SeverityError, Summary: "type is a JSON Schema type"},
+	{ID: "jsonschema-invalid-keyword", Format: FormatJSONSchema, Severity: SeverityError, Summary: "properties, required and items are well formed"},
+
+	{ID: "crd-api-version", Format: FormatCRD, Severity: SeverityError, Summary: "apiVersion is apiextensions.k8s.io/v1"},
+	{ID: "crd-group", Format: FormatCRD, Severity: SeverityError, Summary: "spec.group is a DNS subdomain"},
+	{ID: "crd-names", Format: FormatCRD, Severity: SeverityError, Summary: "spec.names has a kind and a lowercase plural"},
+	{ID: "crd-scope", Format: FormatCRD, Severity: SeverityError, Summary: "spec.scope is Namespaced or Cluster"},
+	{ID: "crd-metadata-name", Format: FormatCRD, Severity: SeverityError, Summary: "metadata.name is <plural>.<group>"},
+	{ID: "crd-versions", Format: FormatCRD, Severity: SeverityError, Summary: "at least one version is declared"},
+	{ID: "crd-version-name", Format: FormatCRD, Severity: SeverityError, Summary: "version names are unique Kubernetes versions"},
+	{ID: "crd-storage-version", Format: FormatCRD, Severity: SeverityError, Summary: "exactly one version is the storage version"},
+	{ID: "crd-served-version", Format: FormatCRD, Severity: SeverityError, Summary: "at least one version is served"},
+	{ID: "crd-schema-missing", Format: FormatCRD, Severity: SeverityError, Summary: "every version has an openAPIV3Schema"},
+	{ID: "crd-root-type", Format: FormatCRD, Severity: SeverityError, Summary: "the root schema is an object"},
+	{ID: "crd-structural-type", Format: FormatCRD, Severity: SeverityError, Summary: "every schema node has a valid type"},
+	{ID: "crd-structural-properties", Format: FormatCRD, Severity: SeverityError, Summary: "properties and additionalProperties are not combined"},
+	{ID: "crd-structural-items", Format: FormatCRD, Severity: SeverityError, Summary: "arrays specify items"},
+}
+
+// LintRules returns the native lint rules of format, or of every format
+// when format is FormatUnknown.
+func LintRules(format SchemaFormat) []LintRule {
+	var rules []LintRule
+	for _, r := range lintRules {
+		if format == FormatUnknown || r.Format == format {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// lookupLintRule returns the registered rule with the given ID.
+func lookupLintRule(id string) (LintRule, bool) {
+	for _, r := range lintRules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return LintRule{}, false
+}
+
+// lintRuleSet is the lint rule configuration of apx.yaml: a severity and
+// naming pattern per rule ID. A nil set applies the defaults.
+type lintRuleSet struct {
+	severity map[string]Severity
+	patterns map[string]*regexp.Regexp
+}
+
+// newLintRuleSet checks the configured rules of a format against the
+// registry and compiles their patterns.
+func newLintRuleSet(format SchemaFormat, rules map[string]config.LintRule) (*lintRuleSet, error) {
+	rs := &lintRuleSet{severity: map[string]Severity{}, patterns: map[string]*regexp.Regexp{}}
+	for _, id := range sortedKeys(rules) {
+		cfg := rules[id]
+		rule, ok := lookupLintRule(id)
+		if !ok || rule.Format != format {
+			return nil, fmt.Errorf("lint.%s.rules: unknown rule %q (run 'apx lint --list-rules --format %s')", format, id, format)
+		}
+		switch sev := Severity(cfg.Severity); sev {
+		case "":
+		case SeverityOff, SeverityWarning, SeverityError:
+			rs.severity[id] = sev
+		default:
+			return nil, fmt.Errorf("lint.%s.rules.%s: invalid severity %q; must be off, warning or error", format, id, cfg.Severity)
+		}
+		if cfg.Pattern == "" {
+			continue
+		}
+		if !rule.Naming {
+			return nil, fmt.Errorf("lint.%s.rules.%s: pattern is only supported by naming rules", format, id)
+		}
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("lint.%s.rules.%s: invalid pattern: %w", format, id, err)
+		}
+		rs.patterns[id] = re
+	}
+	return rs, nil
+}
+
+// enabled reports whether rule id is on, for opt-in rules whose check only
+// runs when configured.
+func (rs *lintRuleSet) enabled(id string) bool {
+	rule, _ := lookupLintRule(id)
+	sev := rule.Severity
+	if rs != nil {
+		if s, ok := rs.severity[id]; ok {
+			sev = s
+		}
+	}
+	return sev != SeverityOff
+}
+
+// nameOK reports whether name satisfies naming rule id: the configured
+// pattern when there is one, otherwise the rule's built-in convention.
+func (rs *lintRuleSet) nameOK(id, name string, convention func(string) bool) bool {
+	if rs != nil {
+		if re, ok := rs.patterns[id]; ok {
+			return re.MatchString(name)
+		}
+	}
+	return convention(name)
+}
+
+// apply drops the findings of rules that are off and sets the configured
+// severity of the others. Findings of rules outside the registry are kept
+// as they are.
+func (rs *lintRuleSet) apply(findings []Finding) []Finding {
+	kept := findings[:0]
+	for _, f := range findings {
+		rule, registered := lookupLintRule(f.RuleID)
+		sev := rule.Severity
+		if rs != nil {
+			if s, ok := rs.severity[f.RuleID]; ok {
+				sev = s
+			}
+		}
+		switch {
+		case !registered:
+		case sev == SeverityOff:
+			continue
+		default:
+			f.Severity = sev
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// SetLintRules applies the lint rule configuration of one format from
+// apx.yaml to LintFindings. An unknown rule, severity or pattern is an
+// error.
+func (v *Validator) SetLintRules(format SchemaFormat, rules map[string]config.LintRule) error {
+	rs, err := newLintRuleSet(format, rules)
+	if err != nil {
+		return err
+	}
+	if v.lintRules == nil {
+		v.lintRules = map[SchemaFormat]*lintRuleSet{}
+	}
+	v.lintRules[format] = rs
+	switch format {
+	case FormatAvro:
+		v.avroValidator.rules = rs
+	case FormatParquet:
+		v.parquetValidator.rules = rs
+	}
+	return nil
+}
+
+// ignoreDirective matches an inline suppression comment:
+//
+//	// apx:ignore parquet-column-snake-case
+//	# apx:ignore-file crd-group crd-names
+var ignoreDirective = regexp.MustCompile(`(?://|#)\s*apx:ignore(-file)?((?:[\s,]+[a-z0-9-]+)+)`)
+
+// suppressions are the rules inline comments turn off: per line and for
+// the whole file.
+type suppressions struct {
+	lines map[int][]string
+	file  []string
+}
+
+// readSuppressions collects the apx:ignore comments of a file.
+func readSuppressions(path string) suppressions {
+	s := suppressions{lines: map[int][]string{}}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(data, []byte("apx:ignore")) {
+		return s
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		m := ignoreDirective.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		rules := strings.FieldsFunc(line[m[4]:m[5]], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if m[2] >= 0 {
+			s.file = append(s.file, rules...)
+			continue
+		}
+		// A directive on a line of its own covers the next line; a
+		// trailing one covers the line it ends.
+		target := n
+		if strings.TrimSpace(line[:m[0]]) == "" {
+			target = n + 1
+		}
+		s.lines[target] = append(s.lines[target], rules...)
+	}
+	return s
+}
+
+// covers reports whether the suppressions turn off rule at line.
+func (s suppressions) covers(rule string, line int) bool {
+	return containsString(s.file, rule) || (line > 0 && containsString(s.lines[line], rule))
+}
+
+// suppressInline drops the findings turned off by apx:ignore comments in
+// their files.
+func suppressInline(findings []Finding) []Finding {
+	byFile := map[string]suppressions{}
+	kept := findings[:0]
+	for _, f := range findings {
+		if f.File != "" {
+			s, ok := byFile[f.File]
+			if !ok {
+				s = readSuppressions(f.File)
+				byFile[f.File] = s
+			}
+			if s.covers(f.RuleID, f.Line) {
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// ignoredRules reads an x-apx-ignore annotation: a rule ID or a list of
+// them. Anything else ignores nothing.
+func ignoredRules(raw json.RawMessage) []string {
+	if raw == nil {
+		return nil
+	}
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return []string{one}
+	}
+	var many []string
+	_ = json.Unmarshal(raw, &many)
+	return many
+}
+
+// ignoring wraps a finding callback to drop the rules an x-apx-ignore
+// annotation lists.
+func ignoring(add func(rule, elem, format string, args ...interface{}), ignored []string) func(rule, elem, format string, args ...interface{}) {
+	if len(ignored) == 0 {
+		return add
+	}
+	return func(rule, elem, format string, args ...interface{}) {
+		if !containsString(ignored, rule) {
+			add(rule, elem, format, args...)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+func lintRuleIDs(findings []Finding) map[string]Severity {
+	got := make(map[string]Severity)
+	for _, f := range findings {
+		got[f.RuleID] = f.Severity
+	}
+	return got
+}
+
+func TestLintRules_Registry(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, r := range LintRules(FormatUnknown) {
+		if seen[r.ID] {
+			t.Errorf("duplicate rule %s", r.ID)
+		}
+		seen[r.ID] = true
+		if !strings.HasPrefix(r.ID, string(r.Format)+"-") {
+			t.Errorf("rule %s is not prefixed with its format %s", r.ID, r.Format)
+		}
+		if r.Summary == "" {
+			t.Errorf("rule %s has no summary", r.ID)
+		}
+	}
+	for _, f := range []SchemaFormat{FormatAvro, FormatParquet, FormatJSONSchema, FormatCRD} {
+		if len(LintRules(f)) == 0 {
+			t.Errorf("no rules registered for %s", f)
+		}
+	}
+}
+
+func TestValidator_SetLintRules(t *testing.T) {
+	file := writeTemp(t, "events.parquet", `message Events {
+  required int64 eventId;
+  optional binary userName (STRING);
+}
+`)
+	v := NewValidator(&ToolchainResolver{})
+	findings, err := v.LintFindings(file, FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 || !HasErrors(findings) {
+		t.Fatalf("expected two snake_case errors by default, got %+v", findings)
+	}
+
+	// A pattern replaces the convention; a severity downgrades the rule.
+	err = v.SetLintRules(FormatParquet, map[string]config.LintRule{
+		"parquet-column-snake-case": {Severity: "warning", Pattern: `^[a-z][a-zA-Z0-9]*$`},
+		"parquet-message-name":      {Severity: "off"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err = v.LintFindings(file, FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected camelCase columns to match the pattern, got %+v", findings)
+	}
+
+	err = v.SetLintRules(FormatParquet, map[string]config.LintRule{
+		"parquet-column-snake-case": {Severity: "warning"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err = v.LintFindings(file, FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 || HasErrors(findings) {
+		t.Errorf("expected two warnings, got %+v", findings)
+	}
+	if err := v.Lint(file, FormatParquet); err != nil {
+		t.Errorf("Lint must follow the configured severities, got: %v", err)
+	}
+}
+
+func TestValidator_SetLintRules_OptIn(t *testing.T) {
+	file := writeTemp(t, "user.avsc",
+		`{"type":"record","name":"user_profile","fields":[{"name":"id","type":"string"}]}`)
+	v := NewValidator(&ToolchainResolver{})
+	findings, err := v.LintFindings(file, FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("expected the opt-in rule to be off, got %+v", findings)
+	}
+	if err := v.SetLintRules(FormatAvro, map[string]config.LintRule{"avro-record-pascal-case": {Severity: "error"}}); err != nil {
+		t.Fatal(err)
+	}
+	findings, err = v.LintFindings(file, FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lintRuleIDs(findings); got["avro-record-pascal-case"] != SeverityError || len(findings) != 1 {
+		t.Errorf("expected avro-record-pascal-case error, got %+v", findings)
+	}
+}
+
+func TestValidator_SetLintRules_Invalid(t *testing.T) {
+	v := NewValidator(&ToolchainResolver{})
+	tests := []struct {
+		format SchemaFormat
+		rules  map[string]config.LintRule
+		want   string
+	}{
+		{FormatAvro, map[string]config.LintRule{"avro-nope": {}}, `lint.avro.rules: unknown rule "avro-nope"`},
+		{FormatAvro, map[string]config.LintRule{"parquet-syntax": {}}, `lint.avro.rules: unknown rule "parquet-syntax"`},
+		{FormatCRD, map[string]config.LintRule{"crd-group": {Severity: "fatal"}}, `lint.crd.rules.crd-group: invalid severity "fatal"`},
+		{FormatCRD, map[string]config.LintRule{"crd-group": {Pattern: "x"}}, "pattern is only supported by naming rules"},
+		{FormatAvro, map[string]config.LintRule{"avro-field-camel-case": {Pattern: "("}}, "invalid pattern"},
+	}
+	for _, tt := range tests {
+		err := v.SetLintRules(tt.format, tt.rules)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("SetLintRules(%s, %v): error %v, want %q", tt.format, tt.rules, err, tt.want)
+		}
+	}
+}
+
+func TestLintFindings_InlineSuppression(t *testing.T) {
+	v := NewValidator(&ToolchainResolver{})
+
+	parquet := writeTemp(t, "events.parquet", `message events {
+  // apx:ignore parquet-column-snake-case
+  required int64 eventId;
+  optional binary userName (STRING); // apx:ignore parquet-column-snake-case
+  optional binary legacyTag (STRING);
+}
+`)
+	findings, err := v.LintFindings(parquet, FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Path != "legacyTag" {
+		t.Errorf("expected only legacyTag to be reported, got %+v", findings)
+	}
+
+	crd := writeTemp(t, "widget.yaml", "# apx:ignore-file crd-group, crd-metadata-name\n"+
+		strings.ReplaceAll(validCRD, "example.com", "example"))
+	findings, err = v.LintFindings(crd, FormatCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected the file-level directive to suppress the CRD findings, got %+v", findings)
+	}
+}
+
+func TestLintFindings_XAPXIgnore(t *testing.T) {
+	v := NewValidator(&ToolchainResolver{})
+
+	avro := writeTemp(t, "user.avsc", `{"type":"record","name":"User","fields":[
+  {"name":"user_id","type":"string","x-apx-ignore":["avro-field-camel-case"]},
+  {"name":"legacy_name","type":"string"}
+]}`)
+	findings, err := v.LintFindings(avro, FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Path != "User.legacy_name" {
+		t.Errorf("expected only legacy_name to be reported, got %+v", findings)
+	}
+
+	schema := writeTemp(t, "order.json",
+		`{"$schema":"https://example.com/custom","x-apx-ignore":"jsonschema-draft","type":"object"}`)
+	findings, err = v.LintFindings(schema, FormatJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected x-apx-ignore to suppress jsonschema-draft, got %+v", findings)
+	}
+}
+
+func TestParquetBreaking_IgnoresNamingRules(t *testing.T) {
+	oldFile := writeTemp(t, "v1.parquet", "message Events {\n  required int64 eventId;\n}\n")
+	newFile := writeTemp(t, "v2.parquet", "message Events {\n  required int64 eventId;\n  optional binary userName (STRING);\n}\n")
+	findings, err := NewParquetValidator(&ToolchainResolver{}).BreakingFindings(newFile, oldFile)
+	if err != nil {
+		t.Fatalf("legacy column names should not block the comparison: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected an additive change, got %+v", findings)
+	}
+}
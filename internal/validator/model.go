@@ -0,0 +1,698 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Element kinds of the schema model. The kinds are format-neutral, so one
+// policy rule can cover the equivalent declarations of every format.
+const (
+	ElementFile      = "file"       // a schema file
+	ElementMessage   = "message"    // proto message, Avro record, JSON Schema object, Parquet message or group
+	ElementField     = "field"      // message field, record field, schema property, operation parameter, Parquet column
+	ElementEnum      = "enum"       // proto or Avro enum, JSON Schema string enum definition
+	ElementEnumValue = "enum_value" // enum value or symbol
+	ElementService   = "service"    // proto service, Avro protocol, OpenAPI document
+	ElementRPC       = "rpc"        // proto rpc, Avro protocol message, OpenAPI operation
+)
+
+// ElementKinds lists every element kind.
+var ElementKinds = []string{
+	ElementFile, ElementMessage, ElementField, ElementEnum, ElementEnumValue, ElementService, ElementRPC,
+}
+
+// Element is one declaration of the format-neutral schema model that policy
+// rules are evaluated over.
+type Element struct {
+	Kind      string
+	Format    SchemaFormat
+	File      string // path relative to the model root
+	Line      int    // 1-based line of the declaration, 0 when not known
+	Name      string
+	FullName  string // qualified name, e.g. acme.users.v1.User.email or "GET /users"
+	Parent    string // FullName of the enclosing element; the package or namespace at top level
+	Namespace string // proto package, Avro namespace
+	// Type is the declared type of a field (as written, e.g. "string",
+	// "google.type.Money", "array<Item>", "[null, string]") and the
+	// declaration keyword of other elements (e.g. "message", "record").
+	Type     string
+	Comment  string // leading comment, doc or description
+	Required bool   // must be present: proto2 required, Avro field without default, listed in JSON Schema required, Parquet required
+	Nullable bool   // may be absent or null: proto optional, union with null, JSON Schema null type, Parquet optional
+	Repeated bool   // holds a list: repeated or map fields, arrays, Parquet repeated
+	// Attrs holds format-specific properties, such as a proto field's
+	// number and options or an OpenAPI operation's method.
+	Attrs map[string]interface{}
+}
+
+// LoadSchemaModel reads every schema file of format at path (a file or a
+// directory) into the element model, files in path order and elements in
+// declaration order. With FormatUnknown each file's format is recognized
+// from its extension and, for JSON and YAML, its content; files that are
+// not schemas are skipped.
+func LoadSchemaModel(path string, format SchemaFormat) ([]Element, error) {
+	files, err := modelFiles(path, format)
+	if err != nil {
+		return nil, err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	var elems []Element
+	for _, rel := range rels {
+		file := files[rel]
+		fileFormat := format
+		if fileFormat == FormatUnknown {
+			fileFormat = modelFileFormat(rel)
+		}
+		fileElems, err := loadModelFile(file, rel, fileFormat)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		elems = append(elems, fileElems...)
+	}
+	return elems, nil
+}
+
+// modelFiles lists the schema files under path, keyed by relative path.
+func modelFiles(path string, format SchemaFormat) (map[string]string, error) {
+	if format != FormatUnknown {
+		return schemaFiles(format, path)
+	}
+	files := map[string]string{}
+	for _, f := range []SchemaFormat{FormatProto, FormatAvro, FormatParquet, FormatJSONSchema} {
+		found, err := schemaFiles(f, path)
+		if err != nil {
+			return nil, err
+		}
+		for rel, file := range found {
+			files[rel] = file
+		}
+	}
+	return files, nil
+}
+
+// modelFileFormat recognizes a file's format by extension. JSON and YAML
+// files are left FormatUnknown for loadModelFile to recognize by content.
+func modelFileFormat(name string) SchemaFormat {
+	for _, f := range []SchemaFormat{FormatProto, FormatAvro, FormatParquet} {
+		if isSchemaFileFor(f, filepath.Base(name)) {
+			return f
+		}
+	}
+	return FormatUnknown
+}
+
+// loadModelFile extracts the elements of one schema file.
+func loadModelFile(file, rel string, format SchemaFormat) ([]Element, error) {
+	b := &modelBuilder{file: filepath.ToSlash(rel), format: format}
+	switch format {
+	case FormatProto:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		b.proto(data)
+	case FormatAvro:
+		data, err := ReadAvroJSON(file)
+		if err != nil {
+			return nil, err
+		}
+		root, lines, err := decodeModelDocument(data)
+		if err != nil {
+			return nil, err
+		}
+		if isAvroIDL(file) {
+			lines = nil // lines of the translated JSON, not of the IDL
+		}
+		b.lines = lines
+		b.avro(root)
+	case FormatParquet:
+		msg, err := loadParquetSchema(file)
+		if err != nil {
+			return nil, err
+		}
+		b.parquet(msg)
+	default:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		root, lines, err := decodeModelDocument(data)
+		if err != nil {
+			if format == FormatUnknown {
+				return nil, nil // not a schema
+			}
+			return nil, err
+		}
+		obj, ok := root.(map[string]interface{})
+		if !ok {
+			if format == FormatUnknown {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("document is not an object")
+		}
+		b.lines = lines
+		switch {
+		case obj["openapi"] != nil || obj["swagger"] != nil:
+			b.format = FormatOpenAPI
+			return b.openAPI(file, data)
+		case LooksLikeCRD(data):
+			b.format = FormatCRD
+			b.crd(obj)
+		case format == FormatJSONSchema || obj["$schema"] != nil || obj["properties"] != nil ||
+			obj["$defs"] != nil || obj["definitions"] != nil:
+			b.format = FormatJSONSchema
+			b.jsonSchemaFile(obj)
+		}
+	}
+	return b.elems, nil
+}
+
+// decodeModelDocument parses JSON or YAML, recording the line of every
+// value by JSON pointer. A member of an object is located at its key, so
+// "get:" rather than the first line of the operation under it.
+func decodeModelDocument(data []byte) (interface{}, map[string]int, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, nil, err
+	}
+	lines := map[string]int{}
+	v, err := yamlNodeValue(&node, "", lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	yamlKeyLines(&node, "", lines)
+	return v, lines, nil
+}
+
+// yamlKeyLines records the line of each object member's key.
+func yamlKeyLines(n *yaml.Node, ptr string, lines map[string]int) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			yamlKeyLines(n.Content[0], ptr, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			child := ptr + "/" + jsonPointerEscape(n.Content[i].Value)
+			lines[child] = n.Content[i].Line
+			yamlKeyLines(n.Content[i+1], child, lines)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			yamlKeyLines(c, fmt.Sprintf("%s/%d", ptr, i), lines)
+		}
+	}
+}
+
+// modelBuilder accumulates the elements of one file.
+type modelBuilder struct {
+	file   string
+	format SchemaFormat
+	lines  map[string]int // JSON pointer → line, for JSON and YAML documents
+	elems  []Element
+}
+
+// add appends an element, filling in the file and format.
+func (b *modelBuilder) add(e Element) int {
+	e.File = b.file
+	e.Format = b.format
+	if e.Attrs == nil {
+		e.Attrs = map[string]interface{}{}
+	}
+	b.elems = append(b.elems, e)
+	return len(b.elems) - 1
+}
+
+// line returns the line of the value at ptr, or of its nearest ancestor.
+func (b *modelBuilder) line(ptr string) int {
+	if b.lines == nil {
+		return 0
+	}
+	for {
+		if l, ok := b.lines[ptr]; ok {
+			return l
+		}
+		i := strings.LastIndex(ptr, "/")
+		if i < 0 {
+			return 0
+		}
+		ptr = ptr[:i]
+	}
+}
+
+// fileElement adds the element of the file itself.
+func (b *modelBuilder) fileElement(namespace string, attrs map[string]interface{}) int {
+	return b.add(Element{
+		Kind: ElementFile, Line: 1, Name: filepath.Base(b.file), FullName: b.file,
+		Namespace: namespace, Type: string(b.format), Attrs: attrs,
+	})
+}
+
+// joinName qualifies name with a dotted prefix.
+func joinName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// --- JSON Schema, OpenAPI and CRD ---
+
+// jsonSchemaFile models a JSON Schema document: the root schema, named
+// after its title or file, and its $defs.
+func (b *modelBuilder) jsonSchemaFile(root map[string]interface{}) {
+	b.fileElement("", nil)
+	name, _ := root["title"].(string)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(b.file), filepath.Ext(b.file))
+	}
+	b.jsonSchema(root, "", name, name, "")
+	for _, key := range []string{"$defs", "definitions"} {
+		defs, _ := root[key].(map[string]interface{})
+		for _, def := range b.sortedByLine(defs, "/"+key) {
+			b.jsonSchema(defs[def], "/"+key+"/"+jsonPointerEscape(def), def, def, "")
+		}
+	}
+}
+
+// jsonSchema models an object schema as a message with its properties as
+// fields, recursing into inline object properties; a string enum schema
+// becomes an enum.
+func (b *modelBuilder) jsonSchema(v interface{}, ptr, name, fullName, parent string) {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if values, ok := s["enum"].([]interface{}); ok && s["properties"] == nil {
+		b.add(Element{
+			Kind: ElementEnum, Line: b.line(ptr), Name: name, FullName: fullName, Parent: parent,
+			Type: "enum", Comment: schemaComment(s), Attrs: map[string]interface{}{"schema": s},
+		})
+		for i, ev := range values {
+			if sv, ok := ev.(string); ok {
+				b.add(Element{
+					Kind: ElementEnumValue, Line: b.line(fmt.Sprintf("%s/enum/%d", ptr, i)), Name: sv,
+					FullName: joinName(fullName, sv), Parent: fullName, Type: "enum_value",
+				})
+			}
+		}
+		return
+	}
+	props, _ := s["properties"].(map[string]interface{})
+	if props == nil && s["type"] != "object" {
+		return
+	}
+	b.add(Element{
+		Kind: ElementMessage, Line: b.line(ptr), Name: name, FullName: fullName, Parent: parent,
+		Type: "object", Comment: schemaComment(s), Attrs: map[string]interface{}{"schema": s},
+	})
+	required := map[string]bool{}
+	if list, ok := s["required"].([]interface{}); ok {
+		for _, r := range list {
+			if rs, ok := r.(string); ok {
+				required[rs] = true
+			}
+		}
+	}
+	for _, prop := range b.sortedByLine(props, ptr+"/properties") {
+		propPtr := ptr + "/properties/" + jsonPointerEscape(prop)
+		ps, _ := props[prop].(map[string]interface{})
+		propName := joinName(fullName, prop)
+		b.add(b.schemaField(ps, propPtr, prop, propName, fullName, required[prop]))
+		b.jsonSchema(ps, propPtr, prop, propName, fullName)
+		if items, ok := ps["items"].(map[string]interface{}); ok {
+			b.jsonSchema(items, propPtr+"/items", prop, propName+"[]", fullName)
+		}
+	}
+}
+
+// schemaField builds the field element of a property or parameter schema.
+func (b *modelBuilder) schemaField(s map[string]interface{}, ptr, name, fullName, parent string, required bool) Element {
+	attrs := map[string]interface{}{"schema": s}
+	if s == nil {
+		attrs["schema"] = map[string]interface{}{}
+	}
+	if f, ok := s["format"].(string); ok {
+		attrs["format"] = f
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		attrs["ref"] = ref
+	}
+	if d, ok := s["deprecated"].(bool); ok {
+		attrs["deprecated"] = d
+	}
+	types := schemaTypes(s)
+	nullable, _ := s["nullable"].(bool)
+	return Element{
+		Kind: ElementField, Line: b.line(ptr), Name: name, FullName: fullName, Parent: parent,
+		Type: schemaTypeLabel(s), Comment: schemaComment(s), Required: required,
+		Nullable: nullable || containsString(types, "null"), Repeated: containsString(types, "array"),
+		Attrs: attrs,
+	}
+}
+
+// schemaTypes returns the type keyword of a schema as a list.
+func schemaTypes(s map[string]interface{}) []string {
+	switch t := s["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var out []string
+		for _, e := range t {
+			if es, ok := e.(string); ok {
+				out = append(out, es)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaTypeLabel renders a schema's type: its type keyword ("string",
+// "null|string"), the last segment of a $ref, or "array<item>".
+func schemaTypeLabel(s map[string]interface{}) string {
+	if ref, ok := s["$ref"].(string); ok {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	types := schemaTypes(s)
+	if len(types) == 1 && types[0] == "array" {
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			return "array<" + schemaTypeLabel(items) + ">"
+		}
+	}
+	return strings.Join(types, "|")
+}
+
+// schemaComment returns a schema's description, or its title.
+func schemaComment(s map[string]interface{}) string {
+	if d, ok := s["description"].(string); ok && d != "" {
+		return strings.TrimSpace(d)
+	}
+	t, _ := s["title"].(string)
+	return t
+}
+
+// sortedByLine orders the keys of an object by their line, so elements
+// follow declaration order.
+func (b *modelBuilder) sortedByLine(m map[string]interface{}, ptr string) []string {
+	keys := sortedKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return b.line(ptr+"/"+jsonPointerEscape(keys[i])) < b.line(ptr+"/"+jsonPointerEscape(keys[j]))
+	})
+	return keys
+}
+
+// openAPI models an OpenAPI document as a service with its operations as
+// rpcs (and their parameters as fields) and its component schemas as
+// messages.
+func (b *modelBuilder) openAPI(file string, data []byte) ([]Element, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parseOASSpec(abs, data)
+	if err != nil {
+		return nil, err
+	}
+	b.fileElement("", nil)
+
+	info, _ := spec.root["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	version, _ := info["version"].(string)
+	desc, _ := info["description"].(string)
+	b.add(Element{
+		Kind: ElementService, Line: b.line("/info"), Name: title, FullName: title, Type: "openapi",
+		Comment: strings.TrimSpace(desc), Attrs: map[string]interface{}{"version": version, "openapi": spec.version},
+	})
+	for _, op := range spec.ops {
+		comment, _ := op.raw["summary"].(string)
+		if comment == "" {
+			comment, _ = op.raw["description"].(string)
+		}
+		var tags []interface{}
+		if list, ok := op.raw["tags"].([]interface{}); ok {
+			tags = list
+		}
+		name := op.id
+		if name == "" {
+			name = op.label()
+		}
+		b.add(Element{
+			Kind: ElementRPC, Line: b.line(op.pointer), Name: name, FullName: op.label(), Parent: title,
+			Type: "operation", Comment: strings.TrimSpace(comment),
+			Attrs: map[string]interface{}{
+				"method": op.method, "path": op.path, "operation_id": op.id, "tags": tags,
+				"deprecated": op.deprecated, "operation": op.raw,
+			},
+		})
+		for _, p := range op.params {
+			field := b.schemaField(p.schema.schema, p.pointer, p.name, op.label()+" "+p.in+":"+p.name, op.label(), p.required)
+			field.Attrs["in"] = p.in
+			b.add(field)
+		}
+	}
+
+	components, _ := spec.root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	ptr := "/components/schemas"
+	if spec.version == "2.0" {
+		schemas, _ = spec.root["definitions"].(map[string]interface{})
+		ptr = "/definitions"
+	}
+	for _, name := range b.sortedByLine(schemas, ptr) {
+		b.jsonSchema(schemas[name], ptr+"/"+jsonPointerEscape(name), name, name, "")
+	}
+	return b.elems, nil
+}
+
+// crd models each version's openAPIV3Schema of a CustomResourceDefinition
+// as a message named <kind>.<version>.
+func (b *modelBuilder) crd(root map[string]interface{}) {
+	b.fileElement("", nil)
+	spec, _ := root["spec"].(map[string]interface{})
+	names, _ := spec["names"].(map[string]interface{})
+	kind, _ := names["kind"].(string)
+	group, _ := spec["group"].(string)
+	versions, _ := spec["versions"].([]interface{})
+	for i, v := range versions {
+		ver, _ := v.(map[string]interface{})
+		name, _ := ver["name"].(string)
+		schema, _ := ver["schema"].(map[string]interface{})
+		ptr := fmt.Sprintf("/spec/versions/%d/schema/openAPIV3Schema", i)
+		start := len(b.elems)
+		b.jsonSchema(schema["openAPIV3Schema"], ptr, kind, joinName(kind, name), group)
+		if start < len(b.elems) {
+			b.elems[start].Attrs["version"] = name
+		}
+	}
+}
+
+// --- Avro ---
+
+// avro models an Avro schema or protocol document.
+func (b *modelBuilder) avro(root interface{}) {
+	obj, _ := root.(map[string]interface{})
+	if protocol, ok := obj["protocol"].(string); ok {
+		ns, _ := obj["namespace"].(string)
+		b.fileElement(ns, nil)
+		doc, _ := obj["doc"].(string)
+		b.add(Element{
+			Kind: ElementService, Line: b.line("/protocol"), Name: protocol, FullName: joinName(ns, protocol),
+			Parent: ns, Namespace: ns, Type: "protocol", Comment: strings.TrimSpace(doc),
+		})
+		types, _ := obj["types"].([]interface{})
+		for i, t := range types {
+			b.avroType(t, fmt.Sprintf("/types/%d", i), ns)
+		}
+		messages, _ := obj["messages"].(map[string]interface{})
+		for _, name := range b.sortedByLine(messages, "/messages") {
+			b.avroMessage(messages[name], "/messages/"+jsonPointerEscape(name), name, joinName(ns, protocol), ns)
+		}
+		return
+	}
+	ns := ""
+	if obj != nil {
+		ns, _ = avroFullNameParts(obj, "")
+	}
+	b.fileElement(ns, nil)
+	b.avroType(root, "", "")
+}
+
+// avroMessage models a protocol message as an rpc with its parameters as
+// fields.
+func (b *modelBuilder) avroMessage(v interface{}, ptr, name, protocol, ns string) {
+	m, _ := v.(map[string]interface{})
+	doc, _ := m["doc"].(string)
+	oneWay, _ := m["one-way"].(bool)
+	response := "null"
+	if r, ok := m["response"]; ok {
+		response = b.avroType(r, ptr+"/response", ns)
+	}
+	var errs []interface{}
+	if list, ok := m["errors"].([]interface{}); ok {
+		for i, e := range list {
+			errs = append(errs, b.avroType(e, fmt.Sprintf("%s/errors/%d", ptr, i), ns))
+		}
+	}
+	fullName := joinName(protocol, name)
+	b.add(Element{
+		Kind: ElementRPC, Line: b.line(ptr), Name: name, FullName: fullName, Parent: protocol,
+		Namespace: ns, Type: "message", Comment: strings.TrimSpace(doc),
+		Attrs: map[string]interface{}{"output": response, "errors": errs, "one_way": oneWay},
+	})
+	params, _ := m["request"].([]interface{})
+	for i, p := range params {
+		b.avroField(p, fmt.Sprintf("%s/request/%d", ptr, i), fullName, ns)
+	}
+}
+
+// avroFullNameParts splits a named type's full name into namespace and
+// name, following the Avro naming rules.
+func avroFullNameParts(obj map[string]interface{}, enclosing string) (string, string) {
+	name, _ := obj["name"].(string)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	if ns, ok := obj["namespace"].(string); ok {
+		return ns, name
+	}
+	return enclosing, name
+}
+
+// avroType models the named types declared by an Avro type and returns its
+// label, as rendered for findings ("long", "array<Item>", "[null, string]").
+func (b *modelBuilder) avroType(v interface{}, ptr, ns string) string {
+	switch t := v.(type) {
+	case string:
+		return avroShortName(t)
+	case []interface{}:
+		parts := make([]string, len(t))
+		for i, branch := range t {
+			parts[i] = b.avroType(branch, fmt.Sprintf("%s/%d", ptr, i), ns)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]interface{}:
+		kind, _ := t["type"].(string)
+		switch kind {
+		case "record", "error", "enum", "fixed":
+		case "array":
+			return "array<" + b.avroType(t["items"], ptr+"/items", ns) + ">"
+		case "map":
+			return "map<" + b.avroType(t["values"], ptr+"/values", ns) + ">"
+		default:
+			if kind == "" {
+				return b.avroType(t["type"], ptr+"/type", ns)
+			}
+			if logical, ok := t["logicalType"].(string); ok {
+				return kind + "(" + logical + ")"
+			}
+			return kind
+		}
+		typeNS, name := avroFullNameParts(t, ns)
+		fullName := joinName(typeNS, name)
+		doc, _ := t["doc"].(string)
+		e := Element{
+			Line: b.line(ptr), Name: name, FullName: fullName, Parent: typeNS, Namespace: typeNS,
+			Type: kind, Comment: strings.TrimSpace(doc), Attrs: map[string]interface{}{},
+		}
+		if aliases, ok := t["aliases"].([]interface{}); ok {
+			e.Attrs["aliases"] = aliases
+		}
+		switch kind {
+		case "record", "error":
+			e.Kind = ElementMessage
+			b.add(e)
+			fields, _ := t["fields"].([]interface{})
+			for i, f := range fields {
+				b.avroField(f, fmt.Sprintf("%s/fields/%d", ptr, i), fullName, typeNS)
+			}
+		case "enum":
+			e.Kind = ElementEnum
+			if def, ok := t["default"].(string); ok {
+				e.Attrs["default"] = def
+			}
+			b.add(e)
+			symbols, _ := t["symbols"].([]interface{})
+			for i, s := range symbols {
+				if sym, ok := s.(string); ok {
+					b.add(Element{
+						Kind: ElementEnumValue, Line: b.line(fmt.Sprintf("%s/symbols/%d", ptr, i)), Name: sym,
+						FullName: joinName(fullName, sym), Parent: fullName, Namespace: typeNS, Type: "symbol",
+					})
+				}
+			}
+		}
+		return name
+	}
+	return ""
+}
+
+// avroField models a record field or message parameter.
+func (b *modelBuilder) avroField(v interface{}, ptr, parent, ns string) {
+	f, _ := v.(map[string]interface{})
+	name, _ := f["name"].(string)
+	doc, _ := f["doc"].(string)
+	typ := b.avroType(f["type"], ptr+"/type", ns)
+	def, hasDefault := f["default"]
+	attrs := map[string]interface{}{}
+	if hasDefault {
+		attrs["default"] = def
+	}
+	if aliases, ok := f["aliases"].([]interface{}); ok {
+		attrs["aliases"] = aliases
+	}
+	if order, ok := f["order"].(string); ok {
+		attrs["order"] = order
+	}
+	nullable := false
+	if union, ok := f["type"].([]interface{}); ok {
+		for _, branch := range union {
+			nullable = nullable || branch == "null"
+		}
+	}
+	b.add(Element{
+		Kind: ElementField, Line: b.line(ptr), Name: name, FullName: joinName(parent, name), Parent: parent,
+		Namespace: ns, Type: typ, Comment: strings.TrimSpace(doc), Required: !hasDefault,
+		Nullable: nullable || f["type"] == "null", Repeated: strings.HasPrefix(typ, "array<"), Attrs: attrs,
+	})
+}
+
+// --- Parquet ---
+
+// parquet models a Parquet message with its columns as fields; groups are
+// both a field of their parent and a message holding their children.
+func (b *modelBuilder) parquet(msg *parquetMessage) {
+	b.fileElement("", nil)
+	b.add(Element{Kind: ElementMessage, Line: msg.Line, Name: msg.Name, FullName: msg.Name, Type: "message"})
+	b.parquetColumns(msg.Name, msg.Columns)
+}
+
+func (b *modelBuilder) parquetColumns(parent string, cols []*parquetColumn) {
+	for _, c := range cols {
+		fullName := joinName(parent, c.Name)
+		attrs := map[string]interface{}{"repetition": c.Repetition}
+		if c.Annotation != "" {
+			attrs["logical_type"] = c.Annotation
+		}
+		b.add(Element{
+			Kind: ElementField, Line: c.Line, Name: c.Name, FullName: fullName, Parent: parent,
+			Type: c.typeLabel(), Required: c.Repetition == "required", Nullable: c.Repetition == "optional",
+			Repeated: c.Repetition == "repeated", Attrs: attrs,
+		})
+		if c.isGroup() {
+			b.add(Element{Kind: ElementMessage, Line: c.Line, Name: c.Name, FullName: fullName, Parent: parent, Type: "group"})
+			b.parquetColumns(fullName, c.Children)
+		}
+	}
+}
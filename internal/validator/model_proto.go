@@ -0,0 +1,327 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// protoStatement collects the tokens of the statement starting at toks[i],
+// up to its terminating ";" or opening "{". Braces inside brackets and
+// parentheses, or after "=" or ":", open an aggregate option value and are
+// kept in the statement. It returns the statement, its terminator (empty
+// at end of input) and the index after it.
+func protoStatement(toks []protoToken, i int) ([]protoToken, protoToken, int) {
+	var stmt []protoToken
+	depth := 0
+	for ; i < len(toks); i++ {
+		t := toks[i]
+		switch t.text {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "{":
+			if depth == 0 && (len(stmt) == 0 || (stmt[len(stmt)-1].text != "=" && stmt[len(stmt)-1].text != ":")) {
+				return stmt, t, i + 1
+			}
+			depth++
+		case "}":
+			if depth == 0 {
+				return stmt, protoToken{}, i // unterminated; let the caller close the block
+			}
+			depth--
+		case ";":
+			if depth == 0 {
+				return stmt, t, i + 1
+			}
+		}
+		stmt = append(stmt, t)
+	}
+	return stmt, protoToken{}, i
+}
+
+// protoBlock is an open block while modelling a proto file.
+type protoBlock struct {
+	kind  string // file, message, enum, service, rpc, oneof or other
+	scope string // full name that nested declarations are qualified with
+	elem  int    // index of the block's element, -1 for none
+	oneof string // oneof name, for fields of a oneof
+}
+
+// proto models a proto file: its messages (including nested ones), fields,
+// enums, services and rpcs with their leading comments and options.
+func (b *modelBuilder) proto(data []byte) {
+	toks := protoTokens(data)
+	fileIdx := b.fileElement("", map[string]interface{}{
+		"options": map[string]interface{}{}, "imports": []interface{}{},
+	})
+	stack := []protoBlock{{kind: "file", elem: fileIdx}}
+
+	for i := 0; i < len(toks); {
+		switch toks[i].text {
+		case "}":
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			i++
+			continue
+		case ";":
+			i++
+			continue
+		}
+		stmt, end, next := protoStatement(toks, i)
+		i = next
+		if len(stmt) == 0 {
+			continue
+		}
+		top := stack[len(stack)-1]
+		comment := stmt[0].leading
+		if comment == "" {
+			comment = end.trailing
+		}
+		opened := protoBlock{kind: "other", scope: top.scope, elem: -1}
+
+		switch kw := stmt[0].text; {
+		case kw == "syntax" || kw == "edition":
+			if len(stmt) >= 3 {
+				b.elems[fileIdx].Attrs[kw] = protoUnquote(stmt[2].text)
+			}
+		case kw == "package" && len(stmt) >= 2 && top.kind == "file":
+			b.elems[fileIdx].Namespace = stmt[1].text
+			stack[0].scope = stmt[1].text
+		case kw == "import" && top.kind == "file":
+			imports := b.elems[fileIdx].Attrs["imports"].([]interface{})
+			b.elems[fileIdx].Attrs["imports"] = append(imports, protoUnquote(stmt[len(stmt)-1].text))
+		case kw == "option":
+			if top.elem >= 0 {
+				name, value := protoOption(stmt[1:])
+				b.elems[top.elem].Attrs["options"].(map[string]interface{})[name] = value
+			}
+		case kw == "reserved" || kw == "extensions":
+		case (kw == "message" || kw == "enum" || kw == "service") && len(stmt) >= 2 && end.text == "{" &&
+			top.kind != "enum" && top.kind != "service":
+			kind := map[string]string{"message": ElementMessage, "enum": ElementEnum, "service": ElementService}[kw]
+			name := stmt[1].text
+			full := joinName(top.scope, name)
+			idx := b.add(Element{
+				Kind: kind, Line: stmt[0].line, Name: name, FullName: full, Parent: top.scope,
+				Namespace: stack[0].scope, Type: kw, Comment: comment,
+				Attrs: map[string]interface{}{"options": map[string]interface{}{}},
+			})
+			opened = protoBlock{kind: kw, scope: full, elem: idx}
+		case kw == "oneof" && len(stmt) >= 2 && top.kind == "message":
+			opened = protoBlock{kind: "oneof", scope: top.scope, elem: -1, oneof: stmt[1].text}
+		case kw == "rpc" && top.kind == "service":
+			if idx := b.protoRPC(stmt, top.scope, stack[0].scope, comment); idx >= 0 {
+				opened = protoBlock{kind: "rpc", scope: top.scope, elem: idx}
+			}
+		case top.kind == "message" || top.kind == "oneof":
+			b.protoField(stmt, top, stack[0].scope, comment)
+		case top.kind == "enum":
+			b.protoEnumValue(stmt, top.scope, stack[0].scope, comment)
+		}
+
+		if end.text == "{" {
+			stack = append(stack, opened)
+		}
+	}
+}
+
+// protoField models a field declaration:
+//
+//	[label] type name = number [options];
+//	map<key, value> name = number [options];
+func (b *modelBuilder) protoField(stmt []protoToken, block protoBlock, pkg, comment string) {
+	attrs := map[string]interface{}{"options": map[string]interface{}{}}
+	j := 0
+	label := ""
+	if t := stmt[0].text; t == "optional" || t == "required" || t == "repeated" {
+		label = t
+		j++
+	}
+	if j >= len(stmt) {
+		return
+	}
+	typ := stmt[j].text
+	isMap := false
+	if typ == "map" && j+1 < len(stmt) && stmt[j+1].text == "<" {
+		var parts []string
+		for j += 2; j < len(stmt) && stmt[j].text != ">"; j++ {
+			if stmt[j].text != "," {
+				parts = append(parts, stmt[j].text)
+			}
+		}
+		if len(parts) == 2 {
+			attrs["key_type"], attrs["value_type"] = parts[0], parts[1]
+		}
+		typ = "map<" + strings.Join(parts, ", ") + ">"
+		isMap = true
+	}
+	j++
+	if j+2 >= len(stmt) || stmt[j+1].text != "=" {
+		return // not a field (e.g. a group or an extension range)
+	}
+	name := stmt[j].text
+	if n, err := strconv.ParseInt(stmt[j+2].text, 0, 64); err == nil {
+		attrs["number"] = n
+	}
+	protoFieldOptions(stmt[j+3:], attrs["options"].(map[string]interface{}))
+	if label != "" {
+		attrs["label"] = label
+	}
+	if block.oneof != "" {
+		attrs["oneof"] = block.oneof
+	}
+	b.add(Element{
+		Kind: ElementField, Line: stmt[0].line, Name: name, FullName: joinName(block.scope, name),
+		Parent: block.scope, Namespace: pkg, Type: typ, Comment: comment,
+		Required: label == "required", Nullable: label == "optional", Repeated: label == "repeated" || isMap,
+		Attrs: attrs,
+	})
+}
+
+// protoEnumValue models an enum value declaration: NAME = number [options];
+func (b *modelBuilder) protoEnumValue(stmt []protoToken, enum, pkg, comment string) {
+	if len(stmt) < 3 || stmt[1].text != "=" {
+		return
+	}
+	attrs := map[string]interface{}{"options": map[string]interface{}{}}
+	j := 2
+	num := stmt[j].text
+	if num == "-" && j+1 < len(stmt) {
+		j++
+		num += stmt[j].text
+	}
+	if n, err := strconv.ParseInt(num, 0, 64); err == nil {
+		attrs["number"] = n
+	}
+	protoFieldOptions(stmt[j+1:], attrs["options"].(map[string]interface{}))
+	name := stmt[0].text
+	b.add(Element{
+		Kind: ElementEnumValue, Line: stmt[0].line, Name: name, FullName: joinName(enum, name),
+		Parent: enum, Namespace: pkg, Type: "enum_value", Comment: comment, Attrs: attrs,
+	})
+}
+
+// protoRPC models an rpc declaration and returns its element index, or -1
+// when the statement is malformed:
+//
+//	rpc Name ([stream] Request) returns ([stream] Response)
+func (b *modelBuilder) protoRPC(stmt []protoToken, service, pkg, comment string) int {
+	if len(stmt) < 2 {
+		return -1
+	}
+	var types []string
+	var streams []bool
+	for j := 2; j < len(stmt); j++ {
+		if stmt[j].text != "(" {
+			continue
+		}
+		stream := false
+		if j+1 < len(stmt) && stmt[j+1].text == "stream" {
+			stream = true
+			j++
+		}
+		if j+1 < len(stmt) {
+			types = append(types, stmt[j+1].text)
+			streams = append(streams, stream)
+		}
+	}
+	if len(types) != 2 {
+		return -1
+	}
+	name := stmt[1].text
+	return b.add(Element{
+		Kind: ElementRPC, Line: stmt[0].line, Name: name, FullName: joinName(service, name),
+		Parent: service, Namespace: pkg, Type: "rpc", Comment: comment,
+		Attrs: map[string]interface{}{
+			"input": types[0], "output": types[1],
+			"client_streaming": streams[0], "server_streaming": streams[1],
+			"options": map[string]interface{}{},
+		},
+	})
+}
+
+// protoFieldOptions reads a bracketed option list, [a = 1, (b).c = "x"],
+// into opts.
+func protoFieldOptions(toks []protoToken, opts map[string]interface{}) {
+	if len(toks) == 0 || toks[0].text != "[" {
+		return
+	}
+	depth := 0
+	start := 1
+	for j := 1; j < len(toks); j++ {
+		switch toks[j].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "}":
+			depth--
+		case "]":
+			if depth > 0 {
+				depth--
+				continue
+			}
+			fallthrough
+		case ",":
+			if depth == 0 {
+				if j > start {
+					name, value := protoOption(toks[start:j])
+					opts[name] = value
+				}
+				start = j + 1
+			}
+		}
+	}
+}
+
+// protoOption splits "name = value" option tokens. The name keeps its
+// parentheses, e.g. "(gorm.opts).ormable"; the value is a string, bool,
+// int or double when it is one, and its source text otherwise.
+func protoOption(toks []protoToken) (string, interface{}) {
+	var name strings.Builder
+	j := 0
+	for ; j < len(toks) && toks[j].text != "="; j++ {
+		name.WriteString(toks[j].text)
+	}
+	if j >= len(toks) {
+		return name.String(), true
+	}
+	vals := toks[j+1:]
+	if len(vals) == 2 && vals[0].text == "-" {
+		vals = []protoToken{{text: "-" + vals[1].text}}
+	}
+	if len(vals) != 1 {
+		parts := make([]string, len(vals))
+		for k, v := range vals {
+			parts[k] = v.text
+		}
+		return name.String(), strings.Join(parts, " ")
+	}
+	text := vals[0].text
+	switch {
+	case text == "true" || text == "false":
+		return name.String(), text == "true"
+	case strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'"):
+		return name.String(), protoUnquote(text)
+	}
+	if n, err := strconv.ParseInt(text, 0, 64); err == nil {
+		return name.String(), n
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return name.String(), f
+	}
+	return name.String(), text
+}
+
+// protoUnquote returns the value of a string literal token.
+func protoUnquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' {
+		if v, err := strconv.Unquote(s); err == nil {
+			return v
+		}
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
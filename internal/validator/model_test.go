@@ -0,0 +1,308 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// modelElement finds an element by kind and full name.
+func modelElement(t *testing.T, elems []Element, kind, fullName string) Element {
+	t.Helper()
+	for _, e := range elems {
+		if e.Kind == kind && e.FullName == fullName {
+			return e
+		}
+	}
+	t.Fatalf("no %s %s in model: %+v", kind, fullName, elems)
+	return Element{}
+}
+
+func TestLoadSchemaModel_Proto(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "ledger.proto"), `syntax = "proto3";
+
+package acme.ledger.v1;
+
+import "google/type/money.proto";
+
+option go_package = "example.com/ledger";
+
+// Detached comment, not attached to anything.
+
+// Entry is a ledger entry.
+message Entry {
+  option deprecated = true;
+  string id = 1; // the entry id
+  google.type.Money amount = 2 [(validate.rules).message.required = true, deprecated = true];
+  repeated string tags = 3;
+  optional string memo = 4;
+  map<string, int64> counts = 5;
+  oneof source {
+    string user = 6;
+  }
+  message Line {
+    /* Line number. */
+    int32 no = 1;
+  }
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+    KIND_DEBIT = 1 [deprecated = true];
+  }
+}
+
+service LedgerService {
+  // Creates an entry.
+  rpc CreateEntry(Entry) returns (Entry) {
+    option (google.api.http) = { post: "/v1/entries" body: "*" };
+  }
+  rpc Watch(stream Entry) returns (stream Entry);
+}
+`)
+	elems, err := LoadSchemaModel(dir, FormatProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := modelElement(t, elems, ElementFile, "ledger.proto")
+	if file.Namespace != "acme.ledger.v1" || file.Attrs["syntax"] != "proto3" ||
+		file.Attrs["options"].(map[string]interface{})["go_package"] != "example.com/ledger" {
+		t.Errorf("file: %+v", file)
+	}
+
+	entry := modelElement(t, elems, ElementMessage, "acme.ledger.v1.Entry")
+	if entry.Comment != "Entry is a ledger entry." || entry.Line != 12 || entry.Parent != "acme.ledger.v1" {
+		t.Errorf("message: %+v", entry)
+	}
+	if entry.Attrs["options"].(map[string]interface{})["deprecated"] != true {
+		t.Errorf("message options: %+v", entry.Attrs)
+	}
+
+	id := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.id")
+	if id.Comment != "the entry id" || id.Type != "string" || id.Attrs["number"] != int64(1) || id.Nullable {
+		t.Errorf("id: %+v", id)
+	}
+	amount := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.amount")
+	opts := amount.Attrs["options"].(map[string]interface{})
+	if amount.Type != "google.type.Money" || opts["(validate.rules).message.required"] != true || opts["deprecated"] != true {
+		t.Errorf("amount: %+v", amount)
+	}
+	if tags := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.tags"); !tags.Repeated {
+		t.Errorf("tags: %+v", tags)
+	}
+	if memo := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.memo"); !memo.Nullable || memo.Attrs["label"] != "optional" {
+		t.Errorf("memo: %+v", memo)
+	}
+	counts := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.counts")
+	if counts.Type != "map<string, int64>" || !counts.Repeated || counts.Attrs["value_type"] != "int64" {
+		t.Errorf("counts: %+v", counts)
+	}
+	if user := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.user"); user.Attrs["oneof"] != "source" {
+		t.Errorf("user: %+v", user)
+	}
+	if no := modelElement(t, elems, ElementField, "acme.ledger.v1.Entry.Line.no"); no.Comment != "Line number." {
+		t.Errorf("nested field: %+v", no)
+	}
+	modelElement(t, elems, ElementEnum, "acme.ledger.v1.Entry.Kind")
+	debit := modelElement(t, elems, ElementEnumValue, "acme.ledger.v1.Entry.Kind.KIND_DEBIT")
+	if debit.Attrs["number"] != int64(1) || debit.Attrs["options"].(map[string]interface{})["deprecated"] != true {
+		t.Errorf("enum value: %+v", debit)
+	}
+
+	modelElement(t, elems, ElementService, "acme.ledger.v1.LedgerService")
+	create := modelElement(t, elems, ElementRPC, "acme.ledger.v1.LedgerService.CreateEntry")
+	if create.Comment != "Creates an entry." || create.Attrs["input"] != "Entry" || create.Attrs["server_streaming"] != false {
+		t.Errorf("rpc: %+v", create)
+	}
+	if _, ok := create.Attrs["options"].(map[string]interface{})["(google.api.http)"]; !ok {
+		t.Errorf("rpc options: %+v", create.Attrs)
+	}
+	watch := modelElement(t, elems, ElementRPC, "acme.ledger.v1.LedgerService.Watch")
+	if watch.Comment != "" || watch.Attrs["client_streaming"] != true || watch.Attrs["server_streaming"] != true {
+		t.Errorf("streaming rpc: %+v", watch)
+	}
+}
+
+func TestLoadSchemaModel_Avro(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "user.avsc"), `{
+  "type": "record", "name": "User", "namespace": "com.acme.users", "doc": "A user.",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "email", "type": ["null", "string"], "default": null, "doc": "Primary email."},
+    {"name": "roles", "type": {"type": "array", "items": {"type": "enum", "name": "Role", "symbols": ["ADMIN", "MEMBER"]}}},
+    {"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+  ]
+}`)
+	elems, err := LoadSchemaModel(dir, FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := modelElement(t, elems, ElementMessage, "com.acme.users.User")
+	if user.Namespace != "com.acme.users" || user.Comment != "A user." || user.Type != "record" || user.Line != 1 {
+		t.Errorf("record: %+v", user)
+	}
+	if id := modelElement(t, elems, ElementField, "com.acme.users.User.id"); !id.Required || id.Nullable {
+		t.Errorf("id: %+v", id)
+	}
+	email := modelElement(t, elems, ElementField, "com.acme.users.User.email")
+	if email.Required || !email.Nullable || email.Type != "[null, string]" || email.Comment != "Primary email." || email.Line != 5 {
+		t.Errorf("email: %+v", email)
+	}
+	if roles := modelElement(t, elems, ElementField, "com.acme.users.User.roles"); roles.Type != "array<Role>" || !roles.Repeated {
+		t.Errorf("roles: %+v", roles)
+	}
+	if created := modelElement(t, elems, ElementField, "com.acme.users.User.created"); created.Type != "long(timestamp-millis)" {
+		t.Errorf("created: %+v", created)
+	}
+	modelElement(t, elems, ElementEnum, "com.acme.users.Role")
+	modelElement(t, elems, ElementEnumValue, "com.acme.users.Role.MEMBER")
+}
+
+func TestLoadSchemaModel_AvroProtocol(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "users.avdl"), `@namespace("com.acme.users")
+protocol Users {
+  /** A user. */
+  record User { string id; }
+  /** Looks a user up. */
+  User getUser(string id);
+}
+`)
+	elems, err := LoadSchemaModel(dir, FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelElement(t, elems, ElementService, "com.acme.users.Users")
+	get := modelElement(t, elems, ElementRPC, "com.acme.users.Users.getUser")
+	if get.Comment != "Looks a user up." || get.Attrs["output"] != "User" {
+		t.Errorf("message: %+v", get)
+	}
+	modelElement(t, elems, ElementField, "com.acme.users.Users.getUser.id")
+	if user := modelElement(t, elems, ElementMessage, "com.acme.users.User"); user.Comment != "A user." {
+		t.Errorf("record: %+v", user)
+	}
+}
+
+func TestLoadSchemaModel_JSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "order.json"), `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Order",
+  "type": "object",
+  "required": ["id"],
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "note": {"type": ["string", "null"], "description": "Free text."},
+    "address": {"type": "object", "properties": {"city": {"type": "string"}}},
+    "items": {"type": "array", "items": {"$ref": "#/$defs/Item"}}
+  },
+  "$defs": {
+    "Item": {"type": "object", "properties": {"sku": {"type": "string"}}},
+    "Status": {"type": "string", "enum": ["open", "closed"]}
+  }
+}`)
+	elems, err := LoadSchemaModel(dir, FormatJSONSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelElement(t, elems, ElementMessage, "Order")
+	id := modelElement(t, elems, ElementField, "Order.id")
+	if !id.Required || id.Attrs["format"] != "uuid" || id.Line != 7 {
+		t.Errorf("id: %+v", id)
+	}
+	if note := modelElement(t, elems, ElementField, "Order.note"); note.Required || !note.Nullable || note.Comment != "Free text." {
+		t.Errorf("note: %+v", note)
+	}
+	modelElement(t, elems, ElementField, "Order.address.city")
+	if items := modelElement(t, elems, ElementField, "Order.items"); items.Type != "array<Item>" || !items.Repeated {
+		t.Errorf("items: %+v", items)
+	}
+	modelElement(t, elems, ElementField, "Item.sku")
+	modelElement(t, elems, ElementEnumValue, "Status.closed")
+}
+
+func TestLoadSchemaModel_OpenAPI(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "openapi.yaml"), `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets.
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          nullable: true
+`)
+	elems, err := LoadSchemaModel(dir, FormatUnknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelElement(t, elems, ElementService, "Pets")
+	list := modelElement(t, elems, ElementRPC, "GET /pets")
+	if list.Name != "listPets" || list.Comment != "List pets." || list.Line != 7 || list.Format != FormatOpenAPI {
+		t.Errorf("operation: %+v", list)
+	}
+	if limit := modelElement(t, elems, ElementField, "GET /pets query:limit"); limit.Type != "integer" || limit.Attrs["in"] != "query" {
+		t.Errorf("parameter: %+v", limit)
+	}
+	if name := modelElement(t, elems, ElementField, "Pet.name"); !name.Nullable {
+		t.Errorf("property: %+v", name)
+	}
+}
+
+func TestLoadSchemaModel_Parquet(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "events.parquet"), `message events {
+  required int64 id;
+  optional binary name (STRING);
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (STRING);
+    }
+  }
+}
+`)
+	elems, err := LoadSchemaModel(dir, FormatParquet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelElement(t, elems, ElementMessage, "events")
+	if id := modelElement(t, elems, ElementField, "events.id"); !id.Required || id.Type != "int64" || id.Line != 2 {
+		t.Errorf("id: %+v", id)
+	}
+	if name := modelElement(t, elems, ElementField, "events.name"); !name.Nullable || name.Attrs["logical_type"] != "STRING" {
+		t.Errorf("name: %+v", name)
+	}
+	modelElement(t, elems, ElementMessage, "events.tags")
+	modelElement(t, elems, ElementField, "events.tags.list.element")
+}
+
+func TestLoadSchemaModel_UnknownFormatSkipsNonSchemas(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "apx.yaml"), "version: 1\norg: acme\n")
+	mustWrite(t, filepath.Join(dir, "a.proto"), "syntax = \"proto3\";\nmessage A {}\n")
+	elems, err := LoadSchemaModel(dir, FormatUnknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elems) != 2 || elems[1].FullName != "A" || elems[1].Format != FormatProto {
+		t.Errorf("model: %+v", elems)
+	}
+}
@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,18 +15,74 @@ import (
 // OpenAPIValidator handles OpenAPI schema validation
 type OpenAPIValidator struct {
 	resolver *ToolchainResolver
+	engine   string // auto (default), native or external
 }
 
 // NewOpenAPIValidator creates a new OpenAPI validator
 func NewOpenAPIValidator(resolver *ToolchainResolver) *OpenAPIValidator {
-	return &OpenAPIValidator{resolver: resolver}
+	return &OpenAPIValidator{resolver: resolver, engine: OpenAPIEngineAuto}
+}
+
+// SetEngine selects the lint/breaking engine: "external" runs spectral and
+// oasdiff, "native" the built-in Go engine, and "auto" the external tools
+// when they resolve and the built-in engine otherwise.
+func (v *OpenAPIValidator) SetEngine(engine string) {
+	v.engine = engine
+}
+
+// resolveEngine resolves tool, at its configured version, for the
+// configured engine. It returns an empty path when the built-in engine
+// should run; fallback is set when auto mode fell back because the tool
+// could not be found. A tool that is found but fails verification is an
+// error in every mode.
+func (v *OpenAPIValidator) resolveEngine(tool string) (toolPath string, fallback bool, err error) {
+	version := v.resolver.ToolVersion(tool)
+	switch strings.ToLower(v.engine) {
+	case OpenAPIEngineNative:
+		return "", false, nil
+	case OpenAPIEngineExternal:
+		toolPath, err = v.resolver.ResolveTool(tool, version)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to resolve %s: %w", tool, err)
+		}
+		return toolPath, false, nil
+	case "", OpenAPIEngineAuto:
+		toolPath, err = v.resolver.ResolveTool(tool, version)
+		if errors.Is(err, ErrToolNotFound) {
+			return "", true, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to resolve %s: %w", tool, err)
+		}
+		return toolPath, false, nil
+	}
+	return "", false, fmt.Errorf("unknown OpenAPI engine: %s (expected auto, native or external)", v.engine)
+}
+
+// nativeFallbackFinding notes that auto mode used the built-in engine.
+func nativeFallbackFinding(tool, file string) Finding {
+	return Finding{
+		File:     file,
+		RuleID:   "oas-native-engine",
+		Severity: SeverityInfo,
+		Format:   FormatOpenAPI,
+		Message:  fmt.Sprintf("%s is not available; checked with the built-in OpenAPI engine", tool),
+	}
 }
 
 // Lint runs spectral lint on OpenAPI specs
 func (v *OpenAPIValidator) Lint(path string) error {
-	spectralPath, err := v.resolver.ResolveTool("spectral", "v6.15.0")
+	findings, err := v.LintFindings(path)
+	return findingsError("spectral lint failed", findings, err)
+}
+
+// LintFindings runs spectral lint and returns its results as findings, parsed
+// from spectral's JSON output format. With the native engine, or when
+// spectral is unavailable in auto mode, the built-in rules run instead.
+func (v *OpenAPIValidator) LintFindings(path string) ([]Finding, error) {
+	spectralPath, fallback, err := v.resolveEngine("spectral")
 	if err != nil {
-		return fmt.Errorf("failed to resolve spectral: %w", err)
+		return nil, err
 	}
 
 	// finalize passes the module DIRECTORY; spectral does not glob a bare
@@ -31,20 +90,40 @@ func (v *OpenAPIValidator) Lint(path string) error {
 	// first (WS-035 G4 directory-glob shim).
 	specPath, err := resolveOpenAPISpecFile(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	absPath, err := filepath.Abs(specPath)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
-
-	cmd := exec.Command(spectralPath, "lint", absPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("spectral lint failed: %w\nOutput: %s", err, string(output))
+	if spectralPath == "" {
+		findings, err := nativeOpenAPILint(absPath)
+		if err == nil && fallback {
+			findings = append([]Finding{nativeFallbackFinding("spectral", absPath)}, findings...)
+		}
+		return findings, err
 	}
 
-	return nil
+	cmd := v.resolver.Command("", spectralPath, "lint", "--format", "json", absPath)
+	stdout, stderr, runErr := runCapture(cmd)
+	findings, parseErr := parseSpectralFindings(stdout)
+	if parseErr != nil {
+		if runErr == nil {
+			runErr = parseErr
+		}
+		return nil, fmt.Errorf("spectral lint failed: %w\nOutput: %s%s", runErr, stdout, stderr)
+	}
+	// spectral exits non-zero when error-severity results exist; those are
+	// already captured as findings.
+	if runErr != nil && !HasErrors(findings) {
+		return nil, fmt.Errorf("spectral lint failed: %w\nOutput: %s%s", runErr, stdout, stderr)
+	}
+	for i := range findings {
+		if findings[i].File == "" {
+			findings[i].File = absPath
+		}
+	}
+	return findings, nil
 }
 
 // Breaking runs oasdiff to detect breaking changes between the base spec
@@ -62,19 +141,29 @@ func (v *OpenAPIValidator) Lint(path string) error {
 // module line. The comparison RESULT is left unchanged (the no-op-severity
 // behavior is the separate G7 issue).
 func (v *OpenAPIValidator) Breaking(revPath, against string) error {
-	oasdiffPath, err := v.resolver.ResolveTool("oasdiff", "v1.9.6")
+	findings, err := v.BreakingFindings(revPath, against)
+	return findingsError("oasdiff breaking failed", findings, err)
+}
+
+// BreakingFindings runs oasdiff and returns each reported change as a
+// finding, parsed from oasdiff's JSON output format. ERR-level changes are
+// error findings; WARN and INFO changes are reported but do not fail. With
+// the native engine, or when oasdiff is unavailable in auto mode, the
+// built-in comparison runs instead.
+func (v *OpenAPIValidator) BreakingFindings(revPath, against string) ([]Finding, error) {
+	oasdiffPath, fallback, err := v.resolveEngine("oasdiff")
 	if err != nil {
-		return fmt.Errorf("failed to resolve oasdiff: %w", err)
+		return nil, err
 	}
 
 	// Resolve the revision to the actual spec file (finalize passes a directory).
 	revFile, err := resolveOpenAPISpecFile(revPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	absRev, err := filepath.Abs(revFile)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	// Resolve the base. When against is a readable path use it directly;
@@ -83,24 +172,160 @@ func (v *OpenAPIValidator) Breaking(revPath, against string) error {
 	if _, statErr := os.Stat(against); statErr != nil {
 		baseFile, cleanup, mErr := baseSpecFromRef(absRev, against)
 		if mErr != nil {
-			return mErr
+			return nil, mErr
 		}
 		defer cleanup()
 		baseArg = baseFile
 	}
+	if oasdiffPath == "" {
+		findings, err := nativeOpenAPIBreaking(absRev, baseArg)
+		if err == nil && fallback {
+			findings = append([]Finding{nativeFallbackFinding("oasdiff", absRev)}, findings...)
+		}
+		return findings, err
+	}
 
 	// --fail-on ERR is load-bearing: without it oasdiff exits 0 even when it
 	// reports breaking changes, so the process exit code alone is a no-op gate
 	// (WS-035 G7). With it, oasdiff exits non-zero when a breaking (ERR-level)
 	// change is present, so a removed path or removed required field actually
-	// fails the check and drives the semver bump.
-	cmd := exec.Command(oasdiffPath, "breaking", "--fail-on", "ERR", baseArg, absRev)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("oasdiff breaking failed: %w\nOutput: %s", err, string(output))
+	// fails the check and drives the semver bump. The JSON findings carry the
+	// same verdict, so an exit without ERR findings is a tool failure.
+	cmd := v.resolver.Command("", oasdiffPath, "breaking", "--format", "json", "--fail-on", "ERR", baseArg, absRev)
+	stdout, stderr, runErr := runCapture(cmd)
+	findings, parseErr := parseOasdiffFindings(stdout)
+	if parseErr != nil || (runErr != nil && !HasErrors(findings)) {
+		if runErr == nil {
+			runErr = parseErr
+		}
+		return nil, fmt.Errorf("oasdiff breaking failed: %w\nOutput: %s%s", runErr, stdout, stderr)
+	}
+	for i := range findings {
+		findings[i].File = absRev
 	}
+	return findings, nil
+}
+
+// runCapture runs cmd and returns its stdout and stderr separately, so JSON
+// reports on stdout are not interleaved with diagnostics on stderr.
+func runCapture(cmd *exec.Cmd) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// spectralResult is one entry of spectral's --format json output.
+type spectralResult struct {
+	Code     string        `json:"code"`
+	Path     []interface{} `json:"path"`
+	Message  string        `json:"message"`
+	Severity int           `json:"severity"` // 0 error, 1 warn, 2 info, 3 hint
+	Source   string        `json:"source"`
+	Range    struct {
+		Start struct {
+			Line      int `json:"line"`
+			Character int `json:"character"`
+		} `json:"start"`
+	} `json:"range"`
+}
 
-	return nil
+// parseSpectralFindings parses spectral's JSON report. spectral positions are
+// zero-based and are converted to one-based lines and columns.
+func parseSpectralFindings(out []byte) ([]Finding, error) {
+	trimmed := bytes.TrimSpace(out)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	var results []spectralResult
+	if err := json.Unmarshal(trimmed, &results); err != nil {
+		return nil, fmt.Errorf("parsing spectral output: %w", err)
+	}
+	findings := make([]Finding, 0, len(results))
+	for _, r := range results {
+		sev := SeverityInfo
+		switch r.Severity {
+		case 0:
+			sev = SeverityError
+		case 1:
+			sev = SeverityWarning
+		}
+		elems := make([]string, 0, len(r.Path))
+		for _, p := range r.Path {
+			elems = append(elems, fmt.Sprint(p))
+		}
+		findings = append(findings, Finding{
+			File:     r.Source,
+			Line:     r.Range.Start.Line + 1,
+			Column:   r.Range.Start.Character + 1,
+			RuleID:   r.Code,
+			Severity: sev,
+			Format:   FormatOpenAPI,
+			Path:     strings.Join(elems, "."),
+			Message:  r.Message,
+		})
+	}
+	return findings, nil
+}
+
+// oasdiffChange is one entry of oasdiff's --format json output.
+type oasdiffChange struct {
+	ID        string          `json:"id"`
+	Text      string          `json:"text"`
+	Level     json.RawMessage `json:"level"` // 3 ERR, 2 WARN, 1 INFO (or their names)
+	Operation string          `json:"operation"`
+	Path      string          `json:"path"`
+}
+
+// parseOasdiffFindings parses oasdiff's JSON report.
+func parseOasdiffFindings(out []byte) ([]Finding, error) {
+	trimmed := bytes.TrimSpace(out)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil, nil
+	}
+	var changes []oasdiffChange
+	if err := json.Unmarshal(trimmed, &changes); err != nil {
+		return nil, fmt.Errorf("parsing oasdiff output: %w", err)
+	}
+	findings := make([]Finding, 0, len(changes))
+	for _, c := range changes {
+		elem := c.Path
+		if c.Operation != "" {
+			elem = strings.TrimSpace(c.Operation + " " + c.Path)
+		}
+		findings = append(findings, Finding{
+			RuleID:   c.ID,
+			Severity: oasdiffSeverity(c.Level),
+			Format:   FormatOpenAPI,
+			Path:     elem,
+			Message:  c.Text,
+		})
+	}
+	return findings, nil
+}
+
+// oasdiffSeverity maps an oasdiff level (numeric or named) to a Severity.
+func oasdiffSeverity(raw json.RawMessage) Severity {
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		switch n {
+		case 3:
+			return SeverityError
+		case 2:
+			return SeverityWarning
+		}
+		return SeverityInfo
+	}
+	var name string
+	_ = json.Unmarshal(raw, &name)
+	switch strings.ToLower(name) {
+	case "err", "error":
+		return SeverityError
+	case "warn", "warning":
+		return SeverityWarning
+	}
+	return SeverityInfo
 }
 
 // resolveOpenAPISpecFile returns the OpenAPI spec file at p. When p is a module
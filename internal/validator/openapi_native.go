@@ -0,0 +1,569 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI engines (apx lint/breaking --engine).
+const (
+	// OpenAPIEngineAuto uses spectral and oasdiff when they can be resolved
+	// and falls back to the built-in engine otherwise.
+	OpenAPIEngineAuto = "auto"
+	// OpenAPIEngineNative always uses the built-in Go engine.
+	OpenAPIEngineNative = "native"
+	// OpenAPIEngineExternal always uses spectral and oasdiff and fails when
+	// they are unavailable.
+	OpenAPIEngineExternal = "external"
+)
+
+// oasMethods are the operation keys of a path item, in report order.
+var oasMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// oasPathParamRe matches a template parameter in a path key.
+var oasPathParamRe = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// oasSpec is an OpenAPI 3.x or Swagger 2.0 document loaded by the native
+// engine. Schemas are kept as JSON Schema nodes so $refs resolve through the
+// same loader as apx's JSON Schema support.
+type oasSpec struct {
+	file    string
+	version string // "2.0", "3.0" or "3.1"
+	loader  *jsonSchemaLoader
+	doc     *jsonSchemaDoc
+	root    map[string]interface{}
+	lines   map[string]int // JSON pointer → line in file
+	ops     []*oasOperation
+}
+
+// oasOperation is one operation with its path-level parameters merged in.
+type oasOperation struct {
+	path       string // path key, e.g. /users/{id}
+	method     string // upper case
+	pointer    string // JSON pointer of the operation object
+	id         string
+	deprecated bool
+	params     []*oasParameter
+	body       *oasBody // nil when the operation takes no body
+	responses  map[string]*oasResponse
+	raw        map[string]interface{}
+}
+
+// label names the operation the way oasdiff does: "GET /users/{id}".
+func (o *oasOperation) label() string {
+	if o.method == "" {
+		return o.path
+	}
+	return o.method + " " + o.path
+}
+
+// oasParameter is a resolved parameter. For Swagger 2.0 non-body parameters
+// the parameter object itself carries the schema keywords.
+type oasParameter struct {
+	name     string
+	in       string
+	required bool
+	schema   jsonSchemaNode
+	pointer  string
+}
+
+// key identifies a parameter across versions. Path parameters are matched
+// by position in the template, so renaming {id} to {userId} is not a change.
+func (p *oasParameter) key(path string) string {
+	if p.in == "path" {
+		for i, m := range oasPathParamRe.FindAllStringSubmatch(path, -1) {
+			if m[1] == p.name {
+				return fmt.Sprintf("path#%d", i)
+			}
+		}
+	}
+	return p.in + ":" + p.name
+}
+
+// oasBody is a request body: its schema per media type. Swagger 2.0 body
+// parameters use the media type "*".
+type oasBody struct {
+	required bool
+	content  map[string]jsonSchemaNode
+}
+
+// oasResponse is one response of an operation.
+type oasResponse struct {
+	described bool
+	content   map[string]jsonSchemaNode
+	pointer   string
+}
+
+// loadOASSpec reads and parses an OpenAPI or Swagger document.
+func loadOASSpec(file string) (*oasSpec, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseOASSpec(abs, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: %w", file, err)
+	}
+	return s, nil
+}
+
+// parseOASSpec parses and normalizes an OpenAPI or Swagger document.
+func parseOASSpec(abs string, data []byte) (*oasSpec, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	lines := map[string]int{}
+	root, err := yamlNodeValue(&node, "", lines)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("document is not an object")
+	}
+
+	loader := newJSONSchemaLoader()
+	doc := &jsonSchemaDoc{file: abs, root: obj}
+	loader.docs[abs] = doc
+	s := &oasSpec{file: abs, loader: loader, doc: doc, root: obj, lines: lines}
+
+	switch {
+	case obj["swagger"] != nil:
+		if v := fmt.Sprint(obj["swagger"]); v == "2.0" || v == "2" {
+			s.version = "2.0"
+		}
+	case obj["openapi"] != nil:
+		v := fmt.Sprint(obj["openapi"])
+		if strings.HasPrefix(v, "3.0") {
+			s.version = "3.0"
+		} else if strings.HasPrefix(v, "3.1") {
+			s.version = "3.1"
+		}
+	}
+	if s.version != "" {
+		s.collectOperations()
+	}
+	return s, nil
+}
+
+// yamlNodeValue converts a YAML node into the JSON data model (numbers as
+// float64), recording the line of every value by JSON pointer.
+func yamlNodeValue(n *yaml.Node, ptr string, lines map[string]int) (interface{}, error) {
+	lines[ptr] = n.Line
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(n.Content[0], ptr, lines)
+	case yaml.AliasNode:
+		return yamlNodeValue(n.Alias, ptr, lines)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i].Value
+			v, err := yamlNodeValue(n.Content[i+1], ptr+"/"+jsonPointerEscape(k), lines)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		arr := make([]interface{}, 0, len(n.Content))
+		for i, c := range n.Content {
+			v, err := yamlNodeValue(c, fmt.Sprintf("%s/%d", ptr, i), lines)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	}
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch num := v.(type) {
+	case int:
+		return float64(num), nil
+	case int64:
+		return float64(num), nil
+	case uint64:
+		return float64(num), nil
+	}
+	return v, nil
+}
+
+// jsonPointerEscape escapes a JSON pointer reference token.
+func jsonPointerEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// line returns the line of the value at ptr, or of its nearest ancestor.
+func (s *oasSpec) line(ptr string) int {
+	for {
+		if l, ok := s.lines[ptr]; ok {
+			return l
+		}
+		i := strings.LastIndex(ptr, "/")
+		if i < 0 {
+			return 0
+		}
+		ptr = ptr[:i]
+	}
+}
+
+// elemPath renders a JSON pointer as a dotted element path, the way
+// spectral reports locations: paths./users/{id}.get.responses.200.
+func elemPath(ptr string) string {
+	toks := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, t := range toks {
+		toks[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return strings.Join(toks, ".")
+}
+
+// deref resolves a $ref'd object (parameter, response, request body). It
+// returns the target, or nil when the reference does not resolve.
+func (s *oasSpec) deref(v interface{}) map[string]interface{} {
+	n, err := s.loader.deref(newJSONSchemaNode(s.doc, v))
+	if err != nil {
+		return nil
+	}
+	return n.schema
+}
+
+// collectOperations walks paths and builds the operation list.
+func (s *oasSpec) collectOperations() {
+	paths, _ := s.root["paths"].(map[string]interface{})
+	for _, p := range sortedKeys(paths) {
+		item := s.deref(paths[p])
+		if item == nil {
+			continue
+		}
+		itemPtr := "/paths/" + jsonPointerEscape(p)
+		shared := s.parameters(item["parameters"], itemPtr+"/parameters")
+		for _, m := range oasMethods {
+			raw, ok := item[m].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ptr := itemPtr + "/" + m
+			op := &oasOperation{
+				path:      p,
+				method:    strings.ToUpper(m),
+				pointer:   ptr,
+				raw:       raw,
+				responses: map[string]*oasResponse{},
+			}
+			op.id, _ = raw["operationId"].(string)
+			op.deprecated, _ = raw["deprecated"].(bool)
+
+			// Operation parameters override path-level ones with the same
+			// name and location.
+			own := s.parameters(raw["parameters"], ptr+"/parameters")
+			seen := map[string]bool{}
+			for _, prm := range own {
+				seen[prm.in+":"+prm.name] = true
+			}
+			for _, prm := range shared {
+				if !seen[prm.in+":"+prm.name] {
+					op.params = append(op.params, prm)
+				}
+			}
+			op.params = append(op.params, own...)
+
+			s.collectBody(op)
+			responses, _ := raw["responses"].(map[string]interface{})
+			for code, r := range responses {
+				rptr := ptr + "/responses/" + jsonPointerEscape(code)
+				resp := &oasResponse{pointer: rptr, content: map[string]jsonSchemaNode{}}
+				obj := s.deref(r)
+				if obj != nil {
+					_, resp.described = obj["description"].(string)
+					if s.version == "2.0" {
+						if sch, ok := obj["schema"]; ok {
+							resp.content["*"] = newJSONSchemaNode(s.doc, sch)
+						}
+					} else {
+						s.collectContent(obj["content"], resp.content)
+					}
+				}
+				op.responses[code] = resp
+			}
+			s.ops = append(s.ops, op)
+		}
+	}
+}
+
+// parameters resolves a parameter list. Swagger 2.0 body and formData
+// parameters are request bodies and are handled by collectBody.
+func (s *oasSpec) parameters(v interface{}, ptr string) []*oasParameter {
+	list, _ := v.([]interface{})
+	var out []*oasParameter
+	for i, raw := range list {
+		obj := s.deref(raw)
+		if obj == nil {
+			continue
+		}
+		in, _ := obj["in"].(string)
+		if in == "body" || in == "formData" {
+			continue
+		}
+		prm := &oasParameter{pointer: fmt.Sprintf("%s/%d", ptr, i), in: in}
+		prm.name, _ = obj["name"].(string)
+		prm.required, _ = obj["required"].(bool)
+		if sch, ok := obj["schema"]; ok {
+			prm.schema = newJSONSchemaNode(s.doc, sch)
+		} else {
+			prm.schema = newJSONSchemaNode(s.doc, obj) // Swagger 2.0
+		}
+		out = append(out, prm)
+	}
+	return out
+}
+
+// collectBody sets op.body from a 3.x requestBody or Swagger 2.0 body and
+// formData parameters.
+func (s *oasSpec) collectBody(op *oasOperation) {
+	if s.version != "2.0" {
+		obj := s.deref(op.raw["requestBody"])
+		if obj == nil {
+			return
+		}
+		op.body = &oasBody{content: map[string]jsonSchemaNode{}}
+		op.body.required, _ = obj["required"].(bool)
+		s.collectContent(obj["content"], op.body.content)
+		return
+	}
+
+	var form map[string]interface{}
+	var formRequired []interface{}
+	params, _ := op.raw["parameters"].([]interface{})
+	for _, raw := range params {
+		obj := s.deref(raw)
+		if obj == nil {
+			continue
+		}
+		required, _ := obj["required"].(bool)
+		switch obj["in"] {
+		case "body":
+			op.body = &oasBody{required: required, content: map[string]jsonSchemaNode{
+				"*": newJSONSchemaNode(s.doc, obj["schema"]),
+			}}
+		case "formData":
+			if form == nil {
+				form = map[string]interface{}{}
+			}
+			name, _ := obj["name"].(string)
+			form[name] = obj
+			if required {
+				formRequired = append(formRequired, name)
+			}
+		}
+	}
+	if form != nil && op.body == nil {
+		schema := map[string]interface{}{"type": "object", "properties": form}
+		if len(formRequired) > 0 {
+			schema["required"] = formRequired
+		}
+		op.body = &oasBody{required: len(formRequired) > 0, content: map[string]jsonSchemaNode{
+			"*": newJSONSchemaNode(s.doc, schema),
+		}}
+	}
+}
+
+// collectContent reads a 3.x content map into schemas by media type.
+func (s *oasSpec) collectContent(v interface{}, into map[string]jsonSchemaNode) {
+	content, _ := v.(map[string]interface{})
+	for media, m := range content {
+		mt, _ := m.(map[string]interface{})
+		if sch, ok := mt["schema"]; ok {
+			into[media] = newJSONSchemaNode(s.doc, sch)
+		} else {
+			into[media] = jsonSchemaNode{doc: s.doc}
+		}
+	}
+}
+
+// nativeOpenAPILint applies the built-in structural checks and core rules.
+func nativeOpenAPILint(file string) ([]Finding, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	s, parseErr := parseOASSpec(abs, data)
+	if parseErr != nil {
+		return []Finding{{
+			File:     file,
+			RuleID:   "oas-parse",
+			Severity: SeverityError,
+			Format:   FormatOpenAPI,
+			Message:  fmt.Sprintf("invalid OpenAPI document: %v", parseErr),
+		}}, nil
+	}
+
+	var findings []Finding
+	add := func(sev Severity, rule, ptr, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     s.file,
+			Line:     s.line(ptr),
+			RuleID:   rule,
+			Severity: sev,
+			Format:   FormatOpenAPI,
+			Path:     elemPath(ptr),
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if s.version == "" {
+		switch {
+		case s.root["swagger"] != nil:
+			add(SeverityError, "oas-version", "/swagger", "unsupported Swagger version %v (expected 2.0)", s.root["swagger"])
+		case s.root["openapi"] != nil:
+			add(SeverityError, "oas-version", "/openapi", "unsupported OpenAPI version %v (expected 3.0.x or 3.1.x)", s.root["openapi"])
+		default:
+			add(SeverityError, "oas-version", "", "document has no 'openapi' or 'swagger' version field")
+		}
+		return findings, nil
+	}
+
+	info, ok := s.root["info"].(map[string]interface{})
+	if !ok {
+		add(SeverityError, "oas-info", "", "document is missing the required 'info' object")
+	} else {
+		for _, field := range []string{"title", "version"} {
+			if v, _ := info[field].(string); v == "" {
+				add(SeverityError, "oas-info", "/info", "info.%s is required", field)
+			}
+		}
+	}
+
+	paths, hasPaths := s.root["paths"].(map[string]interface{})
+	if !hasPaths && (s.version != "3.1" || (s.root["webhooks"] == nil && s.root["components"] == nil)) {
+		add(SeverityError, "oas-paths", "", "document is missing the required 'paths' object")
+	}
+	templates := map[string]string{}
+	for _, p := range sortedKeys(paths) {
+		ptr := "/paths/" + jsonPointerEscape(p)
+		if !strings.HasPrefix(p, "/") {
+			add(SeverityError, "oas-paths", ptr, "path %q must begin with '/'", p)
+		}
+		norm := oasPathParamRe.ReplaceAllString(p, "{}")
+		if prev, dup := templates[norm]; dup {
+			add(SeverityError, "path-params", ptr, "paths %q and %q are equivalent", prev, p)
+		}
+		templates[norm] = p
+		for _, m := range oasPathParamRe.FindAllStringSubmatch(p, -1) {
+			if m[1] == "" {
+				add(SeverityError, "path-params", ptr, "path %q has an empty parameter name", p)
+			}
+		}
+	}
+
+	s.lintOperations(add)
+	s.lintRefs(s.root, "", add)
+	SortFindings(findings)
+	return findings, nil
+}
+
+// lintOperations applies the operation-level rules.
+func (s *oasSpec) lintOperations(add func(Severity, string, string, string, ...interface{})) {
+	ids := map[string]string{} // operationId → first operation
+	for _, op := range s.ops {
+		if op.id == "" {
+			add(SeverityWarning, "operation-operationId", op.pointer, "%s has no operationId", op.label())
+		} else if first, dup := ids[op.id]; dup {
+			add(SeverityError, "operation-operationId-unique", op.pointer+"/operationId",
+				"operationId %q is used by both %s and %s", op.id, first, op.label())
+		} else {
+			ids[op.id] = op.label()
+		}
+
+		if len(op.responses) == 0 {
+			add(SeverityError, "operation-responses", op.pointer, "%s defines no responses", op.label())
+		}
+		for _, code := range sortedKeys(op.responses) {
+			if !op.responses[code].described {
+				add(SeverityError, "operation-response-description", op.responses[code].pointer,
+					"%s response %s has no description", op.label(), code)
+			}
+		}
+
+		seen := map[string]bool{}
+		declared := map[string]bool{}
+		for _, prm := range op.params {
+			k := prm.in + ":" + prm.name
+			if seen[k] {
+				add(SeverityError, "operation-parameters", prm.pointer,
+					"%s declares %s parameter %q more than once", op.label(), prm.in, prm.name)
+			}
+			seen[k] = true
+			if prm.in != "path" {
+				continue
+			}
+			declared[prm.name] = true
+			if !prm.required {
+				add(SeverityError, "path-params", prm.pointer,
+					"%s path parameter %q must be required", op.label(), prm.name)
+			}
+		}
+		inTemplate := map[string]bool{}
+		for _, m := range oasPathParamRe.FindAllStringSubmatch(op.path, -1) {
+			inTemplate[m[1]] = true
+			if m[1] != "" && !declared[m[1]] {
+				add(SeverityError, "path-params", op.pointer,
+					"%s does not declare path parameter %q", op.label(), m[1])
+			}
+		}
+		for _, prm := range op.params {
+			if prm.in == "path" && !inTemplate[prm.name] {
+				add(SeverityError, "path-params", prm.pointer,
+					"%s declares path parameter %q, which is not in the path template", op.label(), prm.name)
+			}
+		}
+	}
+}
+
+// lintRefs reports every $ref that does not resolve.
+func (s *oasSpec) lintRefs(v interface{}, ptr string, add func(Severity, string, string, string, ...interface{})) {
+	switch cur := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := cur["$ref"].(string); ok {
+			if _, err := s.loader.resolveRef(s.doc, ref); err != nil {
+				add(SeverityError, "oas-unresolved-ref", ptr+"/$ref", "%v", err)
+			}
+		}
+		keys := make([]string, 0, len(cur))
+		for k := range cur {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if k == "example" || k == "examples" {
+				continue
+			}
+			s.lintRefs(cur[k], ptr+"/"+jsonPointerEscape(k), add)
+		}
+	case []interface{}:
+		for i, e := range cur {
+			s.lintRefs(e, fmt.Sprintf("%s/%d", ptr, i), add)
+		}
+	}
+}
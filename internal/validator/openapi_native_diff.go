@@ -0,0 +1,473 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// nativeOpenAPIBreaking compares two OpenAPI/Swagger documents with the
+// built-in engine. Rule IDs follow oasdiff's where the checks overlap, so
+// findings read the same whichever engine produced them.
+func nativeOpenAPIBreaking(newFile, oldFile string) ([]Finding, error) {
+	newSpec, err := loadOASSpec(newFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading revision spec: %w", err)
+	}
+	oldSpec, err := loadOASSpec(oldFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading base spec: %w", err)
+	}
+	for _, s := range []*oasSpec{newSpec, oldSpec} {
+		if s.version == "" {
+			return nil, fmt.Errorf("%s is not an OpenAPI 3.0/3.1 or Swagger 2.0 document", s.file)
+		}
+	}
+
+	d := &oasDiff{oldSpec: oldSpec, newSpec: newSpec, seen: map[string]bool{}}
+	d.compareOperations()
+	for i := range d.findings {
+		d.findings[i].File = newSpec.file
+	}
+	findings := dedupeFindings(d.findings)
+	SortFindings(findings)
+	return findings, nil
+}
+
+// oasDiff holds the state of one comparison. Requests must still accept
+// everything old clients send; responses must not send anything old clients
+// cannot read.
+type oasDiff struct {
+	oldSpec, newSpec *oasSpec
+	findings         []Finding
+	seen             map[string]bool // schema pairs already compared (recursion guard)
+}
+
+func (d *oasDiff) add(sev Severity, rule string, op *oasOperation, ptr, oldVal, newVal, format string, args ...interface{}) {
+	f := Finding{
+		RuleID:   rule,
+		Severity: sev,
+		Format:   FormatOpenAPI,
+		Path:     op.label(),
+		Message:  op.label() + ": " + fmt.Sprintf(format, args...),
+		OldValue: oldVal,
+		NewValue: newVal,
+	}
+	if ptr != "" {
+		f.Line = d.newSpec.line(ptr)
+	}
+	d.findings = append(d.findings, f)
+}
+
+// oasPathKey normalizes a path template so renamed parameters still match.
+func oasPathKey(p string) string {
+	return oasPathParamRe.ReplaceAllString(p, "{}")
+}
+
+func (d *oasDiff) compareOperations() {
+	newOps := map[string]*oasOperation{}
+	newPaths := map[string]bool{}
+	for _, op := range d.newSpec.ops {
+		newOps[op.method+" "+oasPathKey(op.path)] = op
+		newPaths[oasPathKey(op.path)] = true
+	}
+	removedPaths := map[string]bool{}
+	for _, old := range d.oldSpec.ops {
+		cur, ok := newOps[old.method+" "+oasPathKey(old.path)]
+		if ok {
+			d.compareOperation(old, cur)
+			continue
+		}
+		sev, suffix := SeverityError, "without-deprecation"
+		if old.deprecated {
+			sev, suffix = SeverityInfo, "with-deprecation"
+		}
+		if !newPaths[oasPathKey(old.path)] {
+			if !removedPaths[old.path] {
+				removedPaths[old.path] = true
+				d.add(sev, "api-path-removed-"+suffix, &oasOperation{path: old.path}, "", old.path, "",
+					"path removed")
+			}
+			continue
+		}
+		d.add(sev, "api-removed-"+suffix, old, "", old.label(), "", "operation removed")
+	}
+}
+
+func (d *oasDiff) compareOperation(old, cur *oasOperation) {
+	d.compareParameters(old, cur)
+	d.compareRequestBody(old, cur)
+	d.compareResponses(old, cur)
+}
+
+func (d *oasDiff) compareParameters(old, cur *oasOperation) {
+	oldParams := map[string]*oasParameter{}
+	for _, p := range old.params {
+		oldParams[p.key(old.path)] = p
+	}
+	newParams := map[string]*oasParameter{}
+	for _, p := range cur.params {
+		newParams[p.key(cur.path)] = p
+	}
+
+	for _, k := range sortedKeys(oldParams) {
+		op := oldParams[k]
+		np, ok := newParams[k]
+		if !ok {
+			d.add(SeverityWarning, "request-parameter-removed", cur, cur.pointer, op.name, "",
+				"%s parameter %q removed", op.in, op.name)
+			continue
+		}
+		if np.required && !op.required {
+			d.add(SeverityError, "request-parameter-became-required", cur, np.pointer, "optional", "required",
+				"%s parameter %q became required", np.in, np.name)
+		}
+		d.compareSchema(op.schema, np.schema, oasSchemaCtx{
+			op:      cur,
+			where:   fmt.Sprintf("%s parameter %q", np.in, np.name),
+			ptr:     np.pointer,
+			root:    "request-parameter",
+			nested:  "request-parameter",
+			request: true,
+		}, "")
+	}
+	for _, k := range sortedKeys(newParams) {
+		np := newParams[k]
+		if _, ok := oldParams[k]; !ok && np.required {
+			d.add(SeverityError, "new-required-request-parameter", cur, np.pointer, "", np.name,
+				"added required %s parameter %q", np.in, np.name)
+		}
+	}
+}
+
+func (d *oasDiff) compareRequestBody(old, cur *oasOperation) {
+	switch {
+	case old.body == nil && cur.body == nil:
+		return
+	case old.body == nil:
+		if cur.body.required {
+			d.add(SeverityError, "new-required-request-body", cur, cur.pointer, "", "required",
+				"added a required request body")
+		}
+		return
+	case cur.body == nil:
+		d.add(SeverityWarning, "request-body-removed", cur, cur.pointer, "", "", "request body removed")
+		return
+	}
+	if cur.body.required && !old.body.required {
+		d.add(SeverityError, "request-body-became-required", cur, cur.pointer, "optional", "required",
+			"request body became required")
+	}
+	pairs, removed := oasMediaPairs(old.body.content, cur.body.content)
+	for _, media := range removed {
+		d.add(SeverityError, "request-body-media-type-removed", cur, cur.pointer, media, "",
+			"request body media type %s removed", media)
+	}
+	for _, p := range pairs {
+		d.compareSchema(old.body.content[p[0]], cur.body.content[p[1]], oasSchemaCtx{
+			op:      cur,
+			where:   oasMediaLabel("request body", p[1]),
+			ptr:     cur.pointer,
+			root:    "request-body",
+			nested:  "request-property",
+			request: true,
+		}, "")
+	}
+}
+
+func (d *oasDiff) compareResponses(old, cur *oasOperation) {
+	for _, code := range sortedKeys(old.responses) {
+		or := old.responses[code]
+		nr, ok := cur.responses[code]
+		if !ok {
+			if strings.HasPrefix(code, "2") {
+				d.add(SeverityError, "response-success-status-removed", cur, cur.pointer, code, "",
+					"success response %s removed", code)
+			} else {
+				d.add(SeverityWarning, "response-non-success-status-removed", cur, cur.pointer, code, "",
+					"response %s removed", code)
+			}
+			continue
+		}
+		pairs, removed := oasMediaPairs(or.content, nr.content)
+		for _, media := range removed {
+			d.add(SeverityError, "response-media-type-removed", cur, nr.pointer, media, "",
+				"response %s media type %s removed", code, media)
+		}
+		for _, p := range pairs {
+			d.compareSchema(or.content[p[0]], nr.content[p[1]], oasSchemaCtx{
+				op:     cur,
+				where:  oasMediaLabel("response "+code, p[1]),
+				ptr:    nr.pointer,
+				root:   "response-body",
+				nested: "response-property",
+			}, "")
+		}
+	}
+}
+
+// oasMediaPairs matches media types between two content maps. Swagger 2.0
+// bodies use "*", which matches any single media type on the other side.
+func oasMediaPairs(old, cur map[string]jsonSchemaNode) (pairs [][2]string, removed []string) {
+	pick := func(m map[string]jsonSchemaNode) string {
+		if _, ok := m["application/json"]; ok {
+			return "application/json"
+		}
+		keys := sortedKeys(m)
+		if len(keys) == 0 {
+			return ""
+		}
+		return keys[0]
+	}
+	_, oldAny := old["*"]
+	_, newAny := cur["*"]
+	if oldAny || newAny {
+		if o, n := pick(old), pick(cur); o != "" && n != "" {
+			pairs = append(pairs, [2]string{o, n})
+		}
+		return pairs, nil
+	}
+	for _, media := range sortedKeys(old) {
+		if _, ok := cur[media]; ok {
+			pairs = append(pairs, [2]string{media, media})
+		} else {
+			removed = append(removed, media)
+		}
+	}
+	return pairs, removed
+}
+
+func oasMediaLabel(what, media string) string {
+	if media == "*" {
+		return what
+	}
+	return what + " (" + media + ")"
+}
+
+// oasSchemaCtx locates a schema comparison for rule IDs and messages.
+type oasSchemaCtx struct {
+	op      *oasOperation
+	where   string // e.g. `query parameter "limit"`, "response 200 (application/json)"
+	ptr     string // JSON pointer in the new spec, for line numbers
+	root    string // rule prefix at the top of the schema
+	nested  string // rule prefix for properties below it
+	request bool   // request direction (new must accept old); else response
+}
+
+func (c oasSchemaCtx) rule(path, kind string) string {
+	if path == "" {
+		return c.root + "-" + kind
+	}
+	return c.nested + "-" + kind
+}
+
+func (c oasSchemaCtx) subject(path string) string {
+	if path == "" {
+		return c.where
+	}
+	return fmt.Sprintf("%s property %q", c.where, path)
+}
+
+// flatten resolves $ref and merges allOf members, so composed schemas are
+// compared by their effective properties.
+func (d *oasDiff) flatten(s *oasSpec, n jsonSchemaNode, depth int) (jsonSchemaNode, map[string]interface{}, error) {
+	n, err := s.loader.deref(n)
+	if err != nil || n.schema == nil {
+		return n, nil, err
+	}
+	all, ok := n.schema["allOf"].([]interface{})
+	if !ok || depth > 16 {
+		return n, n.schema, nil
+	}
+	merged := map[string]interface{}{}
+	props := map[string]interface{}{}
+	var required []interface{}
+	layer := func(m map[string]interface{}) {
+		for k, v := range m {
+			switch k {
+			case "allOf":
+			case "properties":
+				if p, ok := v.(map[string]interface{}); ok {
+					for name, ps := range p {
+						props[name] = ps
+					}
+				}
+			case "required":
+				if r, ok := v.([]interface{}); ok {
+					required = append(required, r...)
+				}
+			default:
+				if _, set := merged[k]; !set {
+					merged[k] = v
+				}
+			}
+		}
+	}
+	layer(n.schema)
+	for _, member := range all {
+		_, ms, err := d.flatten(s, newJSONSchemaNode(n.doc, member), depth+1)
+		if err != nil {
+			return n, nil, err
+		}
+		layer(ms)
+	}
+	if len(props) > 0 {
+		merged["properties"] = props
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return n, merged, nil
+}
+
+// oasTypes returns a schema's types, counting OpenAPI 3.0 "nullable".
+func oasTypes(s map[string]interface{}) []string {
+	types := jsonSchemaTypes(s)
+	if nullable, _ := s["nullable"].(bool); nullable && types != nil {
+		types = append(types, "null")
+	}
+	return types
+}
+
+// oasTypesLost returns the types in from that to cannot hold.
+func oasTypesLost(from, to []string) []string {
+	holds := map[string]bool{}
+	for _, t := range to {
+		holds[t] = true
+	}
+	var lost []string
+	for _, t := range from {
+		if !holds[t] && !(t == "integer" && holds["number"]) {
+			lost = append(lost, t)
+		}
+	}
+	return lost
+}
+
+func (d *oasDiff) compareSchema(oldN, newN jsonSchemaNode, ctx oasSchemaCtx, path string) {
+	oldN, oldS, err := d.flatten(d.oldSpec, oldN, 0)
+	if err != nil {
+		return // unresolved refs in the base are not the revision's fault
+	}
+	newN, newS, err := d.flatten(d.newSpec, newN, 0)
+	if err != nil {
+		d.add(SeverityError, "oas-unresolved-ref", ctx.op, ctx.ptr, "", "", "%s: %v", ctx.subject(path), err)
+		return
+	}
+	if oldS == nil || newS == nil {
+		return
+	}
+	if oldN.loc != "" && newN.loc != "" {
+		key := fmt.Sprintf("%s|%s|%t|%s", oldN.loc, newN.loc, ctx.request, ctx.where)
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+	}
+
+	oldT, newT := oasTypes(oldS), oasTypes(newS)
+	var lost []string
+	if ctx.request && newT != nil {
+		if oldT == nil {
+			lost = []string{"any"}
+		} else {
+			lost = oasTypesLost(oldT, newT)
+		}
+	} else if !ctx.request && oldT != nil && newT != nil {
+		lost = oasTypesLost(newT, oldT)
+	}
+	if len(lost) > 0 {
+		oldLabel, newLabel := "any", jsonSchemaTypeLabel(newT)
+		if oldT != nil {
+			oldLabel = jsonSchemaTypeLabel(oldT)
+		}
+		d.add(SeverityError, ctx.rule(path, "type-changed"), ctx.op, ctx.ptr, oldLabel, newLabel,
+			"%s type changed from %s to %s", ctx.subject(path), oldLabel, newLabel)
+		return
+	}
+
+	d.compareOASEnum(oldS, newS, ctx, path)
+
+	oldProps, _ := oldS["properties"].(map[string]interface{})
+	newProps, _ := newS["properties"].(map[string]interface{})
+	oldReq := jsonSchemaStringSet(oldS["required"])
+	newReq := jsonSchemaStringSet(newS["required"])
+	for _, name := range sortedKeys(oldProps) {
+		child := jsonSchemaJoin(path, name)
+		np, ok := newProps[name]
+		switch {
+		case !ok && ctx.request:
+			d.add(SeverityWarning, "request-property-removed", ctx.op, ctx.ptr, child, "",
+				"%s removed", ctx.subject(child))
+		case !ok && oldReq[name]:
+			d.add(SeverityError, "response-required-property-removed", ctx.op, ctx.ptr, child, "",
+				"%s removed", ctx.subject(child))
+		case !ok:
+			d.add(SeverityWarning, "response-optional-property-removed", ctx.op, ctx.ptr, child, "",
+				"%s removed", ctx.subject(child))
+		default:
+			if ctx.request && newReq[name] && !oldReq[name] {
+				d.add(SeverityError, "request-property-became-required", ctx.op, ctx.ptr, "optional", "required",
+					"%s became required", ctx.subject(child))
+			}
+			if !ctx.request && oldReq[name] && !newReq[name] {
+				d.add(SeverityError, "response-property-became-optional", ctx.op, ctx.ptr, "required", "optional",
+					"%s became optional", ctx.subject(child))
+			}
+			d.compareSchema(newJSONSchemaNode(oldN.doc, oldProps[name]), newJSONSchemaNode(newN.doc, np), ctx, child)
+		}
+	}
+	if ctx.request {
+		for _, name := range sortedKeys(newProps) {
+			if _, ok := oldProps[name]; !ok && newReq[name] {
+				child := jsonSchemaJoin(path, name)
+				d.add(SeverityError, "new-required-request-property", ctx.op, ctx.ptr, "", child,
+					"added required %s", ctx.subject(child))
+			}
+		}
+	}
+
+	if oi, ok := oldS["items"]; ok {
+		if ni, ok := newS["items"]; ok {
+			d.compareSchema(newJSONSchemaNode(oldN.doc, oi), newJSONSchemaNode(newN.doc, ni), ctx, path+"[]")
+		}
+	}
+}
+
+// compareOASEnum reports enum values a request no longer accepts, or new
+// values a response may now return.
+func (d *oasDiff) compareOASEnum(oldS, newS map[string]interface{}, ctx oasSchemaCtx, path string) {
+	oldE, oldHas := jsonSchemaEnum(oldS)
+	newE, newHas := jsonSchemaEnum(newS)
+	diff := func(from, to []string) []string {
+		in := map[string]bool{}
+		for _, v := range to {
+			in[v] = true
+		}
+		var out []string
+		for _, v := range from {
+			if !in[v] {
+				out = append(out, v)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	if ctx.request && newHas {
+		if !oldHas {
+			d.add(SeverityError, ctx.rule(path, "enum-value-removed"), ctx.op, ctx.ptr, "", strings.Join(newE, ", "),
+				"%s is now restricted to [%s]", ctx.subject(path), strings.Join(newE, ", "))
+			return
+		}
+		if removed := diff(oldE, newE); len(removed) > 0 {
+			d.add(SeverityError, ctx.rule(path, "enum-value-removed"), ctx.op, ctx.ptr, strings.Join(removed, ", "), "",
+				"%s no longer accepts %s", ctx.subject(path), strings.Join(removed, ", "))
+		}
+	}
+	if !ctx.request && oldHas && newHas {
+		if added := diff(newE, oldE); len(added) > 0 {
+			d.add(SeverityWarning, ctx.rule(path, "enum-value-added"), ctx.op, ctx.ptr, "", strings.Join(added, ", "),
+				"%s may now return %s", ctx.subject(path), strings.Join(added, ", "))
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNativeOpenAPILint(t *testing.T) {
+	for _, name := range []string{"petstore_v1.yaml", "swagger_v1.yaml"} {
+		findings, err := nativeOpenAPILint(filepath.Join("testdata", "openapi", name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(findings) != 0 {
+			t.Errorf("%s: expected no findings, got %v", name, findings)
+		}
+	}
+
+	findings, err := nativeOpenAPILint(filepath.Join("testdata", "openapi", "lint_bad.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Severity{
+		"oas-info":                       SeverityError,
+		"path-params":                    SeverityError,
+		"operation-operationId-unique":   SeverityError,
+		"operation-response-description": SeverityError,
+		"operation-responses":            SeverityError,
+		"operation-operationId":          SeverityWarning,
+		"oas-unresolved-ref":             SeverityError,
+	}
+	got := map[string]Severity{}
+	for _, f := range findings {
+		got[f.RuleID] = f.Severity
+		if f.Line == 0 {
+			t.Errorf("%s: finding has no line: %v", f.RuleID, f)
+		}
+	}
+	for rule, sev := range want {
+		if got[rule] != sev {
+			t.Errorf("rule %s: got severity %q, want %q", rule, got[rule], sev)
+		}
+	}
+}
+
+func TestNativeOpenAPILint_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, content, wantRule string
+	}{
+		{"syntax", "openapi: [3.0.0\n", "oas-parse"},
+		{"no version", "info: {title: x, version: '1'}\npaths: {}\n", "oas-version"},
+		{"old swagger", "swagger: '1.2'\n", "oas-version"},
+		{"no paths", "openapi: 3.0.0\ninfo: {title: x, version: '1'}\n", "oas-paths"},
+		{"relative path", "openapi: 3.0.0\ninfo: {title: x, version: '1'}\npaths:\n  pets: {}\n", "oas-paths"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "openapi.yaml")
+			mustWrite(t, file, tt.content)
+			findings, err := nativeOpenAPILint(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) == 0 || findings[0].RuleID != tt.wantRule {
+				t.Fatalf("got %v, want rule %s", findings, tt.wantRule)
+			}
+		})
+	}
+
+	// OpenAPI 3.1 documents may carry only webhooks or components.
+	file := filepath.Join(dir, "webhooks.yaml")
+	mustWrite(t, file, "openapi: 3.1.0\ninfo: {title: x, version: '1'}\ncomponents: {}\n")
+	if findings, err := nativeOpenAPILint(file); err != nil || len(findings) != 0 {
+		t.Fatalf("got %v, %v; want no findings", findings, err)
+	}
+}
+
+func TestNativeOpenAPIBreaking(t *testing.T) {
+	spec := func(name string) string { return filepath.Join("testdata", "openapi", name) }
+
+	findings, err := nativeOpenAPIBreaking(spec("petstore_v2_compatible.yaml"), spec("petstore_v1.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("compatible revision: expected no findings, got %v", findings)
+	}
+
+	tests := []struct {
+		newFile, oldFile string
+		want             []Finding // RuleID, Severity and Path are compared
+	}{
+		{
+			newFile: "petstore_v2_breaking.yaml", oldFile: "petstore_v1.yaml",
+			want: []Finding{
+				{RuleID: "api-path-removed-without-deprecation", Severity: SeverityError, Path: "/stores"},
+				{RuleID: "api-removed-without-deprecation", Severity: SeverityError, Path: "DELETE /pets/{petId}"},
+				{RuleID: "new-required-request-parameter", Severity: SeverityError, Path: "GET /pets"},
+				{RuleID: "request-parameter-enum-value-removed", Severity: SeverityError, Path: "GET /pets"},
+				{RuleID: "response-property-type-changed", Severity: SeverityError, Path: "GET /pets"},
+				{RuleID: "response-property-became-optional", Severity: SeverityError, Path: "GET /pets"},
+				{RuleID: "response-property-enum-value-added", Severity: SeverityWarning, Path: "GET /pets"},
+				{RuleID: "new-required-request-property", Severity: SeverityError, Path: "POST /pets"},
+			},
+		},
+		{
+			newFile: "swagger_v2_breaking.yaml", oldFile: "swagger_v1.yaml",
+			want: []Finding{
+				{RuleID: "request-parameter-type-changed", Severity: SeverityError, Path: "GET /users"},
+				{RuleID: "response-optional-property-removed", Severity: SeverityWarning, Path: "GET /users"},
+				{RuleID: "request-body-became-required", Severity: SeverityError, Path: "POST /users"},
+				{RuleID: "request-property-removed", Severity: SeverityWarning, Path: "POST /users"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.newFile, func(t *testing.T) {
+			findings, err := nativeOpenAPIBreaking(spec(tt.newFile), spec(tt.oldFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d: %v", len(findings), len(tt.want), findings)
+			}
+			for _, w := range tt.want {
+				found := false
+				for _, f := range findings {
+					if f.RuleID == w.RuleID && f.Severity == w.Severity && f.Path == w.Path {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("missing %s %s at %s in %v", w.Severity, w.RuleID, w.Path, findings)
+				}
+			}
+		})
+	}
+}
+
+func TestNativeOpenAPIBreaking_Deprecated(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.yaml")
+	newFile := filepath.Join(dir, "new.yaml")
+	mustWrite(t, oldFile, `openapi: 3.0.0
+info: {title: x, version: '1'}
+paths:
+  /a:
+    get:
+      deprecated: true
+      responses: {"200": {description: ok}}
+  /b:
+    get:
+      responses: {"200": {description: ok}}
+    put:
+      deprecated: true
+      responses: {"200": {description: ok}}
+`)
+	mustWrite(t, newFile, `openapi: 3.0.0
+info: {title: x, version: '1'}
+paths:
+  /b:
+    get:
+      responses: {"200": {description: ok}}
+`)
+	findings, err := nativeOpenAPIBreaking(newFile, oldFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HasErrors(findings) || len(findings) != 2 {
+		t.Fatalf("expected two informational findings, got %v", findings)
+	}
+}
+
+func TestOpenAPIValidator_Engine(t *testing.T) {
+	spec := func(name string) string { return filepath.Join("testdata", "openapi", name) }
+	v := NewOpenAPIValidator(NewToolchainResolver())
+
+	v.SetEngine(OpenAPIEngineNative)
+	findings, err := v.BreakingFindings(spec("petstore_v2_breaking.yaml"), spec("petstore_v1.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HasErrors(findings) {
+		t.Errorf("expected breaking findings from the native engine, got %v", findings)
+	}
+	if err := v.Lint(spec("petstore_v1.yaml")); err != nil {
+		t.Errorf("native lint: %v", err)
+	}
+
+	v.SetEngine("bogus")
+	if _, err := v.LintFindings(spec("petstore_v1.yaml")); err == nil {
+		t.Error("expected an error for an unknown engine")
+	}
+}
@@ -223,3 +223,52 @@ func TestBaseSpecFromRef(t *testing.T) {
 		t.Error("baseSpecFromRef on a nonexistent ref must error")
 	}
 }
+
+func TestParseSpectralFindings(t *testing.T) {
+	out := []byte(`[
+  {"code":"operation-operationId","path":["paths","/pets","get"],"message":"Operation must have \"operationId\".","severity":1,"source":"/spec/openapi.yaml","range":{"start":{"line":9,"character":4}}},
+  {"code":"oas3-schema","path":["info"],"message":"\"info\" property must have required property \"version\".","severity":0,"source":"/spec/openapi.yaml","range":{"start":{"line":1,"character":0}}}
+]`)
+	findings, err := parseSpectralFindings(out)
+	if err != nil {
+		t.Fatalf("parseSpectralFindings: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+	w := findings[0]
+	if w.Severity != SeverityWarning || w.Line != 10 || w.Column != 5 || w.Path != "paths./pets.get" {
+		t.Errorf("unexpected warning finding: %+v", w)
+	}
+	if findings[1].Severity != SeverityError || findings[1].RuleID != "oas3-schema" {
+		t.Errorf("unexpected error finding: %+v", findings[1])
+	}
+	if !HasErrors(findings) {
+		t.Error("expected HasErrors to be true")
+	}
+}
+
+func TestParseOasdiffFindings(t *testing.T) {
+	out := []byte(`[
+  {"id":"api-path-removed-without-deprecation","text":"api path removed without deprecation","level":3,"operation":"GET","path":"/pets"},
+  {"id":"response-optional-property-removed","text":"removed optional property","level":"WARN","operation":"POST","path":"/pets"},
+  {"id":"endpoint-added","text":"endpoint added","level":1,"operation":"GET","path":"/owners"}
+]`)
+	findings, err := parseOasdiffFindings(out)
+	if err != nil {
+		t.Fatalf("parseOasdiffFindings: %v", err)
+	}
+	want := []Severity{SeverityError, SeverityWarning, SeverityInfo}
+	for i, f := range findings {
+		if f.Severity != want[i] {
+			t.Errorf("finding %d: severity %q, want %q", i, f.Severity, want[i])
+		}
+	}
+	if findings[0].Path != "GET /pets" {
+		t.Errorf("unexpected path: %q", findings[0].Path)
+	}
+
+	if findings, err := parseOasdiffFindings([]byte("null")); err != nil || len(findings) != 0 {
+		t.Errorf("expected no findings for null report, got %v, %v", findings, err)
+	}
+}
@@ -6,13 +6,15 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
 // ParquetValidator handles Parquet schema validation.
 // APX represents Parquet schemas as message-notation text files (.parquet),
-// using the same schema language that parquet-tools outputs.
+// using the same schema language that parquet-tools outputs. Binary Parquet
+// data files are accepted too; their schema is read from the file footer.
 //
 // Example schema file:
 //
@@ -24,6 +26,7 @@ import (
 type ParquetValidator struct {
 	resolver                  *ToolchainResolver
 	allowAdditiveNullableOnly bool
+	rules                     *lintRuleSet // lint rule settings from apx.yaml
 }
 
 // NewParquetValidator creates a new Parquet validator
@@ -42,15 +45,34 @@ func (v *ParquetValidator) SetAdditiveNullableOnlyPolicy(allow bool) {
 // parquetMessage is a parsed Parquet message schema.
 type parquetMessage struct {
 	Name    string
-	Columns []parquetColumn
+	Columns []*parquetColumn
+	Line    int // line of the message header; 0 for a binary footer
 }
 
-// parquetColumn is a single column definition in a Parquet schema.
+// parquetColumn is a single field in a Parquet schema: either a primitive
+// column or a group holding nested fields.
 type parquetColumn struct {
 	Repetition string // required, optional, repeated
-	PhysType   string // int32, int64, float, double, binary, boolean, etc.
+	PhysType   string // int32, int64, float, double, binary, boolean, etc.; "group" for groups
+	Length     int    // byte length of a fixed_len_byte_array
 	Name       string
-	Annotation string // logical type annotation, e.g. STRING, DATE
+	Annotation string // logical type annotation as written, e.g. STRING, DECIMAL(10,2)
+	Logical    parquetLogicalType
+	Children   []*parquetColumn // fields of a group
+	Line       int
+}
+
+// isGroup reports whether the field is a group rather than a primitive column.
+func (c *parquetColumn) isGroup() bool {
+	return c.PhysType == "group"
+}
+
+// typeLabel renders the physical type, including a fixed length.
+func (c *parquetColumn) typeLabel() string {
+	if c.Length > 0 {
+		return fmt.Sprintf("%s(%d)", c.PhysType, c.Length)
+	}
+	return c.PhysType
 }
 
 // validParquetRepetitions is the set of valid repetition levels.
@@ -65,31 +87,20 @@ var validParquetTypes = map[string]bool{
 	"fixed_len_byte_array": true,
 }
 
-// validParquetAnnotations is the set of valid Parquet logical type annotations.
-var validParquetAnnotations = map[string]bool{
-	"STRING": true, "UTF8": true, "DATE": true,
-	"TIMESTAMP_MILLIS": true, "TIMESTAMP_MICROS": true,
-	"DECIMAL": true,
-	"INT_8":   true, "INT_16": true, "INT_32": true, "INT_64": true,
-	"UINT_8": true, "UINT_16": true, "UINT_32": true, "UINT_64": true,
-	"JSON": true, "BSON": true,
-	"MAP": true, "LIST": true,
-	"ENUM": true, "UUID": true,
-	"TIME_MILLIS": true, "TIME_MICROS": true,
-	"INTERVAL": true,
-}
-
-// columnLineRe matches a flat column definition line:
+// columnLineRe matches a primitive column definition line:
 //
-//	<repetition> <type> <name> [(<annotation>)];
+//	<repetition> <type>[(<length>)] <name> [(<annotation>)] [= <id>];
 var columnLineRe = regexp.MustCompile(
-	`^\s*(required|optional|repeated)\s+([\w_]+)\s+([\w_]+)(?:\s*\(([^)]+)\))?\s*;`)
+	`^\s*(required|optional|repeated)\s+([\w_]+)(?:\s*\(\s*(\d+)\s*\))?\s+([\w_]+)(?:\s*\(([^;]*)\))?(?:\s*=\s*\d+)?\s*;`)
 
 // messageHeaderRe matches the opening line of a message schema.
 var messageHeaderRe = regexp.MustCompile(`^\s*message\s+([\w_]+)\s*\{`)
 
-// groupHeaderRe matches a nested group definition line.
-var groupHeaderRe = regexp.MustCompile(`^\s*(required|optional|repeated)\s+group\s+\w+`)
+// groupHeaderRe matches the opening line of a nested group:
+//
+//	<repetition> group <name> [(<annotation>)] [= <id>] {
+var groupHeaderRe = regexp.MustCompile(
+	`^\s*(required|optional|repeated)\s+group\s+([\w_]+)(?:\s*\(([^{]*)\))?(?:\s*=\s*\d+)?\s*\{\s*$`)
 
 // snakeCaseRe matches valid snake_case identifiers.
 var snakeCaseRe = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
@@ -117,20 +128,72 @@ func isMessageNameValid(name string) bool {
 	return isSnakeCase(name)
 }
 
-// parseParquetSchema parses a Parquet message schema text file.
-func parseParquetSchema(path string) (*parquetMessage, error) {
+// parquetFieldPath joins a parent path and a field name with a dot.
+func parquetFieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// parseParquetSchema parses a Parquet schema into a tree of fields and lints
+// it. path may be a message-notation text file or a binary Parquet data file,
+// whose schema is read from the footer. Syntax and convention violations are
+// returned as findings; msg is nil when the file has no usable message
+// declaration. The error is non-nil only when the file could not be read.
+// rules holds the lint rule settings, nil for the defaults.
+func parseParquetSchema(path string, rules *lintRuleSet) (*parquetMessage, []Finding, error) {
+	if IsParquetDataFile(path) {
+		msg, err := readParquetFooter(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return msg, lintParquetMessage(path, msg, rules), nil
+	}
+	msg, findings, err := parseParquetText(path)
+	if err != nil || msg == nil {
+		return msg, findings, err
+	}
+	return msg, append(findings, lintParquetMessage(path, msg, rules)...), nil
+}
+
+// parseParquetText parses message notation into a tree of fields, reporting
+// syntax errors only; lintParquetMessage checks the result.
+func parseParquetText(path string) (*parquetMessage, []Finding, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("opening %s: %w", path, err)
+		return nil, nil, fmt.Errorf("opening %s: %w", path, err)
 	}
 	defer f.Close()
 
-	var msg parquetMessage
-	var violations []string
-	foundHeader := false
+	var findings []Finding
+	add := func(rule string, line int, elem, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     path,
+			Line:     line,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatParquet,
+			Path:     elem,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// frame is an open message or group whose closing brace is pending.
+	type frame struct {
+		col  *parquetColumn
+		path string
+	}
+	var msg *parquetMessage
+	var stack []*frame
 	lineNum := 0
-	depth := 0
-	seen := make(map[string]int) // column name → line number
+
+	// attach appends a field to the innermost open group.
+	attach := func(col *parquetColumn) string {
+		top := stack[len(stack)-1]
+		top.col.Children = append(top.col.Children, col)
+		return parquetFieldPath(top.path, col.Name)
+	}
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
@@ -143,117 +206,206 @@ func parseParquetSchema(path string) (*parquetMessage, error) {
 			continue
 		}
 
-		if !foundHeader {
+		if msg == nil {
 			m := messageHeaderRe.FindStringSubmatch(line)
 			if m == nil {
-				return nil, fmt.Errorf("line %d: expected 'message <name> {', got: %s", lineNum, trimmed)
-			}
-			msg.Name = m[1]
-			if !isMessageNameValid(msg.Name) {
-				violations = append(violations, fmt.Sprintf(
-					"line %d: message name %q should be PascalCase or snake_case", lineNum, msg.Name))
+				add("parquet-syntax", lineNum, "", "expected 'message <name> {', got: %s", trimmed)
+				return nil, findings, nil
 			}
-			foundHeader = true
-			depth = 1
+			msg = &parquetMessage{Name: m[1], Line: lineNum}
+			stack = []*frame{{col: &parquetColumn{PhysType: "group", Line: lineNum}}}
 			continue
 		}
 
-		// Track nesting depth for group fields
-		openCount := strings.Count(line, "{")
-		closeCount := strings.Count(line, "}")
-		depth += openCount - closeCount
-
-		if depth <= 0 {
-			// Closing brace of the top-level message
-			break
-		}
-
-		// Only parse top-level (depth==1) columns; skip nested group contents
-		if depth > 1 {
+		if strings.HasPrefix(trimmed, "}") {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				// Closing brace of the top-level message
+				msg.Columns = top.col.Children
+				break
+			}
 			continue
 		}
 
-		// Skip group headers (lines containing '{')
-		if groupHeaderRe.MatchString(trimmed) || trimmed == "}" {
+		if m := groupHeaderRe.FindStringSubmatch(line); m != nil {
+			col := &parquetColumn{
+				Repetition: m[1],
+				PhysType:   "group",
+				Name:       m[2],
+				Annotation: strings.TrimSpace(m[3]),
+				Line:       lineNum,
+			}
+			elem := attach(col)
+			stack = append(stack, &frame{col: col, path: elem})
 			continue
 		}
 
 		m := columnLineRe.FindStringSubmatch(line)
 		if m == nil {
-			// Closing brace is not an unrecognized line
-			if trimmed == "}" {
-				continue
+			add("parquet-syntax", lineNum, "", "unrecognized column definition: %s", trimmed)
+			if strings.HasSuffix(trimmed, "{") {
+				// Keep brace matching intact for a malformed group header.
+				stack = append(stack, &frame{col: &parquetColumn{PhysType: "group"}})
 			}
-			violations = append(violations, fmt.Sprintf(
-				"line %d: unrecognized column definition: %s", lineNum, trimmed))
 			continue
 		}
 
-		col := parquetColumn{
+		col := &parquetColumn{
 			Repetition: m[1],
 			PhysType:   m[2],
-			Name:       m[3],
-			Annotation: m[4],
+			Name:       m[4],
+			Annotation: strings.TrimSpace(m[5]),
+			Line:       lineNum,
+		}
+		if m[3] != "" {
+			col.Length, _ = strconv.Atoi(m[3])
 		}
 
 		if !validParquetRepetitions[col.Repetition] {
-			return nil, fmt.Errorf("line %d: invalid repetition %q", lineNum, col.Repetition)
-		}
-		if !validParquetTypes[col.PhysType] {
-			violations = append(violations, fmt.Sprintf(
-				"line %d: unknown physical type %q for column %q", lineNum, col.PhysType, col.Name))
+			add("parquet-syntax", lineNum, col.Name, "invalid repetition %q", col.Repetition)
 			continue
 		}
-
-		// Validate logical type annotation
-		if col.Annotation != "" && !validParquetAnnotations[strings.TrimSpace(col.Annotation)] {
-			violations = append(violations, fmt.Sprintf(
-				"line %d: unknown logical type annotation %q for column %q", lineNum, col.Annotation, col.Name))
-		}
-
-		// Check column naming convention (snake_case)
-		if !isSnakeCase(col.Name) {
-			violations = append(violations, fmt.Sprintf(
-				"line %d: column name %q should be snake_case", lineNum, col.Name))
+		// "optional group name;" declares a group with no fields.
+		if !col.isGroup() && !validParquetTypes[col.PhysType] {
+			add("parquet-physical-type", lineNum, parquetFieldPath(stack[len(stack)-1].path, col.Name),
+				"unknown physical type %q for column %q", col.PhysType, col.Name)
+			continue
 		}
+		attach(col)
+	}
 
-		// Check for duplicate column names
-		if prevLine, exists := seen[col.Name]; exists {
-			violations = append(violations, fmt.Sprintf(
-				"line %d: duplicate column name %q (first defined on line %d)", lineNum, col.Name, prevLine))
-		}
-		seen[col.Name] = lineNum
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if msg == nil {
+		add("parquet-syntax", 0, "", "no 'message' declaration found in %s", path)
+		return nil, findings, nil
+	}
+	if len(stack) > 0 {
+		// Unterminated message: keep what was parsed so far.
+		add("parquet-syntax", lineNum, "", "missing closing '}' for message %q", msg.Name)
+		msg.Columns = stack[0].col.Children
+	}
+	return msg, findings, nil
+}
 
-		msg.Columns = append(msg.Columns, col)
+// lintParquetMessage checks a parsed schema tree against the naming,
+// physical type, logical type and LIST/MAP conventions.
+func lintParquetMessage(path string, msg *parquetMessage, rules *lintRuleSet) []Finding {
+	var findings []Finding
+	add := func(rule string, line int, elem, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     path,
+			Line:     line,
+			RuleID:   rule,
+			Severity: SeverityError,
+			Format:   FormatParquet,
+			Path:     elem,
+			Message:  fmt.Sprintf(format, args...),
+		})
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading %s: %w", path, err)
+	if !rules.nameOK("parquet-message-name", msg.Name, isMessageNameValid) {
+		add("parquet-message-name", msg.Line, msg.Name,
+			"message name %q should be PascalCase or snake_case", msg.Name)
 	}
-	if !foundHeader {
-		return nil, fmt.Errorf("no 'message' declaration found in %s", path)
+
+	var walk func(fields []*parquetColumn, parent string)
+	walk = func(fields []*parquetColumn, parent string) {
+		seen := make(map[string]*parquetColumn, len(fields))
+		for _, col := range fields {
+			elem := parquetFieldPath(parent, col.Name)
+
+			// Check column naming convention (snake_case)
+			if !rules.nameOK("parquet-column-snake-case", col.Name, isSnakeCase) {
+				add("parquet-column-snake-case", col.Line, elem, "column name %q should be snake_case", col.Name)
+			}
+
+			// Check for duplicate column names
+			if prev, exists := seen[col.Name]; exists {
+				if prev.Line > 0 {
+					add("parquet-column-duplicate", col.Line, elem,
+						"duplicate column name %q (first defined on line %d)", col.Name, prev.Line)
+				} else {
+					add("parquet-column-duplicate", col.Line, elem, "duplicate column name %q", col.Name)
+				}
+			} else {
+				seen[col.Name] = col
+			}
+
+			switch {
+			case col.PhysType == "fixed_len_byte_array" && col.Length <= 0:
+				add("parquet-physical-type", col.Line, elem,
+					"column %q: fixed_len_byte_array requires a positive length, e.g. fixed_len_byte_array(16)", col.Name)
+			case col.PhysType != "fixed_len_byte_array" && col.Length > 0:
+				add("parquet-physical-type", col.Line, elem,
+					"column %q: only fixed_len_byte_array takes a length", col.Name)
+			}
+
+			// Validate logical type annotation
+			if col.Annotation != "" {
+				lt, err := parseParquetLogicalType(col.Annotation)
+				if err != nil {
+					add("parquet-logical-type", col.Line, elem, "%v for column %q", err, col.Name)
+				} else {
+					col.Logical = lt
+					if err := lt.checkPhysical(col); err != nil {
+						add("parquet-logical-type", col.Line, elem, "column %q: %v", col.Name, err)
+					}
+				}
+			}
+
+			if col.isGroup() {
+				walk(col.Children, elem)
+				findings = append(findings, checkParquetGroup(path, col, elem)...)
+			}
+		}
 	}
+	walk(msg.Columns, "")
 
 	// Empty message check
 	if len(msg.Columns) == 0 {
-		violations = append(violations, "message has no columns")
+		add("parquet-message-empty", 0, msg.Name, "message has no columns")
 	}
+	return findings
+}
 
-	if len(violations) > 0 {
-		return nil, fmt.Errorf("parquet lint errors:\n  %s", strings.Join(violations, "\n  "))
+// loadParquetSchema parses a schema for breaking-change analysis, where any
+// lint violation makes the schema unusable as a comparison input. Naming
+// conventions are not checked, so legacy column names can be compared.
+func loadParquetSchema(path string) (*parquetMessage, error) {
+	msg, findings, err := parseParquetSchema(path, nil)
+	if err != nil {
+		return nil, err
 	}
-
-	return &msg, nil
+	var problems []Finding
+	for _, f := range findings {
+		if rule, ok := lookupLintRule(f.RuleID); !ok || !rule.Naming {
+			problems = append(problems, f)
+		}
+	}
+	if err := findingsError("parquet lint errors", problems, nil); err != nil {
+		return nil, err
+	}
+	return msg, nil
 }
 
 // Lint validates Parquet schema syntax using the native message-notation parser.
 func (v *ParquetValidator) Lint(path string) error {
+	findings, err := v.LintFindings(path)
+	return findingsError("parquet lint errors", findings, err)
+}
+
+// LintFindings validates Parquet schema syntax and returns every violation as
+// a Finding.
+func (v *ParquetValidator) LintFindings(path string) ([]Finding, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
-	_, err = parseParquetSchema(absPath)
-	return err
+	_, findings, err := parseParquetSchema(absPath, v.rules)
+	return findings, err
 }
 
 // Breaking checks for breaking changes between two Parquet schemas.
@@ -266,82 +418,174 @@ func (v *ParquetValidator) Lint(path string) error {
 //   - Type changes are breaking
 //   - required → optional is allowed (relaxing the constraint)
 //   - optional → required is breaking
+//   - repeated ↔ required/optional is breaking
+//   - DECIMAL scale changes and precision narrowing are breaking; widening
+//     the precision is a warning
+//
+// With the policy off, type widening that every reader can follow is a
+// warning rather than breaking: int32 to int64 or double, float to double,
+// and an INTEGER annotation growing its bit width with the same signedness.
+//
+// Nested groups, including LIST and MAP structures, are compared field by
+// field and reported by dotted path, e.g. address.geo.lat.
 func (v *ParquetValidator) Breaking(path, against string) error {
+	findings, err := v.BreakingFindings(path, against)
+	return findingsError("parquet schema breaking changes", findings, err)
+}
+
+// BreakingFindings checks for breaking changes between two Parquet schemas and
+// returns every incompatibility as a Finding.
+func (v *ParquetValidator) BreakingFindings(path, against string) ([]Finding, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 	absAgainst, err := filepath.Abs(against)
 	if err != nil {
-		return fmt.Errorf("failed to resolve against path: %w", err)
+		return nil, fmt.Errorf("failed to resolve against path: %w", err)
 	}
 
-	newMsg, err := parseParquetSchema(absPath)
+	newMsg, err := loadParquetSchema(absPath)
 	if err != nil {
-		return fmt.Errorf("parsing new schema: %w", err)
+		return nil, fmt.Errorf("parsing new schema: %w", err)
 	}
-	oldMsg, err := parseParquetSchema(absAgainst)
+	oldMsg, err := loadParquetSchema(absAgainst)
 	if err != nil {
-		return fmt.Errorf("parsing old schema: %w", err)
+		return nil, fmt.Errorf("parsing old schema: %w", err)
 	}
 
-	oldCols := make(map[string]parquetColumn, len(oldMsg.Columns))
-	for _, c := range oldMsg.Columns {
-		oldCols[c.Name] = c
-	}
-	newCols := make(map[string]parquetColumn, len(newMsg.Columns))
-	for _, c := range newMsg.Columns {
-		newCols[c.Name] = c
+	var findings []Finding
+	add := func(sev Severity, rule, column string, line int, oldVal, newVal, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     path,
+			Line:     line,
+			RuleID:   rule,
+			Severity: sev,
+			Format:   FormatParquet,
+			Path:     column,
+			Message:  fmt.Sprintf(format, args...),
+			OldValue: oldVal,
+			NewValue: newVal,
+		})
 	}
+	diffParquetFields(add, "", oldMsg.Columns, newMsg.Columns, !v.allowAdditiveNullableOnly)
+	return findings, nil
+}
 
-	var violations []string
+// parquetDiffFunc records one breaking-change finding.
+type parquetDiffFunc func(sev Severity, rule, column string, line int, oldVal, newVal, format string, args ...interface{})
+
+// parquetWidenings lists, for each physical type, the wider types that can
+// represent all of its values.
+var parquetWidenings = map[string][]string{
+	"int32": {"int64", "double"},
+	"float": {"double"},
+}
 
-	for _, nc := range newMsg.Columns {
-		oc, existed := oldCols[nc.Name]
+// diffParquetFields compares the fields of two groups, recursing into nested
+// groups (including LIST and MAP structures) by dotted path. With widen set,
+// type widening is a warning rather than a breaking change.
+func diffParquetFields(add parquetDiffFunc, parent string, oldFields, newFields []*parquetColumn, widen bool) {
+	oldByName := make(map[string]*parquetColumn, len(oldFields))
+	for _, c := range oldFields {
+		oldByName[c.Name] = c
+	}
+	newByName := make(map[string]*parquetColumn, len(newFields))
+	for _, c := range newFields {
+		newByName[c.Name] = c
+	}
+
+	for _, nc := range newFields {
+		name := parquetFieldPath(parent, nc.Name)
+		oc, existed := oldByName[nc.Name]
 		if !existed {
 			// New column: optional is additive-nullable (OK); required is breaking
 			if nc.Repetition == "required" {
-				violations = append(violations, fmt.Sprintf(
+				add(SeverityError, "parquet-column-added-required", name, nc.Line, "", nc.Repetition,
 					"column %q added as required (old data has no values for it; add as optional instead)",
-					nc.Name))
+					name)
 			}
 			continue
 		}
 
-		// Type change
-		if nc.PhysType != oc.PhysType {
-			violations = append(violations, fmt.Sprintf(
+		// Type change, including primitive ↔ group
+		switch {
+		case nc.typeLabel() == oc.typeLabel():
+		case widen && containsString(parquetWidenings[oc.PhysType], nc.PhysType):
+			add(SeverityWarning, "parquet-column-type-widened", name, nc.Line, oc.typeLabel(), nc.typeLabel(),
+				"column %q physical type widened from %s to %s (readers must accept the wider type)",
+				name, oc.typeLabel(), nc.typeLabel())
+		default:
+			add(SeverityError, "parquet-column-type-changed", name, nc.Line, oc.typeLabel(), nc.typeLabel(),
 				"column %q physical type changed from %s to %s",
-				nc.Name, oc.PhysType, nc.PhysType))
+				name, oc.typeLabel(), nc.typeLabel())
 		}
 
 		// optional → required is breaking
-		if oc.Repetition == "optional" && nc.Repetition == "required" {
-			violations = append(violations, fmt.Sprintf(
+		switch {
+		case oc.Repetition == "optional" && nc.Repetition == "required":
+			add(SeverityError, "parquet-column-made-required", name, nc.Line, oc.Repetition, nc.Repetition,
 				"column %q changed from optional to required (old data may contain null values)",
-				nc.Name))
+				name)
+		case (oc.Repetition == "repeated") != (nc.Repetition == "repeated"):
+			add(SeverityError, "parquet-column-repetition-changed", name, nc.Line, oc.Repetition, nc.Repetition,
+				"column %q changed from %s to %s (repeated and non-repeated fields are encoded differently)",
+				name, oc.Repetition, nc.Repetition)
 		}
 
-		// Annotation (logical type) change is breaking
-		if nc.Annotation != oc.Annotation {
-			violations = append(violations, fmt.Sprintf(
-				"column %q annotation changed from %q to %q (logical type change affects deserialization)",
-				nc.Name, oc.Annotation, nc.Annotation))
+		diffParquetLogical(add, name, oc, nc, widen)
+
+		if oc.isGroup() && nc.isGroup() {
+			diffParquetFields(add, name, oc.Children, nc.Children, widen)
 		}
 	}
 
 	// Removed columns
-	for _, oc := range oldMsg.Columns {
-		if _, exists := newCols[oc.Name]; !exists {
-			violations = append(violations, fmt.Sprintf(
+	for _, oc := range oldFields {
+		if _, exists := newByName[oc.Name]; !exists {
+			name := parquetFieldPath(parent, oc.Name)
+			add(SeverityError, "parquet-column-removed", name, 0, oc.typeLabel(), "",
 				"column %q removed (readers depending on this column will break)",
-				oc.Name))
+				name)
 		}
 	}
+}
 
-	if len(violations) > 0 {
-		return fmt.Errorf("parquet schema breaking changes:\n  %s",
-			strings.Join(violations, "\n  "))
+// diffParquetLogical compares the logical type annotations of a column.
+// Legacy and current spellings of the same type (TIMESTAMP_MILLIS and
+// TIMESTAMP(MILLIS,true), UTF8 and STRING) are equivalent. With widen set,
+// a wider INTEGER of the same signedness is a warning.
+func diffParquetLogical(add parquetDiffFunc, name string, oc, nc *parquetColumn, widen bool) {
+	if oc.Logical == nc.Logical {
+		return
+	}
+	if o, n := oc.Logical, nc.Logical; widen && o.Name == "INTEGER" && n.Name == "INTEGER" &&
+		o.Signed == n.Signed && n.BitWidth > o.BitWidth {
+		add(SeverityWarning, "parquet-column-type-widened", name, nc.Line, oc.Annotation, nc.Annotation,
+			"column %q integer width widened from %d to %d bits (readers must accept the wider type)",
+			name, o.BitWidth, n.BitWidth)
+		return
+	}
+	if oc.Logical.Name == "DECIMAL" && nc.Logical.Name == "DECIMAL" {
+		o, n := oc.Logical, nc.Logical
+		if o.Scale != n.Scale {
+			add(SeverityError, "parquet-decimal-scale-changed", name, nc.Line, oc.Annotation, nc.Annotation,
+				"column %q decimal scale changed from %d to %d (stored values would be reinterpreted)",
+				name, o.Scale, n.Scale)
+		}
+		switch {
+		case n.Precision < o.Precision:
+			add(SeverityError, "parquet-decimal-precision-changed", name, nc.Line, oc.Annotation, nc.Annotation,
+				"column %q decimal precision narrowed from %d to %d (existing values may not fit)",
+				name, o.Precision, n.Precision)
+		case n.Precision > o.Precision:
+			add(SeverityWarning, "parquet-decimal-precision-changed", name, nc.Line, oc.Annotation, nc.Annotation,
+				"column %q decimal precision widened from %d to %d (readers must accept the wider precision)",
+				name, o.Precision, n.Precision)
+		}
+		return
 	}
-	return nil
+	add(SeverityError, "parquet-column-annotation-changed", name, nc.Line, oc.Annotation, nc.Annotation,
+		"column %q annotation changed from %q to %q (logical type change affects deserialization)",
+		name, oc.Annotation, nc.Annotation)
 }
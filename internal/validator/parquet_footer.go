@@ -0,0 +1,529 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// parquetMagic opens and closes every binary Parquet data file.
+const parquetMagic = "PAR1"
+
+// IsParquetDataFile reports whether path is a binary Parquet data file rather
+// than a message-notation schema.
+func IsParquetDataFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, len(parquetMagic))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return false
+	}
+	return string(head) == parquetMagic
+}
+
+// readParquetFooter reads the schema of a binary Parquet file. The file ends
+// with the Thrift-encoded FileMetaData, its 4-byte little-endian length and
+// the magic bytes; the schema is the flattened, depth-first list of
+// SchemaElements in FileMetaData field 2.
+func readParquetFooter(path string) (*parquetMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	size := info.Size()
+	const trailer = 8 // footer length + magic
+	if size < int64(2*len(parquetMagic)+4) {
+		return nil, fmt.Errorf("reading %s: file too small to be a Parquet data file", path)
+	}
+	tail := make([]byte, trailer)
+	if _, err := f.ReadAt(tail, size-trailer); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, fmt.Errorf("reading %s: missing Parquet footer magic (file truncated?)", path)
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if footerLen <= 0 || footerLen > size-trailer-int64(len(parquetMagic)) {
+		return nil, fmt.Errorf("reading %s: invalid Parquet footer length %d", path, footerLen)
+	}
+	footer := make([]byte, footerLen)
+	if _, err := f.ReadAt(footer, size-trailer-footerLen); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	elems, err := decodeParquetSchemaElements(footer)
+	if err != nil {
+		return nil, fmt.Errorf("decoding Parquet footer of %s: %w", path, err)
+	}
+	if len(elems) == 0 {
+		return nil, fmt.Errorf("decoding Parquet footer of %s: no schema", path)
+	}
+	root := elems[0]
+	next := 1
+	cols, err := buildParquetFields(elems, &next, root.numChildren)
+	if err != nil {
+		return nil, fmt.Errorf("decoding Parquet footer of %s: %w", path, err)
+	}
+	return &parquetMessage{Name: root.name, Columns: cols}, nil
+}
+
+// parquetSchemaElement holds the SchemaElement fields APX uses.
+type parquetSchemaElement struct {
+	name          string
+	physType      int32 // -1 for groups
+	typeLength    int32
+	repetition    int32
+	numChildren   int
+	convertedType int32 // -1 when unset
+	scale         int32
+	precision     int32
+	logical       string // annotation rendered from the LogicalType union
+}
+
+// parquetPhysicalTypes maps the Thrift Type enum to message-notation names.
+var parquetPhysicalTypes = []string{
+	"boolean", "int32", "int64", "int96", "float", "double", "binary", "fixed_len_byte_array",
+}
+
+// parquetRepetitionTypes maps the Thrift FieldRepetitionType enum.
+var parquetRepetitionTypes = []string{"required", "optional", "repeated"}
+
+// parquetConvertedTypes maps the Thrift ConvertedType enum.
+var parquetConvertedTypes = []string{
+	"UTF8", "MAP", "MAP_KEY_VALUE", "LIST", "ENUM", "DECIMAL", "DATE",
+	"TIME_MILLIS", "TIME_MICROS", "TIMESTAMP_MILLIS", "TIMESTAMP_MICROS",
+	"UINT_8", "UINT_16", "UINT_32", "UINT_64", "INT_8", "INT_16", "INT_32", "INT_64",
+	"JSON", "BSON", "INTERVAL",
+}
+
+// buildParquetFields rebuilds n sibling fields (and their descendants) from
+// the depth-first element list, starting at elems[*next].
+func buildParquetFields(elems []parquetSchemaElement, next *int, n int) ([]*parquetColumn, error) {
+	var cols []*parquetColumn
+	for i := 0; i < n; i++ {
+		if *next >= len(elems) {
+			return nil, errors.New("schema element list ends before all children were read")
+		}
+		e := elems[*next]
+		*next++
+
+		col := &parquetColumn{Name: e.name, Repetition: "required"}
+		if e.repetition >= 0 && int(e.repetition) < len(parquetRepetitionTypes) {
+			col.Repetition = parquetRepetitionTypes[e.repetition]
+		}
+		switch {
+		case e.physType < 0 || e.numChildren > 0:
+			col.PhysType = "group"
+		case int(e.physType) < len(parquetPhysicalTypes):
+			col.PhysType = parquetPhysicalTypes[e.physType]
+		default:
+			return nil, fmt.Errorf("column %q has unknown physical type %d", e.name, e.physType)
+		}
+		if col.PhysType == "fixed_len_byte_array" {
+			col.Length = int(e.typeLength)
+		}
+		col.Annotation = e.annotation()
+
+		if col.isGroup() {
+			children, err := buildParquetFields(elems, next, e.numChildren)
+			if err != nil {
+				return nil, err
+			}
+			col.Children = children
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// annotation renders the element's logical type in message notation,
+// preferring the LogicalType union over the legacy ConvertedType.
+func (e parquetSchemaElement) annotation() string {
+	if e.logical != "" {
+		return e.logical
+	}
+	if e.convertedType < 0 || int(e.convertedType) >= len(parquetConvertedTypes) {
+		return ""
+	}
+	name := parquetConvertedTypes[e.convertedType]
+	if name == "DECIMAL" {
+		return fmt.Sprintf("DECIMAL(%d,%d)", e.precision, e.scale)
+	}
+	return name
+}
+
+// Thrift compact protocol type codes.
+const (
+	thriftStop   = 0
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// thriftReader decodes the subset of the Thrift compact protocol needed to
+// walk Parquet metadata.
+type thriftReader struct {
+	r     *bytes.Reader
+	depth int
+}
+
+func (t *thriftReader) varint() (uint64, error) {
+	return binary.ReadUvarint(t.r)
+}
+
+func (t *thriftReader) zigzag() (int64, error) {
+	u, err := t.varint()
+	return int64(u>>1) ^ -int64(u&1), err
+}
+
+func (t *thriftReader) i32() (int32, error) {
+	v, err := t.zigzag()
+	return int32(v), err
+}
+
+func (t *thriftReader) binary() ([]byte, error) {
+	n, err := t.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(t.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(t.r, b)
+	return b, err
+}
+
+// listHeader reads a list or set header.
+func (t *thriftReader) listHeader() (int, byte, error) {
+	b, err := t.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	size := uint64(b >> 4)
+	if size == 15 {
+		if size, err = t.varint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	if size > uint64(t.r.Len()) {
+		return 0, 0, fmt.Errorf("list of %d elements exceeds the remaining metadata", size)
+	}
+	return int(size), b & 0x0f, nil
+}
+
+// readStruct calls field for each field of a struct until the stop marker.
+// Boolean fields carry their value in the type code, which field receives
+// as thriftTrue or thriftFalse.
+func (t *thriftReader) readStruct(field func(id int16, typ byte) error) error {
+	t.depth++
+	defer func() { t.depth-- }()
+	if t.depth > 64 {
+		return errors.New("metadata nested too deeply")
+	}
+	var last int16
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		typ := b & 0x0f
+		if typ == thriftStop {
+			return nil
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := t.zigzag()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		last = id
+		if err := field(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip discards a value of the given type.
+func (t *thriftReader) skip(typ byte) error {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		return nil
+	case thriftByte:
+		_, err := t.r.ReadByte()
+		return err
+	case thriftI16, thriftI32, thriftI64:
+		_, err := t.varint()
+		return err
+	case thriftDouble:
+		_, err := t.r.Seek(8, io.SeekCurrent)
+		return err
+	case thriftBinary:
+		n, err := t.varint()
+		if err != nil {
+			return err
+		}
+		if n > uint64(t.r.Len()) {
+			return io.ErrUnexpectedEOF
+		}
+		_, err = t.r.Seek(int64(n), io.SeekCurrent)
+		return err
+	case thriftList, thriftSet:
+		n, elem, err := t.listHeader()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err := t.skipElem(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftMap:
+		n, err := t.varint()
+		if err != nil || n == 0 {
+			return err
+		}
+		// Every entry takes at least one byte, so a larger count is corrupt.
+		if n > uint64(t.r.Len()) {
+			return fmt.Errorf("map of %d entries exceeds the remaining metadata", n)
+		}
+		kv, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := t.skipElem(kv >> 4); err != nil {
+				return err
+			}
+			if err := t.skipElem(kv & 0x0f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		return t.readStruct(func(_ int16, typ byte) error { return t.skip(typ) })
+	}
+	return fmt.Errorf("unknown Thrift compact type %d", typ)
+}
+
+// skipElem discards a list, set or map element. Booleans inside containers
+// take one byte each rather than living in the type code.
+func (t *thriftReader) skipElem(typ byte) error {
+	if typ == thriftTrue || typ == thriftFalse {
+		_, err := t.r.ReadByte()
+		return err
+	}
+	return t.skip(typ)
+}
+
+// decodeParquetSchemaElements decodes FileMetaData up to and including its
+// schema field; row group metadata after it is never read.
+func decodeParquetSchemaElements(footer []byte) ([]parquetSchemaElement, error) {
+	t := &thriftReader{r: bytes.NewReader(footer)}
+	var elems []parquetSchemaElement
+	errDone := errors.New("done")
+	err := t.readStruct(func(id int16, typ byte) error {
+		if id != 2 || typ != thriftList {
+			return t.skip(typ)
+		}
+		n, elemType, err := t.listHeader()
+		if err != nil {
+			return err
+		}
+		if elemType != thriftStruct {
+			return fmt.Errorf("schema list holds type %d, want struct", elemType)
+		}
+		for i := 0; i < n; i++ {
+			e, err := t.schemaElement()
+			if err != nil {
+				return fmt.Errorf("schema element %d: %w", i, err)
+			}
+			elems = append(elems, e)
+		}
+		return errDone
+	})
+	if err != nil && !errors.Is(err, errDone) {
+		return nil, err
+	}
+	return elems, nil
+}
+
+// schemaElement decodes one SchemaElement struct.
+func (t *thriftReader) schemaElement() (parquetSchemaElement, error) {
+	e := parquetSchemaElement{physType: -1, repetition: -1, convertedType: -1}
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			e.physType, err = t.i32()
+		case id == 2 && typ == thriftI32:
+			e.typeLength, err = t.i32()
+		case id == 3 && typ == thriftI32:
+			e.repetition, err = t.i32()
+		case id == 4 && typ == thriftBinary:
+			var b []byte
+			b, err = t.binary()
+			e.name = string(b)
+		case id == 5 && typ == thriftI32:
+			var n int32
+			n, err = t.i32()
+			e.numChildren = int(n)
+		case id == 6 && typ == thriftI32:
+			e.convertedType, err = t.i32()
+		case id == 7 && typ == thriftI32:
+			e.scale, err = t.i32()
+		case id == 8 && typ == thriftI32:
+			e.precision, err = t.i32()
+		case id == 10 && typ == thriftStruct:
+			e.logical, err = t.logicalType()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+	if e.numChildren < 0 {
+		return e, fmt.Errorf("column %q has a negative child count", e.name)
+	}
+	return e, err
+}
+
+// parquetLogicalTypeNames maps LogicalType union field IDs that carry no
+// parameters to their annotation names.
+var parquetLogicalTypeNames = map[int16]string{
+	1: "STRING", 2: "MAP", 3: "LIST", 4: "ENUM", 6: "DATE",
+	11: "UNKNOWN", 12: "JSON", 13: "BSON", 14: "UUID", 15: "FLOAT16",
+}
+
+// logicalType decodes the LogicalType union into message notation, e.g.
+// DECIMAL(10,2) or TIMESTAMP(MICROS,true). Unrecognized members yield "".
+func (t *thriftReader) logicalType() (string, error) {
+	var out string
+	err := t.readStruct(func(id int16, typ byte) error {
+		if typ != thriftStruct {
+			return t.skip(typ)
+		}
+		if name, ok := parquetLogicalTypeNames[id]; ok {
+			out = name
+			return t.skip(typ)
+		}
+		switch id {
+		case 5: // DecimalType{1: scale, 2: precision}
+			var scale, precision int32
+			err := t.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					scale, err = t.i32()
+				case id == 2 && typ == thriftI32:
+					precision, err = t.i32()
+				default:
+					err = t.skip(typ)
+				}
+				return err
+			})
+			out = fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+			return err
+		case 7, 8: // TimeType / TimestampType{1: isAdjustedToUTC, 2: unit}
+			utc, unit := false, ""
+			err := t.readStruct(func(id int16, typ byte) error {
+				switch {
+				case id == 1 && (typ == thriftTrue || typ == thriftFalse):
+					utc = typ == thriftTrue
+					return nil
+				case id == 2 && typ == thriftStruct:
+					return t.readStruct(func(id int16, typ byte) error {
+						unit = map[int16]string{1: "MILLIS", 2: "MICROS", 3: "NANOS"}[id]
+						return t.skip(typ)
+					})
+				}
+				return t.skip(typ)
+			})
+			name := "TIME"
+			if id == 8 {
+				name = "TIMESTAMP"
+			}
+			out = fmt.Sprintf("%s(%s,%t)", name, unit, utc)
+			return err
+		case 10: // IntType{1: bitWidth (i8), 2: isSigned}
+			var width int8
+			signed := false
+			err := t.readStruct(func(id int16, typ byte) error {
+				switch {
+				case id == 1 && typ == thriftByte:
+					b, err := t.r.ReadByte()
+					width = int8(b)
+					return err
+				case id == 2 && (typ == thriftTrue || typ == thriftFalse):
+					signed = typ == thriftTrue
+					return nil
+				}
+				return t.skip(typ)
+			})
+			out = fmt.Sprintf("INTEGER(%d,%t)", width, signed)
+			return err
+		}
+		return t.skip(typ)
+	})
+	return out, err
+}
+
+// ParquetInfo is the catalog-facing view of a Parquet schema: the message
+// name and its top-level columns. Groups have PhysType "group".
+type ParquetInfo struct {
+	MessageName string
+	Columns     []ParquetColumnInfo
+}
+
+// ParquetColumnInfo describes one top-level column.
+type ParquetColumnInfo struct {
+	Name       string
+	PhysType   string
+	Repetition string
+	Annotation string
+}
+
+// ExtractParquetInfo reads the schema of a Parquet file (binary data file or
+// message notation) for the catalog. Lint violations are ignored.
+func ExtractParquetInfo(path string) (*ParquetInfo, error) {
+	msg, _, err := parseParquetSchema(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("no message definition found in %s", path)
+	}
+	info := &ParquetInfo{MessageName: msg.Name}
+	for _, c := range msg.Columns {
+		info.Columns = append(info.Columns, ParquetColumnInfo{
+			Name:       c.Name,
+			PhysType:   c.typeLabel(),
+			Repetition: c.Repetition,
+			Annotation: c.Annotation,
+		})
+	}
+	return info, nil
+}
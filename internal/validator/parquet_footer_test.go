@@ -0,0 +1,262 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// thriftWriter encodes Thrift compact protocol for building test footers.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // last field ID per open struct
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (w *thriftWriter) zigzag(v int64) { w.varint(uint64((v << 1) ^ (v >> 63))) }
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	top := &w.last[len(w.last)-1]
+	if d := id - *top; d > 0 && d <= 15 {
+		w.buf.WriteByte(byte(d)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	*top = id
+}
+
+func (w *thriftWriter) begin()                { w.last = append(w.last, 0) }
+func (w *thriftWriter) end()                  { w.buf.WriteByte(thriftStop); w.last = w.last[:len(w.last)-1] }
+func (w *thriftWriter) i32(id int16, v int32) { w.field(id, thriftI32); w.zigzag(int64(v)) }
+func (w *thriftWriter) str(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+func (w *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		w.field(id, thriftTrue)
+	} else {
+		w.field(id, thriftFalse)
+	}
+}
+func (w *thriftWriter) structField(id int16, body func()) {
+	w.field(id, thriftStruct)
+	w.begin()
+	if body != nil {
+		body()
+	}
+	w.end()
+}
+
+// testElement is a SchemaElement for writeTestParquetFile.
+type testElement struct {
+	name        string
+	physType    int32 // -1 for groups
+	repetition  int32 // -1 for the root
+	children    int32
+	converted   int32 // -1 when unset
+	scale       int32
+	precision   int32
+	typeLength  int32
+	logical     func(w *thriftWriter)
+	extraFields bool
+}
+
+// writeTestParquetFile writes a minimal Parquet data file whose footer holds
+// the given flattened schema.
+func writeTestParquetFile(t *testing.T, path string, elems []testElement) {
+	t.Helper()
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, 1) // version
+	w.field(2, thriftList)
+	if len(elems) < 15 {
+		w.buf.WriteByte(byte(len(elems))<<4 | thriftStruct)
+	} else {
+		w.buf.WriteByte(0xf0 | thriftStruct)
+		w.varint(uint64(len(elems)))
+	}
+	for _, e := range elems {
+		w.begin()
+		if e.physType >= 0 {
+			w.i32(1, e.physType)
+		}
+		if e.typeLength > 0 {
+			w.i32(2, e.typeLength)
+		}
+		if e.repetition >= 0 {
+			w.i32(3, e.repetition)
+		}
+		w.str(4, e.name)
+		if e.children > 0 {
+			w.i32(5, e.children)
+		}
+		if e.converted >= 0 {
+			w.i32(6, e.converted)
+		}
+		if e.precision > 0 {
+			w.i32(7, e.scale)
+			w.i32(8, e.precision)
+		}
+		if e.extraFields {
+			w.i32(9, 42) // field_id, not used by APX
+		}
+		if e.logical != nil {
+			w.structField(10, func() { e.logical(w) })
+		}
+		w.end()
+	}
+	w.field(3, thriftI64)
+	w.zigzag(100) // num_rows
+	w.str(6, "apx test writer")
+	w.end()
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+	file.WriteString("column chunk data")
+	file.Write(w.buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(w.buf.Len()))
+	file.WriteString(parquetMagic)
+	if err := os.WriteFile(path, file.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stringLogical(w *thriftWriter) { w.structField(1, nil) }
+
+// customerElements is the footer schema equivalent of:
+//
+//	message customer {
+//	  required binary id (STRING);
+//	  optional int64 amount (DECIMAL(12,2));
+//	  optional int64 created_at (TIMESTAMP(MICROS,true));
+//	  optional group tags (LIST) {
+//	    repeated group list {
+//	      optional binary element (STRING);
+//	    }
+//	  }
+//	}
+func customerElements() []testElement {
+	return []testElement{
+		{name: "customer", physType: -1, repetition: -1, children: 4, converted: -1},
+		{name: "id", physType: 6, repetition: 0, converted: 0, logical: stringLogical, extraFields: true},
+		{name: "amount", physType: 2, repetition: 1, converted: 5, scale: 2, precision: 12, logical: func(w *thriftWriter) {
+			w.structField(5, func() { w.i32(1, 2); w.i32(2, 12) })
+		}},
+		{name: "created_at", physType: 2, repetition: 1, converted: 10, logical: func(w *thriftWriter) {
+			w.structField(8, func() {
+				w.boolean(1, true)
+				w.structField(2, func() { w.structField(2, nil) })
+			})
+		}},
+		{name: "tags", physType: -1, repetition: 1, children: 1, converted: 3, logical: func(w *thriftWriter) { w.structField(3, nil) }},
+		{name: "list", physType: -1, repetition: 2, children: 1, converted: -1},
+		{name: "element", physType: 6, repetition: 1, converted: 0, logical: stringLogical},
+	}
+}
+
+func TestReadParquetFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customer.parquet")
+	writeTestParquetFile(t, path, customerElements())
+
+	if !IsParquetDataFile(path) {
+		t.Fatal("IsParquetDataFile() = false for a binary file")
+	}
+	if IsParquetDataFile("testdata/parquet/v1.parquet") {
+		t.Fatal("IsParquetDataFile() = true for a message-notation file")
+	}
+
+	msg, err := readParquetFooter(path)
+	if err != nil {
+		t.Fatalf("readParquetFooter: %v", err)
+	}
+	if msg.Name != "customer" || len(msg.Columns) != 4 {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	want := []struct{ name, rep, typ, ann string }{
+		{"id", "required", "binary", "STRING"},
+		{"amount", "optional", "int64", "DECIMAL(12,2)"},
+		{"created_at", "optional", "int64", "TIMESTAMP(MICROS,true)"},
+		{"tags", "optional", "group", "LIST"},
+	}
+	for i, w := range want {
+		c := msg.Columns[i]
+		if c.Name != w.name || c.Repetition != w.rep || c.PhysType != w.typ || c.Annotation != w.ann {
+			t.Errorf("column %d = %s %s %s (%s), want %s %s %s (%s)",
+				i, c.Repetition, c.PhysType, c.Name, c.Annotation, w.rep, w.typ, w.name, w.ann)
+		}
+	}
+	el := msg.Columns[3].Children[0].Children[0]
+	if el.Name != "element" || el.Annotation != "STRING" {
+		t.Errorf("unexpected list element: %+v", el)
+	}
+}
+
+func TestParquetValidator_DataFile(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "customer.parquet")
+	writeTestParquetFile(t, data, customerElements())
+	v := NewParquetValidator(&ToolchainResolver{})
+
+	findings, err := v.LintFindings(data)
+	if err != nil {
+		t.Fatalf("LintFindings: %v", err)
+	}
+	if HasErrors(findings) {
+		t.Errorf("expected binary file to lint clean, got %+v", findings)
+	}
+
+	released := filepath.Join(dir, "released.parquet")
+	mustWrite(t, released, `message customer {
+  required binary id (UTF8);
+  optional int64 amount (DECIMAL(12,2));
+  optional int64 created_at (TIMESTAMP_MICROS);
+  optional binary region (STRING);
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (STRING);
+    }
+  }
+}
+`)
+	findings, err = v.BreakingFindings(data, released)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "parquet-column-removed" || findings[0].Path != "region" {
+		t.Errorf("expected only region removed, got %+v", findings)
+	}
+}
+
+func TestReadParquetFooter_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string][]byte{
+		"truncated":      []byte("PAR1 some data without a footer"),
+		"bad length":     append([]byte("PAR1xxxx"), 0xff, 0xff, 0, 0, 'P', 'A', 'R', '1'),
+		"garbage footer": append([]byte("PAR1"), 0xff, 0xff, 0xff, 2, 0, 0, 0, 'P', 'A', 'R', '1'),
+		// Field 1 is a map<bool,bool> claiming 2^63 entries; skipping it
+		// must fail rather than loop.
+		"huge map": append([]byte("PAR1"),
+			0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f, 0x11, 0,
+			12, 0, 0, 0, 'P', 'A', 'R', '1'),
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "bad.parquet")
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewParquetValidator(&ToolchainResolver{}).LintFindings(path); err == nil {
+				t.Error("expected an error for a corrupt footer")
+			}
+		})
+	}
+}
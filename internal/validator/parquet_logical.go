@@ -0,0 +1,293 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parquetLogicalType is a parsed logical type annotation. Legacy converted
+// types are normalized to their current form, so TIMESTAMP_MILLIS and
+// TIMESTAMP(MILLIS,true) compare equal.
+type parquetLogicalType struct {
+	Name      string // STRING, TIMESTAMP, DECIMAL, INTEGER, LIST, ...
+	Unit      string // TIMESTAMP, TIME: MILLIS, MICROS or NANOS
+	UTC       bool   // TIMESTAMP, TIME: isAdjustedToUTC
+	Precision int    // DECIMAL
+	Scale     int    // DECIMAL
+	BitWidth  int    // INTEGER
+	Signed    bool   // INTEGER
+}
+
+// parquetPlainAnnotations are the logical types that take no parameters.
+var parquetPlainAnnotations = map[string]bool{
+	"STRING": true, "DATE": true, "JSON": true, "BSON": true,
+	"ENUM": true, "UUID": true, "INTERVAL": true, "FLOAT16": true,
+	"LIST": true, "MAP": true, "MAP_KEY_VALUE": true, "UNKNOWN": true,
+}
+
+// parquetLegacyAnnotations maps legacy converted types to logical types.
+var parquetLegacyAnnotations = map[string]parquetLogicalType{
+	"UTF8":             {Name: "STRING"},
+	"TIMESTAMP_MILLIS": {Name: "TIMESTAMP", Unit: "MILLIS", UTC: true},
+	"TIMESTAMP_MICROS": {Name: "TIMESTAMP", Unit: "MICROS", UTC: true},
+	"TIME_MILLIS":      {Name: "TIME", Unit: "MILLIS", UTC: true},
+	"TIME_MICROS":      {Name: "TIME", Unit: "MICROS", UTC: true},
+	"INT_8":            {Name: "INTEGER", BitWidth: 8, Signed: true},
+	"INT_16":           {Name: "INTEGER", BitWidth: 16, Signed: true},
+	"INT_32":           {Name: "INTEGER", BitWidth: 32, Signed: true},
+	"INT_64":           {Name: "INTEGER", BitWidth: 64, Signed: true},
+	"UINT_8":           {Name: "INTEGER", BitWidth: 8},
+	"UINT_16":          {Name: "INTEGER", BitWidth: 16},
+	"UINT_32":          {Name: "INTEGER", BitWidth: 32},
+	"UINT_64":          {Name: "INTEGER", BitWidth: 64},
+}
+
+// parseParquetLogicalType parses an annotation such as STRING,
+// DECIMAL(10,2), TIMESTAMP(MILLIS,true) or INTEGER(16,false). TIMESTAMP and
+// TIME accept their unit and isAdjustedToUTC arguments in either order.
+func parseParquetLogicalType(raw string) (parquetLogicalType, error) {
+	name, args, hasArgs := strings.Cut(raw, "(")
+	name = strings.ToUpper(strings.TrimSpace(name))
+	var params []string
+	if hasArgs {
+		inner, ok := strings.CutSuffix(strings.TrimSpace(args), ")")
+		if !ok {
+			return parquetLogicalType{}, fmt.Errorf("malformed logical type annotation %q", raw)
+		}
+		for _, p := range strings.Split(inner, ",") {
+			params = append(params, strings.TrimSpace(p))
+		}
+	}
+
+	if lt, ok := parquetLegacyAnnotations[name]; ok || parquetPlainAnnotations[name] {
+		if hasArgs {
+			return parquetLogicalType{}, fmt.Errorf("logical type annotation %q takes no parameters", raw)
+		}
+		if !ok {
+			lt = parquetLogicalType{Name: name}
+		}
+		return lt, nil
+	}
+
+	lt := parquetLogicalType{Name: name}
+	switch name {
+	case "DECIMAL":
+		if len(params) < 1 || len(params) > 2 {
+			return lt, fmt.Errorf("logical type annotation %q needs DECIMAL(precision[,scale])", raw)
+		}
+		p, err := strconv.Atoi(params[0])
+		if err != nil || p <= 0 {
+			return lt, fmt.Errorf("invalid DECIMAL precision in %q", raw)
+		}
+		lt.Precision = p
+		if len(params) == 2 {
+			s, err := strconv.Atoi(params[1])
+			if err != nil || s < 0 || s > p {
+				return lt, fmt.Errorf("invalid DECIMAL scale in %q (must be between 0 and the precision)", raw)
+			}
+			lt.Scale = s
+		}
+	case "TIMESTAMP", "TIME":
+		var haveUnit, haveUTC bool
+		for _, p := range params {
+			switch up := strings.ToUpper(p); up {
+			case "MILLIS", "MICROS", "NANOS":
+				lt.Unit, haveUnit = up, true
+			case "TRUE", "FALSE":
+				lt.UTC, haveUTC = up == "TRUE", true
+			default:
+				return lt, fmt.Errorf("invalid %s parameter %q in %q", name, p, raw)
+			}
+		}
+		if len(params) != 2 || !haveUnit || !haveUTC {
+			return lt, fmt.Errorf("logical type annotation %q needs %s(unit,isAdjustedToUTC)", raw, name)
+		}
+	case "INTEGER":
+		if len(params) != 2 {
+			return lt, fmt.Errorf("logical type annotation %q needs INTEGER(bitWidth,signed)", raw)
+		}
+		bw, err := strconv.Atoi(params[0])
+		if err != nil || (bw != 8 && bw != 16 && bw != 32 && bw != 64) {
+			return lt, fmt.Errorf("invalid INTEGER bit width in %q (must be 8, 16, 32 or 64)", raw)
+		}
+		signed, err := strconv.ParseBool(params[1])
+		if err != nil {
+			return lt, fmt.Errorf("invalid INTEGER signedness in %q", raw)
+		}
+		lt.BitWidth, lt.Signed = bw, signed
+	default:
+		return lt, fmt.Errorf("unknown logical type annotation %q", raw)
+	}
+	return lt, nil
+}
+
+// checkPhysical reports whether the logical type can annotate col's physical
+// type, following the Parquet LogicalTypes specification.
+func (lt parquetLogicalType) checkPhysical(col *parquetColumn) error {
+	want := func(types ...string) error {
+		for _, t := range types {
+			if col.PhysType == t {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s cannot annotate %s (expected %s)", col.Annotation, col.typeLabel(), strings.Join(types, " or "))
+	}
+	wantFixed := func(n int) error {
+		if col.PhysType != "fixed_len_byte_array" || col.Length != n {
+			return fmt.Errorf("%s requires fixed_len_byte_array(%d), got %s", col.Annotation, n, col.typeLabel())
+		}
+		return nil
+	}
+
+	switch lt.Name {
+	case "LIST", "MAP", "MAP_KEY_VALUE":
+		return want("group")
+	case "UNKNOWN":
+		return nil
+	case "STRING", "ENUM", "JSON", "BSON":
+		return want("binary")
+	case "UUID":
+		return wantFixed(16)
+	case "FLOAT16":
+		return wantFixed(2)
+	case "INTERVAL":
+		return wantFixed(12)
+	case "DATE":
+		return want("int32")
+	case "TIME":
+		if lt.Unit == "MILLIS" {
+			return want("int32")
+		}
+		return want("int64")
+	case "TIMESTAMP":
+		return want("int64")
+	case "INTEGER":
+		if lt.BitWidth == 64 {
+			return want("int64")
+		}
+		return want("int32")
+	case "DECIMAL":
+		if err := want("int32", "int64", "binary", "fixed_len_byte_array"); err != nil {
+			return err
+		}
+		max := 0
+		switch col.PhysType {
+		case "int32":
+			max = 9
+		case "int64":
+			max = 18
+		case "fixed_len_byte_array":
+			max = int(math.Floor(math.Log10(2) * float64(8*col.Length-1)))
+		}
+		if max > 0 && lt.Precision > max {
+			return fmt.Errorf("DECIMAL precision %d exceeds the maximum of %d for %s", lt.Precision, max, col.typeLabel())
+		}
+	}
+	return nil
+}
+
+// checkParquetGroup validates a closed group: it must have fields, and LIST
+// and MAP groups must follow the three-level structure from the Parquet
+// LogicalTypes specification. Legacy element names are warnings.
+func checkParquetGroup(file string, g *parquetColumn, elem string) []Finding {
+	var findings []Finding
+	report := func(sev Severity, rule, path, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			File:     file,
+			Line:     g.Line,
+			RuleID:   rule,
+			Severity: sev,
+			Format:   FormatParquet,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(g.Children) == 0 {
+		report(SeverityError, "parquet-group-empty", elem, "group %q has no fields", g.Name)
+		return findings
+	}
+
+	switch g.Logical.Name {
+	case "LIST":
+		if g.Repetition == "repeated" {
+			report(SeverityError, "parquet-list-structure", elem, "LIST group %q must be required or optional, not repeated", g.Name)
+		}
+		if len(g.Children) != 1 {
+			report(SeverityError, "parquet-list-structure", elem, "LIST group %q must contain exactly one repeated field", g.Name)
+			return findings
+		}
+		mid := g.Children[0]
+		midPath := parquetFieldPath(elem, mid.Name)
+		if mid.Repetition != "repeated" {
+			report(SeverityError, "parquet-list-structure", midPath, "LIST group %q: field %q must be repeated", g.Name, mid.Name)
+			return findings
+		}
+		if !mid.isGroup() {
+			report(SeverityWarning, "parquet-list-structure", midPath,
+				"LIST group %q uses the legacy two-level layout; use 'repeated group list { <element> }'", g.Name)
+			return findings
+		}
+		if mid.Name != "list" {
+			report(SeverityWarning, "parquet-list-structure", midPath, "LIST group %q: repeated group should be named \"list\", not %q", g.Name, mid.Name)
+		}
+		if len(mid.Children) != 1 {
+			report(SeverityError, "parquet-list-structure", midPath, "LIST group %q: repeated group %q must contain exactly one element field", g.Name, mid.Name)
+			return findings
+		}
+		el := mid.Children[0]
+		if el.Repetition == "repeated" {
+			report(SeverityError, "parquet-list-structure", parquetFieldPath(midPath, el.Name),
+				"LIST group %q: element %q must be required or optional", g.Name, el.Name)
+		}
+		if el.Name != "element" {
+			report(SeverityWarning, "parquet-list-structure", parquetFieldPath(midPath, el.Name),
+				"LIST group %q: element field should be named \"element\", not %q", g.Name, el.Name)
+		}
+
+	case "MAP":
+		if g.Repetition == "repeated" {
+			report(SeverityError, "parquet-map-structure", elem, "MAP group %q must be required or optional, not repeated", g.Name)
+		}
+		if len(g.Children) != 1 {
+			report(SeverityError, "parquet-map-structure", elem, "MAP group %q must contain exactly one repeated key/value group", g.Name)
+			return findings
+		}
+		kv := g.Children[0]
+		kvPath := parquetFieldPath(elem, kv.Name)
+		if kv.Repetition != "repeated" || !kv.isGroup() {
+			report(SeverityError, "parquet-map-structure", kvPath, "MAP group %q: %q must be a repeated group", g.Name, kv.Name)
+			return findings
+		}
+		if kv.Name != "key_value" {
+			report(SeverityWarning, "parquet-map-structure", kvPath, "MAP group %q: repeated group should be named \"key_value\", not %q", g.Name, kv.Name)
+		}
+		if len(kv.Children) < 1 || len(kv.Children) > 2 {
+			report(SeverityError, "parquet-map-structure", kvPath, "MAP group %q: %q must contain a key field and at most one value field", g.Name, kv.Name)
+			return findings
+		}
+		key := kv.Children[0]
+		if key.Name != "key" {
+			report(SeverityWarning, "parquet-map-structure", parquetFieldPath(kvPath, key.Name),
+				"MAP group %q: key field should be named \"key\", not %q", g.Name, key.Name)
+		}
+		if key.Repetition != "required" {
+			report(SeverityError, "parquet-map-structure", parquetFieldPath(kvPath, key.Name),
+				"MAP group %q: key field %q must be required", g.Name, key.Name)
+		}
+		if len(kv.Children) == 2 {
+			val := kv.Children[1]
+			if val.Name != "value" {
+				report(SeverityWarning, "parquet-map-structure", parquetFieldPath(kvPath, val.Name),
+					"MAP group %q: value field should be named \"value\", not %q", g.Name, val.Name)
+			}
+			if val.Repetition == "repeated" {
+				report(SeverityError, "parquet-map-structure", parquetFieldPath(kvPath, val.Name),
+					"MAP group %q: value field %q must be required or optional", g.Name, val.Name)
+			}
+		}
+	}
+	return findings
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -213,3 +214,190 @@ func TestParquetValidator_SetAdditiveNullableOnlyPolicy(t *testing.T) {
 		t.Error("SetAdditiveNullableOnlyPolicy(true) failed")
 	}
 }
+
+func TestParquetValidator_LintFindings(t *testing.T) {
+	v := NewParquetValidator(&ToolchainResolver{})
+
+	findings, err := v.LintFindings("testdata/parquet/duplicate_columns.parquet")
+	if err != nil {
+		t.Fatalf("LintFindings: %v", err)
+	}
+	var dup *Finding
+	for i := range findings {
+		if findings[i].RuleID == "parquet-column-duplicate" {
+			dup = &findings[i]
+		}
+	}
+	if dup == nil {
+		t.Fatalf("expected parquet-column-duplicate finding, got %+v", findings)
+	}
+	if dup.Line == 0 || dup.Severity != SeverityError || dup.Format != FormatParquet {
+		t.Errorf("unexpected duplicate finding: %+v", *dup)
+	}
+}
+
+func TestParquetValidator_Lint_NestedStructures(t *testing.T) {
+	v := NewParquetValidator(&ToolchainResolver{})
+
+	tests := []struct {
+		name     string
+		path     string
+		wantRule string // "" means lint passes
+	}{
+		{name: "LIST, MAP, DECIMAL and TIMESTAMP", path: "testdata/parquet/nested_v1.parquet"},
+		{name: "LIST middle level not repeated", path: "testdata/parquet/bad_list.parquet", wantRule: "parquet-list-structure"},
+		{name: "MAP key optional", path: "testdata/parquet/bad_map.parquet", wantRule: "parquet-map-structure"},
+		{name: "DECIMAL precision too large for int32", path: "testdata/parquet/bad_decimal.parquet", wantRule: "parquet-logical-type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := v.LintFindings(tt.path)
+			if err != nil {
+				t.Fatalf("LintFindings: %v", err)
+			}
+			if tt.wantRule == "" {
+				if HasErrors(findings) {
+					t.Fatalf("expected no errors, got %+v", findings)
+				}
+				return
+			}
+			found := false
+			for _, f := range findings {
+				if f.RuleID == tt.wantRule && f.Severity == SeverityError {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected %s error, got %+v", tt.wantRule, findings)
+			}
+		})
+	}
+}
+
+func TestParseParquetLogicalType(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    parquetLogicalType
+		wantErr bool
+	}{
+		{raw: "STRING", want: parquetLogicalType{Name: "STRING"}},
+		{raw: "UTF8", want: parquetLogicalType{Name: "STRING"}},
+		{raw: "DECIMAL(10,2)", want: parquetLogicalType{Name: "DECIMAL", Precision: 10, Scale: 2}},
+		{raw: "DECIMAL(5)", want: parquetLogicalType{Name: "DECIMAL", Precision: 5}},
+		{raw: "TIMESTAMP(MICROS,false)", want: parquetLogicalType{Name: "TIMESTAMP", Unit: "MICROS"}},
+		{raw: "TIMESTAMP(true, NANOS)", want: parquetLogicalType{Name: "TIMESTAMP", Unit: "NANOS", UTC: true}},
+		{raw: "TIMESTAMP_MILLIS", want: parquetLogicalType{Name: "TIMESTAMP", Unit: "MILLIS", UTC: true}},
+		{raw: "INTEGER(16,false)", want: parquetLogicalType{Name: "INTEGER", BitWidth: 16}},
+		{raw: "DECIMAL(2,5)", wantErr: true},
+		{raw: "DECIMAL", wantErr: true},
+		{raw: "TIMESTAMP(SECONDS,true)", wantErr: true},
+		{raw: "INTEGER(12,true)", wantErr: true},
+		{raw: "STRING(1)", wantErr: true},
+		{raw: "BOGUS", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseParquetLogicalType(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseParquetLogicalType(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseParquetLogicalType(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParquetValidator_BreakingFindings_Nested(t *testing.T) {
+	v := NewParquetValidator(&ToolchainResolver{})
+
+	findings, err := v.BreakingFindings("testdata/parquet/nested_v2_compatible.parquet", "testdata/parquet/nested_v1.parquet")
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected compatible nested change, got %+v", findings)
+	}
+
+	findings, err = v.BreakingFindings("testdata/parquet/nested_v2_breaking.parquet", "testdata/parquet/nested_v1.parquet")
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, f := range findings {
+		got[f.Path+" "+f.RuleID] = true
+	}
+	want := map[string]string{
+		"address.geo.lat":   "parquet-column-type-changed",
+		"address.geo.lon":   "parquet-column-removed",
+		"tags.list.element": "parquet-column-type-changed",
+		"balance":           "parquet-decimal-scale-changed",
+	}
+	for path, rule := range want {
+		if !got[path+" "+rule] {
+			t.Errorf("missing %s finding for %s (all findings: %+v)", rule, path, findings)
+		}
+	}
+}
+
+func TestParquetValidator_BreakingFindings_Widening(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "v1.parquet")
+	newFile := filepath.Join(dir, "v2.parquet")
+	mustWrite(t, oldFile, `message events {
+  required int32 id;
+  optional float score;
+  optional int32 count (INTEGER(16,true));
+  optional int32 flags (INTEGER(8,false));
+  optional int64 total;
+}
+`)
+	mustWrite(t, newFile, `message events {
+  required int64 id;
+  optional double score;
+  optional int32 count (INTEGER(32,true));
+  optional int32 flags (INTEGER(16,true));
+  optional int32 total;
+}
+`)
+	v := NewParquetValidator(&ToolchainResolver{})
+
+	// Under the default policy every type change is breaking.
+	findings, err := v.BreakingFindings(newFile, oldFile)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	if len(findings) != 5 || !HasErrors(findings) {
+		t.Errorf("expected 5 breaking changes, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Severity != SeverityError {
+			t.Errorf("expected %s to be breaking, got %s", f.Path, f.Severity)
+		}
+	}
+
+	// With the policy off, widening is a warning; narrowing and changing
+	// signedness are still breaking.
+	v.SetAdditiveNullableOnlyPolicy(false)
+	findings, err = v.BreakingFindings(newFile, oldFile)
+	if err != nil {
+		t.Fatalf("BreakingFindings: %v", err)
+	}
+	got := make(map[string]string)
+	for _, f := range findings {
+		got[f.Path] = f.RuleID + " " + string(f.Severity)
+	}
+	want := map[string]string{
+		"id":    "parquet-column-type-widened warning",
+		"score": "parquet-column-type-widened warning",
+		"count": "parquet-column-type-widened warning",
+		"flags": "parquet-column-annotation-changed error",
+		"total": "parquet-column-type-changed error",
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s: got %q, want %q (all findings: %+v)", path, got[path], w, findings)
+		}
+	}
+}
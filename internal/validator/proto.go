@@ -2,6 +2,8 @@ package validator
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -24,42 +26,50 @@ func NewProtoValidator(resolver *ToolchainResolver) *ProtoValidator {
 
 // Lint runs buf lint on proto files
 func (v *ProtoValidator) Lint(path string) error {
-	bufPath, err := v.resolver.ResolveTool("buf", "v1.66.1")
+	findings, err := v.LintFindings(path)
+	return findingsError("buf lint failed", findings, err)
+}
+
+// LintFindings runs buf lint and returns its violations as findings, parsed
+// from buf's JSON error format.
+func (v *ProtoValidator) LintFindings(path string) ([]Finding, error) {
+	bufPath, err := v.resolver.ResolveTool("buf", v.resolver.ToolVersion("buf"))
 	if err != nil {
-		return fmt.Errorf("failed to resolve buf: %w", err)
+		return nil, fmt.Errorf("failed to resolve buf: %w", err)
 	}
 
 	// Convert to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	dir, targetArgs, err := bufTargetArgs(absPath)
 	if err != nil {
-		return err
-	}
-	cmd := exec.Command(bufPath, append([]string{"lint"}, targetArgs...)...)
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("buf lint failed: %w\nOutput: %s", err, string(output))
+		return nil, err
 	}
-
-	return nil
+	cmd := v.resolver.Command(dir, bufPath, append([]string{"lint", "--error-format=json"}, targetArgs...)...)
+	return runBuf(cmd, "lint")
 }
 
 // Breaking runs buf breaking change detection
 func (v *ProtoValidator) Breaking(path, against string) error {
-	bufPath, err := v.resolver.ResolveTool("buf", "v1.66.1")
+	findings, err := v.BreakingFindings(path, against)
+	return findingsError("buf breaking failed", findings, err)
+}
+
+// BreakingFindings runs buf breaking and returns each breaking change as a
+// finding, parsed from buf's JSON error format.
+func (v *ProtoValidator) BreakingFindings(path, against string) ([]Finding, error) {
+	bufPath, err := v.resolver.ResolveTool("buf", v.resolver.ToolVersion("buf"))
 	if err != nil {
-		return fmt.Errorf("failed to resolve buf: %w", err)
+		return nil, fmt.Errorf("failed to resolve buf: %w", err)
 	}
 
 	// Convert to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	// Convert git refs (HEAD~1, origin/main, release tags) to buf's
@@ -80,17 +90,71 @@ func (v *ProtoValidator) Breaking(path, against string) error {
 
 	dir, targetArgs, err := bufTargetArgs(absPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	args := append([]string{"breaking", "--against", againstArg}, targetArgs...)
-	cmd := exec.Command(bufPath, args...)
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("buf breaking failed: %w\nOutput: %s", err, string(output))
+	args := append([]string{"breaking", "--error-format=json", "--against", againstArg}, targetArgs...)
+	cmd := v.resolver.Command(dir, bufPath, args...)
+	return runBuf(cmd, "breaking")
+}
+
+// bufAnnotation is one line of buf's --error-format=json output.
+type bufAnnotation struct {
+	Path        string `json:"path"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+}
+
+// runBuf runs a buf lint/breaking command and converts its JSON annotations
+// into findings. buf exits non-zero both when it reports violations and when
+// it cannot run at all; the two are told apart by whether the output parses
+// into at least one annotation.
+func runBuf(cmd *exec.Cmd, sub string) ([]Finding, error) {
+	stdout, stderr, runErr := runCapture(cmd)
+	findings, parseErr := parseBufFindings(stdout, cmd.Dir)
+	if parseErr == nil && (runErr == nil || len(findings) > 0) {
+		return findings, nil
+	}
+	if runErr == nil {
+		runErr = parseErr
 	}
+	return nil, fmt.Errorf("buf %s failed: %w\nOutput: %s%s", sub, runErr, stdout, stderr)
+}
 
-	return nil
+// parseBufFindings parses buf's newline-delimited JSON annotations. File
+// paths are reported relative to the buf root dir and are joined onto it.
+func parseBufFindings(out []byte, dir string) ([]Finding, error) {
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var a bufAnnotation
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			return nil, fmt.Errorf("parsing buf output: %w", err)
+		}
+		file := a.Path
+		if file != "" && dir != "" && !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		findings = append(findings, Finding{
+			File:     file,
+			Line:     a.StartLine,
+			Column:   a.StartColumn,
+			RuleID:   a.Type,
+			Severity: SeverityError,
+			Format:   FormatProto,
+			Message:  a.Message,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading buf output: %w", err)
+	}
+	return findings, nil
 }
 
 // bufTargetArgs computes the buf working directory and input/selector args for
@@ -251,6 +315,25 @@ func ExtractGoPackage(protoPath string) (importPath string, alias string, err er
 	return "", "", nil
 }
 
+// ProtoImports returns the files a .proto file imports, such as
+// "google/api/annotations.proto", in the order they are imported.
+func ProtoImports(data []byte) []string {
+	toks := protoTokens(data)
+	var imports []string
+	for i := 0; i < len(toks); {
+		if t := toks[i].text; t == "}" || t == ";" {
+			i++
+			continue
+		}
+		stmt, _, next := protoStatement(toks, i)
+		i = next
+		if len(stmt) >= 2 && stmt[0].text == "import" {
+			imports = append(imports, protoUnquote(stmt[len(stmt)-1].text))
+		}
+	}
+	return imports
+}
+
 // GlobProtoFiles returns all .proto files under the given directory.
 func GlobProtoFiles(dir string) ([]string, error) {
 	var files []string
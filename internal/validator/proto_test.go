@@ -203,6 +203,24 @@ func TestExtractGoPackage_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestProtoImports(t *testing.T) {
+	src := `syntax = "proto3";
+// import "commented/out.proto";
+import "google/api/annotations.proto";
+import public "proto/users/profile/v1/profile.proto";
+import weak 'legacy.proto';
+
+message Entry {
+  string import = 1;
+  option (x) = { import: "not/an/import.proto" };
+}
+`
+	want := []string{"google/api/annotations.proto", "proto/users/profile/v1/profile.proto", "legacy.proto"}
+	if got := ProtoImports([]byte(src)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProtoImports = %v, want %v", got, want)
+	}
+}
+
 func TestGlobProtoFiles(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -402,3 +420,27 @@ func TestBufTargetArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBufFindings(t *testing.T) {
+	out := []byte(`{"path":"acme/v1/a.proto","start_line":4,"start_column":1,"type":"PACKAGE_VERSION_SUFFIX","message":"Package name should be suffixed."}
+{"path":"acme/v1/b.proto","start_line":9,"start_column":3,"type":"FIELD_NO_DELETE","message":"Previously present field \"2\" was deleted."}
+`)
+	findings, err := parseBufFindings(out, "/work")
+	if err != nil {
+		t.Fatalf("parseBufFindings: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+	f := findings[1]
+	if f.File != filepath.Join("/work", "acme/v1/b.proto") || f.Line != 9 || f.Column != 3 {
+		t.Errorf("unexpected location: %+v", f)
+	}
+	if f.RuleID != "FIELD_NO_DELETE" || f.Severity != SeverityError || f.Format != FormatProto {
+		t.Errorf("unexpected classification: %+v", f)
+	}
+
+	if _, err := parseBufFindings([]byte("Failure: no buf.yaml"), ""); err == nil {
+		t.Error("expected error for non-JSON output")
+	}
+}
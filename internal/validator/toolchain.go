@@ -2,27 +2,61 @@ package validator
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/infobloxopen/apx/internal/config"
 	"gopkg.in/yaml.v3"
 )
 
+// defaultToolVersions are the versions run when neither apx.yaml nor
+// apx.lock names one. The client generator versions match the ones
+// internal/client pins.
+var defaultToolVersions = map[string]string{
+	"buf":            "v1.66.1",
+	"spectral":       "v6.15.0",
+	"oasdiff":        "v1.9.6",
+	"helm":           "v3.17.3",
+	"oapi-codegen":   "v2.4.1",
+	"ng-openapi-gen": "1.0.5",
+}
+
+// toolsBundleEnv names an offline toolchain bundle, written by
+// 'apx fetch --bundle', to resolve tools from.
+const toolsBundleEnv = "APX_TOOLS_BUNDLE"
+
+// ErrToolNotFound is returned when a tool is in neither the offline bundle,
+// the local cache nor PATH, and cannot be downloaded.
+var ErrToolNotFound = errors.New("tool not found")
+
 // ToolchainResolver finds and validates external tools
 type ToolchainResolver struct {
 	bundlePath         string
 	offlineMode        bool
 	checksumValidation bool
+	executor           Executor
+	versions           map[string]string
+	lock               map[string]config.ToolchainLock
+	bundle             *BundleManifest
+
+	// mu serializes resolution, so concurrent callers never download or
+	// extract the same tool at once.
+	mu sync.Mutex
 }
 
 // ToolchainResolverOption configures the resolver
 type ToolchainResolverOption func(*ToolchainResolver)
 
-// WithBundlePath sets the offline bundle directory
+// WithBundlePath sets the offline bundle: a .tar.gz written by
+// 'apx fetch --bundle', or a directory of binaries
 func WithBundlePath(path string) ToolchainResolverOption {
 	return func(r *ToolchainResolver) {
 		r.bundlePath = path
@@ -43,12 +77,33 @@ func WithChecksumValidation(validate bool) ToolchainResolverOption {
 	}
 }
 
+// WithToolVersions sets the tool versions apx.yaml asks for
+func WithToolVersions(versions map[string]string) ToolchainResolverOption {
+	return func(r *ToolchainResolver) {
+		r.versions = versions
+	}
+}
+
+// WithLock sets the toolchain versions and checksums pinned in apx.lock
+func WithLock(toolchains map[string]config.ToolchainLock) ToolchainResolverOption {
+	return func(r *ToolchainResolver) {
+		r.lock = toolchains
+	}
+}
+
+// WithExecutor sets where resolved tools run
+func WithExecutor(e Executor) ToolchainResolverOption {
+	return func(r *ToolchainResolver) {
+		r.executor = e
+	}
+}
+
 // NewToolchainResolver creates a new toolchain resolver
 func NewToolchainResolver(opts ...ToolchainResolverOption) *ToolchainResolver {
 	r := &ToolchainResolver{
-		bundlePath:         "",
+		bundlePath:         os.Getenv(toolsBundleEnv),
 		offlineMode:        false,
-		checksumValidation: false,
+		checksumValidation: true,
 	}
 
 	for _, opt := range opts {
@@ -58,32 +113,142 @@ func NewToolchainResolver(opts ...ToolchainResolverOption) *ToolchainResolver {
 	return r
 }
 
+// ToolVersion returns the version of a tool to run: the one apx.yaml asks
+// for, else the one apx.lock pins, else APX's default.
+func (r *ToolchainResolver) ToolVersion(name string) string {
+	if v := r.versions[name]; v != "" {
+		return v
+	}
+	if v := r.lock[name].Version; v != "" {
+		return v
+	}
+	return defaultToolVersions[name]
+}
+
+// Tools returns the sorted names of the tools the resolver knows a version
+// for: APX's defaults and those named in apx.yaml or apx.lock.
+func (r *ToolchainResolver) Tools() []string {
+	seen := map[string]bool{}
+	for name := range defaultToolVersions {
+		seen[name] = true
+	}
+	for name := range r.versions {
+		seen[name] = true
+	}
+	for name := range r.lock {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ResolveTool finds the path to a tool binary.
 // Resolution order: offline bundle → local cache → PATH → auto-download.
+// When apx.lock pins a checksum for the tool on this platform, the binary
+// must match it, and PATH is skipped because a binary found there cannot be
+// tied to the pin. A tool from a bundle archive is verified against the
+// bundle's manifest instead.
+// In container mode the image provides the tools, so the bare name is
+// returned.
 func (r *ToolchainResolver) ResolveTool(name, version string) (string, error) {
+	return r.resolve(name, version, true)
+}
+
+// FetchTool is ResolveTool without the PATH lookup: the tool comes from the
+// offline bundle or the local cache, or is downloaded into the cache.
+func (r *ToolchainResolver) FetchTool(name, version string) (string, error) {
+	return r.resolve(name, version, false)
+}
+
+func (r *ToolchainResolver) resolve(name, version string, usePath bool) (string, error) {
+	if _, ok := r.executor.(*ContainerExecutor); ok {
+		return name, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	checksum, err := r.pinnedChecksum(name, version)
+	if err != nil {
+		return "", err
+	}
+	if checksum != "" {
+		usePath = false
+	}
+
+	path, source, err := r.locate(name, version, usePath)
+	if err != nil {
+		return "", err
+	}
+	// A tool from a bundle archive was verified against the bundle's own
+	// manifest, which is authoritative for it.
+	if checksum != "" && source != fromBundle {
+		if err := verifyChecksum(name, version, path, checksum); err != nil {
+			if source == fromDownload {
+				// Never leave an unverified download in the cache.
+				_ = os.Remove(path)
+			}
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// pinnedChecksum returns the checksum apx.lock pins for a tool on the host
+// platform, or "" when it pins none for this platform or checksum
+// validation is off. A lock entry for another version is stale and is an
+// error.
+func (r *ToolchainResolver) pinnedChecksum(name, version string) (string, error) {
+	pin, ok := r.lock[name]
+	if !ok || !r.checksumValidation {
+		return "", nil
+	}
+	if pin.Version != "" && pin.Version != version {
+		return "", fmt.Errorf("apx.lock pins %s %s but %s is requested (run 'apx fetch --update-lock' to refresh the lock)", name, pin.Version, version)
+	}
+	return pin.Checksums[HostPlatform()], nil
+}
+
+// toolSource says where locate found a tool binary.
+type toolSource int
+
+const (
+	fromLocal    toolSource = iota // a bundle directory, the cache or PATH
+	fromBundle                     // a bundle archive, verified against its manifest
+	fromDownload                   // just downloaded into the cache
+)
+
+// locate finds a tool binary and reports where it came from.
+func (r *ToolchainResolver) locate(name, version string, usePath bool) (string, toolSource, error) {
 	// Try offline bundle first if configured
 	if r.bundlePath != "" {
-		bundlePath := filepath.Join(r.bundlePath, name)
-		if _, err := os.Stat(bundlePath); err == nil {
-			return bundlePath, nil
+		if info, err := os.Stat(r.bundlePath); err == nil && !info.IsDir() {
+			path, err := r.bundledTool(name, version)
+			if err != nil || path != "" {
+				return path, fromBundle, err
+			}
+		} else {
+			bundlePath := filepath.Join(r.bundlePath, name)
+			if _, err := os.Stat(bundlePath); err == nil {
+				return bundlePath, fromLocal, nil
+			}
 		}
 	}
 
 	// Check the local tool cache (~/.apx/tools/<name>/<version>/)
-	binName := name
-	if runtime.GOOS == "windows" {
-		binName += ".exe"
-	}
-	cached := filepath.Join(cacheDir(name, version), binName)
+	cached := binaryPath(name, cacheDir(name, version), runtime.GOOS)
 	if _, err := os.Stat(cached); err == nil {
-		return cached, nil
+		return cached, fromLocal, nil
 	}
 
 	// Fall back to PATH lookup
-	if !r.offlineMode {
+	if usePath && !r.offlineMode {
 		path, err := exec.LookPath(name)
 		if err == nil {
-			return path, nil
+			return path, fromLocal, nil
 		}
 	}
 
@@ -91,11 +256,33 @@ func (r *ToolchainResolver) ResolveTool(name, version string) (string, error) {
 	if !r.offlineMode {
 		path, err := downloadTool(name, version)
 		if err == nil {
-			return path, nil
+			return path, fromDownload, nil
 		}
 	}
 
-	return "", fmt.Errorf("tool not found: %s (version %s)", name, version)
+	return "", fromLocal, fmt.Errorf("%w: %s (version %s)", ErrToolNotFound, name, version)
+}
+
+// verifyChecksum checks a tool binary against the checksum apx.lock pins,
+// written as "sha256:<hex>" or as bare hex.
+func verifyChecksum(name, version, path, want string) error {
+	got, err := ComputeChecksum(path)
+	if err != nil {
+		return fmt.Errorf("verifying %s %s: %w", name, version, err)
+	}
+	if !strings.EqualFold(strings.TrimPrefix(want, "sha256:"), got) {
+		return fmt.Errorf("checksum mismatch for %s %s at %s: apx.lock pins %s for %s, the binary is sha256:%s", name, version, path, want, HostPlatform(), got)
+	}
+	return nil
+}
+
+// Command returns the command that runs a resolved tool in dir, on the
+// host or in the configured container.
+func (r *ToolchainResolver) Command(dir, tool string, args ...string) *exec.Cmd {
+	if r.executor == nil {
+		return LocalExecutor{}.Command(dir, tool, args...)
+	}
+	return r.executor.Command(dir, tool, args...)
 }
 
 // ToolRef represents a tool reference with version and checksum
@@ -1,11 +1,13 @@
 package validator
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/infobloxopen/apx/internal/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,3 +100,123 @@ tools:
 		require.Contains(t, err.Error(), "version mismatch")
 	})
 }
+
+// cacheTool writes a fake tool binary into the local tool cache under home.
+func cacheTool(t *testing.T, home, name, version, content string) string {
+	t.Helper()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	binName := name
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	path := filepath.Join(cacheDir(name, version), binName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+	return path
+}
+
+// pinFor returns apx.lock checksums pinning sum for a platform.
+func pinFor(platform, sum string) map[string]string {
+	return map[string]string{platform: sum}
+}
+
+func TestToolchainResolver_ToolVersion(t *testing.T) {
+	r := NewToolchainResolver(
+		WithToolVersions(map[string]string{"buf": "v1.50.0"}),
+		WithLock(map[string]config.ToolchainLock{
+			"buf":     {Version: "v1.49.0"},
+			"oasdiff": {Version: "v1.9.0"},
+		}),
+	)
+	require.Equal(t, "v1.50.0", r.ToolVersion("buf"), "apx.yaml wins over apx.lock")
+	require.Equal(t, "v1.9.0", r.ToolVersion("oasdiff"), "apx.lock wins over the default")
+	require.Equal(t, "v6.15.0", r.ToolVersion("spectral"), "default")
+	require.Equal(t, []string{"buf", "helm", "ng-openapi-gen", "oapi-codegen", "oasdiff", "spectral"}, r.Tools())
+
+	require.Equal(t, "v1.66.1", (&ToolchainResolver{}).ToolVersion("buf"))
+}
+
+func TestToolchainResolver_Checksums(t *testing.T) {
+	path := cacheTool(t, t.TempDir(), "oasdiff", "v9.9.9", "#!/bin/sh\necho oasdiff\n")
+	sum, err := ComputeChecksum(path)
+	require.NoError(t, err)
+
+	t.Run("verifies cache hits", func(t *testing.T) {
+		for _, pinned := range []string{"sha256:" + sum, sum} {
+			r := NewToolchainResolver(WithLock(map[string]config.ToolchainLock{
+				"oasdiff": {Version: "v9.9.9", Checksums: pinFor(HostPlatform(), pinned)},
+			}))
+			got, err := r.ResolveTool("oasdiff", "v9.9.9")
+			require.NoError(t, err)
+			require.Equal(t, path, got)
+		}
+	})
+
+	t.Run("rejects a mismatch", func(t *testing.T) {
+		r := NewToolchainResolver(WithLock(map[string]config.ToolchainLock{
+			"oasdiff": {Version: "v9.9.9", Checksums: pinFor(HostPlatform(), "sha256:0000")},
+		}))
+		_, err := r.ResolveTool("oasdiff", "v9.9.9")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch for oasdiff v9.9.9")
+		require.False(t, errors.Is(err, ErrToolNotFound))
+		_, statErr := os.Stat(path)
+		require.NoError(t, statErr, "a cached binary is reported, not deleted")
+
+		// Checksum validation can be turned off.
+		r = NewToolchainResolver(WithChecksumValidation(false), WithLock(map[string]config.ToolchainLock{
+			"oasdiff": {Version: "v9.9.9", Checksums: pinFor(HostPlatform(), "sha256:0000")},
+		}))
+		_, err = r.ResolveTool("oasdiff", "v9.9.9")
+		require.NoError(t, err)
+	})
+
+	t.Run("verifies the offline bundle", func(t *testing.T) {
+		bundle := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bundle, "oasdiff"), []byte("tampered"), 0755))
+		r := NewToolchainResolver(WithBundlePath(bundle), WithLock(map[string]config.ToolchainLock{
+			"oasdiff": {Version: "v9.9.9", Checksums: pinFor(HostPlatform(), "sha256:"+sum)},
+		}))
+		_, err := r.ResolveTool("oasdiff", "v9.9.9")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch")
+	})
+
+	t.Run("rejects a stale lock", func(t *testing.T) {
+		r := NewToolchainResolver(WithLock(map[string]config.ToolchainLock{
+			"oasdiff": {Version: "v9.9.8", Checksums: pinFor(HostPlatform(), "sha256:"+sum)},
+		}))
+		_, err := r.ResolveTool("oasdiff", "v9.9.9")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "apx.lock pins oasdiff v9.9.8 but v9.9.9 is requested")
+	})
+
+	t.Run("ignores checksums for other platforms", func(t *testing.T) {
+		r := NewToolchainResolver(WithLock(map[string]config.ToolchainLock{
+			"oasdiff": {Version: "v9.9.9", Checksums: pinFor("plan9/mips", "sha256:0000")},
+		}))
+		got, err := r.ResolveTool("oasdiff", "v9.9.9")
+		require.NoError(t, err)
+		require.Equal(t, path, got)
+
+		// Without a pin for this platform, PATH is still looked up.
+		if runtime.GOOS == "windows" {
+			return
+		}
+		bin := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "helm"), []byte("#!/bin/sh\n"), 0755))
+		t.Setenv("PATH", bin)
+		r = NewToolchainResolver(WithLock(map[string]config.ToolchainLock{
+			"helm": {Version: "v9.9.9", Checksums: pinFor("plan9/mips", "sha256:0000")},
+		}))
+		got, err = r.ResolveTool("helm", "v9.9.9")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(bin, "helm"), got)
+	})
+
+	t.Run("a missing tool is ErrToolNotFound", func(t *testing.T) {
+		_, err := NewToolchainResolver(WithOfflineMode(true)).ResolveTool("buf", "v0.0.1")
+		require.True(t, errors.Is(err, ErrToolNotFound))
+	})
+}
@@ -34,6 +34,8 @@ type Validator struct {
 	jsonValidator    *JSONSchemaValidator
 	parquetValidator *ParquetValidator
 	crdValidator     *CRDValidator
+	waivers          *waiverSet
+	lintRules        map[SchemaFormat]*lintRuleSet
 }
 
 // NewValidator creates a new validator with the specified toolchain resolver
@@ -173,51 +175,116 @@ func DetectFormatFromModuleRoots(roots []string) SchemaFormat {
 	return detected
 }
 
-// Lint validates a schema file for syntax and style issues
+// lintSummaries name the error Lint returns for each format's findings.
+var lintSummaries = map[SchemaFormat]string{
+	FormatProto:      "buf lint failed",
+	FormatOpenAPI:    "spectral lint failed",
+	FormatAvro:       "avro lint errors",
+	FormatJSONSchema: "JSON Schema lint errors",
+	FormatParquet:    "parquet lint errors",
+	FormatCRD:        "CRD lint errors",
+}
+
+// Lint validates a schema file for syntax and style issues. It fails when
+// LintFindings reports an error, so both follow the same rule
+// configuration and inline suppressions.
 func (v *Validator) Lint(path string, format SchemaFormat) error {
 	if format == FormatUnknown {
 		format = DetectFormat(path)
 	}
+	findings, err := v.LintFindings(path, format)
+	return findingsError(lintSummaries[format], findings, err)
+}
+
+// breakingSummaries name the error Breaking returns for each format's
+// findings.
+var breakingSummaries = map[SchemaFormat]string{
+	FormatProto:      "buf breaking failed",
+	FormatOpenAPI:    "oasdiff breaking failed",
+	FormatAvro:       "avro compatibility violations",
+	FormatJSONSchema: "breaking changes detected",
+	FormatParquet:    "parquet schema breaking changes",
+	FormatCRD:        "breaking changes detected (served-version incompatibility)",
+}
+
+// Breaking checks for breaking changes between two schema versions. It
+// fails when BreakingFindings reports an error, so waivers apply to both.
+func (v *Validator) Breaking(path, against string, format SchemaFormat) error {
+	if format == FormatUnknown {
+		format = DetectFormat(path)
+	}
+	findings, err := v.BreakingFindings(path, against, format)
+	return findingsError(breakingSummaries[format], findings, err)
+}
+
+// LintFindings validates a schema file and returns every violation as a
+// Finding. The error is non-nil only when the check could not run (unknown
+// format, unreadable input, missing external tool).
+//
+// The native Avro, Parquet, JSON Schema and CRD rules follow the
+// configuration set by SetLintRules, and findings suppressed by an
+// apx:ignore comment are dropped.
+func (v *Validator) LintFindings(path string, format SchemaFormat) ([]Finding, error) {
+	if format == FormatUnknown {
+		format = DetectFormat(path)
+	}
 
+	var findings []Finding
+	var err error
 	switch format {
 	case FormatProto:
-		return v.protoValidator.Lint(path)
+		return v.protoValidator.LintFindings(path)
 	case FormatOpenAPI:
-		return v.oasValidator.Lint(path)
+		return v.oasValidator.LintFindings(path)
 	case FormatAvro:
-		return v.avroValidator.Lint(path)
+		findings, err = v.avroValidator.LintFindings(path)
 	case FormatJSONSchema:
-		return v.jsonValidator.Lint(path)
+		findings, err = v.jsonValidator.LintFindings(path)
 	case FormatParquet:
-		return v.parquetValidator.Lint(path)
+		findings, err = v.parquetValidator.LintFindings(path)
 	case FormatCRD:
-		return v.crdValidator.Lint(path)
+		findings, err = v.crdValidator.LintFindings(path)
 	default:
-		return fmt.Errorf("unsupported schema format for file: %s", path)
+		return nil, fmt.Errorf("unsupported schema format for file: %s", path)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return suppressInline(v.lintRules[format].apply(findings)), nil
 }
 
-// Breaking checks for breaking changes between two schema versions
-func (v *Validator) Breaking(path, against string, format SchemaFormat) error {
+// BreakingFindings checks for breaking changes between two schema versions
+// and returns each one as a Finding, with any waivers set by SetWaivers
+// applied. The error is non-nil only when the comparison could not run.
+func (v *Validator) BreakingFindings(path, against string, format SchemaFormat) ([]Finding, error) {
+	findings, err := v.breakingFindings(path, against, format)
+	if err != nil {
+		return nil, err
+	}
+	return v.applyWaivers(findings), nil
+}
+
+// breakingFindings runs the format's breaking check.
+func (v *Validator) breakingFindings(path, against string, format SchemaFormat) ([]Finding, error) {
 	if format == FormatUnknown {
 		format = DetectFormat(path)
 	}
 
 	switch format {
 	case FormatProto:
-		return v.protoValidator.Breaking(path, against)
+		return v.protoValidator.BreakingFindings(path, against)
 	case FormatOpenAPI:
-		return v.oasValidator.Breaking(path, against)
+		return v.oasValidator.BreakingFindings(path, against)
 	case FormatAvro:
-		return v.avroValidator.Breaking(path, against)
+		return v.avroValidator.BreakingFindings(path, against)
 	case FormatJSONSchema:
-		return v.jsonValidator.Breaking(path, against)
+		return v.jsonValidator.BreakingFindings(path, against)
 	case FormatParquet:
-		return v.parquetValidator.Breaking(path, against)
+		return v.parquetValidator.BreakingFindings(path, against)
 	case FormatCRD:
-		return v.crdValidator.Breaking(path, against)
+		return v.crdValidator.BreakingFindings(path, against)
 	default:
-		return fmt.Errorf("unsupported schema format for file: %s", path)
+		return nil, fmt.Errorf("unsupported schema format for file: %s", path)
 	}
 }
 
@@ -226,6 +293,24 @@ func (v *Validator) SetAvroCompatibilityMode(mode string) {
 	v.avroValidator.SetCompatibilityMode(mode)
 }
 
+// SetAvroHistory sets the release history used by the Avro *_TRANSITIVE
+// compatibility modes.
+func (v *Validator) SetAvroHistory(h SchemaHistory) {
+	v.avroValidator.SetHistory(h)
+}
+
+// SetJSONSchemaBreakingMode sets the JSON Schema breaking-change mode
+// ("strict" or "lenient").
+func (v *Validator) SetJSONSchemaBreakingMode(mode string) {
+	v.jsonValidator.SetBreakingMode(mode)
+}
+
+// SetOpenAPIEngine selects the OpenAPI lint/breaking engine ("auto",
+// "native" or "external").
+func (v *Validator) SetOpenAPIEngine(engine string) {
+	v.oasValidator.SetEngine(engine)
+}
+
 // SetParquetAdditiveNullableOnly sets the Parquet schema evolution policy
 func (v *Validator) SetParquetAdditiveNullableOnly(allow bool) {
 	v.parquetValidator.SetAdditiveNullableOnlyPolicy(allow)
@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+// RuleWaiverExpired is the rule of the error reported for an expired waiver.
+const RuleWaiverExpired = "waiver-expired"
+
+// waiverSet holds the breaking-change waivers of one API.
+type waiverSet struct {
+	waivers []config.Waiver
+	source  string // the waivers file, for findings about the waivers themselves
+	apiID   string
+	now     func() time.Time
+	applied map[int]bool
+}
+
+// SetWaivers makes BreakingFindings and ClassifyChanges honor the waivers
+// of apiID listed in source: a breaking finding matched by an unexpired
+// waiver is downgraded to a warning, and every expired waiver of the API is
+// reported as an error.
+func (v *Validator) SetWaivers(waivers []config.Waiver, source, apiID string) {
+	v.waivers = &waiverSet{waivers: waivers, source: source, apiID: apiID, now: time.Now, applied: map[int]bool{}}
+}
+
+// AppliedWaivers returns the waivers that have downgraded a finding, in
+// file order.
+func (v *Validator) AppliedWaivers() []config.Waiver {
+	if v.waivers == nil {
+		return nil
+	}
+	var applied []config.Waiver
+	for i, w := range v.waivers.waivers {
+		if v.waivers.applied[i] {
+			applied = append(applied, w)
+		}
+	}
+	return applied
+}
+
+// applyWaivers downgrades the error findings matched by an unexpired waiver
+// and appends an error for each expired waiver of the API.
+func (v *Validator) applyWaivers(findings []Finding) []Finding {
+	ws := v.waivers
+	if ws == nil || ws.apiID == "" {
+		return findings
+	}
+	now := ws.now()
+	for i, f := range findings {
+		if f.Severity != SeverityError {
+			continue
+		}
+		for j, w := range ws.waivers {
+			if w.APIID != ws.apiID || w.Expired(now) || !waiverMatches(w, f) {
+				continue
+			}
+			f.Severity = SeverityWarning
+			f.Message += fmt.Sprintf(" (waived until %s by %s: %s)", w.Expires, w.Owner, w.Reason)
+			findings[i] = f
+			ws.applied[j] = true
+			break
+		}
+	}
+	for _, w := range ws.waivers {
+		if w.APIID != ws.apiID || !w.Expired(now) {
+			continue
+		}
+		findings = append(findings, Finding{
+			File:     ws.source,
+			RuleID:   RuleWaiverExpired,
+			Severity: SeverityError,
+			Path:     w.Path,
+			Message: fmt.Sprintf("waiver for %s at %s (owner %s) expired on %s; fix the change or renew the waiver",
+				w.Rule, w.Path, w.Owner, w.Expires),
+		})
+	}
+	return findings
+}
+
+// waiverMatches reports whether w covers finding f: the same rule, and
+// the finding's element path, or its file when it has none.
+func waiverMatches(w config.Waiver, f Finding) bool {
+	if w.Rule != f.RuleID {
+		return false
+	}
+	if f.Path != "" {
+		return f.Path == w.Path
+	}
+	file := filepath.ToSlash(f.File)
+	return file != "" && (file == w.Path || strings.HasSuffix(file, "/"+w.Path))
+}
@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infobloxopen/apx/internal/config"
+)
+
+func testWaiver(apiID, rule, path, expires string) config.Waiver {
+	return config.Waiver{APIID: apiID, Rule: rule, Path: path, Owner: "identity-team", Reason: "agreed with consumers", Expires: expires}
+}
+
+func TestApplyWaivers(t *testing.T) {
+	const api = "avro/users/profile/v1"
+	v := NewValidator(NewToolchainResolver())
+	v.SetWaivers([]config.Waiver{
+		testWaiver(api, "avro-field-added-without-default", "User.phone", "2026-06-30"),
+		testWaiver(api, "FIELD_NO_DELETE", "ledger.proto", "2026-06-30"),
+		testWaiver("avro/users/other/v1", "avro-field-type-changed", "User.id", "2026-06-30"),
+		testWaiver(api, "avro-field-removed", "User.legacy", "2026-01-31"),
+	}, "apx-waivers.yaml", api)
+	v.waivers.now = func() time.Time { return time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC) }
+
+	findings := v.applyWaivers([]Finding{
+		{RuleID: "avro-field-added-without-default", Severity: SeverityError, Path: "User.phone", Message: "field added"},
+		{RuleID: "FIELD_NO_DELETE", Severity: SeverityError, File: "proto/ledger/v1/ledger.proto", Message: "field deleted"},
+		{RuleID: "avro-field-type-changed", Severity: SeverityError, Path: "User.id", Message: "type changed"},
+		{RuleID: "avro-field-added-without-default", Severity: SeverityError, Path: "User.email", Message: "field added"},
+	})
+
+	wantSeverity := []Severity{SeverityWarning, SeverityWarning, SeverityError, SeverityError, SeverityError}
+	if len(findings) != len(wantSeverity) {
+		t.Fatalf("got %d findings, want %d: %v", len(findings), len(wantSeverity), findings)
+	}
+	for i, want := range wantSeverity {
+		if findings[i].Severity != want {
+			t.Errorf("finding %d (%s %s): severity %s, want %s", i, findings[i].RuleID, findings[i].Path, findings[i].Severity, want)
+		}
+	}
+	if !strings.Contains(findings[0].Message, "waived until 2026-06-30 by identity-team") {
+		t.Errorf("waived message = %q", findings[0].Message)
+	}
+	expired := findings[4]
+	if expired.RuleID != RuleWaiverExpired || expired.File != "apx-waivers.yaml" || expired.Path != "User.legacy" {
+		t.Errorf("expired waiver finding = %+v", expired)
+	}
+	if applied := v.AppliedWaivers(); len(applied) != 2 || applied[0].Path != "User.phone" || applied[1].Path != "ledger.proto" {
+		t.Errorf("applied = %+v", applied)
+	}
+}
+
+func TestBreaking_Waived(t *testing.T) {
+	v := NewValidator(NewToolchainResolver())
+	if err := v.Breaking("testdata/avro/v2_breaking.avsc", "testdata/avro/v1.avsc", FormatAvro); err == nil {
+		t.Fatal("expected a breaking change without a waiver")
+	}
+	v.SetWaivers([]config.Waiver{
+		testWaiver("avro/users/profile/v1", "avro-field-added-without-default", "User.phone", "2099-12-31"),
+	}, "apx-waivers.yaml", "avro/users/profile/v1")
+	if err := v.Breaking("testdata/avro/v2_breaking.avsc", "testdata/avro/v1.avsc", FormatAvro); err != nil {
+		t.Errorf("Breaking must apply waivers like BreakingFindings, got: %v", err)
+	}
+}
+
+func TestClassifyChanges_Waived(t *testing.T) {
+	v := NewValidator(NewToolchainResolver())
+	v.SetWaivers([]config.Waiver{
+		testWaiver("avro/users/profile/v1", "avro-field-added-without-default", "User.phone", "2099-12-31"),
+	}, "apx-waivers.yaml", "avro/users/profile/v1")
+
+	report, err := v.ClassifyChanges("testdata/avro/v2_breaking.avsc", "testdata/avro/v1.avsc", FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != ChangeAdditive {
+		t.Errorf("level = %s, want additive", report.Level)
+	}
+
+	// Without an API ID no waiver applies.
+	v.SetWaivers(v.waivers.waivers, "apx-waivers.yaml", "")
+	report, err = v.ClassifyChanges("testdata/avro/v2_breaking.avsc", "testdata/avro/v1.avsc", FormatAvro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != ChangeBreaking {
+		t.Errorf("level = %s, want breaking", report.Level)
+	}
+}
@@ -77,6 +77,19 @@ branch_targets:
 	return dir
 }
 
+// addUsersEndpoint commits an additive change to the users spec, so a MINOR
+// bump over the v1.1.1 release is the one release prepare accepts.
+func addUsersEndpoint(t *testing.T, dir string) {
+	t.Helper()
+	spec := "openapi: 3.0.0\ninfo:\n  title: Users\n  version: 1.2.0\npaths:\n" +
+		"  /users:\n    get:\n      responses:\n        \"200\":\n          description: Users\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi", "users", "v1", "users.yaml"), []byte(spec), 0o644))
+	cmd := exec.Command("git", "commit", "-am", "add users endpoint")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoErrorf(t, err, "git commit: %s", string(out))
+}
+
 // runPrepare runs `apx release prepare` in dir and returns combined output + err.
 func runPrepare(t *testing.T, dir string, args ...string) (string, error) {
 	t.Helper()
@@ -152,6 +165,7 @@ func TestBranchRouting_BaseBranchResolution(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dir := setupBranchRoutingRepo(t)
+			addUsersEndpoint(t, dir)
 			args := []string{
 				apiID,
 				"--version", tt.version,
@@ -73,6 +73,18 @@ func TestConsumerWorkflow(t *testing.T) {
 		t.Errorf("apx.yaml missing repo field")
 	}
 
+	// The dependencies below are not published, so their schemas cannot be
+	// materialized. Drop the default Go plugins: gen then only scaffolds
+	// overlays, which is what this workflow exercises.
+	goPlugins := "        plugins:\n            - name: protoc-gen-go\n              version: v1.64.0\n            - name: protoc-gen-go-grpc\n              version: v1.5.0\n"
+	if !strings.Contains(string(apxYaml), goPlugins) {
+		t.Fatalf("apx.yaml missing default Go plugins, got:\n%s", apxYaml)
+	}
+	apxYaml = []byte(strings.Replace(string(apxYaml), goPlugins, "", 1))
+	if err := os.WriteFile(filepath.Join(workDir, "apx.yaml"), apxYaml, 0644); err != nil {
+		t.Fatalf("Failed to write apx.yaml: %v", err)
+	}
+
 	// Step 2: Add dependencies
 	output = run(apxBin, "add", "proto/payments/ledger/v1@v1.2.3")
 	if !strings.Contains(output, "Added dependency") {